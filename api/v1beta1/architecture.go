@@ -0,0 +1,47 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// Architecture is the CPU architecture of a machine type or of an image, named as in the architecture of GCE images.
+type Architecture string
+
+const (
+	// ArchitectureAMD64 is the x86-64 architecture.
+	ArchitectureAMD64 Architecture = "X86_64"
+	// ArchitectureARM64 is the 64-bit Arm architecture.
+	ArchitectureARM64 Architecture = "ARM64"
+)
+
+// Machine series running on Arm processors, all other machine series run on x86-64 processors.
+// reference: https://cloud.google.com/compute/docs/instances/arm-on-compute
+var arm64MachineSeries = []string{"t2a", "c4a", "a4x"}
+
+// MachineTypeArchitecture returns the CPU architecture of a machine type, given by its name, e.g. t2a-standard-4, or
+// its URL, e.g. zones/us-central1-a/machineTypes/t2a-standard-4.
+func MachineTypeArchitecture(machineType string) Architecture {
+	series, _, _ := strings.Cut(path.Base(machineType), "-")
+	if slices.Contains(arm64MachineSeries, series) {
+		return ArchitectureARM64
+	}
+	return ArchitectureAMD64
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "testing"
+
+func TestMachineTypeArchitecture(t *testing.T) {
+	tests := []struct {
+		machineType string
+		want        Architecture
+	}{
+		{machineType: "n1-standard-2", want: ArchitectureAMD64},
+		{machineType: "e2-medium", want: ArchitectureAMD64},
+		{machineType: "t2d-standard-4", want: ArchitectureAMD64},
+		{machineType: "n2-custom-4-8192", want: ArchitectureAMD64},
+		{machineType: "t2a-standard-4", want: ArchitectureARM64},
+		{machineType: "c4a-highmem-8", want: ArchitectureARM64},
+		{machineType: "a4x-highgpu-4g", want: ArchitectureARM64},
+		{machineType: "zones/us-central1-a/machineTypes/t2a-standard-1", want: ArchitectureARM64},
+		{machineType: "zones/us-central1-a/machineTypes/n2-standard-2", want: ArchitectureAMD64},
+		{machineType: "", want: ArchitectureAMD64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.machineType, func(t *testing.T) {
+			if got := MachineTypeArchitecture(tt.machineType); got != tt.want {
+				t.Errorf("MachineTypeArchitecture(%q) = %v, want %v", tt.machineType, got, tt.want)
+			}
+		})
+	}
+}
@@ -16,9 +16,155 @@ limitations under the License.
 
 package v1beta1
 
+import clusterv1beta1 "sigs.k8s.io/cluster-api/api/core/v1beta1"
+
 const (
 	// WaitingForClusterInfrastructureReason used when machine is waiting for cluster infrastructure to be ready before proceeding.
 	WaitingForClusterInfrastructureReason = "WaitingForClusterInfrastructure"
 	// WaitingForBootstrapDataReason used when machine is waiting for bootstrap data to be ready before proceeding.
 	WaitingForBootstrapDataReason = "WaitingForBootstrapData"
 )
+
+const (
+	// InstanceReadyCondition reports on the state of the GCE instance backing the GCPMachine.
+	InstanceReadyCondition clusterv1beta1.ConditionType = "InstanceReady"
+	// InstancePreemptedReason used when the Spot or preemptible instance has been preempted by GCE.
+	InstancePreemptedReason = "InstancePreempted"
+	// InstanceProvisioningReason used when GCE is allocating resources for the instance.
+	InstanceProvisioningReason = "InstanceProvisioning"
+	// InstanceStagingReason used when GCE is preparing the instance for its first boot.
+	InstanceStagingReason = "InstanceStaging"
+	// InstanceRepairingReason used when GCE is repairing the instance after an internal error or a host failure.
+	InstanceRepairingReason = "InstanceRepairing"
+	// InstanceStoppingReason used when the instance is being stopped.
+	InstanceStoppingReason = "InstanceStopping"
+	// InstanceStoppedReason used when the instance has been stopped.
+	InstanceStoppedReason = "InstanceStopped"
+	// InstanceSuspendingReason used when the instance is being suspended.
+	InstanceSuspendingReason = "InstanceSuspending"
+	// InstanceSuspendedReason used when the instance has been suspended.
+	InstanceSuspendedReason = "InstanceSuspended"
+	// InstanceTerminatedReason used when the instance has been stopped outside of a delete of the GCPMachine,
+	// e.g. manually or by a guest OS shutdown.
+	InstanceTerminatedReason = "InstanceTerminated"
+	// InstanceStateUnknownReason used when the instance is in a state unknown to the controller.
+	InstanceStateUnknownReason = "InstanceStateUnknown"
+	// InvalidConfigurationReason used when the instance can't be created because of an invalid GCPMachine spec,
+	// e.g. an attached disk in another zone than the instance.
+	InvalidConfigurationReason = "InvalidConfiguration"
+	// ZoneResourcePoolExhaustedReason used when the zone of the instance doesn't have enough resources to create it.
+	ZoneResourcePoolExhaustedReason = "ZoneResourcePoolExhausted"
+	// InsufficientResourcesReason used when the instance can't be created because a quota of the project is exceeded.
+	InsufficientResourcesReason = "InsufficientResources"
+	// ResourceManagerTagsBindingFailedReason used when the resource manager tags can't be resolved or bound to the
+	// instance, usually because of missing permissions on the tags.
+	ResourceManagerTagsBindingFailedReason = "ResourceManagerTagsBindingFailed"
+)
+
+const (
+	// InstanceProvisionedCondition reports whether the GCE instance backing the GCPMachine was created, or found. Its
+	// reason tells why the instance couldn't be created, the InstanceReady condition then reports whether it's running.
+	InstanceProvisionedCondition clusterv1beta1.ConditionType = "InstanceProvisioned"
+	// ImageNotFoundReason used when the image of the instance, or the latest image of its image family or image
+	// lookup, doesn't exist.
+	ImageNotFoundReason = "ImageNotFound"
+	// InvalidMachineTypeReason used when the machine type of the instance doesn't exist in its zone.
+	InvalidMachineTypeReason = "InvalidMachineType"
+	// InstanceCreationFailedReason used when the instance can't be created for any other reason.
+	InstanceCreationFailedReason = "InstanceCreationFailed"
+)
+
+const (
+	// BootstrapDataReadyCondition reports whether the bootstrap data of the Machine was delivered to the GCE instance
+	// backing the GCPMachine, through its metadata or a GCS object.
+	BootstrapDataReadyCondition clusterv1beta1.ConditionType = "BootstrapDataReady"
+	// BootstrapDataInvalidReason used when the bootstrap data has an unsupported format or exceeds the size limit of
+	// the instance metadata.
+	BootstrapDataInvalidReason = "BootstrapDataInvalid"
+	// BootstrapDataStorageFailedReason used when the bootstrap data can't be uploaded to the GCS bucket of the cluster.
+	BootstrapDataStorageFailedReason = "BootstrapDataStorageFailed"
+	// BootstrapDataSyncFailedReason used when the changed bootstrap data of an instance that hasn't joined the cluster
+	// yet can't be applied to it.
+	BootstrapDataSyncFailedReason = "BootstrapDataSyncFailed"
+)
+
+const (
+	// InstanceDrainedCondition reports whether the connections to the GCE instance backing a deleted GCPMachine were
+	// drained, after removing the instance from the instance groups managed by the provider.
+	InstanceDrainedCondition clusterv1beta1.ConditionType = "InstanceDrained"
+	// InstanceDrainingReason used while waiting for the drain timeout of the GCPMachine before deleting the instance.
+	InstanceDrainingReason = "InstanceDraining"
+)
+
+const (
+	// DriftDetectedCondition reports the fields of the GCE instance backing the GCPMachine that drifted from the
+	// GCPMachine spec and aren't converged, either because they're immutable or because drift remediation is disabled.
+	// It is removed when the instance matches the spec.
+	DriftDetectedCondition clusterv1beta1.ConditionType = "DriftDetected"
+	// InstanceDriftedReason used when fields of the instance drifted from the GCPMachine spec.
+	InstanceDriftedReason = "InstanceDrifted"
+)
+
+const (
+	// EgressReadyCondition reports whether the GCE instance backing the GCPMachine has egress, either through an
+	// external IP or through the Cloud NAT managed by the cluster.
+	EgressReadyCondition clusterv1beta1.ConditionType = "EgressReady"
+	// NoCloudNATReason used when the instance has no external IP and the cluster doesn't manage a Cloud NAT.
+	NoCloudNATReason = "NoCloudNAT"
+)
+
+const (
+	// PrivateGoogleAccessReadyCondition reports whether the GCE instance backing the GCPMachine can reach Google APIs,
+	// either through an external IP or through the Private Google Access of its subnet.
+	PrivateGoogleAccessReadyCondition clusterv1beta1.ConditionType = "PrivateGoogleAccessReady"
+	// PrivateGoogleAccessDisabledReason used when the instance has no external IP and Private Google Access is
+	// disabled on its subnet.
+	PrivateGoogleAccessDisabledReason = "PrivateGoogleAccessDisabled"
+)
+
+const (
+	// NetworkPeeringsReadyCondition reports whether the peerings of the cluster network are active.
+	NetworkPeeringsReadyCondition clusterv1beta1.ConditionType = "NetworkPeeringsReady"
+	// NetworkPeeringInactiveReason used when the peer network hasn't created the reciprocal peering.
+	NetworkPeeringInactiveReason = "NetworkPeeringInactive"
+)
+
+const (
+	// NetworkReadyCondition reports whether the network of the GCPCluster is reconciled, i.e. created or found, and
+	// its MTU and peerings are up to date.
+	NetworkReadyCondition clusterv1beta1.ConditionType = "NetworkReady"
+	// NetworkReconciliationFailedReason used when the network can't be reconciled, e.g. a shared VPC host project
+	// the project isn't attached to.
+	NetworkReconciliationFailedReason = "NetworkReconciliationFailed"
+)
+
+const (
+	// RouterReadyCondition reports whether the Cloud Router and Cloud NAT of the network managed by the GCPCluster are
+	// reconciled. It is removed when the network isn't managed by the GCPCluster.
+	RouterReadyCondition clusterv1beta1.ConditionType = "RouterReady"
+	// RouterReconciliationFailedReason used when the Cloud Router can't be created.
+	RouterReconciliationFailedReason = "RouterReconciliationFailed"
+)
+
+const (
+	// SubnetsReadyCondition reports whether the subnets of the GCPCluster are reconciled.
+	SubnetsReadyCondition clusterv1beta1.ConditionType = "SubnetsReady"
+	// SubnetsReconciliationFailedReason used when a subnet can't be created or updated.
+	SubnetsReconciliationFailedReason = "SubnetsReconciliationFailed"
+)
+
+const (
+	// FirewallsReadyCondition reports whether the firewall rules of the GCPCluster are reconciled. It is removed when
+	// the firewall rules aren't managed by the GCPCluster.
+	FirewallsReadyCondition clusterv1beta1.ConditionType = "FirewallsReady"
+	// FirewallsReconciliationFailedReason used when a firewall rule can't be created or updated.
+	FirewallsReconciliationFailedReason = "FirewallsReconciliationFailed"
+)
+
+const (
+	// LoadBalancerReadyCondition reports whether the control plane load balancers of the GCPCluster are reconciled.
+	LoadBalancerReadyCondition clusterv1beta1.ConditionType = "LoadBalancerReady"
+	// LoadBalancerReconciliationFailedReason used when a resource of the load balancers can't be created or updated,
+	// e.g. the instance groups, the backend services or the forwarding rules.
+	LoadBalancerReconciliationFailedReason = "LoadBalancerReconciliationFailed"
+)
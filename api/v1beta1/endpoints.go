@@ -46,4 +46,11 @@ type ServiceEndpoints struct {
 	// +kubebuilder:validation:Pattern=`^https://`
 	// +optional
 	ResourceManagerServiceEndpoint string `json:"resourceManager,omitempty"`
+
+	// StorageServiceEndpoint is the custom endpoint url for the Storage Service
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=uri
+	// +kubebuilder:validation:Pattern=`^https://`
+	// +optional
+	StorageServiceEndpoint string `json:"storage,omitempty"`
 }
@@ -45,15 +45,27 @@ type GCPClusterSpec struct {
 
 	// FailureDomains is an optional field which is used to assign selected availability zones to a cluster
 	// FailureDomains if empty, defaults to all the zones in the selected region and if specified would override
-	// the default zones.
+	// the default zones. The zones must be in the selected region.
 	// +optional
 	FailureDomains []string `json:"failureDomains,omitempty"`
 
+	// ControlPlaneCompactPlacement defines whether a compact placement policy is created in the cluster region and
+	// attached to the control plane machines, to place them close to each other for low network latency.
+	// Compact placement requires the control plane machines to run in a single zone, FailureDomains must list
+	// exactly one zone.
+	// +optional
+	ControlPlaneCompactPlacement *bool `json:"controlPlaneCompactPlacement,omitempty"`
+
 	// AdditionalLabels is an optional set of tags to add to GCP resources managed by the GCP provider, in addition to the
-	// ones added by default.
+	// ones added by default. Changes are applied to the existing instances of the cluster.
 	// +optional
 	AdditionalLabels Labels `json:"additionalLabels,omitempty"`
 
+	// AdditionalNetworkTags is a list of network tags that should be applied to all the instances of the cluster,
+	// in addition to the ones defined by each GCPMachine. Changes are applied to the existing instances.
+	// +optional
+	AdditionalNetworkTags []string `json:"additionalNetworkTags,omitempty"`
+
 	// ResourceManagerTags is an optional set of tags to apply to GCP resources managed
 	// by the GCP provider. GCP supports a maximum of 50 tags per resource.
 	// +maxItems=50
@@ -64,9 +76,19 @@ type GCPClusterSpec struct {
 	// supplied then the credentials of the controller will be used.
 	// When creating a new GCP client, the controller will try to extract the type
 	// of credential from the JSON data, and it will request a client for the specific credential type.
+	// The credentials are read from the `credentials` key of the Secret on every reconcile, so that they can be rotated
+	// in the Secret without restarting the controller.
 	// +optional
 	CredentialsRef *ObjectReference `json:"credentialsRef,omitempty"`
 
+	// ServiceAccountToImpersonate is the email of a service account the controller impersonates to provision this
+	// cluster, e.g. in another project, rather than using a service account key. The controller authenticates with
+	// short-lived tokens the IAM Credentials API issues for it, so its own service account, or the one of the
+	// CredentialsRef, must have the Service Account Token Creator role (roles/iam.serviceAccountTokenCreator) on it.
+	// +kubebuilder:validation:Pattern=`^[^@]+@[^@]+\.gserviceaccount\.com$`
+	// +optional
+	ServiceAccountToImpersonate string `json:"serviceAccountToImpersonate,omitempty"`
+
 	// LoadBalancer contains configuration for one or more LoadBalancers.
 	// +optional
 	LoadBalancer LoadBalancerSpec `json:"loadBalancer,omitempty"`
@@ -75,6 +97,119 @@ type GCPClusterSpec struct {
 	// For instance, the user can specify a new endpoint for the compute service.
 	// +optional
 	ServiceEndpoints *ServiceEndpoints `json:"serviceEndpoints,omitempty"`
+
+	// DiskEncryptionKey is the default Customer-Managed Encryption Key (CMEK) used to encrypt the boot
+	// and additional persistent disks of the cluster machines that don't define their own encryption key.
+	// Only keys of type Managed are supported.
+	// +optional
+	DiskEncryptionKey *CustomerEncryptionKey `json:"diskEncryptionKey,omitempty"`
+
+	// MachinePublicIP defines whether the cluster machines get a public IP by default.
+	// It can be overridden by the GCPMachine PublicIP setting.
+	// Machines without a public IP rely on the Cloud NAT created with the cluster network for egress.
+	// +optional
+	MachinePublicIP *bool `json:"machinePublicIP,omitempty"`
+
+	// EnableOSLogin defines whether OS Login is enabled by default on the cluster machines.
+	// It can be overridden by the GCPMachine EnableOSLogin setting. Changes are applied to the running instances.
+	// +optional
+	EnableOSLogin *bool `json:"enableOSLogin,omitempty"`
+
+	// EnableOSLogin2FA defines whether two-factor authentication is required by default for OS Login on the
+	// cluster machines. It can be overridden by the GCPMachine EnableOSLogin2FA setting.
+	// +optional
+	EnableOSLogin2FA *bool `json:"enableOSLogin2FA,omitempty"`
+
+	// ImageLookupProject is the default project to look up the images of the cluster machines in.
+	// It can be overridden by the GCPMachine ImageLookupProject setting.
+	// +optional
+	ImageLookupProject *string `json:"imageLookupProject,omitempty"`
+
+	// ImageLookupBaseOS is the default base OS of the images looked up for the cluster machines.
+	// It can be overridden by the GCPMachine ImageLookupBaseOS setting.
+	// +optional
+	ImageLookupBaseOS *string `json:"imageLookupBaseOS,omitempty"`
+
+	// ImageLookupFormat is the default name format of the images looked up for the cluster machines.
+	// It can be overridden by the GCPMachine ImageLookupFormat setting.
+	// +optional
+	ImageLookupFormat *string `json:"imageLookupFormat,omitempty"`
+
+	// NodeServiceAccount configures a dedicated IAM service account created by the controller for the worker
+	// machines of the cluster, granted the minimal roles required by the nodes. Worker GCPMachines that don't define
+	// their own ServiceAccount use it. The service account is deleted along with the cluster.
+	// +optional
+	NodeServiceAccount *NodeServiceAccountSpec `json:"nodeServiceAccount,omitempty"`
+
+	// BootstrapDataStorage configures where the bootstrap data of the cluster machines is stored. By default it is
+	// set to the instance metadata, whose values are limited to 256KB.
+	// +optional
+	BootstrapDataStorage *BootstrapDataStorageSpec `json:"bootstrapDataStorage,omitempty"`
+
+	// WorkloadIdentity configures the Workload Identity Federation of the cluster workloads, letting pods impersonate
+	// IAM service accounts with their Kubernetes service account tokens instead of keys. The pool provider is
+	// advertised to the workloads through the metadata of the cluster machines. Changes are applied to the running
+	// instances.
+	// +optional
+	WorkloadIdentity *WorkloadIdentitySpec `json:"workloadIdentity,omitempty"`
+}
+
+const (
+	// WorkloadIdentityProviderMetadataKey is the instance metadata key holding the full resource name of the
+	// workload identity pool provider of the cluster.
+	WorkloadIdentityProviderMetadataKey = "workload-identity-provider"
+
+	// WorkloadIdentityAudienceMetadataKey is the instance metadata key holding the audience of the Kubernetes
+	// service account tokens exchanged with the workload identity pool provider of the cluster.
+	WorkloadIdentityAudienceMetadataKey = "workload-identity-audience"
+)
+
+// WorkloadIdentitySpec configures the Workload Identity Federation of the cluster workloads.
+type WorkloadIdentitySpec struct {
+	// Provider is the full resource name of the workload identity pool provider trusting the service account
+	// issuer of the cluster, i.e.
+	// projects/<project number>/locations/global/workloadIdentityPools/<pool>/providers/<provider>.
+	// +kubebuilder:validation:Pattern=`^projects/[0-9]+/locations/global/workloadIdentityPools/[a-z0-9-]+/providers/[a-z0-9-]+$`
+	Provider string `json:"provider"`
+}
+
+// NodeServiceAccountSpec configures the service account created for the worker machines of a cluster.
+type NodeServiceAccountSpec struct {
+	// AdditionalRoles is a list of IAM roles granted to the service account on the project, in addition to
+	// roles/logging.logWriter, roles/monitoring.metricWriter and roles/artifactregistry.reader.
+	// +optional
+	AdditionalRoles []string `json:"additionalRoles,omitempty"`
+}
+
+// BootstrapDataStorageType is where the bootstrap data of the machines is stored.
+type BootstrapDataStorageType string
+
+const (
+	// BootstrapDataStorageMetadata sets the bootstrap data to the instance metadata.
+	BootstrapDataStorageMetadata BootstrapDataStorageType = "Metadata"
+	// BootstrapDataStorageGCS uploads the bootstrap data to a GCS object, fetched by the instance on boot with its
+	// service account.
+	BootstrapDataStorageGCS BootstrapDataStorageType = "GCS"
+)
+
+// BootstrapDataStorageSpec configures where the bootstrap data of the machines is stored.
+type BootstrapDataStorageSpec struct {
+	// Type is where the bootstrap data is stored. With GCS, the bootstrap data is uploaded to an object of the
+	// Bucket and the instance metadata only holds a short script fetching it, so bootstrap data larger than the
+	// 256KB limit of the metadata values can be used. The service account of the instances must be allowed to read
+	// the objects of the Bucket, e.g. with the roles/storage.objectViewer role.
+	// +kubebuilder:validation:Enum=Metadata;GCS
+	// +kubebuilder:default=Metadata
+	Type BootstrapDataStorageType `json:"type"`
+
+	// Bucket is the name of the GCS bucket the bootstrap data is uploaded to. Required when Type is GCS.
+	// +optional
+	Bucket string `json:"bucket,omitempty"`
+
+	// TTL is how long the bootstrap data objects are kept after their instance is created, they are deleted
+	// afterwards or when the machine is deleted, whichever comes first. Defaults to 1h.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
 }
 
 // GCPClusterStatus defines the observed state of GCPCluster.
@@ -82,8 +217,17 @@ type GCPClusterStatus struct {
 	FailureDomains clusterv1beta1.FailureDomains `json:"failureDomains,omitempty"`
 	Network        Network                       `json:"network,omitempty"`
 
+	// NodeServiceAccountEmail is the email of the service account created for the worker machines of the cluster,
+	// when NodeServiceAccount is set.
+	// +optional
+	NodeServiceAccountEmail string `json:"nodeServiceAccountEmail,omitempty"`
+
 	// Bastion Instance `json:"bastion,omitempty"`
 	Ready bool `json:"ready"`
+
+	// Conditions defines current service state of the GCPCluster.
+	// +optional
+	Conditions clusterv1beta1.Conditions `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -104,6 +248,16 @@ type GCPCluster struct {
 	Status GCPClusterStatus `json:"status,omitempty"`
 }
 
+// GetConditions returns the GCPCluster conditions.
+func (c *GCPCluster) GetConditions() clusterv1beta1.Conditions {
+	return c.Status.Conditions
+}
+
+// SetConditions sets the status conditions for the GCPCluster.
+func (c *GCPCluster) SetConditions(conditions clusterv1beta1.Conditions) {
+	c.Status.Conditions = conditions
+}
+
 // +kubebuilder:object:root=true
 
 // GCPClusterList contains a list of GCPCluster.
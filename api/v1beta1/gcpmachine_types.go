@@ -19,12 +19,31 @@ package v1beta1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1beta1 "sigs.k8s.io/cluster-api/api/core/v1beta1"
 )
 
 const (
 	// MachineFinalizer allows ReconcileGCPMachine to clean up GCP resources associated with GCPMachine before
 	// removing it from the apiserver.
 	MachineFinalizer = "gcpmachine.infrastructure.cluster.x-k8s.io"
+
+	// BootstrapDataMetadataKey is the instance metadata key holding the bootstrap data of the machine.
+	// It is reserved and can't be set through AdditionalMetadata.
+	BootstrapDataMetadataKey = "user-data"
+
+	// WindowsBootstrapDataMetadataKey is the instance metadata key holding the bootstrap data of a Windows machine,
+	// run as a PowerShell script by the Windows guest environment. It is reserved and can't be set through
+	// AdditionalMetadata on Windows machines.
+	WindowsBootstrapDataMetadataKey = "windows-startup-script-ps1"
+
+	// LastAppliedLabelsAnnotation records the user-declared labels last applied to the instance of the GCPMachine, so
+	// that labels removed from the spec can be removed from the instance without removing labels set by other tools.
+	LastAppliedLabelsAnnotation = "gcp.cluster.x-k8s.io/last-applied-labels"
+
+	// LastAppliedMetadataAnnotation records the keys of the metadata last applied to the instance of the GCPMachine,
+	// so that metadata removed from the spec can be removed from the instance without removing metadata set by other
+	// tools, e.g. the ssh-keys of the guest environment.
+	LastAppliedMetadataAnnotation = "gcp.cluster.x-k8s.io/last-applied-metadata"
 )
 
 // DiskType is a type to use to define with disk type will be used.
@@ -35,6 +54,12 @@ const (
 	PdStandardDiskType DiskType = "pd-standard"
 	// PdSsdDiskType defines the name for the ssd disk.
 	PdSsdDiskType DiskType = "pd-ssd"
+	// PdBalancedDiskType defines the name for the balanced disk.
+	PdBalancedDiskType DiskType = "pd-balanced"
+	// PdExtremeDiskType defines the name for the extreme disk.
+	PdExtremeDiskType DiskType = "pd-extreme"
+	// HyperdiskBalancedDiskType defines the name for the hyperdisk balanced disk.
+	HyperdiskBalancedDiskType DiskType = "hyperdisk-balanced"
 	// LocalSsdDiskType defines the name for the local ssd disk.
 	LocalSsdDiskType DiskType = "local-ssd"
 )
@@ -52,12 +77,87 @@ type AttachedDiskSpec struct {
 	// +optional
 	DeviceType *DiskType `json:"deviceType,omitempty"`
 	// Size is the size of the disk in GBs.
-	// Defaults to 30GB. For "local-ssd" size is always 375GB.
+	// Defaults to 30GB. For "local-ssd" size is always 375GB and any other value is rejected.
 	// +optional
 	Size *int64 `json:"size,omitempty"`
 	// EncryptionKey defines the KMS key to be used to encrypt the disk.
 	// +optional
 	EncryptionKey *CustomerEncryptionKey `json:"encryptionKey,omitempty"`
+	// DeviceName is the name exposed to the guest OS under /dev/disk/by-id/google-*.
+	// For GCPMachines, it is also used to name the persistent disk after the instance, i.e. <instance-name>-<device-name>.
+	// If not specified, the disk is named after its position in the AdditionalDisks list, i.e. <instance-name>-disk-<index>.
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=32
+	// +optional
+	DeviceName *string `json:"deviceName,omitempty"`
+	// Labels is an optional set of labels applied to the disk,
+	// in addition to the labels applied to the instance. Labels are ignored for "local-ssd" disks.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// AutoDelete defines whether the disk is deleted when the instance is deleted.
+	// Defaults to true. Local SSD disks are always deleted with the instance.
+	// +optional
+	AutoDelete *bool `json:"autoDelete,omitempty"`
+	// ReplicaZones makes the disk a regional persistent disk synchronously replicated across the two zones,
+	// e.g. us-central1-a and us-central1-b. The zones must be in the region of the instance and one of them must
+	// be the zone of the instance. Only supported by the "pd-standard", "pd-balanced" and "pd-ssd" device types,
+	// and by GCPMachines.
+	// +kubebuilder:validation:MinItems=2
+	// +kubebuilder:validation:MaxItems=2
+	// +optional
+	ReplicaZones []string `json:"replicaZones,omitempty"`
+}
+
+// DiskMode is the mode in which a disk is attached to an instance.
+type DiskMode string
+
+const (
+	// DiskModeReadWrite attaches the disk in read-write mode. A disk can only be attached to a single
+	// instance in read-write mode.
+	DiskModeReadWrite DiskMode = "ReadWrite"
+	// DiskModeReadOnly attaches the disk in read-only mode, it can be attached to multiple instances at once.
+	DiskModeReadOnly DiskMode = "ReadOnly"
+)
+
+// ExistingDiskSpec references a pre-existing persistent disk attached to a GCP machine.
+type ExistingDiskSpec struct {
+	// Name is the name of the disk, which must exist in the zone of the instance. A disk of another project
+	// can be referenced by its URL, i.e. projects/<project>/zones/<zone>/disks/<disk>.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// DeviceName is the name exposed to the guest OS under /dev/disk/by-id/google-*.
+	// If not specified, GCE names the device persistent-disk-<index>.
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	DeviceName *string `json:"deviceName,omitempty"`
+	// Mode is the mode in which the disk is attached.
+	// Default is "ReadWrite".
+	// +kubebuilder:validation:Enum=ReadWrite;ReadOnly
+	// +optional
+	Mode *DiskMode `json:"mode,omitempty"`
+}
+
+// LocalSSDInterface is the interface used to attach local SSDs to the GCP machine.
+type LocalSSDInterface string
+
+const (
+	// LocalSSDInterfaceNVME attaches local SSDs using the NVMe interface.
+	LocalSSDInterfaceNVME LocalSSDInterface = "NVME"
+	// LocalSSDInterfaceSCSI attaches local SSDs using the SCSI interface.
+	LocalSSDInterfaceSCSI LocalSSDInterface = "SCSI"
+)
+
+// LocalSSDSpec defines a set of local SSDs attached to the GCP machine.
+type LocalSSDSpec struct {
+	// Count is the number of local SSDs to attach. Each local SSD is 375GB.
+	// +kubebuilder:validation:Minimum=1
+	Count int64 `json:"count"`
+	// Interface is the interface used to attach the local SSDs.
+	// Default is "NVME".
+	// +kubebuilder:validation:Enum=NVME;SCSI
+	// +optional
+	Interface *LocalSSDInterface `json:"interface,omitempty"`
 }
 
 // IPForwarding represents the IP forwarding configuration for the GCP machine.
@@ -105,6 +205,8 @@ const (
 type GCPShieldedInstanceConfig struct {
 	// SecureBoot Defines whether the instance should have secure boot enabled.
 	// Secure Boot verify the digital signature of all boot components, and halting the boot process if signature verification fails.
+	// Secure Boot requires the machine image to support UEFI, i.e. to have the UEFI_COMPATIBLE guest OS feature.
+	// This is verified when the instance is created.
 	// If omitted, the platform chooses a default, which is subject to change over time, currently that default is Disabled.
 	// +kubebuilder:validation:Enum=Enabled;Disabled
 	//+optional
@@ -228,6 +330,112 @@ const (
 	ProvisioningModelSpot ProvisioningModel = "Spot"
 )
 
+// RebootstrapPolicy defines what happens when the bootstrap data of a machine changes before its instance joined the
+// cluster.
+type RebootstrapPolicy string
+
+const (
+	// RebootstrapPolicyNever keeps the bootstrap data the instance was created with.
+	RebootstrapPolicyNever RebootstrapPolicy = "Never"
+	// RebootstrapPolicyUpdateMetadata updates the bootstrap data in the instance metadata.
+	RebootstrapPolicyUpdateMetadata RebootstrapPolicy = "UpdateMetadata"
+	// RebootstrapPolicyReset updates the bootstrap data in the instance metadata and resets the instance.
+	RebootstrapPolicyReset RebootstrapPolicy = "Reset"
+)
+
+// OSFamily is the operating system family of the image of an instance.
+type OSFamily string
+
+const (
+	// OSFamilyLinux is the Linux operating system family.
+	OSFamilyLinux OSFamily = "Linux"
+	// OSFamilyWindows is the Windows Server operating system family.
+	OSFamilyWindows OSFamily = "Windows"
+)
+
+// InstanceTerminationAction is a type for the action taken when a Spot VM is preempted.
+type InstanceTerminationAction string
+
+const (
+	// InstanceTerminationActionStop stops the VM when it is preempted.
+	InstanceTerminationActionStop InstanceTerminationAction = "Stop"
+	// InstanceTerminationActionDelete deletes the VM when it is preempted.
+	InstanceTerminationActionDelete InstanceTerminationAction = "Delete"
+)
+
+// NodeAffinityOperator is the operator used to match a sole-tenant node affinity label.
+type NodeAffinityOperator string
+
+const (
+	// NodeAffinityOperatorIn schedules the instance on the nodes the label value of which is one of the values.
+	NodeAffinityOperatorIn NodeAffinityOperator = "In"
+	// NodeAffinityOperatorNotIn schedules the instance on the nodes the label value of which is not one of the values.
+	NodeAffinityOperatorNotIn NodeAffinityOperator = "NotIn"
+)
+
+// NodeAffinity is a sole-tenant node affinity label used to schedule the instance on sole-tenant nodes.
+type NodeAffinity struct {
+	// Key is the node affinity label key, e.g. compute.googleapis.com/node-group-name.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+	// Operator is the operator used to match the node affinity label values.
+	// +kubebuilder:validation:Enum=In;NotIn
+	Operator NodeAffinityOperator `json:"operator"`
+	// Values are the node affinity label values.
+	// +kubebuilder:validation:MinItems=1
+	Values []string `json:"values"`
+}
+
+// NicType is the type of virtual network interface of an instance.
+type NicType string
+
+const (
+	// NicTypeGVNIC is the Google Virtual NIC, which is required for higher network bandwidths.
+	NicTypeGVNIC NicType = "GVNIC"
+	// NicTypeVirtioNet is the VirtIO network interface.
+	NicTypeVirtioNet NicType = "VIRTIO_NET"
+)
+
+// EgressBandwidthTier is the maximum egress bandwidth tier of an instance.
+type EgressBandwidthTier string
+
+const (
+	// EgressBandwidthTierDefault is the default egress bandwidth of the machine type.
+	EgressBandwidthTierDefault EgressBandwidthTier = "DEFAULT"
+	// EgressBandwidthTier1 raises the maximum egress bandwidth of the instance up to 100 Gbps, depending on
+	// the machine type.
+	EgressBandwidthTier1 EgressBandwidthTier = "TIER_1"
+)
+
+// NetworkPerformanceConfig defines the network performance of an instance.
+type NetworkPerformanceConfig struct {
+	// TotalEgressBandwidthTier is the egress bandwidth tier of the instance. TIER_1 requires the GVNIC NicType
+	// and a machine series that supports it, with at least 30 vCPUs. Defaults to DEFAULT.
+	// reference: https://cloud.google.com/compute/docs/networking/configure-vm-with-high-bandwidth-configuration
+	// +kubebuilder:validation:Enum=DEFAULT;TIER_1
+	// +optional
+	TotalEgressBandwidthTier *EgressBandwidthTier `json:"totalEgressBandwidthTier,omitempty"`
+}
+
+// AdvancedMachineFeatures defines the advanced CPU features of an instance.
+type AdvancedMachineFeatures struct {
+	// EnableNestedVirtualization defines whether nested virtualization is enabled, exposing VMX to the guest.
+	// Nested virtualization is only supported on Intel machine series, E2 and AMD machine series don't support it.
+	// +optional
+	EnableNestedVirtualization *bool `json:"enableNestedVirtualization,omitempty"`
+	// ThreadsPerCore is the number of threads per physical core, set it to 1 to disable simultaneous multithreading.
+	// If omitted, the maximum number of threads per core supported by the processor is used.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2
+	// +optional
+	ThreadsPerCore *int64 `json:"threadsPerCore,omitempty"`
+	// VisibleCoreCount is the number of physical cores exposed to the instance.
+	// If omitted, all the physical cores of the machine type are exposed.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	VisibleCoreCount *int64 `json:"visibleCoreCount,omitempty"`
+}
+
 // AliasIPRange is an alias IP range attached to an instance's network interface.
 type AliasIPRange struct {
 	// IPCidrRange is the IP alias ranges to allocate for this interface. This IP
@@ -240,16 +448,76 @@ type AliasIPRange struct {
 	IPCidrRange string `json:"ipCidrRange"`
 	// SubnetworkRangeName is the name of a subnetwork secondary IP range from which
 	// to allocate an IP alias range. If not specified, the primary range of the
-	// subnetwork is used.
+	// subnetwork is used. When the subnetwork is managed by the cluster, the range
+	// must be defined in its secondaryCidrBlocks.
 	// +optional
 	SubnetworkRangeName string `json:"subnetworkRangeName,omitempty"`
 }
 
+// InternalAddressSpec configures a static regional internal address of an instance.
+type InternalAddressSpec struct {
+	// Name is the name of the address resource. An existing address is reused, otherwise it is reserved in the
+	// subnet of the primary network interface and released when the machine is deleted.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	// +required
+	Name string `json:"name"`
+
+	// Address is the internal IPv4 address to reserve when the address resource doesn't exist.
+	// If omitted, an available address of the subnet is reserved.
+	// +optional
+	Address *string `json:"address,omitempty"`
+}
+
+// NetworkInterfaceSpec configures an additional network interface of an instance.
+type NetworkInterfaceSpec struct {
+	// Network is the name of the VPC network of the interface, in the network project of the cluster, or
+	// the full reference to the network, e.g. projects/<project>/global/networks/<network>.
+	// Each network interface of an instance must be attached to a different network.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Network string `json:"network"`
+
+	// Subnetwork is the name of the subnetwork of the interface, in the network project and region of the cluster,
+	// or the full reference to the subnetwork, e.g. projects/<project>/regions/<region>/subnetworks/<subnetwork>.
+	// It is required for custom mode networks.
+	// +optional
+	Subnetwork *string `json:"subnetwork,omitempty"`
+
+	// InternalIP is the static internal IPv4 address of the interface, which must belong to the subnetwork range.
+	// If omitted, an ephemeral internal IP is assigned.
+	// +optional
+	InternalIP *string `json:"internalIP,omitempty"`
+
+	// PublicIP specifies whether the interface should get an external IP.
+	// +optional
+	PublicIP *bool `json:"publicIP,omitempty"`
+
+	// AliasIPRanges let you assign ranges of internal IP addresses as aliases to the interface.
+	// Secondary ranges referenced by name must exist on the subnetwork of the interface.
+	// +optional
+	AliasIPRanges []AliasIPRange `json:"aliasIPRanges,omitempty"`
+
+	// NicType is the type of virtual NIC of the interface. Defaults to the NicType of the machine.
+	// +kubebuilder:validation:Enum=GVNIC;VIRTIO_NET
+	// +optional
+	NicType *NicType `json:"nicType,omitempty"`
+}
+
 // GCPMachineSpec defines the desired state of GCPMachine.
 type GCPMachineSpec struct {
 	// InstanceType is the type of instance to create. Example: n1.standard-2
 	InstanceType string `json:"instanceType"`
 
+	// InstanceNamePrefix is prepended to the GCPMachine name to form the name of the instance, e.g. "prod-". Instance
+	// names exceeding the 63 characters allowed by GCE are truncated and suffixed with a hash of the full name, so that
+	// they stay unique. Names of existing instances are kept.
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^[a-z][-a-z0-9]*$`
+	// +optional
+	InstanceNamePrefix *string `json:"instanceNamePrefix,omitempty"`
+
 	// Subnet is a reference to the subnetwork to use for this instance. If not specified,
 	// the first subnetwork retrieved from the Cluster Region and Network is picked.
 	// +optional
@@ -259,44 +527,152 @@ type GCPMachineSpec struct {
 	// +optional
 	AliasIPRanges []AliasIPRange `json:"aliasIPRanges,omitempty"`
 
+	// InternalAddress defines a static internal address for the primary network interface, so that the instance
+	// keeps the same internal IP across recreations.
+	// +optional
+	InternalAddress *InternalAddressSpec `json:"internalAddress,omitempty"`
+
+	// NicType is the type of virtual NIC of the network interfaces of the instance. GVNIC requires a boot image
+	// with the GVNIC guest OS feature. If omitted, the platform default is used, currently VIRTIO_NET.
+	// +kubebuilder:validation:Enum=GVNIC;VIRTIO_NET
+	// +optional
+	NicType *NicType `json:"nicType,omitempty"`
+
+	// StackType is the IP stack type of the primary network interface of the instance. IPV4_IPV6 requires the
+	// subnet of the interface to be a dual-stack subnet. Defaults to IPV4_ONLY.
+	// +kubebuilder:validation:Enum=IPV4_ONLY;IPV4_IPV6
+	// +optional
+	StackType *StackType `json:"stackType,omitempty"`
+
+	// Ipv6AccessType is the access type of the IPv6 address of the primary network interface, it must match the
+	// IPv6 access type of its subnet. EXTERNAL assigns an external IPv6 address reachable from the internet.
+	// Requires the IPV4_IPV6 StackType.
+	// +kubebuilder:validation:Enum=INTERNAL;EXTERNAL
+	// +optional
+	Ipv6AccessType *Ipv6AccessType `json:"ipv6AccessType,omitempty"`
+
+	// AdditionalNetworkInterfaces is a list of network interfaces attached to the instance in addition to the
+	// primary one, which is attached to the cluster network. PublicIP, Subnet and AliasIPRanges only apply to the
+	// primary network interface. The number of network interfaces of an instance is limited by its machine type.
+	// +optional
+	AdditionalNetworkInterfaces []NetworkInterfaceSpec `json:"additionalNetworkInterfaces,omitempty"`
+
 	// ProviderID is the unique identifier as specified by the cloud provider.
+	// Setting it when creating the GCPMachine adopts the existing instance it references, of the form
+	// gce://<project>/<zone>/<name>, instead of creating one. The instance must be named after the GCPMachine and be
+	// attached to the cluster network.
 	// +optional
 	ProviderID *string `json:"providerID,omitempty"`
 
-	// ImageFamily is the full reference to a valid image family to be used for this machine.
+	// Zone is the zone to create the instance in when the Machine has no failure domain. When neither is set, the
+	// controller spreads the machines of a MachineSet or control plane across the failure domains of the GCPCluster,
+	// and records the zone it selected for the machine.
+	// +optional
+	Zone *string `json:"zone,omitempty"`
+
+	// ImageFamily is the full reference to a valid image family to be used for this machine,
+	// e.g. projects/my-images/global/images/family/capi-ubuntu-2204, or the name of an image family of the
+	// ImageFamilyProject, e.g. ubuntu-2204-lts. The latest non-deprecated image of the family is used when the
+	// instance is created, and recorded in the status.
 	// +optional
 	ImageFamily *string `json:"imageFamily,omitempty"`
 
+	// ImageFamilyProject is the project hosting the ImageFamily when it is an image family name,
+	// e.g. ubuntu-os-cloud. Defaults to the project of the cluster.
+	// +optional
+	ImageFamilyProject *string `json:"imageFamilyProject,omitempty"`
+
 	// Image is the full reference to a valid image to be used for this machine.
 	// Takes precedence over ImageFamily.
 	// +optional
 	Image *string `json:"image,omitempty"`
 
+	// ImageLookupProject is the project to look up the image of the machine in when neither Image nor ImageFamily
+	// is set. The images are looked up by name, using ImageLookupFormat, and by their k8s-version label, e.g.
+	// v1-24-3, and the newest non-deprecated match is used. Defaults to the GCPCluster ImageLookupProject, images
+	// aren't looked up if neither is set.
+	// +optional
+	ImageLookupProject *string `json:"imageLookupProject,omitempty"`
+
+	// ImageLookupBaseOS is the base OS of the image to look up, e.g. ubuntu-2204.
+	// Defaults to the GCPCluster ImageLookupBaseOS, or ubuntu-2204.
+	// +optional
+	ImageLookupBaseOS *string `json:"imageLookupBaseOS,omitempty"`
+
+	// ImageLookupFormat is the Go template of the name prefix of the image to look up, which can reference the
+	// base OS as {{.BaseOS}} and the Kubernetes version of the machine, e.g. v1-24-3, as {{.K8sVersion}}.
+	// Defaults to the GCPCluster ImageLookupFormat, or cluster-api-{{.BaseOS}}-{{.K8sVersion}}.
+	// +optional
+	ImageLookupFormat *string `json:"imageLookupFormat,omitempty"`
+
+	// OSFamily is the operating system family of the image of the instance. The bootstrap data of Windows machines is
+	// set to the "windows-startup-script-ps1" metadata key instead of "user-data", and the Linux-only OS Login
+	// settings of the GCPCluster don't apply to them.
+	// Defaults to Linux.
+	// +kubebuilder:validation:Enum=Linux;Windows
+	// +optional
+	OSFamily *OSFamily `json:"osFamily,omitempty"`
+
+	// BootstrapDataMetadataKey is the instance metadata key the bootstrap data is set to, for images consuming it from
+	// another key than the default of the OS family. The bootstrap data is set unmodified, whatever its format, e.g.
+	// cloud-config or Ignition for Flatcar Container Linux and Fedora CoreOS, which read it from "user-data".
+	// Defaults to "user-data", or "windows-startup-script-ps1" on Windows machines.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=128
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	// +optional
+	BootstrapDataMetadataKey *string `json:"bootstrapDataMetadataKey,omitempty"`
+
+	// RebootstrapPolicy defines what happens when the bootstrap data of the machine changes while its instance hasn't
+	// joined the cluster yet, e.g. after the bootstrap token expired while the instance was waiting for capacity.
+	// UpdateMetadata updates the bootstrap data in the instance metadata, for bootstrap processes still waiting for
+	// it. Reset also resets the instance so that it boots with the new bootstrap data. Bootstrap data stored in GCS
+	// is uploaded to a new object, kept until the machine joins the cluster. Changes are applied to the existing
+	// instance.
+	// Defaults to Never.
+	// +kubebuilder:validation:Enum=Never;UpdateMetadata;Reset
+	// +optional
+	RebootstrapPolicy *RebootstrapPolicy `json:"rebootstrapPolicy,omitempty"`
+
 	// AdditionalLabels is an optional set of tags to add to an instance, in addition to the ones added by default by the
 	// GCP provider. If both the GCPCluster and the GCPMachine specify the same tag name with different values, the
-	// GCPMachine's value takes precedence.
+	// GCPMachine's value takes precedence. Changes are applied to the existing instance.
 	// +optional
 	AdditionalLabels Labels `json:"additionalLabels,omitempty"`
 
 	// AdditionalMetadata is an optional set of metadata to add to an instance, in addition to the ones added by default by the
-	// GCP provider.
+	// GCP provider. The BootstrapDataMetadataKey, "user-data" or "windows-startup-script-ps1" on Windows machines by default,
+	// is reserved for the bootstrap data and can't be set. Changes are applied to the running instance, replacing the
+	// metadata it has drifted to.
 	// +listType=map
 	// +listMapKey=key
 	// +optional
 	AdditionalMetadata []MetadataItem `json:"additionalMetadata,omitempty"`
 
+	// EnableOSLogin defines whether OS Login is enabled on the instance, by setting the "enable-oslogin" metadata key.
+	// If omitted, the GCPCluster EnableOSLogin setting is used, if any. Changes are applied to the running instance.
+	// +optional
+	EnableOSLogin *bool `json:"enableOSLogin,omitempty"`
+
+	// EnableOSLogin2FA defines whether two-factor authentication is required for OS Login on the instance, by setting
+	// the "enable-oslogin-2fa" metadata key. It requires OS Login to be enabled.
+	// If omitted, the GCPCluster EnableOSLogin2FA setting is used, if any.
+	// +optional
+	EnableOSLogin2FA *bool `json:"enableOSLogin2FA,omitempty"`
+
 	// IAMInstanceProfile is a name of an IAM instance profile to assign to the instance
 	// +optional
 	// IAMInstanceProfile string `json:"iamInstanceProfile,omitempty"`
 
 	// PublicIP specifies whether the instance should get a public IP.
 	// Set this to true if you don't have a NAT instances or Cloud Nat setup.
+	// If omitted, the GCPCluster MachinePublicIP setting is used, and instances get no public IP by default.
 	// +optional
 	PublicIP *bool `json:"publicIP,omitempty"`
 
 	// AdditionalNetworkTags is a list of network tags that should be applied to the
 	// instance. These tags are set in addition to any network tags defined
-	// at the cluster level or in the actuator.
+	// at the cluster level or in the actuator, duplicated tags are ignored.
 	// +optional
 	AdditionalNetworkTags []string `json:"additionalNetworkTags,omitempty"`
 
@@ -316,21 +692,54 @@ type GCPMachineSpec struct {
 	// 1. "pd-standard" - Standard (HDD) persistent disk
 	// 2. "pd-ssd" - SSD persistent disk
 	// 3. "pd-balanced" - Balanced Persistent Disk
-	// 4. "hyperdisk-balanced" - Hyperdisk Balanced
+	// 4. "pd-extreme" - Extreme Persistent Disk
+	// 5. "hyperdisk-balanced" - Hyperdisk Balanced
 	// Default is "pd-standard".
 	// +optional
 	RootDeviceType *DiskType `json:"rootDeviceType,omitempty"`
 
+	// RootDeviceProvisionedIops is the number of I/O operations per second provisioned for the root volume.
+	// Only supported by the "pd-extreme" and "hyperdisk-balanced" root volume types.
+	// +optional
+	RootDeviceProvisionedIops *int64 `json:"rootDeviceProvisionedIops,omitempty"`
+
+	// RootDeviceProvisionedThroughput is the throughput in MiB per second provisioned for the root volume.
+	// Only supported by the "hyperdisk-balanced" root volume type.
+	// +optional
+	RootDeviceProvisionedThroughput *int64 `json:"rootDeviceProvisionedThroughput,omitempty"`
+
+	// RootDeviceReplicaZones makes the root volume a regional persistent disk synchronously replicated across the
+	// two zones, e.g. us-central1-a and us-central1-b, so that it survives the loss of a zone. The zones must be in
+	// the region of the cluster and one of them must be the zone of the machine. Only supported by the "pd-standard",
+	// "pd-balanced" and "pd-ssd" root volume types.
+	// +kubebuilder:validation:MinItems=2
+	// +kubebuilder:validation:MaxItems=2
+	// +optional
+	RootDeviceReplicaZones []string `json:"rootDeviceReplicaZones,omitempty"`
+
 	// AdditionalDisks are optional non-boot attached disks.
 	// +optional
 	AdditionalDisks []AttachedDiskSpec `json:"additionalDisks,omitempty"`
 
+	// AttachedDisks are optional pre-existing persistent disks attached to the instance.
+	// The disks are detached, but never deleted, when the instance is deleted.
+	// +optional
+	AttachedDisks []ExistingDiskSpec `json:"attachedDisks,omitempty"`
+
+	// LocalSSDs are optional local SSDs attached to the instance as scratch disks.
+	// Each local SSD has a fixed size of 375GB. Local SSDs can only be attached at creation
+	// time and are deleted with the instance.
+	// +optional
+	LocalSSDs []LocalSSDSpec `json:"localSSDs,omitempty"`
+
 	// ServiceAccount specifies the service account email and which scopes to assign to the machine.
 	// Defaults to: email: "default", scope: []{compute.CloudPlatformScope}
+	// The controller service account needs the roles/iam.serviceAccountUser role on the service account.
 	// +optional
 	ServiceAccount *ServiceAccount `json:"serviceAccounts,omitempty"`
 
-	// Preemptible defines if instance is preemptible
+	// Preemptible defines if instance is preemptible.
+	// Preemptible instances are never automatically restarted and are terminated on host maintenance events.
 	// +optional
 	Preemptible bool `json:"preemptible,omitempty"`
 
@@ -341,6 +750,12 @@ type GCPMachineSpec struct {
 	// +optional
 	ProvisioningModel *ProvisioningModel `json:"provisioningModel,omitempty"`
 
+	// TerminationAction defines what happens to a Spot VM when it is preempted.
+	// Only applicable when ProvisioningModel is "Spot". When unspecified, the VM is stopped.
+	// +kubebuilder:validation:Enum=Stop;Delete
+	// +optional
+	TerminationAction *InstanceTerminationAction `json:"terminationAction,omitempty"`
+
 	// IPForwarding Allows this instance to send and receive packets with non-matching destination or source IPs.
 	// This is required if you plan to use this instance to forward routes. Defaults to enabled.
 	// +kubebuilder:validation:Enum=Enabled;Disabled
@@ -348,6 +763,48 @@ type GCPMachineSpec struct {
 	// +optional
 	IPForwarding *IPForwarding `json:"ipForwarding,omitempty"`
 
+	// NodeAffinities are sole-tenant node affinity labels used to schedule the instance on sole-tenant nodes,
+	// e.g. the compute.googleapis.com/node-group-name label of a pre-created node group.
+	// OnHostMaintenance must be compatible with the maintenance policy of the node group.
+	// reference: https://cloud.google.com/compute/docs/nodes/provisioning-sole-tenant-vms
+	// +optional
+	NodeAffinities []NodeAffinity `json:"nodeAffinities,omitempty"`
+
+	// ResourcePolicies are the names or URLs of resource policies attached to the instance, such as compact
+	// placement policies for low-latency node groups. Names refer to resource policies of the cluster region.
+	// reference: https://cloud.google.com/compute/docs/instances/use-compact-placement-policies
+	// +optional
+	ResourcePolicies []string `json:"resourcePolicies,omitempty"`
+
+	// MinCPUPlatform is the minimum CPU platform of the instance, e.g. "Intel Ice Lake".
+	// If not specified, the default CPU platform of the machine type in the zone is used.
+	// The CPU platform can't be changed without recreating the instance.
+	// reference: https://cloud.google.com/compute/docs/instances/specify-min-cpu-platform
+	// +optional
+	MinCPUPlatform *string `json:"minCpuPlatform,omitempty"`
+
+	// AdvancedMachineFeatures defines the advanced CPU features of the instance, such as nested virtualization
+	// or simultaneous multithreading. The features can't be changed without recreating the instance.
+	// reference: https://cloud.google.com/compute/docs/instances/nested-virtualization/overview
+	// +optional
+	AdvancedMachineFeatures *AdvancedMachineFeatures `json:"advancedMachineFeatures,omitempty"`
+
+	// NetworkPerformanceConfig defines the network performance of the instance, such as its egress bandwidth tier.
+	// +optional
+	NetworkPerformanceConfig *NetworkPerformanceConfig `json:"networkPerformanceConfig,omitempty"`
+
+	// DeletionProtection enables the GCE deletion protection of the instance, so it can't be deleted out of band,
+	// e.g. from the console. The protection is lifted by the controller when the machine itself is deleted.
+	// Changes are applied to the existing instance.
+	// +optional
+	DeletionProtection *bool `json:"deletionProtection,omitempty"`
+
+	// DrainTimeout is how long to wait for the connections to the instance to drain when the machine is deleted,
+	// after the instance is removed from the instance groups managed by the provider and before it is deleted.
+	// The instance is deleted right away if unset.
+	// +optional
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
+
 	// ShieldedInstanceConfig is the Shielded VM configuration for this machine
 	// +optional
 	ShieldedInstanceConfig *GCPShieldedInstanceConfig `json:"shieldedInstanceConfig,omitempty"`
@@ -358,6 +815,12 @@ type GCPMachineSpec struct {
 	// +optional
 	OnHostMaintenance *HostMaintenancePolicy `json:"onHostMaintenance,omitempty"`
 
+	// AutomaticRestart defines whether the instance is automatically restarted if it is terminated by Compute Engine,
+	// e.g. after a host maintenance event with OnHostMaintenance set to "Terminate" or a host error.
+	// Defaults to true, except for Preemptible and Spot instances which are never automatically restarted.
+	// +optional
+	AutomaticRestart *bool `json:"automaticRestart,omitempty"`
+
 	// ConfidentialCompute Defines whether the instance should have confidential compute enabled or not, and the confidential computing technology of choice.
 	// If Disabled, the machine will not be configured to be a confidential computing instance.
 	// If Enabled, confidential computing will be configured and AMD Secure Encrypted Virtualization will be configured by default. That is subject to change over time. If using AMD Secure Encrypted Virtualization is vital, use AMDEncryptedVirtualization explicitly instead.
@@ -415,6 +878,29 @@ type GCPMachineStatus struct {
 	// +optional
 	InstanceStatus *InstanceStatus `json:"instanceState,omitempty"`
 
+	// DeletionProtection reflects whether the GCE deletion protection of the instance is enabled.
+	// +optional
+	DeletionProtection *bool `json:"deletionProtection,omitempty"`
+
+	// InstanceID is the unique numeric identifier GCE assigned to the instance.
+	// +optional
+	InstanceID *string `json:"instanceID,omitempty"`
+
+	// Zone is the zone the instance was created in.
+	// +optional
+	Zone *string `json:"zone,omitempty"`
+
+	// Image is the full reference to the image the boot disk of the instance was created from. When the image is
+	// resolved from the ImageFamily or looked up, it is resolved once when the instance is created, later images
+	// don't affect the instance.
+	// +optional
+	Image *string `json:"image,omitempty"`
+
+	// BootstrapDataObject is the gs:// URL of the GCS object the bootstrap data of the instance was uploaded to,
+	// when the GCPCluster BootstrapDataStorage is GCS. It is unset once the object is deleted.
+	// +optional
+	BootstrapDataObject *string `json:"bootstrapDataObject,omitempty"`
+
 	// FailureReason will be set in the event that there is a terminal problem
 	// reconciling the Machine and will contain a succinct value suitable
 	// for machine interpretation.
@@ -452,6 +938,10 @@ type GCPMachineStatus struct {
 	// controller's output.
 	// +optional
 	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Conditions defines current service state of the GCPMachine.
+	// +optional
+	Conditions clusterv1beta1.Conditions `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -473,6 +963,16 @@ type GCPMachine struct {
 	Status GCPMachineStatus `json:"status,omitempty"`
 }
 
+// GetConditions returns the GCPMachine conditions.
+func (m *GCPMachine) GetConditions() clusterv1beta1.Conditions {
+	return m.Status.Conditions
+}
+
+// SetConditions sets the status conditions for the GCPMachine.
+func (m *GCPMachine) SetConditions(conditions clusterv1beta1.Conditions) {
+	m.Status.Conditions = conditions
+}
+
 // +kubebuilder:object:root=true
 
 // GCPMachineList contains a list of GCPMachine.
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,16 +26,26 @@ type GCPMachineTemplateSpec struct {
 	Template GCPMachineTemplateResource `json:"template"`
 }
 
+// GCPMachineTemplateStatus defines the observed state of GCPMachineTemplate.
+type GCPMachineTemplateStatus struct {
+	// Capacity defines the resource capacity of the machines created from the template, i.e. the cpu and memory of
+	// their machine type and their NVIDIA GPUs. It is used by the cluster-autoscaler to scale node groups from zero.
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=gcpmachinetemplates,scope=Namespaced,categories=cluster-api
 // +kubebuilder:storageversion
+// +kubebuilder:subresource:status
 
 // GCPMachineTemplate is the Schema for the gcpmachinetemplates API.
 type GCPMachineTemplate struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec GCPMachineTemplateSpec `json:"spec,omitempty"`
+	Spec   GCPMachineTemplateSpec   `json:"spec,omitempty"`
+	Status GCPMachineTemplateStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -56,6 +56,10 @@ type Network struct {
 	// +optional
 	Router *string `json:"router,omitempty"`
 
+	// Peerings is a map from the name of the network peering to its state, i.e. ACTIVE or INACTIVE.
+	// +optional
+	Peerings map[string]string `json:"peerings,omitempty"`
+
 	// APIServerAddress is the IPV4 global address assigned to the load balancer
 	// created for the API Server.
 	// +optional
@@ -143,7 +147,7 @@ type FirewallDescriptor struct {
 	// +kubebuilder:validation:Required
 	IPProtocol FirewallProtocol `json:"IPProtocol,omitempty"`
 	// Ports is an optional list of ports to which this rule applies. This field is
-	// only applicable for the UDP or TCP protocol. Each entry must be either an
+	// only applicable for the UDP, TCP or SCTP protocol. Each entry must be either an
 	// integer or a range. If not specified, this rule applies to connections
 	// through any port. Example inputs include: ["22"], ["80","443"], and
 	// ["12345-12349"].
@@ -336,6 +340,8 @@ type NetworkSpec struct {
 	LoadBalancerBackendPort *int32 `json:"loadBalancerBackendPort,omitempty"`
 
 	// HostProject is the name of the project hosting the shared VPC network resources.
+	// The network and subnetworks must exist in the host project and the cluster project must be attached
+	// to it as a service project.
 	// +optional
 	HostProject *string `json:"hostProject,omitempty"`
 
@@ -363,6 +369,69 @@ type NetworkSpec struct {
 	// +kubebuilder:default:=64
 	// +optional
 	MinPortsPerVM int64 `json:"minPortsPerVm,omitempty"`
+
+	// CloudNAT configures the Cloud NAT gateway created with the cluster network, which provides egress to the
+	// instances without a public IP.
+	// +optional
+	CloudNAT *CloudNATSpec `json:"cloudNAT,omitempty"`
+
+	// NetworkPeerings is a list of peerings between the cluster network and other VPC networks. The peerings
+	// are created from the cluster side only and are inactive until the peer network creates the reciprocal
+	// peering. NetworkPeerings has no effect when a HostProject is specified.
+	// +optional
+	NetworkPeerings []NetworkPeering `json:"networkPeerings,omitempty"`
+}
+
+// NetworkPeering defines a peering between the cluster network and another VPC network.
+type NetworkPeering struct {
+	// Name is the name of the peering.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	// +required
+	Name string `json:"name"`
+
+	// PeerNetwork is the self link of the peer network, i.e. projects/<project>/global/networks/<network>.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	PeerNetwork string `json:"peerNetwork"`
+
+	// ExportCustomRoutes defines whether the custom routes of the cluster network are exported to the peer network.
+	// +optional
+	ExportCustomRoutes *bool `json:"exportCustomRoutes,omitempty"`
+
+	// ImportCustomRoutes defines whether the custom routes of the peer network are imported to the cluster network.
+	// +optional
+	ImportCustomRoutes *bool `json:"importCustomRoutes,omitempty"`
+}
+
+// CloudNATLogFilter defines which Cloud NAT events are logged.
+type CloudNATLogFilter string
+
+const (
+	// CloudNATLogFilterErrorsOnly logs the connections dropped because of errors.
+	CloudNATLogFilterErrorsOnly CloudNATLogFilter = "ErrorsOnly"
+	// CloudNATLogFilterTranslationsOnly logs the successful connections.
+	CloudNATLogFilterTranslationsOnly CloudNATLogFilter = "TranslationsOnly"
+	// CloudNATLogFilterAll logs all the connections.
+	CloudNATLogFilterAll CloudNATLogFilter = "All"
+)
+
+// CloudNATSpec configures the Cloud NAT gateway of the cluster network.
+type CloudNATSpec struct {
+	// MaxPortsPerVM is the maximum number of ports allocated to a VM from the NAT config.
+	// Setting it enables dynamic port allocation, in which case both MinPortsPerVM and MaxPortsPerVM must be
+	// powers of 2 and MaxPortsPerVM must be greater than MinPortsPerVM.
+	// +kubebuilder:validation:Minimum:=64
+	// +kubebuilder:validation:Maximum:=65536
+	// +optional
+	MaxPortsPerVM *int64 `json:"maxPortsPerVm,omitempty"`
+
+	// LogFilter enables the Cloud NAT logging and defines which connections are logged.
+	// Logging is disabled if omitted.
+	// +kubebuilder:validation:Enum=ErrorsOnly;TranslationsOnly;All
+	// +optional
+	LogFilter *CloudNATLogFilter `json:"logFilter,omitempty"`
 }
 
 // LoadBalancerType defines the Load Balancer that should be created.
@@ -372,6 +441,8 @@ var (
 	// External creates a Global External Proxy Load Balancer
 	// to manage traffic to backends in multiple regions. This is the default Load
 	// Balancer and will be created if no LoadBalancerType is defined.
+	// It is made of a global forwarding rule, a target TCP proxy and a global backend
+	// service, the TLS connections are passed through to the API servers.
 	External = LoadBalancerType("External")
 
 	// Internal creates a Regional Internal Passthrough Load
@@ -393,6 +464,13 @@ type LoadBalancerSpec struct {
 	// +optional
 	APIServerInstanceGroupTagOverride *string `json:"apiServerInstanceGroupTagOverride,omitempty"`
 
+	// APIServerAddress is a pre-reserved global static external IP address used by the Global External Proxy
+	// Load Balancer, given by its name or as a literal IP address. It keeps the control plane endpoint stable
+	// across cluster recreations. If not set, an address is reserved for the cluster and released with it,
+	// a provided address is never released.
+	// +optional
+	APIServerAddress *string `json:"apiServerAddress,omitempty"`
+
 	// LoadBalancerType defines the type of Load Balancer that should be created.
 	// If not set, a Global External Proxy Load Balancer will be created by default.
 	// +optional
@@ -401,6 +479,52 @@ type LoadBalancerSpec struct {
 	// InternalLoadBalancer is the configuration for an Internal Passthrough Network Load Balancer.
 	// +optional
 	InternalLoadBalancer *LoadBalancer `json:"internalLoadBalancer,omitempty"`
+
+	// HealthCheck configures the health check probing the API servers behind the load balancers.
+	// Unset fields keep their defaults.
+	// +optional
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+}
+
+// HealthCheck defines the parameters of the API server health check.
+type HealthCheck struct {
+	// CheckIntervalSec is how often, in seconds, the API servers are probed. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=300
+	// +optional
+	CheckIntervalSec *int64 `json:"checkIntervalSec,omitempty"`
+
+	// TimeoutSec is how long, in seconds, to wait before claiming a probe failed. It can't be greater than
+	// CheckIntervalSec. Defaults to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=300
+	// +optional
+	TimeoutSec *int64 `json:"timeoutSec,omitempty"`
+
+	// HealthyThreshold is the number of consecutive successful probes before an API server is marked healthy.
+	// Defaults to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	HealthyThreshold *int64 `json:"healthyThreshold,omitempty"`
+
+	// UnhealthyThreshold is the number of consecutive failed probes before an API server is marked unhealthy.
+	// Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	UnhealthyThreshold *int64 `json:"unhealthyThreshold,omitempty"`
+
+	// Port is the port probed on the API servers. Defaults to the load balancer backend port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+
+	// RequestPath is the HTTPS request path of the probes. Defaults to /readyz.
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	RequestPath *string `json:"requestPath,omitempty"`
 }
 
 // SubnetSpec configures an GCP Subnet.
@@ -420,7 +544,9 @@ type SubnetSpec struct {
 	Description *string `json:"description,omitempty"`
 
 	// SecondaryCidrBlocks defines secondary CIDR ranges,
-	// from which secondary IP ranges of a VM may be allocated
+	// from which secondary IP ranges of a VM may be allocated.
+	// It is a map from the name of the range to its CIDR range, which must not overlap with the other ranges of
+	// the network. Missing ranges are added to existing subnets created by the provider.
 	// +optional
 	SecondaryCidrBlocks map[string]string `json:"secondaryCidrBlocks,omitempty"`
 
@@ -428,7 +554,8 @@ type SubnetSpec struct {
 	Region string `json:"region,omitempty"`
 
 	// PrivateGoogleAccess defines whether VMs in this subnet can access
-	// Google services without assigning external IP addresses
+	// Google services without assigning external IP addresses.
+	// Changes are applied to existing subnets created by the provider.
 	// +optional
 	PrivateGoogleAccess *bool `json:"privateGoogleAccess,omitempty"`
 
@@ -438,6 +565,11 @@ type SubnetSpec struct {
 	// +optional
 	EnableFlowLogs *bool `json:"enableFlowLogs,omitempty"`
 
+	// FlowLogs configures the VPC flow logs of the subnetwork, it takes precedence over EnableFlowLogs.
+	// The configuration is applied to existing subnetworks created by the provider.
+	// +optional
+	FlowLogs *SubnetFlowLogs `json:"flowLogs,omitempty"`
+
 	// Purpose: The purpose of the resource.
 	// If unspecified, the purpose defaults to PRIVATE_RFC_1918.
 	// The enableFlowLogs field isn't supported with the purpose field set to INTERNAL_HTTPS_LOAD_BALANCER.
@@ -472,6 +604,61 @@ type SubnetSpec struct {
 	// +kubebuilder:default=IPV4_ONLY
 	// +optional
 	StackType string `json:"stackType,omitempty"`
+
+	// Ipv6AccessType is the access type of the IPv6 range of the subnet, it is required when the StackType is
+	// IPV4_IPV6 or IPV6_ONLY. INTERNAL ranges are only reachable from within the network and require the network to
+	// have ULA internal IPv6 enabled, EXTERNAL ranges are reachable from the internet.
+	// Changes of the StackType and Ipv6AccessType are applied to existing subnets created by the provider.
+	// +kubebuilder:validation:Enum=INTERNAL;EXTERNAL
+	// +optional
+	Ipv6AccessType *Ipv6AccessType `json:"ipv6AccessType,omitempty"`
+}
+
+// StackType is the IP stack type of a subnet or network interface.
+type StackType string
+
+const (
+	// StackTypeIPv4Only assigns IPv4 addresses only.
+	StackTypeIPv4Only StackType = "IPV4_ONLY"
+	// StackTypeIPv4IPv6 assigns both IPv4 and IPv6 addresses.
+	StackTypeIPv4IPv6 StackType = "IPV4_IPV6"
+	// StackTypeIPv6Only assigns IPv6 addresses only.
+	StackTypeIPv6Only StackType = "IPV6_ONLY"
+)
+
+// Ipv6AccessType is the access type of an IPv6 range.
+type Ipv6AccessType string
+
+const (
+	// Ipv6AccessTypeInternal IPv6 addresses are only reachable from within the network.
+	Ipv6AccessTypeInternal Ipv6AccessType = "INTERNAL"
+	// Ipv6AccessTypeExternal IPv6 addresses are reachable from the internet.
+	Ipv6AccessTypeExternal Ipv6AccessType = "EXTERNAL"
+)
+
+// SubnetFlowLogs defines the VPC flow logs configuration of a subnetwork.
+type SubnetFlowLogs struct {
+	// Enable defines whether flow logs are enabled for the subnetwork.
+	// +kubebuilder:default=true
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// AggregationInterval is the interval over which the flow logs of a connection are aggregated.
+	// If not set, GCP defaults to INTERVAL_5_SEC.
+	// +kubebuilder:validation:Enum=INTERVAL_5_SEC;INTERVAL_30_SEC;INTERVAL_1_MIN;INTERVAL_5_MIN;INTERVAL_10_MIN;INTERVAL_15_MIN
+	// +optional
+	AggregationInterval *string `json:"aggregationInterval,omitempty"`
+
+	// FlowSampling is the sampling rate of the flow logs, a decimal number between 0 and 1.
+	// If not set, GCP defaults to 0.5, half of the flows are logged.
+	// +optional
+	FlowSampling *string `json:"flowSampling,omitempty"`
+
+	// Metadata defines whether metadata fields are added to the flow logs.
+	// If not set, GCP defaults to INCLUDE_ALL_METADATA.
+	// +kubebuilder:validation:Enum=INCLUDE_ALL_METADATA;EXCLUDE_ALL_METADATA
+	// +optional
+	Metadata *string `json:"metadata,omitempty"`
 }
 
 // String returns a string representation of the subnet.
@@ -557,10 +744,36 @@ type ServiceAccount struct {
 	Email string `json:"email,omitempty"`
 
 	// Scopes: The list of scopes to be made available for this service
-	// account.
+	// account. Scopes are either full URLs or the aliases gcloud accepts, such as "cloud-platform" or "storage-ro".
 	Scopes []string `json:"scopes,omitempty"`
 }
 
+// ServiceAccountScopeAliases maps the service account scope aliases accepted by gcloud to their full URL.
+// reference: https://cloud.google.com/sdk/gcloud/reference/compute/instances/create#--scopes
+var ServiceAccountScopeAliases = map[string]string{
+	"bigquery":              "https://www.googleapis.com/auth/bigquery",
+	"cloud-platform":        "https://www.googleapis.com/auth/cloud-platform",
+	"cloud-source-repos":    "https://www.googleapis.com/auth/source.full_control",
+	"cloud-source-repos-ro": "https://www.googleapis.com/auth/source.read_only",
+	"compute-ro":            "https://www.googleapis.com/auth/compute.readonly",
+	"compute-rw":            "https://www.googleapis.com/auth/compute",
+	"datastore":             "https://www.googleapis.com/auth/datastore",
+	"logging-write":         "https://www.googleapis.com/auth/logging.write",
+	"monitoring":            "https://www.googleapis.com/auth/monitoring",
+	"monitoring-read":       "https://www.googleapis.com/auth/monitoring.read",
+	"monitoring-write":      "https://www.googleapis.com/auth/monitoring.write",
+	"pubsub":                "https://www.googleapis.com/auth/pubsub",
+	"service-control":       "https://www.googleapis.com/auth/servicecontrol",
+	"service-management":    "https://www.googleapis.com/auth/service.management.readonly",
+	"sql-admin":             "https://www.googleapis.com/auth/sqlservice.admin",
+	"storage-full":          "https://www.googleapis.com/auth/devstorage.full_control",
+	"storage-ro":            "https://www.googleapis.com/auth/devstorage.read_only",
+	"storage-rw":            "https://www.googleapis.com/auth/devstorage.read_write",
+	"taskqueue":             "https://www.googleapis.com/auth/taskqueue",
+	"trace":                 "https://www.googleapis.com/auth/trace.append",
+	"userinfo-email":        "https://www.googleapis.com/auth/userinfo.email",
+}
+
 // ObjectReference is a reference to another Kubernetes object instance.
 type ObjectReference struct {
 	// Namespace of the referent.
@@ -22,6 +22,7 @@ package v1beta1
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	corev1beta1 "sigs.k8s.io/cluster-api/api/core/v1beta1"
 )
@@ -41,6 +42,36 @@ func (in *Accelerator) DeepCopy() *Accelerator {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdvancedMachineFeatures) DeepCopyInto(out *AdvancedMachineFeatures) {
+	*out = *in
+	if in.EnableNestedVirtualization != nil {
+		in, out := &in.EnableNestedVirtualization, &out.EnableNestedVirtualization
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ThreadsPerCore != nil {
+		in, out := &in.ThreadsPerCore, &out.ThreadsPerCore
+		*out = new(int64)
+		**out = **in
+	}
+	if in.VisibleCoreCount != nil {
+		in, out := &in.VisibleCoreCount, &out.VisibleCoreCount
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdvancedMachineFeatures.
+func (in *AdvancedMachineFeatures) DeepCopy() *AdvancedMachineFeatures {
+	if in == nil {
+		return nil
+	}
+	out := new(AdvancedMachineFeatures)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AliasIPRange) DeepCopyInto(out *AliasIPRange) {
 	*out = *in
@@ -74,6 +105,28 @@ func (in *AttachedDiskSpec) DeepCopyInto(out *AttachedDiskSpec) {
 		*out = new(CustomerEncryptionKey)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DeviceName != nil {
+		in, out := &in.DeviceName, &out.DeviceName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AutoDelete != nil {
+		in, out := &in.AutoDelete, &out.AutoDelete
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReplicaZones != nil {
+		in, out := &in.ReplicaZones, &out.ReplicaZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttachedDiskSpec.
@@ -86,6 +139,26 @@ func (in *AttachedDiskSpec) DeepCopy() *AttachedDiskSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapDataStorageSpec) DeepCopyInto(out *BootstrapDataStorageSpec) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapDataStorageSpec.
+func (in *BootstrapDataStorageSpec) DeepCopy() *BootstrapDataStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapDataStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildParams) DeepCopyInto(out *BuildParams) {
 	*out = *in
@@ -113,6 +186,31 @@ func (in *BuildParams) DeepCopy() *BuildParams {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudNATSpec) DeepCopyInto(out *CloudNATSpec) {
+	*out = *in
+	if in.MaxPortsPerVM != nil {
+		in, out := &in.MaxPortsPerVM, &out.MaxPortsPerVM
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LogFilter != nil {
+		in, out := &in.LogFilter, &out.LogFilter
+		*out = new(CloudNATLogFilter)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudNATSpec.
+func (in *CloudNATSpec) DeepCopy() *CloudNATSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudNATSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CustomerEncryptionKey) DeepCopyInto(out *CustomerEncryptionKey) {
 	*out = *in
@@ -143,6 +241,31 @@ func (in *CustomerEncryptionKey) DeepCopy() *CustomerEncryptionKey {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExistingDiskSpec) DeepCopyInto(out *ExistingDiskSpec) {
+	*out = *in
+	if in.DeviceName != nil {
+		in, out := &in.DeviceName, &out.DeviceName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(DiskMode)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExistingDiskSpec.
+func (in *ExistingDiskSpec) DeepCopy() *ExistingDiskSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExistingDiskSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Filter) DeepCopyInto(out *Filter) {
 	*out = *in
@@ -323,6 +446,11 @@ func (in *GCPClusterSpec) DeepCopyInto(out *GCPClusterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ControlPlaneCompactPlacement != nil {
+		in, out := &in.ControlPlaneCompactPlacement, &out.ControlPlaneCompactPlacement
+		*out = new(bool)
+		**out = **in
+	}
 	if in.AdditionalLabels != nil {
 		in, out := &in.AdditionalLabels, &out.AdditionalLabels
 		*out = make(Labels, len(*in))
@@ -330,6 +458,11 @@ func (in *GCPClusterSpec) DeepCopyInto(out *GCPClusterSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.AdditionalNetworkTags != nil {
+		in, out := &in.AdditionalNetworkTags, &out.AdditionalNetworkTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ResourceManagerTags != nil {
 		in, out := &in.ResourceManagerTags, &out.ResourceManagerTags
 		*out = make(ResourceManagerTags, len(*in))
@@ -346,6 +479,56 @@ func (in *GCPClusterSpec) DeepCopyInto(out *GCPClusterSpec) {
 		*out = new(ServiceEndpoints)
 		**out = **in
 	}
+	if in.DiskEncryptionKey != nil {
+		in, out := &in.DiskEncryptionKey, &out.DiskEncryptionKey
+		*out = new(CustomerEncryptionKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MachinePublicIP != nil {
+		in, out := &in.MachinePublicIP, &out.MachinePublicIP
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableOSLogin != nil {
+		in, out := &in.EnableOSLogin, &out.EnableOSLogin
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableOSLogin2FA != nil {
+		in, out := &in.EnableOSLogin2FA, &out.EnableOSLogin2FA
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ImageLookupProject != nil {
+		in, out := &in.ImageLookupProject, &out.ImageLookupProject
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImageLookupBaseOS != nil {
+		in, out := &in.ImageLookupBaseOS, &out.ImageLookupBaseOS
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImageLookupFormat != nil {
+		in, out := &in.ImageLookupFormat, &out.ImageLookupFormat
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeServiceAccount != nil {
+		in, out := &in.NodeServiceAccount, &out.NodeServiceAccount
+		*out = new(NodeServiceAccountSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BootstrapDataStorage != nil {
+		in, out := &in.BootstrapDataStorage, &out.BootstrapDataStorage
+		*out = new(BootstrapDataStorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkloadIdentity != nil {
+		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
+		*out = new(WorkloadIdentitySpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPClusterSpec.
@@ -369,6 +552,13 @@ func (in *GCPClusterStatus) DeepCopyInto(out *GCPClusterStatus) {
 		}
 	}
 	in.Network.DeepCopyInto(&out.Network)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(corev1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPClusterStatus.
@@ -534,6 +724,11 @@ func (in *GCPMachineList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GCPMachineSpec) DeepCopyInto(out *GCPMachineSpec) {
 	*out = *in
+	if in.InstanceNamePrefix != nil {
+		in, out := &in.InstanceNamePrefix, &out.InstanceNamePrefix
+		*out = new(string)
+		**out = **in
+	}
 	if in.Subnet != nil {
 		in, out := &in.Subnet, &out.Subnet
 		*out = new(string)
@@ -544,21 +739,88 @@ func (in *GCPMachineSpec) DeepCopyInto(out *GCPMachineSpec) {
 		*out = make([]AliasIPRange, len(*in))
 		copy(*out, *in)
 	}
+	if in.InternalAddress != nil {
+		in, out := &in.InternalAddress, &out.InternalAddress
+		*out = new(InternalAddressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NicType != nil {
+		in, out := &in.NicType, &out.NicType
+		*out = new(NicType)
+		**out = **in
+	}
+	if in.StackType != nil {
+		in, out := &in.StackType, &out.StackType
+		*out = new(StackType)
+		**out = **in
+	}
+	if in.Ipv6AccessType != nil {
+		in, out := &in.Ipv6AccessType, &out.Ipv6AccessType
+		*out = new(Ipv6AccessType)
+		**out = **in
+	}
+	if in.AdditionalNetworkInterfaces != nil {
+		in, out := &in.AdditionalNetworkInterfaces, &out.AdditionalNetworkInterfaces
+		*out = make([]NetworkInterfaceSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ProviderID != nil {
 		in, out := &in.ProviderID, &out.ProviderID
 		*out = new(string)
 		**out = **in
 	}
+	if in.Zone != nil {
+		in, out := &in.Zone, &out.Zone
+		*out = new(string)
+		**out = **in
+	}
 	if in.ImageFamily != nil {
 		in, out := &in.ImageFamily, &out.ImageFamily
 		*out = new(string)
 		**out = **in
 	}
+	if in.ImageFamilyProject != nil {
+		in, out := &in.ImageFamilyProject, &out.ImageFamilyProject
+		*out = new(string)
+		**out = **in
+	}
 	if in.Image != nil {
 		in, out := &in.Image, &out.Image
 		*out = new(string)
 		**out = **in
 	}
+	if in.ImageLookupProject != nil {
+		in, out := &in.ImageLookupProject, &out.ImageLookupProject
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImageLookupBaseOS != nil {
+		in, out := &in.ImageLookupBaseOS, &out.ImageLookupBaseOS
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImageLookupFormat != nil {
+		in, out := &in.ImageLookupFormat, &out.ImageLookupFormat
+		*out = new(string)
+		**out = **in
+	}
+	if in.OSFamily != nil {
+		in, out := &in.OSFamily, &out.OSFamily
+		*out = new(OSFamily)
+		**out = **in
+	}
+	if in.BootstrapDataMetadataKey != nil {
+		in, out := &in.BootstrapDataMetadataKey, &out.BootstrapDataMetadataKey
+		*out = new(string)
+		**out = **in
+	}
+	if in.RebootstrapPolicy != nil {
+		in, out := &in.RebootstrapPolicy, &out.RebootstrapPolicy
+		*out = new(RebootstrapPolicy)
+		**out = **in
+	}
 	if in.AdditionalLabels != nil {
 		in, out := &in.AdditionalLabels, &out.AdditionalLabels
 		*out = make(Labels, len(*in))
@@ -573,6 +835,16 @@ func (in *GCPMachineSpec) DeepCopyInto(out *GCPMachineSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EnableOSLogin != nil {
+		in, out := &in.EnableOSLogin, &out.EnableOSLogin
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableOSLogin2FA != nil {
+		in, out := &in.EnableOSLogin2FA, &out.EnableOSLogin2FA
+		*out = new(bool)
+		**out = **in
+	}
 	if in.PublicIP != nil {
 		in, out := &in.PublicIP, &out.PublicIP
 		*out = new(bool)
@@ -593,6 +865,21 @@ func (in *GCPMachineSpec) DeepCopyInto(out *GCPMachineSpec) {
 		*out = new(DiskType)
 		**out = **in
 	}
+	if in.RootDeviceProvisionedIops != nil {
+		in, out := &in.RootDeviceProvisionedIops, &out.RootDeviceProvisionedIops
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RootDeviceProvisionedThroughput != nil {
+		in, out := &in.RootDeviceProvisionedThroughput, &out.RootDeviceProvisionedThroughput
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RootDeviceReplicaZones != nil {
+		in, out := &in.RootDeviceReplicaZones, &out.RootDeviceReplicaZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.AdditionalDisks != nil {
 		in, out := &in.AdditionalDisks, &out.AdditionalDisks
 		*out = make([]AttachedDiskSpec, len(*in))
@@ -600,6 +887,20 @@ func (in *GCPMachineSpec) DeepCopyInto(out *GCPMachineSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AttachedDisks != nil {
+		in, out := &in.AttachedDisks, &out.AttachedDisks
+		*out = make([]ExistingDiskSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LocalSSDs != nil {
+		in, out := &in.LocalSSDs, &out.LocalSSDs
+		*out = make([]LocalSSDSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ServiceAccount != nil {
 		in, out := &in.ServiceAccount, &out.ServiceAccount
 		*out = new(ServiceAccount)
@@ -610,11 +911,53 @@ func (in *GCPMachineSpec) DeepCopyInto(out *GCPMachineSpec) {
 		*out = new(ProvisioningModel)
 		**out = **in
 	}
+	if in.TerminationAction != nil {
+		in, out := &in.TerminationAction, &out.TerminationAction
+		*out = new(InstanceTerminationAction)
+		**out = **in
+	}
 	if in.IPForwarding != nil {
 		in, out := &in.IPForwarding, &out.IPForwarding
 		*out = new(IPForwarding)
 		**out = **in
 	}
+	if in.NodeAffinities != nil {
+		in, out := &in.NodeAffinities, &out.NodeAffinities
+		*out = make([]NodeAffinity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResourcePolicies != nil {
+		in, out := &in.ResourcePolicies, &out.ResourcePolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MinCPUPlatform != nil {
+		in, out := &in.MinCPUPlatform, &out.MinCPUPlatform
+		*out = new(string)
+		**out = **in
+	}
+	if in.AdvancedMachineFeatures != nil {
+		in, out := &in.AdvancedMachineFeatures, &out.AdvancedMachineFeatures
+		*out = new(AdvancedMachineFeatures)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPerformanceConfig != nil {
+		in, out := &in.NetworkPerformanceConfig, &out.NetworkPerformanceConfig
+		*out = new(NetworkPerformanceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionProtection != nil {
+		in, out := &in.DeletionProtection, &out.DeletionProtection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.ShieldedInstanceConfig != nil {
 		in, out := &in.ShieldedInstanceConfig, &out.ShieldedInstanceConfig
 		*out = new(GCPShieldedInstanceConfig)
@@ -625,6 +968,11 @@ func (in *GCPMachineSpec) DeepCopyInto(out *GCPMachineSpec) {
 		*out = new(HostMaintenancePolicy)
 		**out = **in
 	}
+	if in.AutomaticRestart != nil {
+		in, out := &in.AutomaticRestart, &out.AutomaticRestart
+		*out = new(bool)
+		**out = **in
+	}
 	if in.ConfidentialCompute != nil {
 		in, out := &in.ConfidentialCompute, &out.ConfidentialCompute
 		*out = new(ConfidentialComputePolicy)
@@ -665,6 +1013,31 @@ func (in *GCPMachineStatus) DeepCopyInto(out *GCPMachineStatus) {
 		*out = new(InstanceStatus)
 		**out = **in
 	}
+	if in.DeletionProtection != nil {
+		in, out := &in.DeletionProtection, &out.DeletionProtection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.InstanceID != nil {
+		in, out := &in.InstanceID, &out.InstanceID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Zone != nil {
+		in, out := &in.Zone, &out.Zone
+		*out = new(string)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.BootstrapDataObject != nil {
+		in, out := &in.BootstrapDataObject, &out.BootstrapDataObject
+		*out = new(string)
+		**out = **in
+	}
 	if in.FailureReason != nil {
 		in, out := &in.FailureReason, &out.FailureReason
 		*out = new(string)
@@ -675,6 +1048,13 @@ func (in *GCPMachineStatus) DeepCopyInto(out *GCPMachineStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(corev1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPMachineStatus.
@@ -693,6 +1073,7 @@ func (in *GCPMachineTemplate) DeepCopyInto(out *GCPMachineTemplate) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPMachineTemplate.
@@ -778,6 +1159,28 @@ func (in *GCPMachineTemplateSpec) DeepCopy() *GCPMachineTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPMachineTemplateStatus) DeepCopyInto(out *GCPMachineTemplateStatus) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPMachineTemplateStatus.
+func (in *GCPMachineTemplateStatus) DeepCopy() *GCPMachineTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPMachineTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GCPShieldedInstanceConfig) DeepCopyInto(out *GCPShieldedInstanceConfig) {
 	*out = *in
@@ -793,6 +1196,71 @@ func (in *GCPShieldedInstanceConfig) DeepCopy() *GCPShieldedInstanceConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
+	*out = *in
+	if in.CheckIntervalSec != nil {
+		in, out := &in.CheckIntervalSec, &out.CheckIntervalSec
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TimeoutSec != nil {
+		in, out := &in.TimeoutSec, &out.TimeoutSec
+		*out = new(int64)
+		**out = **in
+	}
+	if in.HealthyThreshold != nil {
+		in, out := &in.HealthyThreshold, &out.HealthyThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.UnhealthyThreshold != nil {
+		in, out := &in.UnhealthyThreshold, &out.UnhealthyThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RequestPath != nil {
+		in, out := &in.RequestPath, &out.RequestPath
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheck.
+func (in *HealthCheck) DeepCopy() *HealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalAddressSpec) DeepCopyInto(out *InternalAddressSpec) {
+	*out = *in
+	if in.Address != nil {
+		in, out := &in.Address, &out.Address
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalAddressSpec.
+func (in *InternalAddressSpec) DeepCopy() *InternalAddressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalAddressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in Labels) DeepCopyInto(out *Labels) {
 	{
@@ -852,6 +1320,11 @@ func (in *LoadBalancerSpec) DeepCopyInto(out *LoadBalancerSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.APIServerAddress != nil {
+		in, out := &in.APIServerAddress, &out.APIServerAddress
+		*out = new(string)
+		**out = **in
+	}
 	if in.LoadBalancerType != nil {
 		in, out := &in.LoadBalancerType, &out.LoadBalancerType
 		*out = new(LoadBalancerType)
@@ -862,6 +1335,11 @@ func (in *LoadBalancerSpec) DeepCopyInto(out *LoadBalancerSpec) {
 		*out = new(LoadBalancer)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerSpec.
@@ -874,6 +1352,26 @@ func (in *LoadBalancerSpec) DeepCopy() *LoadBalancerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalSSDSpec) DeepCopyInto(out *LocalSSDSpec) {
+	*out = *in
+	if in.Interface != nil {
+		in, out := &in.Interface, &out.Interface
+		*out = new(LocalSSDInterface)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalSSDSpec.
+func (in *LocalSSDSpec) DeepCopy() *LocalSSDSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalSSDSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedKey) DeepCopyInto(out *ManagedKey) {
 	*out = *in
@@ -929,6 +1427,13 @@ func (in *Network) DeepCopyInto(out *Network) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Peerings != nil {
+		in, out := &in.Peerings, &out.Peerings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.APIServerAddress != nil {
 		in, out := &in.APIServerAddress, &out.APIServerAddress
 		*out = new(string)
@@ -993,6 +1498,91 @@ func (in *Network) DeepCopy() *Network {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterfaceSpec) DeepCopyInto(out *NetworkInterfaceSpec) {
+	*out = *in
+	if in.Subnetwork != nil {
+		in, out := &in.Subnetwork, &out.Subnetwork
+		*out = new(string)
+		**out = **in
+	}
+	if in.InternalIP != nil {
+		in, out := &in.InternalIP, &out.InternalIP
+		*out = new(string)
+		**out = **in
+	}
+	if in.PublicIP != nil {
+		in, out := &in.PublicIP, &out.PublicIP
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AliasIPRanges != nil {
+		in, out := &in.AliasIPRanges, &out.AliasIPRanges
+		*out = make([]AliasIPRange, len(*in))
+		copy(*out, *in)
+	}
+	if in.NicType != nil {
+		in, out := &in.NicType, &out.NicType
+		*out = new(NicType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterfaceSpec.
+func (in *NetworkInterfaceSpec) DeepCopy() *NetworkInterfaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterfaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPeering) DeepCopyInto(out *NetworkPeering) {
+	*out = *in
+	if in.ExportCustomRoutes != nil {
+		in, out := &in.ExportCustomRoutes, &out.ExportCustomRoutes
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ImportCustomRoutes != nil {
+		in, out := &in.ImportCustomRoutes, &out.ImportCustomRoutes
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPeering.
+func (in *NetworkPeering) DeepCopy() *NetworkPeering {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPeering)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPerformanceConfig) DeepCopyInto(out *NetworkPerformanceConfig) {
+	*out = *in
+	if in.TotalEgressBandwidthTier != nil {
+		in, out := &in.TotalEgressBandwidthTier, &out.TotalEgressBandwidthTier
+		*out = new(EgressBandwidthTier)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPerformanceConfig.
+func (in *NetworkPerformanceConfig) DeepCopy() *NetworkPerformanceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPerformanceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 	*out = *in
@@ -1024,6 +1614,18 @@ func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 		**out = **in
 	}
 	in.Firewall.DeepCopyInto(&out.Firewall)
+	if in.CloudNAT != nil {
+		in, out := &in.CloudNAT, &out.CloudNAT
+		*out = new(CloudNATSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPeerings != nil {
+		in, out := &in.NetworkPeerings, &out.NetworkPeerings
+		*out = make([]NetworkPeering, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
@@ -1036,6 +1638,46 @@ func (in *NetworkSpec) DeepCopy() *NetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeAffinity) DeepCopyInto(out *NodeAffinity) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAffinity.
+func (in *NodeAffinity) DeepCopy() *NodeAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeServiceAccountSpec) DeepCopyInto(out *NodeServiceAccountSpec) {
+	*out = *in
+	if in.AdditionalRoles != nil {
+		in, out := &in.AdditionalRoles, &out.AdditionalRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeServiceAccountSpec.
+func (in *NodeServiceAccountSpec) DeepCopy() *NodeServiceAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeServiceAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
 	*out = *in
@@ -1141,6 +1783,41 @@ func (in *ServiceEndpoints) DeepCopy() *ServiceEndpoints {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubnetFlowLogs) DeepCopyInto(out *SubnetFlowLogs) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AggregationInterval != nil {
+		in, out := &in.AggregationInterval, &out.AggregationInterval
+		*out = new(string)
+		**out = **in
+	}
+	if in.FlowSampling != nil {
+		in, out := &in.FlowSampling, &out.FlowSampling
+		*out = new(string)
+		**out = **in
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetFlowLogs.
+func (in *SubnetFlowLogs) DeepCopy() *SubnetFlowLogs {
+	if in == nil {
+		return nil
+	}
+	out := new(SubnetFlowLogs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 	*out = *in
@@ -1166,11 +1843,21 @@ func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.FlowLogs != nil {
+		in, out := &in.FlowLogs, &out.FlowLogs
+		*out = new(SubnetFlowLogs)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Purpose != nil {
 		in, out := &in.Purpose, &out.Purpose
 		*out = new(string)
 		**out = **in
 	}
+	if in.Ipv6AccessType != nil {
+		in, out := &in.Ipv6AccessType, &out.Ipv6AccessType
+		*out = new(Ipv6AccessType)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetSpec.
@@ -1228,3 +1915,18 @@ func (in *SuppliedKey) DeepCopy() *SuppliedKey {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadIdentitySpec) DeepCopyInto(out *WorkloadIdentitySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadIdentitySpec.
+func (in *WorkloadIdentitySpec) DeepCopy() *WorkloadIdentitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadIdentitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
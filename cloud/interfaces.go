@@ -19,10 +19,13 @@ package cloud
 import (
 	"context"
 
+	clusterv1beta1 "sigs.k8s.io/cluster-api/api/core/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/storage/v1"
 	corev1 "k8s.io/api/core/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 )
@@ -46,6 +49,7 @@ type ReconcilerWithResult interface {
 type Client interface {
 	Cloud() Cloud
 	NetworkCloud() Cloud
+	ComputeService() *compute.Service
 }
 
 // ClusterGetter is an interface which can get cluster information.
@@ -60,16 +64,34 @@ type ClusterGetter interface {
 	IsSharedVpc() bool
 	SkipFirewallRulesManagement() bool
 	Network() *infrav1.Network
+	SubnetSpecs() []*compute.Subnetwork
 	AdditionalLabels() infrav1.Labels
+	AdditionalNetworkTags() []string
 	FailureDomains() []string
 	ControlPlaneEndpoint() clusterv1.APIEndpoint
 	ResourceManagerTags() infrav1.ResourceManagerTags
 	LoadBalancer() infrav1.LoadBalancerSpec
+	DiskEncryptionKey() *infrav1.CustomerEncryptionKey
+	MachinePublicIP() *bool
+	EnableOSLogin() *bool
+	EnableOSLogin2FA() *bool
+	ImageLookupProject() *string
+	ImageLookupBaseOS() *string
+	ImageLookupFormat() *string
+	ControlPlanePlacementPolicy() *string
+	NodeServiceAccountEmail() string
+	BootstrapDataStorage() *infrav1.BootstrapDataStorageSpec
+	StorageService() *storage.Service
+	WorkloadIdentityProvider() string
+	CredentialsIdentity() string
 }
 
 // ClusterSetter is an interface which can set cluster information.
 type ClusterSetter interface {
 	SetControlPlaneEndpoint(endpoint clusterv1.APIEndpoint)
+	MarkConditionTrue(condition clusterv1beta1.ConditionType)
+	MarkConditionFalse(condition clusterv1beta1.ConditionType, reason string, err error)
+	DeleteCondition(condition clusterv1beta1.ConditionType)
 }
 
 // Cluster is an interface which can get and set cluster information.
@@ -102,6 +124,8 @@ type MachineSetter interface {
 	SetFailureReason(v string)
 	SetAnnotation(key, value string)
 	SetAddresses(addressList []corev1.NodeAddress)
+	MarkConditionTrue(condition clusterv1beta1.ConditionType)
+	MarkConditionFalse(condition clusterv1beta1.ConditionType, reason string, err error)
 }
 
 // Machine is an interface which can get and set machine information.
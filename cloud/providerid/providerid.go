@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"strings"
 
 	"sigs.k8s.io/cluster-api-provider-gcp/util/resourceurl"
 )
@@ -47,6 +48,16 @@ func NewFromResourceURL(url string) (ProviderID, error) {
 	return New(resourceURL.Project, resourceURL.Location, resourceURL.Name)
 }
 
+// Parse parses a provider id of the form gce://project/location/name.
+func Parse(id string) (ProviderID, error) {
+	parts := strings.Split(strings.TrimPrefix(id, Prefix), "/")
+	if !strings.HasPrefix(id, Prefix) || len(parts) != 3 {
+		return nil, fmt.Errorf("provider id %s must be of the form %sproject/location/name", id, Prefix)
+	}
+
+	return New(parts[0], parts[1], parts[2])
+}
+
 // New creates a new provider id.
 func New(project, location, name string) (ProviderID, error) {
 	if project == "" {
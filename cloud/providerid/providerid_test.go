@@ -114,3 +114,47 @@ func TestProviderID_NewFromResourceURL(t *testing.T) {
 		})
 	}
 }
+
+func TestProviderID_Parse(t *testing.T) {
+	RegisterTestingT(t)
+
+	testCases := []struct {
+		testname    string
+		id          string
+		expectError bool
+	}{
+		{
+			testname:    "valid provider id, should pass",
+			id:          "gce://myproject/europe-west2-a/my-instance",
+			expectError: false,
+		},
+		{
+			testname:    "missing prefix, should fail",
+			id:          "aws://myproject/europe-west2-a/my-instance",
+			expectError: true,
+		},
+		{
+			testname:    "missing location, should fail",
+			id:          "gce://myproject/my-instance",
+			expectError: true,
+		},
+		{
+			testname:    "empty name, should fail",
+			id:          "gce://myproject/europe-west2-a/",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testname, func(_ *testing.T) {
+			providerID, err := providerid.Parse(tc.id)
+
+			if tc.expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(providerID.String()).To(Equal(tc.id))
+			}
+		})
+	}
+}
@@ -18,8 +18,16 @@ package scope
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
 
 	computerest "cloud.google.com/go/compute/apiv1"
@@ -28,21 +36,141 @@ import (
 	resourcemanager "cloud.google.com/go/resourcemanager/apiv3"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/pkg/errors"
+	"google.golang.org/api/cloudresourcemanager/v1"
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/iam/v1"
+	"google.golang.org/api/impersonate"
 	"google.golang.org/api/option"
+	"google.golang.org/api/option/internaloption"
+	"google.golang.org/api/storage/v1"
+	htransport "google.golang.org/api/transport/http"
+	"k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/client-go/pkg/version"
 	"k8s.io/client-go/util/flowcontrol"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-gcp/util/reconciler"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // GCPServices contains all the gcp services used by the scopes.
 type GCPServices struct {
-	Compute *compute.Service
+	Compute         *compute.Service
+	IAM             *iam.Service
+	ResourceManager *cloudresourcemanager.Service
+	Storage         *storage.Service
 }
 
-// GCPRateLimiter implements cloud.RateLimiter.
-type GCPRateLimiter struct{}
+// GCPRateLimiter implements cloud.RateLimiter, it also records the metrics of the GCP API requests.
+type GCPRateLimiter struct {
+	// starts holds the start time of the requests in flight, by request key.
+	starts sync.Map
+}
+
+const (
+	// operationPollQPS is the rate of the polls of all the GCP operations, e.g. of a mass scale up.
+	operationPollQPS = 5
+	// operationPollBurst is the max number of the polls of the GCP operations sent at once.
+	operationPollBurst = 5
+	// operationPollCacheSize is the max number of operations whose polls are tracked.
+	operationPollCacheSize = 4096
+	// operationPollCacheTTL is how long the polls of an operation are tracked, longer than most operations take.
+	operationPollCacheTTL = time.Hour
+)
+
+// operationPollRegexp matches the path of the requests polling a GCP operation, either getting or waiting for it.
+var operationPollRegexp = regexp.MustCompile(`^(.*/projects/[^/]+/(?:(?:zones|regions)/[^/]+|global)/operations/[^/]+)(?:/wait)?$`)
+
+// operationPoller backs off the polls of each GCP operation, under a rate limit shared by all the operations.
+type operationPoller struct {
+	limiter flowcontrol.RateLimiter
+
+	mu sync.Mutex
+	// polls holds the number of polls of the operations, by operation URL.
+	polls *cache.LRUExpireCache
+}
+
+func newOperationPoller() *operationPoller {
+	return &operationPoller{
+		limiter: flowcontrol.NewTokenBucketRateLimiter(operationPollQPS, operationPollBurst),
+		polls:   cache.NewLRUExpireCache(operationPollCacheSize),
+	}
+}
+
+// defaultOperationPoller is shared by the compute services of all the clusters, created for each reconcile.
+var defaultOperationPoller = newOperationPoller()
+
+// wait blocks until the operation can be polled again.
+func (p *operationPoller) wait(ctx context.Context, operation string) error {
+	p.mu.Lock()
+	n := 0
+	if polls, ok := p.polls.Get(operation); ok {
+		n = polls.(int)
+	}
+	p.polls.Add(operation, n+1, operationPollCacheTTL)
+	p.mu.Unlock()
+
+	timer := time.NewTimer(operationPollDelay(n))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+	return p.limiter.Wait(ctx)
+}
+
+// operationPollTransport backs off the requests polling the GCP operations. The operations are polled with an
+// exponential backoff, as most of them take tens of seconds to complete. The polls are identified by their URL, the
+// k8s-cloud-provider rate limit keys don't name the operation.
+type operationPollTransport struct {
+	base   http.RoundTripper
+	poller *operationPoller
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *operationPollTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if match := operationPollRegexp.FindStringSubmatch(req.URL.Path); match != nil {
+		if err := t.poller.wait(req.Context(), req.URL.Host+match[1]); err != nil {
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// operationPollBackoff is the backoff between the polls of the GCP operations.
+var operationPollBackoff = struct {
+	interval    time.Duration
+	maxInterval time.Duration
+}{
+	interval:    reconciler.DefaultOperationPollInterval,
+	maxInterval: reconciler.DefaultOperationPollMaxInterval,
+}
+
+// SetOperationPollBackoff sets the interval before the first poll of the GCP operations, doubling with each poll up to
+// the max interval. It must be called before the controllers are started.
+func SetOperationPollBackoff(interval, maxInterval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("operation poll interval must be positive, got %s", interval)
+	}
+	if maxInterval < interval {
+		return fmt.Errorf("operation poll max interval %s must not be less than the interval %s", maxInterval, interval)
+	}
+	operationPollBackoff.interval = interval
+	operationPollBackoff.maxInterval = maxInterval
+	return nil
+}
+
+// operationPollDelay returns the delay before the nth poll of an operation, exponentially increasing up to the max
+// interval. Half of the delay is jittered so that the operations started together, e.g. during a mass scale up, are
+// not polled together.
+func operationPollDelay(n int) time.Duration {
+	delay := operationPollBackoff.interval
+	for i := 0; i < n && delay < operationPollBackoff.maxInterval; i++ {
+		delay *= 2
+	}
+	delay = min(delay, operationPollBackoff.maxInterval)
+	return delay/2 + rand.N(delay/2+1) //nolint:gosec // The jitter doesn't need a secure random number.
+}
 
 // credentialHeader is a helper struct used for determining the type of
 // GCP credentials from JSON data.
@@ -51,25 +179,82 @@ type credentialHeader struct {
 }
 
 // Accept blocks until the operation can be performed.
-func (rl *GCPRateLimiter) Accept(ctx context.Context, key *cloud.RateLimitKey) error {
-	if key.Operation == "Get" && key.Service == "Operations" {
-		// Wait a minimum amount of time regardless of rate limiter.
-		rl := &cloud.MinimumRateLimiter{
-			// Convert flowcontrol.RateLimiter into cloud.RateLimiter
-			RateLimiter: &cloud.AcceptRateLimiter{
-				Acceptor: flowcontrol.NewTokenBucketRateLimiter(5, 5), // 5
-			},
-			Minimum: time.Second,
-		}
+func (rl *GCPRateLimiter) Accept(_ context.Context, key *cloud.RateLimitKey) error {
+	// Operations are polled until they complete, each poll is accepted but only the final one is observed. The polls are
+	// backed off by the operationPollTransport of the compute service.
+	if !isOperationPoll(key) {
+		rl.starts.Store(key, time.Now())
+	}
+	return nil
+}
+
+// Observe records the result and latency of a GCP API request.
+func (rl *GCPRateLimiter) Observe(_ context.Context, err error, key *cloud.RateLimitKey) {
+	operation := apiOperation(key)
+	gcpAPIRequestsTotal.WithLabelValues(operation, apiResult(err)).Inc()
+	if start, ok := rl.starts.LoadAndDelete(key); ok {
+		gcpAPIRequestDuration.WithLabelValues(operation).Observe(time.Since(start.(time.Time)).Seconds())
+	}
+}
+
+// isOperationPoll reports whether the request polls an operation until it completes.
+func isOperationPoll(key *cloud.RateLimitKey) bool {
+	return key.Operation == "Get" && key.Service == "Operations"
+}
 
-		return rl.Accept(ctx, key)
+// defaultServiceEndpoints are the GCP API endpoints of the clusters not setting theirs, e.g. an API emulator or the
+// private endpoints of an air-gapped environment.
+var defaultServiceEndpoints infrav1.ServiceEndpoints
+
+// SetDefaultServiceEndpoints sets the GCP API endpoints of the clusters not setting theirs in their spec, the empty
+// ones defaulting to the public GCP endpoints. It must be called before the controllers are started.
+func SetDefaultServiceEndpoints(endpoints infrav1.ServiceEndpoints) error {
+	// The container client is a gRPC one, taking a host:port endpoint.
+	if endpoint := endpoints.ContainerServiceEndpoint; endpoint != "" {
+		if _, _, err := net.SplitHostPort(endpoint); err != nil || strings.Contains(endpoint, "/") {
+			return fmt.Errorf("container service endpoint %q must be a host:port endpoint", endpoint)
+		}
+	}
+	for name, endpoint := range map[string]string{
+		"compute":          endpoints.ComputeServiceEndpoint,
+		"iam":              endpoints.IAMServiceEndpoint,
+		"resource manager": endpoints.ResourceManagerServiceEndpoint,
+		"storage":          endpoints.StorageServiceEndpoint,
+	} {
+		if endpoint == "" {
+			continue
+		}
+		// Plain HTTP is allowed for the emulators.
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("%s service endpoint %q must be an http or https URL", name, endpoint)
+		}
 	}
+	defaultServiceEndpoints = endpoints
 	return nil
 }
 
-// Observe does nothing.
-func (rl *GCPRateLimiter) Observe(context.Context, error, *cloud.RateLimitKey) {
-	// noop
+// withDefaultServiceEndpoints returns the service endpoints of the cluster, defaulting the ones it doesn't set.
+func withDefaultServiceEndpoints(endpoints *infrav1.ServiceEndpoints) *infrav1.ServiceEndpoints {
+	merged := defaultServiceEndpoints
+	if endpoints == nil {
+		return &merged
+	}
+	if endpoints.ComputeServiceEndpoint != "" {
+		merged.ComputeServiceEndpoint = endpoints.ComputeServiceEndpoint
+	}
+	if endpoints.ContainerServiceEndpoint != "" {
+		merged.ContainerServiceEndpoint = endpoints.ContainerServiceEndpoint
+	}
+	if endpoints.IAMServiceEndpoint != "" {
+		merged.IAMServiceEndpoint = endpoints.IAMServiceEndpoint
+	}
+	if endpoints.ResourceManagerServiceEndpoint != "" {
+		merged.ResourceManagerServiceEndpoint = endpoints.ResourceManagerServiceEndpoint
+	}
+	if endpoints.StorageServiceEndpoint != "" {
+		merged.StorageServiceEndpoint = endpoints.StorageServiceEndpoint
+	}
+	return &merged
 }
 
 func newCloud(project string, service GCPServices) cloud.Cloud {
@@ -80,13 +265,28 @@ func newCloud(project string, service GCPServices) cloud.Cloud {
 	})
 }
 
-func defaultClientOptions(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client) ([]option.ClientOption, error) {
-	opts := []option.ClientOption{
-		option.WithUserAgent(fmt.Sprintf("gcp.cluster.x-k8s.io/%s", version.Get())),
-	}
+const (
+	// impersonationCacheSize is the max number of cached client options authenticating as impersonated service
+	// accounts, a few per tenant.
+	impersonationCacheSize = 1024
+	// impersonationCacheTTL is how long the client options authenticating as an impersonated service account are
+	// cached, the default lifetime of the tokens the IAM Credentials API issues.
+	impersonationCacheTTL = time.Hour
+)
+
+// impersonationClientOptions caches the client options authenticating as the impersonated service accounts, by
+// service account and impersonating credentials, so that their short-lived tokens are reused across the reconciles
+// until they expire. They are evicted once expired, e.g. when the credentials are rotated or the cluster is deleted.
+var impersonationClientOptions = cache.NewLRUExpireCache(impersonationCacheSize)
 
+func defaultClientOptions(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client, serviceAccountToImpersonate string) ([]option.ClientOption, error) {
+	userAgent := option.WithUserAgent(fmt.Sprintf("gcp.cluster.x-k8s.io/%s", version.Get()))
+	opts := []option.ClientOption{userAgent}
+
+	var rawData []byte
 	if credentialsRef != nil {
-		rawData, err := getCredentialDataFromRef(ctx, credentialsRef, crClient)
+		var err error
+		rawData, err = getCredentialDataFromRef(ctx, credentialsRef, crClient)
 		if err != nil {
 			return nil, fmt.Errorf("getting gcp credentials from reference %s: %w", credentialsRef, err)
 		}
@@ -108,11 +308,44 @@ func defaultClientOptions(ctx context.Context, credentialsRef *infrav1.ObjectRef
 		}
 	}
 
+	if serviceAccountToImpersonate != "" {
+		impersonation, err := impersonationClientOption(serviceAccountToImpersonate, rawData, opts)
+		if err != nil {
+			return nil, fmt.Errorf("impersonating service account %s: %w", serviceAccountToImpersonate, err)
+		}
+		// The credentials take precedence over the token source, they are only used to impersonate the service account.
+		opts = []option.ClientOption{userAgent, impersonation}
+	}
+
 	return opts, nil
 }
 
-func newComputeService(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client, endpoints *infrav1.ServiceEndpoints) (*compute.Service, error) {
-	opts, err := defaultClientOptions(ctx, credentialsRef, crClient)
+// impersonationClientOption returns the client option authenticating as the service account, with the short-lived
+// tokens the IAM Credentials API issues to the credentials of the client options, the ones of the controller when
+// they have none.
+func impersonationClientOption(serviceAccount string, credentials []byte, opts []option.ClientOption) (option.ClientOption, error) {
+	key := fmt.Sprintf("%s/%x", serviceAccount, sha256.Sum256(credentials))
+	if cached, ok := impersonationClientOptions.Get(key); ok {
+		return cached.(option.ClientOption), nil
+	}
+
+	// The token source outlives the reconcile, it isn't bound to its context.
+	tokenSource, err := impersonate.CredentialsTokenSource(context.Background(), impersonate.CredentialsConfig{
+		TargetPrincipal: serviceAccount,
+		Scopes:          []string{compute.CloudPlatformScope},
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := option.WithTokenSource(tokenSource)
+	impersonationClientOptions.Add(key, opt, impersonationCacheTTL)
+	return opt, nil
+}
+
+func newComputeService(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client, serviceAccountToImpersonate string, endpoints *infrav1.ServiceEndpoints) (*compute.Service, error) {
+	endpoints = withDefaultServiceEndpoints(endpoints)
+	opts, err := defaultClientOptions(ctx, credentialsRef, crClient, serviceAccountToImpersonate)
 	if err != nil {
 		return nil, fmt.Errorf("getting default gcp client options: %w", err)
 	}
@@ -121,6 +354,16 @@ func newComputeService(ctx context.Context, credentialsRef *infrav1.ObjectRefere
 		opts = append(opts, option.WithEndpoint(endpoints.ComputeServiceEndpoint))
 	}
 
+	// The transport is built with the same options as the compute service, which doesn't take a base transport.
+	transport, err := htransport.NewTransport(ctx, http.DefaultTransport.(*http.Transport).Clone(),
+		append([]option.ClientOption{internaloption.WithDefaultScopes(compute.CloudPlatformScope)}, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("creating compute service transport: %w", err)
+	}
+	opts = append(opts, option.WithHTTPClient(&http.Client{
+		Transport: &operationPollTransport{base: transport, poller: defaultOperationPoller},
+	}))
+
 	computeSvc, err := compute.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("creating new compute service instance: %w", err)
@@ -129,8 +372,66 @@ func newComputeService(ctx context.Context, credentialsRef *infrav1.ObjectRefere
 	return computeSvc, nil
 }
 
-func newClusterManagerClient(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client, endpoints *infrav1.ServiceEndpoints) (*container.ClusterManagerClient, error) {
-	opts, err := defaultClientOptions(ctx, credentialsRef, crClient)
+func newIAMService(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client, serviceAccountToImpersonate string, endpoints *infrav1.ServiceEndpoints) (*iam.Service, error) {
+	endpoints = withDefaultServiceEndpoints(endpoints)
+	opts, err := defaultClientOptions(ctx, credentialsRef, crClient, serviceAccountToImpersonate)
+	if err != nil {
+		return nil, fmt.Errorf("getting default gcp client options: %w", err)
+	}
+
+	if endpoints != nil && endpoints.IAMServiceEndpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoints.IAMServiceEndpoint))
+	}
+
+	iamSvc, err := iam.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating new iam service instance: %w", err)
+	}
+
+	return iamSvc, nil
+}
+
+func newResourceManagerService(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client, serviceAccountToImpersonate string, endpoints *infrav1.ServiceEndpoints) (*cloudresourcemanager.Service, error) {
+	endpoints = withDefaultServiceEndpoints(endpoints)
+	opts, err := defaultClientOptions(ctx, credentialsRef, crClient, serviceAccountToImpersonate)
+	if err != nil {
+		return nil, fmt.Errorf("getting default gcp client options: %w", err)
+	}
+
+	if endpoints != nil && endpoints.ResourceManagerServiceEndpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoints.ResourceManagerServiceEndpoint))
+	}
+
+	resourceManagerSvc, err := cloudresourcemanager.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating new resource manager service instance: %w", err)
+	}
+
+	return resourceManagerSvc, nil
+}
+
+func newStorageService(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client, serviceAccountToImpersonate string, endpoints *infrav1.ServiceEndpoints) (*storage.Service, error) {
+	endpoints = withDefaultServiceEndpoints(endpoints)
+	opts, err := defaultClientOptions(ctx, credentialsRef, crClient, serviceAccountToImpersonate)
+	if err != nil {
+		return nil, fmt.Errorf("getting default gcp client options: %w", err)
+	}
+
+	if endpoints != nil && endpoints.StorageServiceEndpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoints.StorageServiceEndpoint))
+	}
+
+	storageSvc, err := storage.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating new storage service instance: %w", err)
+	}
+
+	return storageSvc, nil
+}
+
+func newClusterManagerClient(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client, serviceAccountToImpersonate string, endpoints *infrav1.ServiceEndpoints) (*container.ClusterManagerClient, error) {
+	endpoints = withDefaultServiceEndpoints(endpoints)
+	opts, err := defaultClientOptions(ctx, credentialsRef, crClient, serviceAccountToImpersonate)
 	if err != nil {
 		return nil, fmt.Errorf("getting default gcp client options: %w", err)
 	}
@@ -147,8 +448,9 @@ func newClusterManagerClient(ctx context.Context, credentialsRef *infrav1.Object
 	return managedClusterClient, nil
 }
 
-func newIamCredentialsClient(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client, endpoints *infrav1.ServiceEndpoints) (*credentials.IamCredentialsClient, error) {
-	opts, err := defaultClientOptions(ctx, credentialsRef, crClient)
+func newIamCredentialsClient(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client, serviceAccountToImpersonate string, endpoints *infrav1.ServiceEndpoints) (*credentials.IamCredentialsClient, error) {
+	endpoints = withDefaultServiceEndpoints(endpoints)
+	opts, err := defaultClientOptions(ctx, credentialsRef, crClient, serviceAccountToImpersonate)
 	if err != nil {
 		return nil, fmt.Errorf("getting default gcp client options: %w", err)
 	}
@@ -165,8 +467,9 @@ func newIamCredentialsClient(ctx context.Context, credentialsRef *infrav1.Object
 	return credentialsClient, nil
 }
 
-func newInstanceGroupManagerClient(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client, endpoints *infrav1.ServiceEndpoints) (*computerest.InstanceGroupManagersClient, error) {
-	opts, err := defaultClientOptions(ctx, credentialsRef, crClient)
+func newInstanceGroupManagerClient(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client, serviceAccountToImpersonate string, endpoints *infrav1.ServiceEndpoints) (*computerest.InstanceGroupManagersClient, error) {
+	endpoints = withDefaultServiceEndpoints(endpoints)
+	opts, err := defaultClientOptions(ctx, credentialsRef, crClient, serviceAccountToImpersonate)
 	if err != nil {
 		return nil, fmt.Errorf("getting default gcp client options: %w", err)
 	}
@@ -183,8 +486,9 @@ func newInstanceGroupManagerClient(ctx context.Context, credentialsRef *infrav1.
 	return instanceGroupManagersClient, nil
 }
 
-func newTagBindingsClient(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client, location string, endpoints *infrav1.ServiceEndpoints) (*resourcemanager.TagBindingsClient, error) {
-	opts, err := defaultClientOptions(ctx, credentialsRef, crClient)
+func newTagBindingsClient(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client, serviceAccountToImpersonate, location string, endpoints *infrav1.ServiceEndpoints) (*resourcemanager.TagBindingsClient, error) {
+	endpoints = withDefaultServiceEndpoints(endpoints)
+	opts, err := defaultClientOptions(ctx, credentialsRef, crClient, serviceAccountToImpersonate)
 
 	if endpoints != nil && endpoints.ResourceManagerServiceEndpoint != "" {
 		opts = append(opts, option.WithEndpoint(endpoints.ResourceManagerServiceEndpoint))
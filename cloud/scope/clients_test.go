@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-gcp/util/reconciler"
+)
+
+func setTestOperationPollBackoff(t *testing.T, interval, maxInterval time.Duration) {
+	t.Helper()
+	assert.NoError(t, SetOperationPollBackoff(interval, maxInterval))
+	t.Cleanup(func() {
+		_ = SetOperationPollBackoff(reconciler.DefaultOperationPollInterval, reconciler.DefaultOperationPollMaxInterval)
+	})
+}
+
+func TestSetOperationPollBackoff(t *testing.T) {
+	assert.Error(t, SetOperationPollBackoff(0, time.Second))
+	assert.Error(t, SetOperationPollBackoff(time.Minute, time.Second))
+	setTestOperationPollBackoff(t, time.Second, time.Second)
+}
+
+func TestOperationPollDelay(t *testing.T) {
+	setTestOperationPollBackoff(t, time.Second, 30*time.Second)
+
+	tests := []struct {
+		poll int
+		want time.Duration
+	}{
+		{poll: 0, want: time.Second},
+		{poll: 1, want: 2 * time.Second},
+		{poll: 3, want: 8 * time.Second},
+		{poll: 5, want: 30 * time.Second},
+		{poll: 100, want: 30 * time.Second},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			delay := operationPollDelay(tt.poll)
+			assert.GreaterOrEqual(t, delay, tt.want/2, "poll %d", tt.poll)
+			assert.LessOrEqual(t, delay, tt.want, "poll %d", tt.poll)
+		}
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestOperationPollTransport verifies that the polls of each operation back off on their own, and that the other
+// requests aren't delayed.
+func TestOperationPollTransport(t *testing.T) {
+	setTestOperationPollBackoff(t, time.Millisecond, 4*time.Millisecond)
+	poller := newOperationPoller()
+	transport := &operationPollTransport{
+		base: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}),
+		poller: poller,
+	}
+	roundTrip := func(ctx context.Context, method, url string) error {
+		req, err := http.NewRequestWithContext(ctx, method, url, http.NoBody)
+		assert.NoError(t, err)
+		_, err = transport.RoundTrip(req)
+		return err
+	}
+	polls := func(operation string) int {
+		n, ok := poller.polls.Get(operation)
+		if !ok {
+			return 0
+		}
+		return n.(int)
+	}
+
+	const (
+		zonal    = "compute.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/operations/operation-1"
+		regional = "compute.googleapis.com/compute/v1/projects/my-project/regions/us-central1/operations/operation-2"
+		global   = "compute.googleapis.com/compute/v1/projects/my-project/global/operations/operation-3"
+	)
+	for range 3 {
+		assert.NoError(t, roundTrip(context.TODO(), http.MethodPost, "https://"+zonal+"/wait"))
+	}
+	assert.NoError(t, roundTrip(context.TODO(), http.MethodGet, "https://"+regional))
+	assert.NoError(t, roundTrip(context.TODO(), http.MethodPost, "https://"+global+"/wait"))
+	assert.Equal(t, 3, polls(zonal))
+	assert.Equal(t, 1, polls(regional))
+	assert.Equal(t, 1, polls(global))
+
+	instances := "compute.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/instances"
+	assert.NoError(t, roundTrip(context.TODO(), http.MethodPost, "https://"+instances))
+	assert.Equal(t, 0, polls(instances))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	assert.ErrorIs(t, roundTrip(ctx, http.MethodPost, "https://"+zonal+"/wait"), context.Canceled)
+}
+
+func TestServiceEndpoints(t *testing.T) {
+	assert.Error(t, SetDefaultServiceEndpoints(infrav1.ServiceEndpoints{ComputeServiceEndpoint: "localhost:8080"}))
+	assert.Error(t, SetDefaultServiceEndpoints(infrav1.ServiceEndpoints{IAMServiceEndpoint: "ftp://iam.example.com"}))
+	assert.Error(t, SetDefaultServiceEndpoints(infrav1.ServiceEndpoints{ContainerServiceEndpoint: "https://container.example.com/"}))
+	assert.Error(t, SetDefaultServiceEndpoints(infrav1.ServiceEndpoints{StorageServiceEndpoint: "storage.example.com"}))
+
+	// The clusters use the public endpoints by default.
+	assert.Equal(t, &infrav1.ServiceEndpoints{}, withDefaultServiceEndpoints(nil))
+
+	assert.NoError(t, SetDefaultServiceEndpoints(infrav1.ServiceEndpoints{
+		ComputeServiceEndpoint:   "http://localhost:8080/compute/v1/",
+		ContainerServiceEndpoint: "container.example.com:443",
+	}))
+	t.Cleanup(func() { _ = SetDefaultServiceEndpoints(infrav1.ServiceEndpoints{}) })
+
+	assert.Equal(t, &infrav1.ServiceEndpoints{
+		ComputeServiceEndpoint:   "http://localhost:8080/compute/v1/",
+		ContainerServiceEndpoint: "container.example.com:443",
+	}, withDefaultServiceEndpoints(nil))
+	// The endpoints of the cluster take precedence.
+	assert.Equal(t, &infrav1.ServiceEndpoints{
+		ComputeServiceEndpoint:         "https://compute.example.com/compute/v1/",
+		ContainerServiceEndpoint:       "container.example.com:443",
+		ResourceManagerServiceEndpoint: "https://cloudresourcemanager.example.com",
+		StorageServiceEndpoint:         "https://storage.example.com/storage/v1/",
+	}, withDefaultServiceEndpoints(&infrav1.ServiceEndpoints{
+		ComputeServiceEndpoint:         "https://compute.example.com/compute/v1/",
+		ResourceManagerServiceEndpoint: "https://cloudresourcemanager.example.com",
+		StorageServiceEndpoint:         "https://storage.example.com/storage/v1/",
+	}))
+}
@@ -18,16 +18,24 @@ package scope
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
+	"google.golang.org/api/cloudresourcemanager/v1"
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/iam/v1"
+	"google.golang.org/api/storage/v1"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud"
 	clusterv1beta1 "sigs.k8s.io/cluster-api/api/core/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	v1beta1conditions "sigs.k8s.io/cluster-api/util/deprecated/v1beta1/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -51,7 +59,7 @@ func NewClusterScope(ctx context.Context, params ClusterScopeParams) (*ClusterSc
 	}
 
 	if params.Compute == nil {
-		computeSvc, err := newComputeService(ctx, params.GCPCluster.Spec.CredentialsRef, params.Client, params.GCPCluster.Spec.ServiceEndpoints)
+		computeSvc, err := newComputeService(ctx, params.GCPCluster.Spec.CredentialsRef, params.Client, params.GCPCluster.Spec.ServiceAccountToImpersonate, params.GCPCluster.Spec.ServiceEndpoints)
 		if err != nil {
 			return nil, errors.Errorf("failed to create gcp compute client: %v", err)
 		}
@@ -59,6 +67,36 @@ func NewClusterScope(ctx context.Context, params ClusterScopeParams) (*ClusterSc
 		params.Compute = computeSvc
 	}
 
+	// The IAM and resource manager services are only used to manage the node service account.
+	if params.GCPCluster.Spec.NodeServiceAccount != nil {
+		if params.IAM == nil {
+			iamSvc, err := newIAMService(ctx, params.GCPCluster.Spec.CredentialsRef, params.Client, params.GCPCluster.Spec.ServiceAccountToImpersonate, params.GCPCluster.Spec.ServiceEndpoints)
+			if err != nil {
+				return nil, errors.Errorf("failed to create gcp iam client: %v", err)
+			}
+
+			params.IAM = iamSvc
+		}
+		if params.ResourceManager == nil {
+			resourceManagerSvc, err := newResourceManagerService(ctx, params.GCPCluster.Spec.CredentialsRef, params.Client, params.GCPCluster.Spec.ServiceAccountToImpersonate, params.GCPCluster.Spec.ServiceEndpoints)
+			if err != nil {
+				return nil, errors.Errorf("failed to create gcp resource manager client: %v", err)
+			}
+
+			params.ResourceManager = resourceManagerSvc
+		}
+	}
+
+	// The storage service is only used to upload the bootstrap data of the machines to GCS.
+	if params.Storage == nil && params.GCPCluster.Spec.BootstrapDataStorage != nil && params.GCPCluster.Spec.BootstrapDataStorage.Type == infrav1.BootstrapDataStorageGCS {
+		storageSvc, err := newStorageService(ctx, params.GCPCluster.Spec.CredentialsRef, params.Client, params.GCPCluster.Spec.ServiceAccountToImpersonate, params.GCPCluster.Spec.ServiceEndpoints)
+		if err != nil {
+			return nil, errors.Errorf("failed to create gcp storage client: %v", err)
+		}
+
+		params.Storage = storageSvc
+	}
+
 	helper, err := patch.NewHelper(params.GCPCluster, params.Client)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to init patch helper")
@@ -95,6 +133,26 @@ func (s *ClusterScope) NetworkCloud() cloud.Cloud {
 	return newCloud(s.NetworkProject(), s.GCPServices)
 }
 
+// ComputeService returns the compute service used by the cloud, for the operations the cloud doesn't support.
+func (s *ClusterScope) ComputeService() *compute.Service {
+	return s.Compute
+}
+
+// StorageService returns the storage service used to upload the bootstrap data of the machines to GCS.
+func (s *ClusterScope) StorageService() *storage.Service {
+	return s.Storage
+}
+
+// IAMService returns the IAM service used to manage the node service account.
+func (s *ClusterScope) IAMService() *iam.Service {
+	return s.IAM
+}
+
+// ResourceManagerService returns the resource manager service used to grant roles to the node service account.
+func (s *ClusterScope) ResourceManagerService() *cloudresourcemanager.Service {
+	return s.ResourceManager
+}
+
 // Project returns the current project name.
 func (s *ClusterScope) Project() string {
 	return s.GCPCluster.Spec.Project
@@ -168,11 +226,60 @@ func (s *ClusterScope) AdditionalLabels() infrav1.Labels {
 	return s.GCPCluster.Spec.AdditionalLabels
 }
 
+// AdditionalNetworkTags returns the network tags applied to all the instances of the cluster.
+func (s *ClusterScope) AdditionalNetworkTags() []string {
+	return s.GCPCluster.Spec.AdditionalNetworkTags
+}
+
 // LoadBalancer returns the LoadBalancer configuration.
 func (s *ClusterScope) LoadBalancer() infrav1.LoadBalancerSpec {
 	return s.GCPCluster.Spec.LoadBalancer
 }
 
+// DiskEncryptionKey returns the default encryption key of the machine disks.
+func (s *ClusterScope) DiskEncryptionKey() *infrav1.CustomerEncryptionKey {
+	return s.GCPCluster.Spec.DiskEncryptionKey
+}
+
+// MachinePublicIP returns whether the cluster machines get a public IP by default.
+func (s *ClusterScope) MachinePublicIP() *bool {
+	return s.GCPCluster.Spec.MachinePublicIP
+}
+
+// EnableOSLogin returns whether OS Login is enabled by default on the cluster machines.
+func (s *ClusterScope) EnableOSLogin() *bool {
+	return s.GCPCluster.Spec.EnableOSLogin
+}
+
+// EnableOSLogin2FA returns whether OS Login two-factor authentication is enabled by default on the cluster machines.
+func (s *ClusterScope) EnableOSLogin2FA() *bool {
+	return s.GCPCluster.Spec.EnableOSLogin2FA
+}
+
+// ImageLookupProject returns the default project to look up the images of the cluster machines in.
+func (s *ClusterScope) ImageLookupProject() *string {
+	return s.GCPCluster.Spec.ImageLookupProject
+}
+
+// ImageLookupBaseOS returns the default base OS of the images looked up for the cluster machines.
+func (s *ClusterScope) ImageLookupBaseOS() *string {
+	return s.GCPCluster.Spec.ImageLookupBaseOS
+}
+
+// ImageLookupFormat returns the default name format of the images looked up for the cluster machines.
+func (s *ClusterScope) ImageLookupFormat() *string {
+	return s.GCPCluster.Spec.ImageLookupFormat
+}
+
+// ControlPlanePlacementPolicy returns the name of the compact placement policy of the control plane machines,
+// or nil when ControlPlaneCompactPlacement is not enabled.
+func (s *ClusterScope) ControlPlanePlacementPolicy() *string {
+	if !ptr.Deref(s.GCPCluster.Spec.ControlPlaneCompactPlacement, false) {
+		return nil
+	}
+	return ptr.To(fmt.Sprintf("%s-control-plane", s.Name()))
+}
+
 // ResourceManagerTags returns ResourceManagerTags from the scope's GCPCluster. The returned value will never be nil.
 func (s *ClusterScope) ResourceManagerTags() infrav1.ResourceManagerTags {
 	if len(s.GCPCluster.Spec.ResourceManagerTags) == 0 {
@@ -204,6 +311,31 @@ func (s *ClusterScope) FailureDomains() []string {
 	return failureDomains
 }
 
+// NodeServiceAccountEmail returns the email of the service account created for the worker machines of the cluster,
+// or an empty string when it hasn't been created.
+func (s *ClusterScope) NodeServiceAccountEmail() string {
+	return s.GCPCluster.Status.NodeServiceAccountEmail
+}
+
+// BootstrapDataStorage returns where the bootstrap data of the machines is stored, nil meaning the instance metadata.
+func (s *ClusterScope) BootstrapDataStorage() *infrav1.BootstrapDataStorageSpec {
+	return s.GCPCluster.Spec.BootstrapDataStorage
+}
+
+// WorkloadIdentityProvider returns the full resource name of the workload identity pool provider of the cluster, or
+// an empty string when Workload Identity Federation isn't configured.
+func (s *ClusterScope) WorkloadIdentityProvider() string {
+	if s.GCPCluster.Spec.WorkloadIdentity == nil {
+		return ""
+	}
+	return s.GCPCluster.Spec.WorkloadIdentity.Provider
+}
+
+// CredentialsIdentity identifies the credentials the GCP clients of the cluster are authenticated with.
+func (s *ClusterScope) CredentialsIdentity() string {
+	return credentialsIdentity(s.GCPCluster.Spec.CredentialsRef, s.GCPCluster.Spec.ServiceAccountToImpersonate)
+}
+
 // ANCHOR_END: ClusterGetter
 
 // ANCHOR: ClusterSetter
@@ -218,6 +350,22 @@ func (s *ClusterScope) SetFailureDomains(fd clusterv1beta1.FailureDomains) {
 	s.GCPCluster.Status.FailureDomains = fd
 }
 
+// MarkConditionTrue sets the condition of the GCPCluster to True.
+func (s *ClusterScope) MarkConditionTrue(condition clusterv1beta1.ConditionType) {
+	v1beta1conditions.MarkTrue(s.GCPCluster, condition)
+}
+
+// MarkConditionFalse sets the condition of the GCPCluster to False with the reason and the error as message. The
+// errors are retried, hence the warning severity.
+func (s *ClusterScope) MarkConditionFalse(condition clusterv1beta1.ConditionType, reason string, err error) {
+	v1beta1conditions.MarkFalse(s.GCPCluster, condition, reason, clusterv1beta1.ConditionSeverityWarning, "%v", err)
+}
+
+// DeleteCondition removes the condition of the GCPCluster, e.g. when the resources it reports on aren't managed.
+func (s *ClusterScope) DeleteCondition(condition clusterv1beta1.ConditionType) {
+	v1beta1conditions.Delete(s.GCPCluster, condition)
+}
+
 // SetControlPlaneEndpoint sets cluster control-plane endpoint.
 func (s *ClusterScope) SetControlPlaneEndpoint(endpoint clusterv1.APIEndpoint) {
 	s.GCPCluster.Spec.ControlPlaneEndpoint = clusterv1beta1.APIEndpoint{
@@ -226,6 +374,11 @@ func (s *ClusterScope) SetControlPlaneEndpoint(endpoint clusterv1.APIEndpoint) {
 	}
 }
 
+// SetNodeServiceAccountEmail sets the email of the service account created for the worker machines of the cluster.
+func (s *ClusterScope) SetNodeServiceAccountEmail(email string) {
+	s.GCPCluster.Status.NodeServiceAccountEmail = email
+}
+
 // ANCHOR_END: ClusterSetter
 
 // ANCHOR: ClusterNetworkSpec
@@ -246,20 +399,64 @@ func (s *ClusterScope) NetworkSpec() *compute.Network {
 
 // NatRouterSpec returns google compute nat router spec.
 func (s *ClusterScope) NatRouterSpec() *compute.Router {
-	networkSpec := s.NetworkSpec()
+	return natRouterSpec(s.NetworkSpec().Name, s.GCPCluster.Spec.Network)
+}
+
+// natRouterSpec returns the cloudnat router spec of the network.
+func natRouterSpec(networkName string, network infrav1.NetworkSpec) *compute.Router {
+	nat := &compute.RouterNat{
+		Name:                          fmt.Sprintf("%s-%s", networkName, "nat"),
+		NatIpAllocateOption:           "AUTO_ONLY",
+		SourceSubnetworkIpRangesToNat: "ALL_SUBNETWORKS_ALL_IP_RANGES",
+		MinPortsPerVm:                 network.MinPortsPerVM,
+	}
+	if cloudNAT := network.CloudNAT; cloudNAT != nil {
+		if cloudNAT.MaxPortsPerVM != nil {
+			nat.EnableDynamicPortAllocation = true
+			nat.MaxPortsPerVm = *cloudNAT.MaxPortsPerVM
+		}
+		if cloudNAT.LogFilter != nil {
+			nat.LogConfig = &compute.RouterNatLogConfig{Enable: true}
+			switch *cloudNAT.LogFilter {
+			case infrav1.CloudNATLogFilterErrorsOnly:
+				nat.LogConfig.Filter = "ERRORS_ONLY"
+			case infrav1.CloudNATLogFilterTranslationsOnly:
+				nat.LogConfig.Filter = "TRANSLATIONS_ONLY"
+			case infrav1.CloudNATLogFilterAll:
+				nat.LogConfig.Filter = "ALL"
+			default:
+			}
+		}
+	}
+
 	return &compute.Router{
-		Name: fmt.Sprintf("%s-%s", networkSpec.Name, "router"),
-		Nats: []*compute.RouterNat{
-			{
-				Name:                          fmt.Sprintf("%s-%s", networkSpec.Name, "nat"),
-				NatIpAllocateOption:           "AUTO_ONLY",
-				SourceSubnetworkIpRangesToNat: "ALL_SUBNETWORKS_ALL_IP_RANGES",
-				MinPortsPerVm:                 s.GCPCluster.Spec.Network.MinPortsPerVM,
-			},
-		},
+		Name: fmt.Sprintf("%s-%s", networkName, "router"),
+		Nats: []*compute.RouterNat{nat},
 	}
 }
 
+// NetworkPeeringSpecs returns google compute network peerings spec.
+func (s *ClusterScope) NetworkPeeringSpecs() []*compute.NetworkPeering {
+	return networkPeeringSpecs(s.GCPCluster.Spec.Network)
+}
+
+// networkPeeringSpecs returns the peerings spec of the network.
+func networkPeeringSpecs(network infrav1.NetworkSpec) []*compute.NetworkPeering {
+	peerings := make([]*compute.NetworkPeering, 0, len(network.NetworkPeerings))
+	for _, peering := range network.NetworkPeerings {
+		peerings = append(peerings, &compute.NetworkPeering{
+			Name:                 peering.Name,
+			Network:              peering.PeerNetwork,
+			ExchangeSubnetRoutes: true,
+			ExportCustomRoutes:   ptr.Deref(peering.ExportCustomRoutes, false),
+			ImportCustomRoutes:   ptr.Deref(peering.ImportCustomRoutes, false),
+			ForceSendFields:      []string{"ExportCustomRoutes", "ImportCustomRoutes"},
+		})
+	}
+
+	return peerings
+}
+
 // ANCHOR_END: ClusterNetworkSpec
 
 // SubnetSpecs returns google compute subnets spec.
@@ -270,7 +467,7 @@ func (s *ClusterScope) SubnetSpecs() []*compute.Subnetwork {
 		for rangeName, secondaryCidrBlock := range subnetwork.SecondaryCidrBlocks {
 			secondaryIPRanges = append(secondaryIPRanges, &compute.SubnetworkSecondaryRange{RangeName: rangeName, IpCidrRange: secondaryCidrBlock})
 		}
-		subnets = append(subnets, &compute.Subnetwork{
+		subnet := &compute.Subnetwork{
 			Name:                  subnetwork.Name,
 			Region:                subnetwork.Region,
 			EnableFlowLogs:        ptr.Deref(subnetwork.EnableFlowLogs, false),
@@ -282,12 +479,38 @@ func (s *ClusterScope) SubnetSpecs() []*compute.Subnetwork {
 			Purpose:               ptr.Deref(subnetwork.Purpose, "PRIVATE_RFC_1918"),
 			Role:                  "ACTIVE",
 			StackType:             subnetwork.StackType,
-		})
+			Ipv6AccessType:        string(ptr.Deref(subnetwork.Ipv6AccessType, "")),
+		}
+		if subnet.LogConfig = subnetLogConfig(subnetwork.FlowLogs); subnet.LogConfig != nil {
+			subnet.EnableFlowLogs = subnet.LogConfig.Enable
+		}
+		subnets = append(subnets, subnet)
 	}
 
 	return subnets
 }
 
+// subnetLogConfig returns the flow logs config of a subnet, nil if it has none.
+func subnetLogConfig(flowLogs *infrav1.SubnetFlowLogs) *compute.SubnetworkLogConfig {
+	if flowLogs == nil {
+		return nil
+	}
+
+	logConfig := &compute.SubnetworkLogConfig{
+		Enable:              ptr.Deref(flowLogs.Enable, true),
+		AggregationInterval: ptr.Deref(flowLogs.AggregationInterval, ""),
+		Metadata:            ptr.Deref(flowLogs.Metadata, ""),
+		ForceSendFields:     []string{"Enable"},
+	}
+	if flowLogs.FlowSampling != nil {
+		// The sampling rate is validated by the webhook.
+		logConfig.FlowSampling, _ = strconv.ParseFloat(*flowLogs.FlowSampling, 64)
+		logConfig.ForceSendFields = append(logConfig.ForceSendFields, "FlowSampling")
+	}
+
+	return logConfig
+}
+
 // ANCHOR: ClusterFirewallSpec
 
 // FirewallRulesSpec returns google compute firewall spec.
@@ -310,6 +533,7 @@ func (s *ClusterScope) AddressSpec(lbname string) *compute.Address {
 		Name:        fmt.Sprintf("%s-%s", s.Name(), lbname),
 		AddressType: "EXTERNAL",
 		IpVersion:   "IPV4",
+		Labels:      s.AdditionalLabels(),
 	}
 }
 
@@ -341,19 +565,26 @@ func (s *ClusterScope) ForwardingRuleSpec(lbname string) *compute.ForwardingRule
 }
 
 // HealthCheckSpec returns google compute health-check spec.
+// The API servers are probed on the instance group named port, the load balancer passes TLS through to them,
+// unless the LoadBalancer HealthCheck says otherwise.
 func (s *ClusterScope) HealthCheckSpec(lbname string) *compute.HealthCheck {
+	hc := s.GCPCluster.Spec.LoadBalancer.HealthCheck
+	if hc == nil {
+		hc = &infrav1.HealthCheck{}
+	}
+	port := ptr.Deref(hc.Port, ptr.Deref(s.GCPCluster.Spec.Network.LoadBalancerBackendPort, 6443))
 	return &compute.HealthCheck{
 		Name: fmt.Sprintf("%s-%s", s.Name(), lbname),
 		Type: "HTTPS",
 		HttpsHealthCheck: &compute.HTTPSHealthCheck{
-			Port:              6443,
+			Port:              int64(port),
 			PortSpecification: "USE_FIXED_PORT",
-			RequestPath:       "/readyz",
+			RequestPath:       ptr.Deref(hc.RequestPath, "/readyz"),
 		},
-		CheckIntervalSec:   10,
-		TimeoutSec:         5,
-		HealthyThreshold:   5,
-		UnhealthyThreshold: 3,
+		CheckIntervalSec:   ptr.Deref(hc.CheckIntervalSec, 10),
+		TimeoutSec:         ptr.Deref(hc.TimeoutSec, 5),
+		HealthyThreshold:   ptr.Deref(hc.HealthyThreshold, 5),
+		UnhealthyThreshold: ptr.Deref(hc.UnhealthyThreshold, 3),
 	}
 }
 
@@ -380,8 +611,66 @@ func (s *ClusterScope) TargetTCPProxySpec() *compute.TargetTcpProxy {
 	}
 }
 
+// PlacementPolicySpec returns google compute resource-policy spec of the control plane compact placement policy.
+func (s *ClusterScope) PlacementPolicySpec() *compute.ResourcePolicy {
+	return &compute.ResourcePolicy{
+		Name:        ptr.Deref(s.ControlPlanePlacementPolicy(), ""),
+		Description: infrav1.ClusterTagKey(s.Name()),
+		Region:      s.Region(),
+		GroupPlacementPolicy: &compute.ResourcePolicyGroupPlacementPolicy{
+			Collocation: "COLLOCATED",
+		},
+	}
+}
+
 // ANCHOR_END: ClusterControlPlaneSpec
 
+// ANCHOR: ClusterNodeServiceAccountSpec
+
+// nodeServiceAccountRoles are the minimal roles granted to the node service account to write logs and metrics
+// and pull images.
+var nodeServiceAccountRoles = []string{
+	"roles/logging.logWriter",
+	"roles/monitoring.metricWriter",
+	"roles/artifactregistry.reader",
+}
+
+// NodeServiceAccountSpec returns the request creating the service account of the worker machines, or nil when
+// NodeServiceAccount is not set.
+func (s *ClusterScope) NodeServiceAccountSpec() *iam.CreateServiceAccountRequest {
+	if s.GCPCluster.Spec.NodeServiceAccount == nil {
+		return nil
+	}
+
+	// The clusters of different namespaces may share the project and their names, which may be too long or have
+	// characters not allowed in the ID.
+	sum := sha256.Sum256([]byte(s.Namespace() + "/" + s.Name()))
+	return &iam.CreateServiceAccountRequest{
+		AccountId: fmt.Sprintf("capg-%s-nodes", hex.EncodeToString(sum[:])[:10]),
+		ServiceAccount: &iam.ServiceAccount{
+			DisplayName: fmt.Sprintf("%s/%s nodes", s.Namespace(), s.Name()),
+			Description: infrav1.ClusterTagKey(s.Name()),
+		},
+	}
+}
+
+// NodeServiceAccountRoles returns the IAM roles granted to the service account of the worker machines on the project.
+func (s *ClusterScope) NodeServiceAccountRoles() []string {
+	if s.GCPCluster.Spec.NodeServiceAccount == nil {
+		return nil
+	}
+
+	roles := append([]string{}, nodeServiceAccountRoles...)
+	for _, role := range s.GCPCluster.Spec.NodeServiceAccount.AdditionalRoles {
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// ANCHOR_END: ClusterNodeServiceAccountSpec
+
 // PatchObject persists the cluster configuration and status.
 func (s *ClusterScope) PatchObject() error {
 	return s.patchHelper.Patch(context.TODO(), s.GCPCluster)
@@ -59,6 +59,19 @@ func getCredentials(ctx context.Context, credentialsRef *infrav1.ObjectReference
 	return parseCredential(credentialData)
 }
 
+// credentialsIdentity identifies the credentials read from the credentialsRef Secret, or the default credentials without
+// one, and the service account impersonated with them, if any.
+func credentialsIdentity(credentialsRef *infrav1.ObjectReference, serviceAccountToImpersonate string) string {
+	identity := "default"
+	if credentialsRef != nil {
+		identity = credentialsRef.Namespace + "/" + credentialsRef.Name
+	}
+	if serviceAccountToImpersonate != "" {
+		identity += "/" + serviceAccountToImpersonate
+	}
+	return identity
+}
+
 func getCredentialDataFromRef(ctx context.Context, credentialsRef *infrav1.ObjectReference, crClient client.Client) ([]byte, error) {
 	secretRefName := types.NamespacedName{
 		Name:      credentialsRef.Name,
@@ -0,0 +1,147 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestGetCredentialsFromRef verifies that the credentials are read from the referenced Secret every time, so that
+// rotating them in the Secret doesn't require restarting the controller.
+func TestGetCredentialsFromRef(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant-credentials", Namespace: "tenant"},
+		Data: map[string][]byte{
+			"credentials": []byte(`{"type": "service_account", "project_id": "tenant-project", "client_email": "old@tenant-project.iam.gserviceaccount.com"}`),
+		},
+	}
+	testClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	credentialsRef := &infrav1.ObjectReference{Name: "tenant-credentials", Namespace: "tenant"}
+
+	credential, err := getCredentials(ctx, credentialsRef, testClient)
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant-project", credential.ProjectID)
+	assert.Equal(t, "old@tenant-project.iam.gserviceaccount.com", credential.ClientEmail)
+
+	secret.Data["credentials"] = []byte(`{"type": "service_account", "project_id": "tenant-project", "client_email": "new@tenant-project.iam.gserviceaccount.com"}`)
+	assert.NoError(t, testClient.Update(ctx, secret))
+	credential, err = getCredentials(ctx, credentialsRef, testClient)
+	assert.NoError(t, err)
+	assert.Equal(t, "new@tenant-project.iam.gserviceaccount.com", credential.ClientEmail)
+
+	delete(secret.Data, "credentials")
+	assert.NoError(t, testClient.Update(ctx, secret))
+	_, err = getCredentials(ctx, credentialsRef, testClient)
+	assert.Error(t, err)
+
+	_, err = getCredentials(ctx, &infrav1.ObjectReference{Name: "missing", Namespace: "tenant"}, testClient)
+	assert.Error(t, err)
+}
+
+// TestCredentialsIdentity verifies that the clusters using different credentials, or impersonating different service
+// accounts, have different identities, so that they don't share the GCP resources cached for the other.
+func TestCredentialsIdentity(t *testing.T) {
+	clusterScope := func(credentialsRef *infrav1.ObjectReference, serviceAccountToImpersonate string) *ClusterScope {
+		return &ClusterScope{GCPCluster: &infrav1.GCPCluster{Spec: infrav1.GCPClusterSpec{
+			CredentialsRef:              credentialsRef,
+			ServiceAccountToImpersonate: serviceAccountToImpersonate,
+		}}}
+	}
+	tenantA := &infrav1.ObjectReference{Name: "credentials", Namespace: "tenant-a"}
+	tenantB := &infrav1.ObjectReference{Name: "credentials", Namespace: "tenant-b"}
+
+	identities := []string{
+		clusterScope(nil, "").CredentialsIdentity(),
+		clusterScope(tenantA, "").CredentialsIdentity(),
+		clusterScope(tenantB, "").CredentialsIdentity(),
+		clusterScope(tenantB, "admin@tenant-b.iam.gserviceaccount.com").CredentialsIdentity(),
+		clusterScope(tenantB, "viewer@tenant-b.iam.gserviceaccount.com").CredentialsIdentity(),
+	}
+	for i := range identities {
+		for j := range i {
+			assert.NotEqual(t, identities[j], identities[i])
+		}
+	}
+	assert.Equal(t, clusterScope(tenantA.DeepCopy(), "").CredentialsIdentity(), identities[1])
+}
+
+// serviceAccountCredentials returns the key file of a service account.
+func serviceAccountCredentials(t *testing.T, email string) []byte {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+	credentials, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "tenant-project",
+		"client_email": email,
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    "https://oauth2.googleapis.com/token",
+	})
+	assert.NoError(t, err)
+	return credentials
+}
+
+// TestImpersonationClientOptions verifies that the clients authenticate as the service account to impersonate rather
+// than with the credentials impersonating it, and that its token source is reused until these credentials rotate.
+func TestImpersonationClientOptions(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant-credentials", Namespace: "tenant"},
+		Data: map[string][]byte{
+			"credentials": serviceAccountCredentials(t, "old@tenant-project.iam.gserviceaccount.com"),
+		},
+	}
+	testClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	credentialsRef := &infrav1.ObjectReference{Name: "tenant-credentials", Namespace: "tenant"}
+	const serviceAccount = "provisioner@target-project.iam.gserviceaccount.com"
+
+	opts, err := defaultClientOptions(ctx, credentialsRef, testClient, "")
+	assert.NoError(t, err)
+	assert.Len(t, opts, 2)
+
+	opts, err = defaultClientOptions(ctx, credentialsRef, testClient, serviceAccount)
+	assert.NoError(t, err)
+	assert.Len(t, opts, 2)
+	assert.NotContains(t, opts, option.WithAuthCredentialsJSON(option.ServiceAccount, secret.Data["credentials"]))
+	impersonation := opts[1]
+
+	opts, err = defaultClientOptions(ctx, credentialsRef, testClient, serviceAccount)
+	assert.NoError(t, err)
+	assert.Equal(t, impersonation, opts[1])
+
+	secret.Data["credentials"] = serviceAccountCredentials(t, "new@tenant-project.iam.gserviceaccount.com")
+	assert.NoError(t, testClient.Update(ctx, secret))
+	opts, err = defaultClientOptions(ctx, credentialsRef, testClient, serviceAccount)
+	assert.NoError(t, err)
+	assert.NotEqual(t, impersonation, opts[1])
+}
@@ -19,15 +19,23 @@ package scope
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"path"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"text/template"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"golang.org/x/mod/semver"
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/storage/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
@@ -35,8 +43,11 @@ import (
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/providerid"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/shared"
+	clusterv1beta1 "sigs.k8s.io/cluster-api/api/core/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	v1beta1conditions "sigs.k8s.io/cluster-api/util/deprecated/v1beta1/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -47,12 +58,20 @@ const (
 	onHostMaintenanceMigrate   = "MIGRATE"
 )
 
+// Defaults of the image lookup.
+const (
+	defaultImageLookupBaseOS = "ubuntu-2204"
+	defaultImageLookupFormat = "cluster-api-{{.BaseOS}}-{{.K8sVersion}}"
+)
+
 // MachineScopeParams defines the input parameters used to create a new MachineScope.
 type MachineScopeParams struct {
 	Client        client.Client
 	ClusterGetter cloud.ClusterGetter
 	Machine       *clusterv1.Machine
 	GCPMachine    *infrav1.GCPMachine
+	// DisableDriftRemediation only reports the drift of the mutable instance fields instead of converging them.
+	DisableDriftRemediation bool
 }
 
 // NewMachineScope creates a new MachineScope from the supplied parameters.
@@ -74,21 +93,23 @@ func NewMachineScope(params MachineScopeParams) (*MachineScope, error) {
 	}
 
 	return &MachineScope{
-		client:        params.Client,
-		Machine:       params.Machine,
-		GCPMachine:    params.GCPMachine,
-		ClusterGetter: params.ClusterGetter,
-		patchHelper:   helper,
+		client:                  params.Client,
+		Machine:                 params.Machine,
+		GCPMachine:              params.GCPMachine,
+		ClusterGetter:           params.ClusterGetter,
+		patchHelper:             helper,
+		disableDriftRemediation: params.DisableDriftRemediation,
 	}, nil
 }
 
 // MachineScope defines a scope defined around a machine and its cluster.
 type MachineScope struct {
-	client        client.Client
-	patchHelper   *patch.Helper
-	ClusterGetter cloud.ClusterGetter
-	Machine       *clusterv1.Machine
-	GCPMachine    *infrav1.GCPMachine
+	client                  client.Client
+	patchHelper             *patch.Helper
+	ClusterGetter           cloud.ClusterGetter
+	Machine                 *clusterv1.Machine
+	GCPMachine              *infrav1.GCPMachine
+	disableDriftRemediation bool
 }
 
 // ANCHOR: MachineGetter
@@ -103,9 +124,62 @@ func (m *MachineScope) NetworkCloud() cloud.Cloud {
 	return m.ClusterGetter.NetworkCloud()
 }
 
-// Zone returns the FailureDomain for the GCPMachine.
+// ComputeService returns the compute service used by the cloud.
+func (m *MachineScope) ComputeService() *compute.Service {
+	return m.ClusterGetter.ComputeService()
+}
+
+// SubnetSpecs returns the subnets managed by the cluster.
+func (m *MachineScope) SubnetSpecs() []*compute.Subnetwork {
+	return m.ClusterGetter.SubnetSpecs()
+}
+
+// PublicIP returns whether the instance gets a public IP, falling back to the cluster default.
+func (m *MachineScope) PublicIP() *bool {
+	if m.GCPMachine.Spec.PublicIP != nil {
+		return m.GCPMachine.Spec.PublicIP
+	}
+	return m.ClusterGetter.MachinePublicIP()
+}
+
+// PrivateGoogleAccess returns whether Private Google Access is enabled on the subnet of the instance, nil when the
+// subnet isn't defined by the cluster network.
+func (m *MachineScope) PrivateGoogleAccess() *bool {
+	var match *compute.Subnetwork
+	for _, subnet := range m.SubnetSpecs() {
+		if subnet.Region != "" && subnet.Region != m.Region() {
+			continue
+		}
+		if m.GCPMachine.Spec.Subnet != nil {
+			if subnet.Name == *m.GCPMachine.Spec.Subnet {
+				return ptr.To(subnet.PrivateIpGoogleAccess)
+			}
+			continue
+		}
+		if match != nil {
+			// GCE can't pick the subnet of the instance without the GCPMachine subnet.
+			return nil
+		}
+		match = subnet
+	}
+	if match == nil || m.GCPMachine.Spec.Subnet != nil {
+		return nil
+	}
+
+	return ptr.To(match.PrivateIpGoogleAccess)
+}
+
+// Region returns the region of the GCPMachine's cluster.
+func (m *MachineScope) Region() string {
+	return m.ClusterGetter.Region()
+}
+
+// Zone returns the FailureDomain for the GCPMachine, falling back to its zone.
 func (m *MachineScope) Zone() string {
 	if m.Machine.Spec.FailureDomain == "" {
+		if m.GCPMachine.Spec.Zone != nil {
+			return *m.GCPMachine.Spec.Zone
+		}
 		fd := m.ClusterGetter.FailureDomains()
 		if len(fd) == 0 {
 			return ""
@@ -116,14 +190,39 @@ func (m *MachineScope) Zone() string {
 	return m.Machine.Spec.FailureDomain
 }
 
+// SetSelectedZone records the zone selected for a GCPMachine without failure domain in its spec.
+func (m *MachineScope) SetSelectedZone(zone string) {
+	m.GCPMachine.Spec.Zone = ptr.To[string](zone)
+}
+
 // Project return the project for the GCPMachine's cluster.
 func (m *MachineScope) Project() string {
 	return m.ClusterGetter.Project()
 }
 
-// Name returns the GCPMachine name.
+// Name returns the name of the instance of the GCPMachine, i.e. the GCPMachine name with the instance name prefix.
 func (m *MachineScope) Name() string {
-	return m.GCPMachine.Name
+	return instanceName(ptr.Deref(m.GCPMachine.Spec.InstanceNamePrefix, ""), m.GCPMachine.Name)
+}
+
+const (
+	// instanceNameMaxLength is the maximum length of GCE instance names.
+	instanceNameMaxLength = 63
+	// instanceNameHashLength is the length of the hash suffixed to truncated instance names.
+	instanceNameHashLength = 8
+)
+
+// instanceName returns the name prefixed with prefix. Names exceeding the maximum length of instance names are
+// truncated and suffixed with a hash of the full name, so that names sharing a long common prefix don't collide.
+func instanceName(prefix, name string) string {
+	name = prefix + name
+	if len(name) <= instanceNameMaxLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(sum[:])[:instanceNameHashLength]
+	return strings.TrimRight(name[:instanceNameMaxLength-len(suffix)-1], "-") + "-" + suffix
 }
 
 // Namespace returns the namespace name.
@@ -186,6 +285,11 @@ func (m *MachineScope) SetProviderID() {
 	m.GCPMachine.Spec.ProviderID = ptr.To[string](providerID.String())
 }
 
+// IsPreemptible returns true if the instance is a preemptible or Spot VM.
+func (m *MachineScope) IsPreemptible() bool {
+	return m.GCPMachine.Spec.Preemptible || ptr.Deref(m.GCPMachine.Spec.ProvisioningModel, infrav1.ProvisioningModelStandard) == infrav1.ProvisioningModelSpot
+}
+
 // GetInstanceStatus returns the GCPMachine instance status.
 func (m *MachineScope) GetInstanceStatus() *infrav1.InstanceStatus {
 	return m.GCPMachine.Status.InstanceStatus
@@ -201,6 +305,11 @@ func (m *MachineScope) SetReady() {
 	m.GCPMachine.Status.Ready = true
 }
 
+// SetNotReady sets the GCPMachine Ready Status to false.
+func (m *MachineScope) SetNotReady() {
+	m.GCPMachine.Status.Ready = false
+}
+
 // SetFailureMessage sets the GCPMachine status failure message.
 func (m *MachineScope) SetFailureMessage(v error) {
 	m.GCPMachine.Status.FailureMessage = ptr.To[string](v.Error())
@@ -219,6 +328,147 @@ func (m *MachineScope) SetAnnotation(key, value string) {
 	m.GCPMachine.Annotations[key] = value
 }
 
+// MarkConditionTrue sets the condition of the GCPMachine to True.
+func (m *MachineScope) MarkConditionTrue(condition clusterv1beta1.ConditionType) {
+	v1beta1conditions.MarkTrue(m.GCPMachine, condition)
+}
+
+// MarkConditionFalse sets the condition of the GCPMachine to False with the reason and the error as message.
+func (m *MachineScope) MarkConditionFalse(condition clusterv1beta1.ConditionType, reason string, err error) {
+	v1beta1conditions.MarkFalse(m.GCPMachine, condition, reason, clusterv1beta1.ConditionSeverityWarning, "%v", err)
+}
+
+// LastAppliedLabels returns the user-declared labels last applied to the instance.
+func (m *MachineScope) LastAppliedLabels() infrav1.Labels {
+	labels := infrav1.Labels{}
+	if value, ok := m.GCPMachine.Annotations[infrav1.LastAppliedLabelsAnnotation]; ok {
+		if err := json.Unmarshal([]byte(value), &labels); err != nil {
+			return infrav1.Labels{}
+		}
+	}
+	return labels
+}
+
+// SetLastAppliedLabels records the user-declared labels applied to the instance.
+func (m *MachineScope) SetLastAppliedLabels(labels infrav1.Labels) {
+	value, err := json.Marshal(labels)
+	if err != nil {
+		return
+	}
+	m.SetAnnotation(infrav1.LastAppliedLabelsAnnotation, string(value))
+}
+
+// LastAppliedMetadataKeys returns the keys of the metadata last applied to the instance.
+func (m *MachineScope) LastAppliedMetadataKeys() []string {
+	var keys []string
+	if value, ok := m.GCPMachine.Annotations[infrav1.LastAppliedMetadataAnnotation]; ok {
+		if err := json.Unmarshal([]byte(value), &keys); err != nil {
+			return nil
+		}
+	}
+	return keys
+}
+
+// SetLastAppliedMetadataKeys records the keys of the metadata applied to the instance.
+func (m *MachineScope) SetLastAppliedMetadataKeys(keys []string) {
+	value, err := json.Marshal(keys)
+	if err != nil {
+		return
+	}
+	m.SetAnnotation(infrav1.LastAppliedMetadataAnnotation, string(value))
+}
+
+// ImageFamily returns the full reference to the image family to resolve the instance image from, or nil if an
+// image is set or the default image family is used.
+func (m *MachineScope) ImageFamily() *string {
+	family := m.GCPMachine.Spec.ImageFamily
+	if m.GCPMachine.Spec.Image != nil || family == nil {
+		return nil
+	}
+	if strings.Contains(*family, "/") {
+		return family
+	}
+	project := ptr.Deref(m.GCPMachine.Spec.ImageFamilyProject, m.ClusterGetter.Project())
+	return ptr.To(path.Join("projects", project, "global", "images", "family", *family))
+}
+
+// ImageLookup returns the project and the filter to look up the instance image with. The project is empty if the
+// image isn't looked up, i.e. an image or an image family is set or no image lookup project is set.
+func (m *MachineScope) ImageLookup() (string, *filter.F, error) {
+	if m.GCPMachine.Spec.Image != nil || m.GCPMachine.Spec.ImageFamily != nil {
+		return "", nil, nil
+	}
+	project := ptr.Deref(m.GCPMachine.Spec.ImageLookupProject, ptr.Deref(m.ClusterGetter.ImageLookupProject(), ""))
+	if project == "" {
+		return "", nil, nil
+	}
+
+	baseOS := ptr.Deref(m.GCPMachine.Spec.ImageLookupBaseOS, ptr.Deref(m.ClusterGetter.ImageLookupBaseOS(), defaultImageLookupBaseOS))
+	format := ptr.Deref(m.GCPMachine.Spec.ImageLookupFormat, ptr.Deref(m.ClusterGetter.ImageLookupFormat(), defaultImageLookupFormat))
+	k8sVersion := strings.ReplaceAll(m.Machine.Spec.Version, ".", "-")
+	tmpl, err := template.New("imageLookupFormat").Parse(format)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to parse image lookup format %q", format)
+	}
+	name := &strings.Builder{}
+	if err := tmpl.Execute(name, struct{ BaseOS, K8sVersion string }{BaseOS: baseOS, K8sVersion: k8sVersion}); err != nil {
+		return "", nil, errors.Wrapf(err, "failed to execute image lookup format %q", format)
+	}
+
+	return project, filter.Regexp("name", regexp.QuoteMeta(name.String())+".*").AndRegexp("labels.k8s-version", k8sVersion), nil
+}
+
+// SetDeletionProtection sets the GCPMachine status deletion protection.
+func (m *MachineScope) SetDeletionProtection(enabled bool) {
+	m.GCPMachine.Status.DeletionProtection = ptr.To(enabled)
+}
+
+// DriftRemediationDisabled returns true if the drift of the mutable instance fields must only be reported.
+func (m *MachineScope) DriftRemediationDisabled() bool {
+	return m.disableDriftRemediation
+}
+
+// SetDrift reports the instance fields that drifted from the GCPMachine spec and weren't converged in the
+// DriftDetected condition, removing the condition when there are none.
+func (m *MachineScope) SetDrift(fields []string) {
+	if len(fields) == 0 {
+		v1beta1conditions.Delete(m.GCPMachine, infrav1.DriftDetectedCondition)
+		return
+	}
+	v1beta1conditions.Set(m.GCPMachine, &clusterv1beta1.Condition{
+		Type:     infrav1.DriftDetectedCondition,
+		Status:   corev1.ConditionTrue,
+		Severity: clusterv1beta1.ConditionSeverityWarning,
+		Reason:   infrav1.InstanceDriftedReason,
+		Message:  fmt.Sprintf("Instance %s drifted from the GCPMachine spec", strings.Join(fields, ", ")),
+	})
+}
+
+// RecordDriftCorrected records an event for an instance field whose drift from the GCPMachine spec was corrected.
+func (m *MachineScope) RecordDriftCorrected(field string) {
+	record.Eventf(m.GCPMachine, "DriftCorrected", "Corrected the drift of instance %s", field)
+}
+
+// GetImage returns the GCPMachine status image.
+func (m *MachineScope) GetImage() *string {
+	return m.GCPMachine.Status.Image
+}
+
+// SetImage sets the GCPMachine status image.
+func (m *MachineScope) SetImage(image string) {
+	m.GCPMachine.Status.Image = ptr.To[string](image)
+}
+
+// SetInstanceID sets the GCPMachine status instance ID, the numeric identifier of the GCE instance.
+func (m *MachineScope) SetInstanceID(id string) {
+	m.GCPMachine.Status.InstanceID = ptr.To[string](id)
+}
+
+// SetZone sets the GCPMachine status zone.
+func (m *MachineScope) SetZone(zone string) {
+	m.GCPMachine.Status.Zone = ptr.To[string](zone)
+}
+
 // SetAddresses sets the addresses field on the GCPMachine.
 func (m *MachineScope) SetAddresses(addressList []corev1.NodeAddress) {
 	m.GCPMachine.Status.Addresses = addressList
@@ -235,8 +485,8 @@ func (m *MachineScope) InstanceImageSpec() *compute.AttachedDisk {
 	sourceImage := path.Join("projects", m.ClusterGetter.Project(), "global", "images", "family", image)
 	if m.GCPMachine.Spec.Image != nil {
 		sourceImage = *m.GCPMachine.Spec.Image
-	} else if m.GCPMachine.Spec.ImageFamily != nil {
-		sourceImage = *m.GCPMachine.Spec.ImageFamily
+	} else if family := m.ImageFamily(); family != nil {
+		sourceImage = *family
 	}
 
 	diskType := infrav1.PdStandardDiskType
@@ -244,33 +494,49 @@ func (m *MachineScope) InstanceImageSpec() *compute.AttachedDisk {
 		diskType = *t
 	}
 
+	rootDiskEncryptionKey := m.GCPMachine.Spec.RootDiskEncryptionKey
+	if rootDiskEncryptionKey == nil {
+		rootDiskEncryptionKey = m.ClusterGetter.DiskEncryptionKey()
+	}
+
 	disk := &compute.AttachedDisk{
 		AutoDelete: true,
 		Boot:       true,
 		InitializeParams: &compute.AttachedDiskInitializeParams{
-			DiskSizeGb:          m.GCPMachine.Spec.RootDeviceSize,
-			DiskType:            path.Join("zones", m.Zone(), "diskTypes", string(diskType)),
-			ResourceManagerTags: shared.ResourceTagConvert(context.TODO(), m.GCPMachine.Spec.ResourceManagerTags),
-			SourceImage:         sourceImage,
-			Labels:              m.ClusterGetter.AdditionalLabels().AddLabels(m.GCPMachine.Spec.AdditionalLabels),
+			DiskSizeGb:            m.GCPMachine.Spec.RootDeviceSize,
+			DiskType:              path.Join("zones", m.Zone(), "diskTypes", string(diskType)),
+			ResourceManagerTags:   shared.ResourceTagConvert(context.TODO(), m.GCPMachine.Spec.ResourceManagerTags),
+			SourceImage:           sourceImage,
+			Labels:                infrav1.Labels{}.AddLabels(m.ClusterGetter.AdditionalLabels()).AddLabels(m.GCPMachine.Spec.AdditionalLabels),
+			ProvisionedIops:       ptr.Deref(m.GCPMachine.Spec.RootDeviceProvisionedIops, 0),
+			ProvisionedThroughput: ptr.Deref(m.GCPMachine.Spec.RootDeviceProvisionedThroughput, 0),
 		},
 	}
 
-	if m.GCPMachine.Spec.RootDiskEncryptionKey != nil {
-		if m.GCPMachine.Spec.RootDiskEncryptionKey.KeyType == infrav1.CustomerManagedKey && m.GCPMachine.Spec.RootDiskEncryptionKey.ManagedKey != nil {
+	if len(m.GCPMachine.Spec.RootDeviceReplicaZones) > 0 {
+		// Regional boot disks are created before the instance, with the name the instance would give its boot disk.
+		disk.InitializeParams.DiskName = m.Name()
+		disk.InitializeParams.DiskType = path.Join("regions", m.Region(), "diskTypes", string(diskType))
+		for _, replicaZone := range m.GCPMachine.Spec.RootDeviceReplicaZones {
+			disk.InitializeParams.ReplicaZones = append(disk.InitializeParams.ReplicaZones, path.Join("zones", replicaZone))
+		}
+	}
+
+	if rootDiskEncryptionKey != nil {
+		if rootDiskEncryptionKey.KeyType == infrav1.CustomerManagedKey && rootDiskEncryptionKey.ManagedKey != nil {
 			disk.DiskEncryptionKey = &compute.CustomerEncryptionKey{
-				KmsKeyName: m.GCPMachine.Spec.RootDiskEncryptionKey.ManagedKey.KMSKeyName,
+				KmsKeyName: rootDiskEncryptionKey.ManagedKey.KMSKeyName,
 			}
-			if m.GCPMachine.Spec.RootDiskEncryptionKey.KMSKeyServiceAccount != nil {
-				disk.DiskEncryptionKey.KmsKeyServiceAccount = *m.GCPMachine.Spec.RootDiskEncryptionKey.KMSKeyServiceAccount
+			if rootDiskEncryptionKey.KMSKeyServiceAccount != nil {
+				disk.DiskEncryptionKey.KmsKeyServiceAccount = *rootDiskEncryptionKey.KMSKeyServiceAccount
 			}
-		} else if m.GCPMachine.Spec.RootDiskEncryptionKey.KeyType == infrav1.CustomerSuppliedKey && m.GCPMachine.Spec.RootDiskEncryptionKey.SuppliedKey != nil {
+		} else if rootDiskEncryptionKey.KeyType == infrav1.CustomerSuppliedKey && rootDiskEncryptionKey.SuppliedKey != nil {
 			disk.DiskEncryptionKey = &compute.CustomerEncryptionKey{
-				RawKey:          string(m.GCPMachine.Spec.RootDiskEncryptionKey.SuppliedKey.RawKey),
-				RsaEncryptedKey: string(m.GCPMachine.Spec.RootDiskEncryptionKey.SuppliedKey.RSAEncryptedKey),
+				RawKey:          string(rootDiskEncryptionKey.SuppliedKey.RawKey),
+				RsaEncryptedKey: string(rootDiskEncryptionKey.SuppliedKey.RSAEncryptedKey),
 			}
-			if m.GCPMachine.Spec.RootDiskEncryptionKey.KMSKeyServiceAccount != nil {
-				disk.DiskEncryptionKey.KmsKeyServiceAccount = *m.GCPMachine.Spec.RootDiskEncryptionKey.KMSKeyServiceAccount
+			if rootDiskEncryptionKey.KMSKeyServiceAccount != nil {
+				disk.DiskEncryptionKey.KmsKeyServiceAccount = *rootDiskEncryptionKey.KMSKeyServiceAccount
 			}
 		}
 	}
@@ -278,20 +544,41 @@ func (m *MachineScope) InstanceImageSpec() *compute.AttachedDisk {
 	return disk
 }
 
+// additionalDiskName returns a deterministic name for an additional persistent disk,
+// so that a disk left behind by a previous failed instance creation can be found again.
+func additionalDiskName(instanceName string, index int, deviceName *string) string {
+	suffix := fmt.Sprintf("disk-%d", index)
+	if deviceName != nil {
+		suffix = *deviceName
+	}
+	// GCE resource names are limited to 63 characters.
+	return limitStringLength(instanceName, 62-len(suffix)) + "-" + suffix
+}
+
 // instanceAdditionalDiskSpec returns compute instance additional attched-disk spec.
-func instanceAdditionalDiskSpec(ctx context.Context, spec []infrav1.AttachedDiskSpec, rootDiskEncryptionKey *infrav1.CustomerEncryptionKey, zone string, resourceManagerTags infrav1.ResourceManagerTags) []*compute.AttachedDisk {
+func instanceAdditionalDiskSpec(ctx context.Context, instanceName string, spec []infrav1.AttachedDiskSpec, zone string, resourceManagerTags infrav1.ResourceManagerTags, labels infrav1.Labels, defaultEncryptionKey *infrav1.CustomerEncryptionKey) []*compute.AttachedDisk {
 	additionalDisks := make([]*compute.AttachedDisk, 0, len(spec))
-	for _, disk := range spec {
+	for i, disk := range spec {
 		additionalDisk := &compute.AttachedDisk{
-			AutoDelete: true,
+			AutoDelete: ptr.Deref(disk.AutoDelete, true),
+			DeviceName: ptr.Deref(disk.DeviceName, ""),
 			InitializeParams: &compute.AttachedDiskInitializeParams{
+				DiskName:            additionalDiskName(instanceName, i, disk.DeviceName),
 				DiskSizeGb:          ptr.Deref(disk.Size, 30),
-				DiskType:            path.Join("zones", zone, "diskTypes", string(*disk.DeviceType)),
+				DiskType:            path.Join("zones", zone, "diskTypes", string(ptr.Deref(disk.DeviceType, infrav1.PdStandardDiskType))),
 				ResourceManagerTags: shared.ResourceTagConvert(ctx, resourceManagerTags),
+				Labels:              infrav1.Labels{}.AddLabels(labels).AddLabels(disk.Labels),
 			},
 		}
+		for _, replicaZone := range disk.ReplicaZones {
+			additionalDisk.InitializeParams.ReplicaZones = append(additionalDisk.InitializeParams.ReplicaZones, path.Join("zones", replicaZone))
+		}
 		if strings.HasSuffix(additionalDisk.InitializeParams.DiskType, string(infrav1.LocalSsdDiskType)) {
 			additionalDisk.Type = "SCRATCH" // Default is PERSISTENT.
+			// Local SSDs are not persistent disks, they can't be named nor outlive the instance.
+			additionalDisk.AutoDelete = true
+			additionalDisk.InitializeParams.DiskName = ""
+			additionalDisk.InitializeParams.Labels = nil
 			// Override the Disk size
 			additionalDisk.InitializeParams.DiskSizeGb = 375
 			// For local SSDs set interface to NVME (instead of default SCSI) which is faster.
@@ -300,21 +587,26 @@ func instanceAdditionalDiskSpec(ctx context.Context, spec []infrav1.AttachedDisk
 			// https://cloud.google.com/compute/docs/disks/local-ssd#choose_an_interface
 			additionalDisk.Interface = "NVME"
 		}
-		if disk.EncryptionKey != nil {
-			if rootDiskEncryptionKey.KeyType == infrav1.CustomerManagedKey && rootDiskEncryptionKey.ManagedKey != nil {
+		encryptionKey := disk.EncryptionKey
+		if encryptionKey == nil && additionalDisk.Type != "SCRATCH" {
+			// Local SSDs are always encrypted with a Google-managed key.
+			encryptionKey = defaultEncryptionKey
+		}
+		if encryptionKey != nil {
+			if encryptionKey.KeyType == infrav1.CustomerManagedKey && encryptionKey.ManagedKey != nil {
 				additionalDisk.DiskEncryptionKey = &compute.CustomerEncryptionKey{
-					KmsKeyName: rootDiskEncryptionKey.ManagedKey.KMSKeyName,
+					KmsKeyName: encryptionKey.ManagedKey.KMSKeyName,
 				}
-				if rootDiskEncryptionKey.KMSKeyServiceAccount != nil {
-					additionalDisk.DiskEncryptionKey.KmsKeyServiceAccount = *rootDiskEncryptionKey.KMSKeyServiceAccount
+				if encryptionKey.KMSKeyServiceAccount != nil {
+					additionalDisk.DiskEncryptionKey.KmsKeyServiceAccount = *encryptionKey.KMSKeyServiceAccount
 				}
-			} else if rootDiskEncryptionKey.KeyType == infrav1.CustomerSuppliedKey && rootDiskEncryptionKey.SuppliedKey != nil {
+			} else if encryptionKey.KeyType == infrav1.CustomerSuppliedKey && encryptionKey.SuppliedKey != nil {
 				additionalDisk.DiskEncryptionKey = &compute.CustomerEncryptionKey{
-					RawKey:          string(rootDiskEncryptionKey.SuppliedKey.RawKey),
-					RsaEncryptedKey: string(rootDiskEncryptionKey.SuppliedKey.RSAEncryptedKey),
+					RawKey:          string(encryptionKey.SuppliedKey.RawKey),
+					RsaEncryptedKey: string(encryptionKey.SuppliedKey.RSAEncryptedKey),
 				}
-				if rootDiskEncryptionKey.KMSKeyServiceAccount != nil {
-					additionalDisk.DiskEncryptionKey.KmsKeyServiceAccount = *rootDiskEncryptionKey.KMSKeyServiceAccount
+				if encryptionKey.KMSKeyServiceAccount != nil {
+					additionalDisk.DiskEncryptionKey.KmsKeyServiceAccount = *encryptionKey.KMSKeyServiceAccount
 				}
 			}
 		}
@@ -325,6 +617,54 @@ func instanceAdditionalDiskSpec(ctx context.Context, spec []infrav1.AttachedDisk
 	return additionalDisks
 }
 
+// instanceLocalSSDSpec returns compute instance local SSD attached-disk spec.
+func instanceLocalSSDSpec(spec []infrav1.LocalSSDSpec, zone string) []*compute.AttachedDisk {
+	var localSSDs []*compute.AttachedDisk
+	for _, ssd := range spec {
+		for range ssd.Count {
+			localSSDs = append(localSSDs, &compute.AttachedDisk{
+				AutoDelete: true,
+				Type:       "SCRATCH",
+				Interface:  string(ptr.Deref(ssd.Interface, infrav1.LocalSSDInterfaceNVME)),
+				InitializeParams: &compute.AttachedDiskInitializeParams{
+					DiskSizeGb: 375,
+					DiskType:   path.Join("zones", zone, "diskTypes", string(infrav1.LocalSsdDiskType)),
+				},
+			})
+		}
+	}
+
+	return localSSDs
+}
+
+// instanceAttachedDiskSpec returns the compute disks referencing the pre-existing disks of the spec.
+// Disks referenced by name are looked up in the zone of the instance.
+func instanceAttachedDiskSpec(spec []infrav1.ExistingDiskSpec, project, zone string) []*compute.AttachedDisk {
+	disks := make([]*compute.AttachedDisk, 0, len(spec))
+	for _, disk := range spec {
+		source := disk.Name
+		if !strings.Contains(source, "/") {
+			source = path.Join("projects", project, "zones", zone, "disks", source)
+		}
+
+		mode := "READ_WRITE"
+		if ptr.Deref(disk.Mode, infrav1.DiskModeReadWrite) == infrav1.DiskModeReadOnly {
+			mode = "READ_ONLY"
+		}
+
+		disks = append(disks, &compute.AttachedDisk{
+			// The disks outlive the instance, they are only detached when it is deleted.
+			AutoDelete: false,
+			Type:       "PERSISTENT",
+			Source:     source,
+			DeviceName: ptr.Deref(disk.DeviceName, ""),
+			Mode:       mode,
+		})
+	}
+
+	return disks
+}
+
 // InstanceNetworkInterfaceSpec returns compute network interface spec.
 func InstanceNetworkInterfaceSpec(cluster cloud.ClusterGetter, publicIP *bool, subnet *string, aliasIPRanges []infrav1.AliasIPRange) *compute.NetworkInterface {
 	networkInterface := &compute.NetworkInterface{
@@ -349,6 +689,56 @@ func InstanceNetworkInterfaceSpec(cluster cloud.ClusterGetter, publicIP *bool, s
 	return networkInterface
 }
 
+// instanceResourcePoliciesSpec returns the resource policies of an instance, names refer to resource policies of the cluster region.
+func instanceResourcePoliciesSpec(cluster cloud.ClusterGetter, policies []string) []string {
+	if len(policies) == 0 {
+		return nil
+	}
+
+	out := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		if !strings.Contains(policy, "/") {
+			policy = path.Join("projects", cluster.Project(), "regions", cluster.Region(), "resourcePolicies", policy)
+		}
+		out = append(out, policy)
+	}
+	return out
+}
+
+// instanceAdditionalNetworkInterfacesSpec returns the additional network interfaces of an instance,
+// interfaces without a NicType use the NicType of the machine.
+func instanceAdditionalNetworkInterfacesSpec(cluster cloud.ClusterGetter, spec []infrav1.NetworkInterfaceSpec, nicType *infrav1.NicType) []*compute.NetworkInterface {
+	networkInterfaces := make([]*compute.NetworkInterface, 0, len(spec))
+	for _, nic := range spec {
+		networkInterface := &compute.NetworkInterface{
+			Network:       nic.Network,
+			NetworkIP:     ptr.Deref(nic.InternalIP, ""),
+			AliasIpRanges: InstanceNetworkInterfaceAliasIPRangesSpec(nic.AliasIPRanges),
+			NicType:       string(ptr.Deref(nic.NicType, ptr.Deref(nicType, ""))),
+		}
+		if !strings.Contains(nic.Network, "/") {
+			networkInterface.Network = path.Join("projects", cluster.NetworkProject(), "global", "networks", nic.Network)
+		}
+		if nic.Subnetwork != nil {
+			networkInterface.Subnetwork = *nic.Subnetwork
+			if !strings.Contains(*nic.Subnetwork, "/") {
+				networkInterface.Subnetwork = path.Join("projects", cluster.NetworkProject(), "regions", cluster.Region(), "subnetworks", *nic.Subnetwork)
+			}
+		}
+		if ptr.Deref(nic.PublicIP, false) {
+			networkInterface.AccessConfigs = []*compute.AccessConfig{
+				{
+					Type: "ONE_TO_ONE_NAT",
+					Name: "External NAT",
+				},
+			}
+		}
+		networkInterfaces = append(networkInterfaces, networkInterface)
+	}
+
+	return networkInterfaces
+}
+
 // InstanceNetworkInterfaceAliasIPRangesSpec returns a slice of Alias IP Range specs.
 func InstanceNetworkInterfaceAliasIPRangesSpec(spec []infrav1.AliasIPRange) []*compute.AliasIpRange {
 	if len(spec) == 0 {
@@ -376,7 +766,13 @@ func instanceServiceAccountsSpec(serviceAccount *infrav1.ServiceAccount) *comput
 
 	if serviceAccount != nil {
 		out.Email = serviceAccount.Email
-		out.Scopes = serviceAccount.Scopes
+		out.Scopes = make([]string, 0, len(serviceAccount.Scopes))
+		for _, scope := range serviceAccount.Scopes {
+			if url, ok := infrav1.ServiceAccountScopeAliases[scope]; ok {
+				scope = url
+			}
+			out.Scopes = append(out.Scopes, scope)
+		}
 	}
 
 	return out
@@ -395,6 +791,78 @@ func InstanceAdditionalMetadataSpec(spec []infrav1.MetadataItem) *compute.Metada
 	return metadata
 }
 
+// instanceNetworkTagsSpec returns the network tags of an instance, made of the machine and cluster additional network
+// tags and the cluster and role tags targeted by the cluster firewall rules, without duplicates.
+func instanceNetworkTagsSpec(clusterName, role string, machineNetworkTags, clusterNetworkTags []string) *compute.Tags {
+	items := slices.Concat(machineNetworkTags, clusterNetworkTags, []string{fmt.Sprintf("%s-%s", clusterName, role), clusterName})
+	tags := &compute.Tags{
+		Items: make([]string, 0, len(items)),
+	}
+	seen := make(map[string]bool, len(items))
+	for _, tag := range items {
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags.Items = append(tags.Items, tag)
+	}
+
+	return tags
+}
+
+// instanceOSLoginMetadataSpec sets the OS Login metadata key from the machine setting, falling back to the cluster one.
+func instanceOSLoginMetadataSpec(metadata *compute.Metadata, key string, machineValue, clusterValue *bool) {
+	enabled := machineValue
+	if enabled == nil {
+		enabled = clusterValue
+	}
+	if enabled == nil {
+		return
+	}
+
+	value := "FALSE"
+	if *enabled {
+		value = "TRUE"
+	}
+	for _, item := range metadata.Items {
+		if item.Key == key {
+			item.Value = ptr.To[string](value)
+			return
+		}
+	}
+	metadata.Items = append(metadata.Items, &compute.MetadataItems{
+		Key:   key,
+		Value: ptr.To[string](value),
+	})
+}
+
+// instanceWorkloadIdentityMetadataSpec advertises the workload identity pool provider of the cluster to the workloads
+// through the instance metadata, replacing the values set in the additional metadata.
+func instanceWorkloadIdentityMetadataSpec(metadata *compute.Metadata, provider string) {
+	if provider == "" {
+		return
+	}
+
+	values := map[string]string{
+		infrav1.WorkloadIdentityProviderMetadataKey: provider,
+		infrav1.WorkloadIdentityAudienceMetadataKey: "//iam.googleapis.com/" + provider,
+	}
+	for _, item := range metadata.Items {
+		if value, ok := values[item.Key]; ok {
+			item.Value = ptr.To(value)
+			delete(values, item.Key)
+		}
+	}
+	for _, key := range []string{infrav1.WorkloadIdentityProviderMetadataKey, infrav1.WorkloadIdentityAudienceMetadataKey} {
+		if value, ok := values[key]; ok {
+			metadata.Items = append(metadata.Items, &compute.MetadataItems{
+				Key:   key,
+				Value: ptr.To(value),
+			})
+		}
+	}
+}
+
 // instanceGuestAcceleratorsSpec returns a slice of Guest Accelerator Config specs.
 func instanceGuestAcceleratorsSpec(guestAccelerators []infrav1.Accelerator) []*compute.AcceleratorConfig {
 	if len(guestAccelerators) == 0 {
@@ -411,6 +879,39 @@ func instanceGuestAcceleratorsSpec(guestAccelerators []infrav1.Accelerator) []*c
 	return accelConfigs
 }
 
+// instanceNodeAffinitiesSpec returns the compute scheduling node affinities.
+func instanceNodeAffinitiesSpec(log logr.Logger, nodeAffinities []infrav1.NodeAffinity) []*compute.SchedulingNodeAffinity {
+	if len(nodeAffinities) == 0 {
+		return nil
+	}
+	affinities := make([]*compute.SchedulingNodeAffinity, 0, len(nodeAffinities))
+	for _, affinity := range nodeAffinities {
+		nodeAffinity := &compute.SchedulingNodeAffinity{
+			Key:    affinity.Key,
+			Values: affinity.Values,
+		}
+		switch affinity.Operator {
+		case infrav1.NodeAffinityOperatorIn:
+			nodeAffinity.Operator = "IN"
+		case infrav1.NodeAffinityOperatorNotIn:
+			nodeAffinity.Operator = "NOT_IN"
+		default:
+			log.Error(errors.New("Invalid value"), "Unknown NodeAffinity Operator value", "Operator", affinity.Operator)
+		}
+		affinities = append(affinities, nodeAffinity)
+	}
+	return affinities
+}
+
+// instanceAcceleratorType returns the accelerator type URL in the given zone.
+// Accelerator types given as a bare name, e.g. nvidia-tesla-t4, are resolved against the zone of the instance.
+func instanceAcceleratorType(acceleratorType, zone string) string {
+	if strings.Contains(acceleratorType, "/") {
+		return acceleratorType
+	}
+	return path.Join("zones", zone, "acceleratorTypes", acceleratorType)
+}
+
 // InstanceSpec returns instance spec.
 func (m *MachineScope) InstanceSpec(log logr.Logger) *compute.Instance {
 	ctx := context.TODO()
@@ -419,13 +920,7 @@ func (m *MachineScope) InstanceSpec(log logr.Logger) *compute.Instance {
 		Name:        m.Name(),
 		Zone:        m.Zone(),
 		MachineType: path.Join("zones", m.Zone(), "machineTypes", m.GCPMachine.Spec.InstanceType),
-		Tags: &compute.Tags{
-			Items: append(
-				m.GCPMachine.Spec.AdditionalNetworkTags,
-				fmt.Sprintf("%s-%s", m.ClusterGetter.Name(), m.Role()),
-				m.ClusterGetter.Name(),
-			),
-		},
+		Tags:        instanceNetworkTagsSpec(m.ClusterGetter.Name(), m.Role(), m.GCPMachine.Spec.AdditionalNetworkTags, m.ClusterGetter.AdditionalNetworkTags()),
 		Params: &compute.InstanceParams{
 			ResourceManagerTags: shared.ResourceTagConvert(context.TODO(), m.ResourceManagerTags()),
 		},
@@ -435,7 +930,7 @@ func (m *MachineScope) InstanceSpec(log logr.Logger) *compute.Instance {
 			Role:        ptr.To[string](m.Role()),
 			//nolint: godox
 			// TODO: Check what needs to be added for the cloud provider label.
-			Additional: m.ClusterGetter.AdditionalLabels().AddLabels(m.GCPMachine.Spec.AdditionalLabels),
+			Additional: infrav1.Labels{}.AddLabels(m.ClusterGetter.AdditionalLabels()).AddLabels(m.GCPMachine.Spec.AdditionalLabels),
 		}),
 		Scheduling: &compute.Scheduling{
 			Preemptible: m.GCPMachine.Spec.Preemptible,
@@ -451,24 +946,61 @@ func (m *MachineScope) InstanceSpec(log logr.Logger) *compute.Instance {
 			log.Error(errors.New("Invalid value"), "Unknown ProvisioningModel value", "Spec.ProvisioningModel", *m.GCPMachine.Spec.ProvisioningModel)
 		}
 	}
+	if m.GCPMachine.Spec.TerminationAction != nil {
+		switch *m.GCPMachine.Spec.TerminationAction {
+		case infrav1.InstanceTerminationActionStop:
+			instance.Scheduling.InstanceTerminationAction = "STOP"
+		case infrav1.InstanceTerminationActionDelete:
+			instance.Scheduling.InstanceTerminationAction = "DELETE"
+		default:
+			log.Error(errors.New("Invalid value"), "Unknown TerminationAction value", "Spec.TerminationAction", *m.GCPMachine.Spec.TerminationAction)
+		}
+	}
+
+	instance.Scheduling.NodeAffinities = instanceNodeAffinitiesSpec(log, m.GCPMachine.Spec.NodeAffinities)
+	instance.ResourcePolicies = instanceResourcePoliciesSpec(m.ClusterGetter, m.GCPMachine.Spec.ResourcePolicies)
+	if policy := m.ClusterGetter.ControlPlanePlacementPolicy(); policy != nil && m.IsControlPlane() {
+		instance.ResourcePolicies = append(instance.ResourcePolicies, instanceResourcePoliciesSpec(m.ClusterGetter, []string{*policy})...)
+	}
 
 	instance.CanIpForward = true
 	if m.GCPMachine.Spec.IPForwarding != nil && *m.GCPMachine.Spec.IPForwarding == infrav1.IPForwardingDisabled {
 		instance.CanIpForward = false
 	}
+	instance.MinCpuPlatform = ptr.Deref(m.GCPMachine.Spec.MinCPUPlatform, "")
+	instance.DeletionProtection = ptr.Deref(m.GCPMachine.Spec.DeletionProtection, false)
+	if features := m.GCPMachine.Spec.AdvancedMachineFeatures; features != nil {
+		instance.AdvancedMachineFeatures = &compute.AdvancedMachineFeatures{
+			EnableNestedVirtualization: ptr.Deref(features.EnableNestedVirtualization, false),
+			ThreadsPerCore:             ptr.Deref(features.ThreadsPerCore, 0),
+			VisibleCoreCount:           ptr.Deref(features.VisibleCoreCount, 0),
+		}
+	}
+	if config := m.GCPMachine.Spec.NetworkPerformanceConfig; config != nil && config.TotalEgressBandwidthTier != nil {
+		instance.NetworkPerformanceConfig = &compute.NetworkPerformanceConfig{
+			TotalEgressBandwidthTier: string(*config.TotalEgressBandwidthTier),
+		}
+	}
 	if m.GCPMachine.Spec.ShieldedInstanceConfig != nil {
 		instance.ShieldedInstanceConfig = &compute.ShieldedInstanceConfig{
 			EnableSecureBoot:          false,
 			EnableVtpm:                true,
 			EnableIntegrityMonitoring: true,
 		}
+		if infrav1.MachineTypeArchitecture(m.GCPMachine.Spec.InstanceType) == infrav1.ArchitectureARM64 {
+			// Arm machine types reject integrity monitoring, don't enable it by default.
+			instance.ShieldedInstanceConfig.EnableIntegrityMonitoring = false
+		}
 		if m.GCPMachine.Spec.ShieldedInstanceConfig.SecureBoot == infrav1.SecureBootPolicyEnabled {
 			instance.ShieldedInstanceConfig.EnableSecureBoot = true
 		}
 		if m.GCPMachine.Spec.ShieldedInstanceConfig.VirtualizedTrustedPlatformModule == infrav1.VirtualizedTrustedPlatformModulePolicyDisabled {
 			instance.ShieldedInstanceConfig.EnableVtpm = false
 		}
-		if m.GCPMachine.Spec.ShieldedInstanceConfig.IntegrityMonitoring == infrav1.IntegrityMonitoringPolicyDisabled {
+		switch m.GCPMachine.Spec.ShieldedInstanceConfig.IntegrityMonitoring {
+		case infrav1.IntegrityMonitoringPolicyEnabled:
+			instance.ShieldedInstanceConfig.EnableIntegrityMonitoring = true
+		case infrav1.IntegrityMonitoringPolicyDisabled:
 			instance.ShieldedInstanceConfig.EnableIntegrityMonitoring = false
 		}
 	}
@@ -484,11 +1016,21 @@ func (m *MachineScope) InstanceSpec(log logr.Logger) *compute.Instance {
 
 		instance.Scheduling.OnHostMaintenance = strings.ToUpper(string(*m.GCPMachine.Spec.OnHostMaintenance))
 	}
+	instance.Scheduling.AutomaticRestart = m.GCPMachine.Spec.AutomaticRestart
+	if instance.Scheduling.Preemptible || instance.Scheduling.ProvisioningModel == "SPOT" {
+		// Preemptible and Spot instances can neither be live migrated nor automatically restarted.
+		instance.Scheduling.AutomaticRestart = ptr.To(false)
+		instance.Scheduling.OnHostMaintenance = onHostMaintenanceTerminate
+	}
 	if m.GCPMachine.Spec.ConfidentialCompute != nil {
 		enabled := *m.GCPMachine.Spec.ConfidentialCompute != infrav1.ConfidentialComputePolicyDisabled
 		instance.ConfidentialInstanceConfig = &compute.ConfidentialInstanceConfig{
 			EnableConfidentialCompute: enabled,
 		}
+		if enabled {
+			// Confidential VMs do not support live migration.
+			instance.Scheduling.OnHostMaintenance = onHostMaintenanceTerminate
+		}
 		switch *m.GCPMachine.Spec.ConfidentialCompute {
 		case infrav1.ConfidentialComputePolicySEV:
 			instance.ConfidentialInstanceConfig.ConfidentialInstanceType = "SEV"
@@ -501,12 +1043,52 @@ func (m *MachineScope) InstanceSpec(log logr.Logger) *compute.Instance {
 	}
 
 	instance.Disks = append(instance.Disks, m.InstanceImageSpec())
-	instance.Disks = append(instance.Disks, instanceAdditionalDiskSpec(ctx, m.GCPMachine.Spec.AdditionalDisks, m.GCPMachine.Spec.RootDiskEncryptionKey, m.Zone(), m.ResourceManagerTags())...)
+	instance.Disks = append(instance.Disks, instanceAdditionalDiskSpec(ctx, m.Name(), m.GCPMachine.Spec.AdditionalDisks, m.Zone(), m.ResourceManagerTags(), infrav1.Labels{}.AddLabels(m.ClusterGetter.AdditionalLabels()).AddLabels(m.GCPMachine.Spec.AdditionalLabels), m.ClusterGetter.DiskEncryptionKey())...)
+	instance.Disks = append(instance.Disks, instanceAttachedDiskSpec(m.GCPMachine.Spec.AttachedDisks, m.Project(), m.Zone())...)
+	instance.Disks = append(instance.Disks, instanceLocalSSDSpec(m.GCPMachine.Spec.LocalSSDs, m.Zone())...)
+	// The persistent disks carry the ownership labels of the instance, so that a disk of the same name which wasn't
+	// created for the machine is never attached to the instance nor deleted with it.
+	ownershipLabels := infrav1.Build(infrav1.BuildParams{
+		ClusterName: m.ClusterGetter.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Role:        ptr.To[string](m.Role()),
+	})
+	for _, disk := range instance.Disks {
+		if disk.InitializeParams != nil && disk.Type != "SCRATCH" {
+			disk.InitializeParams.Labels = infrav1.Labels(disk.InitializeParams.Labels).AddLabels(ownershipLabels)
+		}
+	}
 
 	instance.Metadata = InstanceAdditionalMetadataSpec(m.GCPMachine.Spec.AdditionalMetadata)
-	instance.ServiceAccounts = append(instance.ServiceAccounts, instanceServiceAccountsSpec(m.GCPMachine.Spec.ServiceAccount))
-	instance.NetworkInterfaces = append(instance.NetworkInterfaces, InstanceNetworkInterfaceSpec(m.ClusterGetter, m.GCPMachine.Spec.PublicIP, m.GCPMachine.Spec.Subnet, m.GCPMachine.Spec.AliasIPRanges))
+	if !m.IsWindows() {
+		// OS Login is only supported by Linux images.
+		instanceOSLoginMetadataSpec(instance.Metadata, "enable-oslogin", m.GCPMachine.Spec.EnableOSLogin, m.ClusterGetter.EnableOSLogin())
+		instanceOSLoginMetadataSpec(instance.Metadata, "enable-oslogin-2fa", m.GCPMachine.Spec.EnableOSLogin2FA, m.ClusterGetter.EnableOSLogin2FA())
+	}
+	instanceWorkloadIdentityMetadataSpec(instance.Metadata, m.ClusterGetter.WorkloadIdentityProvider())
+	serviceAccount := m.GCPMachine.Spec.ServiceAccount
+	if email := m.ClusterGetter.NodeServiceAccountEmail(); serviceAccount == nil && email != "" && !m.IsControlPlane() {
+		// The node service account only has the roles required by the worker nodes.
+		serviceAccount = &infrav1.ServiceAccount{Email: email, Scopes: []string{"cloud-platform"}}
+	}
+	instance.ServiceAccounts = append(instance.ServiceAccounts, instanceServiceAccountsSpec(serviceAccount))
+	primaryNetworkInterface := InstanceNetworkInterfaceSpec(m.ClusterGetter, m.PublicIP(), m.GCPMachine.Spec.Subnet, m.GCPMachine.Spec.AliasIPRanges)
+	primaryNetworkInterface.NicType = string(ptr.Deref(m.GCPMachine.Spec.NicType, ""))
+	primaryNetworkInterface.StackType = string(ptr.Deref(m.GCPMachine.Spec.StackType, ""))
+	if ptr.Deref(m.GCPMachine.Spec.Ipv6AccessType, "") == infrav1.Ipv6AccessTypeExternal {
+		primaryNetworkInterface.Ipv6AccessConfigs = []*compute.AccessConfig{
+			{
+				Type: "DIRECT_IPV6",
+				Name: "External IPv6",
+			},
+		}
+	}
+	instance.NetworkInterfaces = append(instance.NetworkInterfaces, primaryNetworkInterface)
+	instance.NetworkInterfaces = append(instance.NetworkInterfaces, instanceAdditionalNetworkInterfacesSpec(m.ClusterGetter, m.GCPMachine.Spec.AdditionalNetworkInterfaces, m.GCPMachine.Spec.NicType)...)
 	instance.GuestAccelerators = instanceGuestAcceleratorsSpec(m.GCPMachine.Spec.GuestAccelerators)
+	for _, accel := range instance.GuestAccelerators {
+		accel.AcceleratorType = instanceAcceleratorType(accel.AcceleratorType, m.Zone())
+	}
 	if len(instance.GuestAccelerators) > 0 {
 		instance.Scheduling.OnHostMaintenance = onHostMaintenanceTerminate
 	}
@@ -514,31 +1096,122 @@ func (m *MachineScope) InstanceSpec(log logr.Logger) *compute.Instance {
 	return instance
 }
 
+// InternalAddressSpec returns the static internal address spec of the instance, or nil if it has none.
+func (m *MachineScope) InternalAddressSpec() *compute.Address {
+	spec := m.GCPMachine.Spec.InternalAddress
+	if spec == nil {
+		return nil
+	}
+
+	address := &compute.Address{
+		Name:        spec.Name,
+		Address:     ptr.Deref(spec.Address, ""),
+		AddressType: "INTERNAL",
+		Description: infrav1.ClusterTagKey(m.ClusterGetter.Name()),
+		Labels: infrav1.Build(infrav1.BuildParams{
+			ClusterName: m.ClusterGetter.Name(),
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Role:        ptr.To[string](m.Role()),
+			Additional:  infrav1.Labels{}.AddLabels(m.ClusterGetter.AdditionalLabels()).AddLabels(m.GCPMachine.Spec.AdditionalLabels),
+		}),
+	}
+	if m.GCPMachine.Spec.Subnet != nil {
+		address.Subnetwork = path.Join("projects", m.ClusterGetter.NetworkProject(), "regions", m.ClusterGetter.Region(), "subnetworks", *m.GCPMachine.Spec.Subnet)
+	}
+
+	return address
+}
+
 // ANCHOR_END: MachineInstanceSpec
 
+// IsWindows returns true if the image of the instance is a Windows Server one.
+func (m *MachineScope) IsWindows() bool {
+	return ptr.Deref(m.GCPMachine.Spec.OSFamily, infrav1.OSFamilyLinux) == infrav1.OSFamilyWindows
+}
+
+// BootstrapDataMetadataKey returns the instance metadata key holding the bootstrap data, either the one set in the spec
+// or the default of the OS family.
+func (m *MachineScope) BootstrapDataMetadataKey() string {
+	if key := ptr.Deref(m.GCPMachine.Spec.BootstrapDataMetadataKey, ""); key != "" {
+		return key
+	}
+	if m.IsWindows() {
+		return infrav1.WindowsBootstrapDataMetadataKey
+	}
+	return infrav1.BootstrapDataMetadataKey
+}
+
+// RebootstrapPolicy returns what happens when the bootstrap data changes before the instance joined the cluster.
+func (m *MachineScope) RebootstrapPolicy() infrav1.RebootstrapPolicy {
+	return ptr.Deref(m.GCPMachine.Spec.RebootstrapPolicy, infrav1.RebootstrapPolicyNever)
+}
+
+// HasNodeRef returns true if the instance of the machine joined the cluster as a node.
+func (m *MachineScope) HasNodeRef() bool {
+	return m.Machine.Status.NodeRef.IsDefined()
+}
+
+// BootstrapDataStorage returns where the bootstrap data of the machine is stored, nil meaning the instance metadata.
+func (m *MachineScope) BootstrapDataStorage() *infrav1.BootstrapDataStorageSpec {
+	return m.ClusterGetter.BootstrapDataStorage()
+}
+
+// StorageService returns the storage service used to upload the bootstrap data of the machine to GCS.
+func (m *MachineScope) StorageService() *storage.Service {
+	return m.ClusterGetter.StorageService()
+}
+
+// BootstrapDataObject returns the gs:// URL of the GCS object the bootstrap data was uploaded to, if any.
+func (m *MachineScope) BootstrapDataObject() string {
+	return ptr.Deref(m.GCPMachine.Status.BootstrapDataObject, "")
+}
+
+// SetBootstrapDataObject sets the gs:// URL of the GCS object the bootstrap data was uploaded to, an empty URL
+// unsets it.
+func (m *MachineScope) SetBootstrapDataObject(url string) {
+	if url == "" {
+		m.GCPMachine.Status.BootstrapDataObject = nil
+		return
+	}
+	m.GCPMachine.Status.BootstrapDataObject = ptr.To(url)
+}
+
 // GetBootstrapData returns the bootstrap data from the secret in the Machine's bootstrap.dataSecretName.
 func (m *MachineScope) GetBootstrapData(ctx context.Context) (string, error) {
 	return GetBootstrapData(ctx, m.client, m.Machine, m.Machine.Spec.Bootstrap)
 }
 
+// GetBootstrapDataWithFormat returns the bootstrap data and its format, e.g. cloud-config or ignition, from the secret in
+// the Machine's bootstrap.dataSecretName.
+func (m *MachineScope) GetBootstrapDataWithFormat(ctx context.Context) (string, string, error) {
+	return GetBootstrapDataWithFormat(ctx, m.client, m.Machine, m.Machine.Spec.Bootstrap)
+}
+
 // GetBootstrapData returns the bootstrap data from the secret in the Machine's bootstrap.dataSecretName.
 func GetBootstrapData(ctx context.Context, client client.Client, parent client.Object, bootstrap clusterv1.Bootstrap) (string, error) {
+	value, _, err := GetBootstrapDataWithFormat(ctx, client, parent, bootstrap)
+	return value, err
+}
+
+// GetBootstrapDataWithFormat returns the bootstrap data and its format from the secret in the Machine's
+// bootstrap.dataSecretName. The format is empty when the bootstrap provider doesn't set it, which means cloud-config.
+func GetBootstrapDataWithFormat(ctx context.Context, client client.Client, parent client.Object, bootstrap clusterv1.Bootstrap) (string, string, error) {
 	if bootstrap.DataSecretName == nil {
-		return "", errors.New("error retrieving bootstrap data: linked Machine's bootstrap.dataSecretName is nil")
+		return "", "", errors.New("error retrieving bootstrap data: linked Machine's bootstrap.dataSecretName is nil")
 	}
 
 	secret := &corev1.Secret{}
 	key := types.NamespacedName{Namespace: parent.GetNamespace(), Name: *bootstrap.DataSecretName}
 	if err := client.Get(ctx, key, secret); err != nil {
-		return "", errors.Wrapf(err, "failed to retrieve bootstrap data secret %s/%s", key.Namespace, key.Name)
+		return "", "", errors.Wrapf(err, "failed to retrieve bootstrap data secret %s/%s", key.Namespace, key.Name)
 	}
 
 	value, ok := secret.Data["value"]
 	if !ok {
-		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
+		return "", "", errors.New("error retrieving bootstrap data: secret value key is missing")
 	}
 
-	return string(value), nil
+	return string(value), string(secret.Data["format"]), nil
 }
 
 // PatchObject persists the cluster configuration and status.
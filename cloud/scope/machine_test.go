@@ -2,9 +2,13 @@ package scope
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/compute/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -65,7 +69,7 @@ func TestMachineLocalSSDDiskType(t *testing.T) {
 	assert.NotNil(t, testMachineScope)
 
 	// Now make sure the local-ssd disk type is detected as SCRATCH.
-	diskSpec := instanceAdditionalDiskSpec(ctx, testGCPMachine.Spec.AdditionalDisks, testGCPMachine.Spec.RootDiskEncryptionKey, testMachineScope.Zone(), testGCPMachine.Spec.ResourceManagerTags)
+	diskSpec := instanceAdditionalDiskSpec(ctx, testGCPMachine.Name, testGCPMachine.Spec.AdditionalDisks, testMachineScope.Zone(), testGCPMachine.Spec.ResourceManagerTags, nil, nil)
 	assert.NotEmpty(t, diskSpec)
 
 	// Get the local-ssd disk now.
@@ -74,6 +78,7 @@ func TestMachineLocalSSDDiskType(t *testing.T) {
 	assert.Equal(t, "SCRATCH", localSSDTest.Type)
 	assert.Equal(t, "NVME", localSSDTest.Interface)
 	assert.Equal(t, int64(375), localSSDTest.InitializeParams.DiskSizeGb)
+	assert.Empty(t, localSSDTest.InitializeParams.DiskName)
 }
 
 // TestInstanceNetworkInterfaceAliasIPRangesSpec tests the InstanceNetworkInterfaceAliasIPRangesSpec function
@@ -152,3 +157,400 @@ func TestInstanceNetworkInterfaceAliasIPRangesSpec(t *testing.T) {
 		assert.Equal(t, "", result[0].SubnetworkRangeName)
 	})
 }
+
+// TestMachineAdditionalDiskSpec verifies the naming and auto-delete behavior of additional persistent disks.
+func TestMachineAdditionalDiskSpec(t *testing.T) {
+	ctx := context.Background()
+	diskSpec := instanceAdditionalDiskSpec(ctx, "my-machine", []infrav1.AttachedDiskSpec{
+		{
+			DeviceName: ptr.To[string]("data"),
+			AutoDelete: ptr.To(false),
+			Labels:     map[string]string{"tier": "data"},
+		},
+		{},
+	}, "us-central1-a", nil, infrav1.Labels{"foo": "bar"}, nil)
+	assert.Len(t, diskSpec, 2)
+
+	assert.Equal(t, map[string]string{"foo": "bar", "tier": "data"}, diskSpec[0].InitializeParams.Labels)
+	assert.Equal(t, map[string]string{"foo": "bar"}, diskSpec[1].InitializeParams.Labels)
+
+	assert.Equal(t, "data", diskSpec[0].DeviceName)
+	assert.Equal(t, "my-machine-data", diskSpec[0].InitializeParams.DiskName)
+	assert.False(t, diskSpec[0].AutoDelete)
+
+	assert.Empty(t, diskSpec[1].DeviceName)
+	assert.Equal(t, "my-machine-disk-1", diskSpec[1].InitializeParams.DiskName)
+	assert.Equal(t, "zones/us-central1-a/diskTypes/pd-standard", diskSpec[1].InitializeParams.DiskType)
+	assert.True(t, diskSpec[1].AutoDelete)
+
+	// Additional disks are encrypted with their own key.
+	diskSpec = instanceAdditionalDiskSpec(ctx, "my-machine", []infrav1.AttachedDiskSpec{
+		{
+			EncryptionKey: &infrav1.CustomerEncryptionKey{
+				KeyType: infrav1.CustomerManagedKey,
+				ManagedKey: &infrav1.ManagedKey{
+					KMSKeyName: "projects/my-project/locations/us-central1/keyRings/my-ring/cryptoKeys/data-key",
+				},
+				KMSKeyServiceAccount: ptr.To[string]("kms@my-project.iam.gserviceaccount.com"),
+			},
+		},
+		{},
+		{
+			DeviceType: ptr.To(infrav1.LocalSsdDiskType),
+		},
+	}, "us-central1-a", nil, nil, &infrav1.CustomerEncryptionKey{
+		KeyType: infrav1.CustomerManagedKey,
+		ManagedKey: &infrav1.ManagedKey{
+			KMSKeyName: "projects/my-project/locations/us-central1/keyRings/my-ring/cryptoKeys/cluster-key",
+		},
+	})
+	assert.Equal(t, "projects/my-project/locations/us-central1/keyRings/my-ring/cryptoKeys/data-key", diskSpec[0].DiskEncryptionKey.KmsKeyName)
+	assert.Equal(t, "kms@my-project.iam.gserviceaccount.com", diskSpec[0].DiskEncryptionKey.KmsKeyServiceAccount)
+	// Disks without their own key fall back to the cluster key, except local SSDs.
+	assert.Equal(t, "projects/my-project/locations/us-central1/keyRings/my-ring/cryptoKeys/cluster-key", diskSpec[1].DiskEncryptionKey.KmsKeyName)
+	assert.Nil(t, diskSpec[2].DiskEncryptionKey)
+
+	// Disk names never exceed the GCE limit of 63 characters.
+	longName := strings.Repeat("a", 63)
+	assert.Len(t, additionalDiskName(longName, 0, ptr.To[string]("data")), 63)
+}
+
+// TestMachineLocalSSDSpec verifies that local SSDs are rendered as scratch disks with the requested interface.
+func TestMachineLocalSSDSpec(t *testing.T) {
+	diskSpec := instanceLocalSSDSpec([]infrav1.LocalSSDSpec{
+		{Count: 2},
+		{Count: 1, Interface: ptr.To(infrav1.LocalSSDInterfaceSCSI)},
+	}, "us-central1-a")
+	assert.Len(t, diskSpec, 3)
+
+	for i, disk := range diskSpec {
+		assert.True(t, disk.AutoDelete)
+		assert.Equal(t, "SCRATCH", disk.Type)
+		assert.Equal(t, "zones/us-central1-a/diskTypes/local-ssd", disk.InitializeParams.DiskType)
+		assert.Equal(t, int64(375), disk.InitializeParams.DiskSizeGb)
+		if i < 2 {
+			assert.Equal(t, "NVME", disk.Interface)
+		} else {
+			assert.Equal(t, "SCSI", disk.Interface)
+		}
+	}
+}
+
+// TestMachineAttachedDiskSpec verifies that attached disks reference the existing disks and are never auto-deleted.
+func TestMachineAttachedDiskSpec(t *testing.T) {
+	diskSpec := instanceAttachedDiskSpec([]infrav1.ExistingDiskSpec{
+		{Name: "data", DeviceName: ptr.To[string]("data")},
+		{Name: "projects/other-project/zones/us-central1-a/disks/shared", Mode: ptr.To(infrav1.DiskModeReadOnly)},
+	}, "my-project", "us-central1-a")
+	assert.Len(t, diskSpec, 2)
+
+	assert.Equal(t, "projects/my-project/zones/us-central1-a/disks/data", diskSpec[0].Source)
+	assert.Equal(t, "data", diskSpec[0].DeviceName)
+	assert.Equal(t, "READ_WRITE", diskSpec[0].Mode)
+	assert.Equal(t, "projects/other-project/zones/us-central1-a/disks/shared", diskSpec[1].Source)
+	assert.Equal(t, "READ_ONLY", diskSpec[1].Mode)
+	for _, disk := range diskSpec {
+		assert.False(t, disk.AutoDelete)
+		assert.Nil(t, disk.InitializeParams)
+	}
+}
+
+// TestMachineAcceleratorType verifies that bare accelerator type names are resolved against the instance zone.
+func TestMachineAcceleratorType(t *testing.T) {
+	assert.Equal(t, "zones/us-central1-a/acceleratorTypes/nvidia-tesla-t4", instanceAcceleratorType("nvidia-tesla-t4", "us-central1-a"))
+	assert.Equal(t, "projects/my-proj/zones/us-central1-c/acceleratorTypes/nvidia-tesla-t4",
+		instanceAcceleratorType("projects/my-proj/zones/us-central1-c/acceleratorTypes/nvidia-tesla-t4", "us-central1-a"))
+}
+
+// TestMachineIsPreemptible verifies that both preemptible and Spot VMs are reported as preemptible.
+func TestMachineIsPreemptible(t *testing.T) {
+	scope := &MachineScope{GCPMachine: &infrav1.GCPMachine{}}
+	assert.False(t, scope.IsPreemptible())
+
+	scope.GCPMachine.Spec.Preemptible = true
+	assert.True(t, scope.IsPreemptible())
+
+	scope.GCPMachine.Spec.Preemptible = false
+	scope.GCPMachine.Spec.ProvisioningModel = ptr.To(infrav1.ProvisioningModelSpot)
+	assert.True(t, scope.IsPreemptible())
+}
+
+// TestMachineName verifies that instance names are prefixed and that names exceeding 63 characters are truncated
+// with a hash suffix keeping them unique.
+func TestMachineName(t *testing.T) {
+	scope := &MachineScope{GCPMachine: &infrav1.GCPMachine{}}
+	scope.GCPMachine.Name = "my-machine"
+	assert.Equal(t, "my-machine", scope.Name())
+
+	scope.GCPMachine.Spec.InstanceNamePrefix = ptr.To("prod-")
+	assert.Equal(t, "prod-my-machine", scope.Name())
+
+	scope.GCPMachine.Spec.InstanceNamePrefix = nil
+	scope.GCPMachine.Name = strings.Repeat("a", 63)
+	assert.Equal(t, strings.Repeat("a", 63), scope.Name())
+
+	scope.GCPMachine.Spec.InstanceNamePrefix = ptr.To("p")
+	name := scope.Name()
+	assert.Len(t, name, 63)
+	assert.Equal(t, "p"+strings.Repeat("a", 53)+"-", name[:55])
+	assert.Regexp(t, "^[a-f0-9]{8}$", name[55:])
+	assert.Equal(t, name, scope.Name())
+
+	// Names only differing past the truncation get different hash suffixes.
+	scope.GCPMachine.Name = strings.Repeat("a", 63) + "b"
+	assert.Len(t, scope.Name(), 63)
+	assert.NotEqual(t, name, scope.Name())
+
+	// Dashes at the truncation don't end up doubled before the hash suffix.
+	scope.GCPMachine.Name = strings.Repeat("a", 52) + "-" + strings.Repeat("b", 10)
+	assert.Regexp(t, "^p"+strings.Repeat("a", 52)+"-[a-f0-9]{8}$", scope.Name())
+}
+
+// TestMachineBootstrapDataMetadataKey verifies that the bootstrap data of Windows machines is set to their startup script.
+func TestMachineBootstrapDataMetadataKey(t *testing.T) {
+	scope := &MachineScope{GCPMachine: &infrav1.GCPMachine{}}
+	assert.Equal(t, "user-data", scope.BootstrapDataMetadataKey())
+
+	scope.GCPMachine.Spec.OSFamily = ptr.To(infrav1.OSFamilyWindows)
+	assert.Equal(t, "windows-startup-script-ps1", scope.BootstrapDataMetadataKey())
+}
+
+// TestMachineOSLoginMetadataSpec verifies that the machine OS Login setting wins over the cluster one.
+func TestMachineOSLoginMetadataSpec(t *testing.T) {
+	metadata := InstanceAdditionalMetadataSpec([]infrav1.MetadataItem{{Key: "enable-oslogin", Value: ptr.To("FALSE")}})
+	instanceOSLoginMetadataSpec(metadata, "enable-oslogin", nil, ptr.To(true))
+	instanceOSLoginMetadataSpec(metadata, "enable-oslogin-2fa", ptr.To(false), ptr.To(true))
+	instanceOSLoginMetadataSpec(metadata, "unset", nil, nil)
+	assert.Len(t, metadata.Items, 2)
+	assert.Equal(t, "enable-oslogin", metadata.Items[0].Key)
+	assert.Equal(t, "TRUE", *metadata.Items[0].Value)
+	assert.Equal(t, "enable-oslogin-2fa", metadata.Items[1].Key)
+	assert.Equal(t, "FALSE", *metadata.Items[1].Value)
+}
+
+// TestMachineWorkloadIdentityMetadataSpec verifies that the workload identity pool provider of the cluster is
+// advertised in the instance metadata, over the additional metadata.
+func TestMachineWorkloadIdentityMetadataSpec(t *testing.T) {
+	metadata := InstanceAdditionalMetadataSpec([]infrav1.MetadataItem{{Key: "workload-identity-provider", Value: ptr.To("foo")}})
+	instanceWorkloadIdentityMetadataSpec(metadata, "")
+	assert.Len(t, metadata.Items, 1)
+
+	provider := "projects/123456789/locations/global/workloadIdentityPools/my-pool/providers/my-cluster"
+	instanceWorkloadIdentityMetadataSpec(metadata, provider)
+	assert.Len(t, metadata.Items, 2)
+	assert.Equal(t, "workload-identity-provider", metadata.Items[0].Key)
+	assert.Equal(t, provider, *metadata.Items[0].Value)
+	assert.Equal(t, "workload-identity-audience", metadata.Items[1].Key)
+	assert.Equal(t, "//iam.googleapis.com/"+provider, *metadata.Items[1].Value)
+}
+
+// TestMachineNetworkTagsSpec verifies that the machine and cluster network tags are deduplicated and the cluster tags preserved.
+func TestMachineNetworkTagsSpec(t *testing.T) {
+	additionalNetworkTags := []string{"web", "my-cluster", "web", "ssh"}
+	tags := instanceNetworkTagsSpec("my-cluster", "node", additionalNetworkTags, []string{"ssh", "monitoring"})
+	assert.Equal(t, []string{"web", "my-cluster", "ssh", "monitoring", "my-cluster-node"}, tags.Items)
+	assert.Equal(t, []string{"web", "my-cluster", "web", "ssh"}, additionalNetworkTags)
+}
+
+// TestMachineAdditionalNetworkInterfacesSpec verifies that network and subnetwork names are expanded in the cluster network project.
+func TestMachineAdditionalNetworkInterfacesSpec(t *testing.T) {
+	cluster := &ClusterScope{
+		GCPCluster: &infrav1.GCPCluster{
+			Spec: infrav1.GCPClusterSpec{Project: "my-proj", Region: "us-central1"},
+		},
+	}
+
+	result := instanceAdditionalNetworkInterfacesSpec(cluster, []infrav1.NetworkInterfaceSpec{
+		{Network: "mgmt", Subnetwork: ptr.To("mgmt-subnet"), InternalIP: ptr.To("10.10.0.5"), PublicIP: ptr.To(true)},
+		{
+			Network:       "projects/appliances/global/networks/appliance",
+			AliasIPRanges: []infrav1.AliasIPRange{{IPCidrRange: "/28"}},
+			NicType:       ptr.To(infrav1.NicTypeVirtioNet),
+		},
+	}, ptr.To(infrav1.NicTypeGVNIC))
+	assert.Len(t, result, 2)
+	assert.Equal(t, "projects/my-proj/global/networks/mgmt", result[0].Network)
+	assert.Equal(t, "projects/my-proj/regions/us-central1/subnetworks/mgmt-subnet", result[0].Subnetwork)
+	assert.Equal(t, "10.10.0.5", result[0].NetworkIP)
+	assert.Len(t, result[0].AccessConfigs, 1)
+	assert.Equal(t, "projects/appliances/global/networks/appliance", result[1].Network)
+	assert.Empty(t, result[1].Subnetwork)
+	assert.Empty(t, result[1].AccessConfigs)
+	assert.Empty(t, result[0].AliasIpRanges)
+	assert.Equal(t, []*compute.AliasIpRange{{IpCidrRange: "/28"}}, result[1].AliasIpRanges)
+	assert.Equal(t, "GVNIC", result[0].NicType)
+	assert.Equal(t, "VIRTIO_NET", result[1].NicType)
+}
+
+// TestMachineResourcePoliciesSpec verifies that resource policy names are expanded in the cluster region and that
+// the control plane placement policy is only set when enabled.
+func TestMachineResourcePoliciesSpec(t *testing.T) {
+	cluster := &ClusterScope{
+		Cluster:    &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+		GCPCluster: &infrav1.GCPCluster{Spec: infrav1.GCPClusterSpec{Project: "my-proj", Region: "us-central1"}},
+	}
+	assert.Nil(t, instanceResourcePoliciesSpec(cluster, nil))
+	assert.Nil(t, cluster.ControlPlanePlacementPolicy())
+
+	result := instanceResourcePoliciesSpec(cluster, []string{"hpc", "projects/other/regions/us-central1/resourcePolicies/shared"})
+	assert.Equal(t, []string{
+		"projects/my-proj/regions/us-central1/resourcePolicies/hpc",
+		"projects/other/regions/us-central1/resourcePolicies/shared",
+	}, result)
+
+	cluster.GCPCluster.Spec.ControlPlaneCompactPlacement = ptr.To(true)
+	assert.Equal(t, "my-cluster-control-plane", *cluster.ControlPlanePlacementPolicy())
+	assert.Equal(t, "COLLOCATED", cluster.PlacementPolicySpec().GroupPlacementPolicy.Collocation)
+}
+
+// TestMachinePublicIP verifies that the machine PublicIP setting wins over the cluster default.
+func TestMachinePublicIP(t *testing.T) {
+	cluster := &ClusterScope{GCPCluster: &infrav1.GCPCluster{}}
+	scope := &MachineScope{ClusterGetter: cluster, GCPMachine: &infrav1.GCPMachine{}}
+	assert.Nil(t, scope.PublicIP())
+
+	cluster.GCPCluster.Spec.MachinePublicIP = ptr.To(true)
+	assert.True(t, *scope.PublicIP())
+
+	scope.GCPMachine.Spec.PublicIP = ptr.To(false)
+	assert.False(t, *scope.PublicIP())
+}
+
+// TestMachinePrivateGoogleAccess verifies that the Private Google Access setting of the machine subnet is found.
+func TestMachinePrivateGoogleAccess(t *testing.T) {
+	cluster := &ClusterScope{
+		Cluster:    &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+		GCPCluster: &infrav1.GCPCluster{Spec: infrav1.GCPClusterSpec{Region: "us-central1"}},
+	}
+	scope := &MachineScope{ClusterGetter: cluster, GCPMachine: &infrav1.GCPMachine{}}
+	assert.Nil(t, scope.PrivateGoogleAccess())
+
+	cluster.GCPCluster.Spec.Network.Subnets = infrav1.Subnets{
+		{Name: "control-plane", PrivateGoogleAccess: ptr.To(true)},
+		{Name: "other-region", Region: "europe-west1"},
+	}
+	assert.True(t, *scope.PrivateGoogleAccess())
+
+	cluster.GCPCluster.Spec.Network.Subnets = append(cluster.GCPCluster.Spec.Network.Subnets, infrav1.SubnetSpec{Name: "workers"})
+	assert.Nil(t, scope.PrivateGoogleAccess())
+
+	scope.GCPMachine.Spec.Subnet = ptr.To("workers")
+	assert.False(t, *scope.PrivateGoogleAccess())
+
+	scope.GCPMachine.Spec.Subnet = ptr.To("unknown")
+	assert.Nil(t, scope.PrivateGoogleAccess())
+}
+
+// TestInstanceServiceAccountsSpec verifies that service account scope aliases are expanded to full URLs.
+func TestInstanceServiceAccountsSpec(t *testing.T) {
+	result := instanceServiceAccountsSpec(nil)
+	assert.Equal(t, "default", result.Email)
+	assert.Equal(t, []string{compute.CloudPlatformScope}, result.Scopes)
+
+	result = instanceServiceAccountsSpec(&infrav1.ServiceAccount{
+		Email:  "workers@my-project.iam.gserviceaccount.com",
+		Scopes: []string{"storage-ro", "https://www.googleapis.com/auth/logging.write"},
+	})
+	assert.Equal(t, "workers@my-project.iam.gserviceaccount.com", result.Email)
+	assert.Equal(t, []string{
+		"https://www.googleapis.com/auth/devstorage.read_only",
+		"https://www.googleapis.com/auth/logging.write",
+	}, result.Scopes)
+}
+
+// TestMachineImageLookup verifies that the image lookup filter is built from the machine and cluster settings.
+func TestMachineImageLookup(t *testing.T) {
+	cluster := &ClusterScope{GCPCluster: &infrav1.GCPCluster{}}
+	scope := &MachineScope{
+		ClusterGetter: cluster,
+		Machine:       &clusterv1.Machine{Spec: clusterv1.MachineSpec{Version: "v1.24.3"}},
+		GCPMachine:    &infrav1.GCPMachine{},
+	}
+
+	project, fl, err := scope.ImageLookup()
+	assert.NoError(t, err)
+	assert.Empty(t, project)
+	assert.Nil(t, fl)
+
+	cluster.GCPCluster.Spec.ImageLookupProject = ptr.To("my-images")
+	project, fl, err = scope.ImageLookup()
+	assert.NoError(t, err)
+	assert.Equal(t, "my-images", project)
+	assert.Equal(t, `(name eq cluster-api-ubuntu-2204-v1-24-3.*) (labels.k8s-version eq v1-24-3)`, fl.String())
+
+	scope.GCPMachine.Spec.ImageLookupBaseOS = ptr.To("rhel-9")
+	scope.GCPMachine.Spec.ImageLookupFormat = ptr.To("capi-{{.BaseOS}}-k8s-{{.K8sVersion}}")
+	_, fl, err = scope.ImageLookup()
+	assert.NoError(t, err)
+	assert.Equal(t, `(name eq capi-rhel-9-k8s-v1-24-3.*) (labels.k8s-version eq v1-24-3)`, fl.String())
+
+	scope.GCPMachine.Spec.ImageFamily = ptr.To("projects/my-images/global/images/family/capi")
+	project, _, err = scope.ImageLookup()
+	assert.NoError(t, err)
+	assert.Empty(t, project)
+}
+
+// TestMachineImageFamily verifies that image family names are resolved against the image family project.
+func TestMachineImageFamily(t *testing.T) {
+	scope := &MachineScope{
+		ClusterGetter: &ClusterScope{GCPCluster: &infrav1.GCPCluster{Spec: infrav1.GCPClusterSpec{Project: "my-project"}}},
+		GCPMachine:    &infrav1.GCPMachine{},
+	}
+	assert.Nil(t, scope.ImageFamily())
+
+	scope.GCPMachine.Spec.ImageFamily = ptr.To("capi-ubuntu-2204")
+	assert.Equal(t, "projects/my-project/global/images/family/capi-ubuntu-2204", ptr.Deref(scope.ImageFamily(), ""))
+
+	scope.GCPMachine.Spec.ImageFamily = ptr.To("ubuntu-2204-lts")
+	scope.GCPMachine.Spec.ImageFamilyProject = ptr.To("ubuntu-os-cloud")
+	assert.Equal(t, "projects/ubuntu-os-cloud/global/images/family/ubuntu-2204-lts", ptr.Deref(scope.ImageFamily(), ""))
+
+	scope.GCPMachine.Spec.ImageFamily = ptr.To("projects/my-images/global/images/family/capi")
+	assert.Equal(t, "projects/my-images/global/images/family/capi", ptr.Deref(scope.ImageFamily(), ""))
+
+	scope.GCPMachine.Spec.Image = ptr.To("projects/my-images/global/images/capi-v1")
+	assert.Nil(t, scope.ImageFamily())
+}
+
+// TestMachineInstanceImageSpecProvisionedPerformance verifies that the provisioned performance of the root volume
+// is passed to the boot disk.
+func TestMachineInstanceImageSpecProvisionedPerformance(t *testing.T) {
+	scope := &MachineScope{
+		ClusterGetter: &ClusterScope{GCPCluster: &infrav1.GCPCluster{}},
+		Machine:       &clusterv1.Machine{Spec: clusterv1.MachineSpec{Version: "v1.24.3", FailureDomain: "us-central1-c"}},
+		GCPMachine: &infrav1.GCPMachine{
+			Spec: infrav1.GCPMachineSpec{
+				RootDeviceSize:                  100,
+				RootDeviceType:                  ptr.To(infrav1.HyperdiskBalancedDiskType),
+				RootDeviceProvisionedIops:       ptr.To[int64](6000),
+				RootDeviceProvisionedThroughput: ptr.To[int64](290),
+			},
+		},
+	}
+
+	disk := scope.InstanceImageSpec()
+	assert.Equal(t, "zones/us-central1-c/diskTypes/hyperdisk-balanced", disk.InitializeParams.DiskType)
+	assert.Equal(t, int64(6000), disk.InitializeParams.ProvisionedIops)
+	assert.Equal(t, int64(290), disk.InitializeParams.ProvisionedThroughput)
+}
+
+// TestMachineInstanceImageSpecReplicaZones verifies that the boot disk is named after the instance and uses regional
+// disk types when the root volume is replicated.
+func TestMachineInstanceImageSpecReplicaZones(t *testing.T) {
+	scope := &MachineScope{
+		ClusterGetter: &ClusterScope{GCPCluster: &infrav1.GCPCluster{Spec: infrav1.GCPClusterSpec{Region: "us-central1"}}},
+		Machine:       &clusterv1.Machine{Spec: clusterv1.MachineSpec{Version: "v1.24.3", FailureDomain: "us-central1-c"}},
+		GCPMachine: &infrav1.GCPMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-machine"},
+			Spec: infrav1.GCPMachineSpec{
+				RootDeviceType:         ptr.To(infrav1.PdBalancedDiskType),
+				RootDeviceReplicaZones: []string{"us-central1-c", "us-central1-f"},
+			},
+		},
+	}
+
+	disk := scope.InstanceImageSpec()
+	assert.Equal(t, "my-machine", disk.InitializeParams.DiskName)
+	assert.Equal(t, "regions/us-central1/diskTypes/pd-balanced", disk.InitializeParams.DiskType)
+	assert.Equal(t, []string{"zones/us-central1-c", "zones/us-central1-f"}, disk.InitializeParams.ReplicaZones)
+}
@@ -20,12 +20,14 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/pkg/errors"
 	"golang.org/x/mod/semver"
 	"google.golang.org/api/compute/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -39,6 +41,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// defaultAutoscalingCPUTarget is the default average CPU utilization, in percent, the autoscaler maintains.
+const defaultAutoscalingCPUTarget = 60
+
 // MachinePoolScope defines a scope defined around a machine and its cluster.
 type MachinePoolScope struct {
 	client                     client.Client
@@ -122,6 +127,9 @@ func (m *MachinePoolScope) getBootstrapData(ctx context.Context) (string, error)
 
 // Zones returns the targeted zones for the machine pool
 func (m *MachinePoolScope) Zones() []string {
+	if distributionPolicy := m.GCPMachinePool.Spec.DistributionPolicy; distributionPolicy != nil {
+		return distributionPolicy.Zones
+	}
 	zones := m.MachinePool.Spec.FailureDomains
 	if len(zones) == 0 {
 		zones = append(zones, m.ClusterGetter.FailureDomains()...)
@@ -129,6 +137,16 @@ func (m *MachinePoolScope) Zones() []string {
 	return zones
 }
 
+// Project returns the project of the GCP resources.
+func (m *MachinePoolScope) Project() string {
+	return m.ClusterGetter.Project()
+}
+
+// ComputeService returns the compute service, for the operations the cloud doesn't support.
+func (m *MachinePoolScope) ComputeService() *compute.Service {
+	return m.ClusterGetter.ComputeService()
+}
+
 // Region returns the region for the GCP resources
 func (m *MachinePoolScope) Region() string {
 	return m.ClusterGetter.Region()
@@ -142,6 +160,7 @@ func (m *MachinePoolScope) PatchObject(ctx context.Context) error {
 		patch.WithOwnedConditions{Conditions: []string{
 			string(expinfrav1.MIGReadyCondition),
 			string(expinfrav1.InstanceTemplateReadyCondition),
+			string(expinfrav1.AutoscalerReadyCondition),
 		}})
 }
 
@@ -158,18 +177,27 @@ func (m *MachinePoolScope) Close() error {
 	return m.PatchObject(context.TODO())
 }
 
-// InstanceGroupManagerResourceName is the name to use for the instanceGroupManager GCP resource
+// InstanceGroupManagerResourceName is the name to use for the instanceGroupManager GCP resource.
+// The instanceGroupManager is zonal when the machine pool targets a single zone without a distribution policy, and
+// regional otherwise.
 func (m *MachinePoolScope) InstanceGroupManagerResourceName() (*meta.Key, error) {
 	igmName := m.ClusterName() + "-" + m.Name()
 
 	zones := m.Zones()
-	if len(zones) != 1 {
-		return nil, fmt.Errorf("instanceGroupManager must be created in a single zone, got %d zones (%s)", len(zones), strings.Join(zones, ","))
+	switch {
+	case len(zones) == 0:
+		return nil, errors.New("must specify at least one zone")
+	case m.regionalInstanceGroupManager():
+		return meta.RegionalKey(igmName, m.Region()), nil
+	default:
+		return meta.ZonalKey(igmName, zones[0]), nil
 	}
-	zone := zones[0]
-	igmKey := meta.ZonalKey(igmName, zone)
+}
 
-	return igmKey, nil
+// regionalInstanceGroupManager reports whether the instanceGroupManager is regional, i.e. spans several zones or has
+// a distribution policy.
+func (m *MachinePoolScope) regionalInstanceGroupManager() bool {
+	return m.GCPMachinePool.Spec.DistributionPolicy != nil || len(m.Zones()) > 1
 }
 
 // InstanceGroupManagerResource is the desired state for the instanceGroupManager GCP resource
@@ -181,22 +209,48 @@ func (m *MachinePoolScope) InstanceGroupManagerResource(instanceTemplate *meta.K
 	if len(zones) == 0 {
 		return nil, errors.New("must specify at least one zone")
 	}
+	for _, zone := range zones {
+		if idx := strings.LastIndex(zone, "-"); idx < 0 || zone[:idx] != m.Region() {
+			return nil, fmt.Errorf("zone %s of the machine pool is not in the region %s of the cluster", zone, m.Region())
+		}
+	}
 
 	replicas := int64(1)
 	if p := m.MachinePool.Spec.Replicas; p != nil {
 		replicas = int64(*p)
 	}
+	if autoscaling := m.GCPMachinePool.Spec.Autoscaling; autoscaling != nil {
+		// The autoscaler sizes the group once created, start it within the autoscaler bounds.
+		replicas = min(max(replicas, int64(autoscaling.MinReplicas)), int64(autoscaling.MaxReplicas))
+	}
+
+	updatePolicy, err := m.instanceGroupManagerUpdatePolicy(len(zones))
+	if err != nil {
+		return nil, err
+	}
 
 	desired := &compute.InstanceGroupManager{
 		BaseInstanceName: baseInstanceName,
 		Description:      "",
 		InstanceTemplate: instanceTemplateSelfLink,
 		TargetSize:       replicas,
+		UpdatePolicy:     updatePolicy,
+		StatefulPolicy:   m.instanceGroupManagerStatefulPolicy(),
 	}
 
-	// DistributionPolicy can only be used if there are multiple zones
-	if len(zones) > 1 {
-		desired.DistributionPolicy = &compute.DistributionPolicy{}
+	// DistributionPolicy can only be used by a regional instanceGroupManager
+	if m.regionalInstanceGroupManager() {
+		targetShape := expinfrav1.DistributionTargetShapeEven
+		if distributionPolicy := m.GCPMachinePool.Spec.DistributionPolicy; distributionPolicy != nil && distributionPolicy.TargetShape != "" {
+			targetShape = distributionPolicy.TargetShape
+		}
+		// GCE only supports the proactive redistribution of the instances for the EVEN target shape.
+		updatePolicy.InstanceRedistributionType = "PROACTIVE"
+		if targetShape != expinfrav1.DistributionTargetShapeEven {
+			updatePolicy.InstanceRedistributionType = "NONE"
+		}
+
+		desired.DistributionPolicy = &compute.DistributionPolicy{TargetShape: string(targetShape)}
 		for _, zone := range zones {
 			zoneSelfLink, err := buildZoneSelfLink(zone)
 			if err != nil {
@@ -217,6 +271,118 @@ func (m *MachinePoolScope) InstanceGroupManagerResource(instanceTemplate *meta.K
 	return desired, nil
 }
 
+// instanceGroupManagerUpdatePolicy returns the policy rolling out the instance template changes by replacing the
+// instances. By default, one instance per zone is created before deleting the old ones, GCE requiring the fixed
+// values of a regional instanceGroupManager to be 0 or at least its number of zones. The instances with stateful
+// disks are recreated instead, one per zone at a time by default, as GCE can't create them above the target size.
+func (m *MachinePoolScope) instanceGroupManagerUpdatePolicy(zoneCount int) (*compute.InstanceGroupManagerUpdatePolicy, error) {
+	replacementMethod := "SUBSTITUTE"
+	maxSurge := intstr.FromInt32(int32(zoneCount)) //nolint:gosec // The number of zones of a region is small.
+	maxUnavailable := intstr.FromInt32(0)
+	if len(m.GCPMachinePool.Spec.StatefulDisks) > 0 {
+		replacementMethod = "RECREATE"
+		maxSurge, maxUnavailable = maxUnavailable, maxSurge
+	}
+	if rollingUpdate := m.GCPMachinePool.Spec.RollingUpdate; rollingUpdate != nil {
+		maxSurge = ptr.Deref(rollingUpdate.MaxSurge, maxSurge)
+		maxUnavailable = ptr.Deref(rollingUpdate.MaxUnavailable, maxUnavailable)
+	}
+
+	surge, err := fixedOrPercent(maxSurge)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid rollingUpdate.maxSurge")
+	}
+	unavailable, err := fixedOrPercent(maxUnavailable)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid rollingUpdate.maxUnavailable")
+	}
+
+	return &compute.InstanceGroupManagerUpdatePolicy{
+		Type:              "PROACTIVE",
+		MinimalAction:     "REPLACE",
+		ReplacementMethod: replacementMethod,
+		MaxSurge:          surge,
+		MaxUnavailable:    unavailable,
+	}, nil
+}
+
+// instanceGroupManagerStatefulPolicy returns the policy preserving the stateful disks of the instances, nil when there
+// are none.
+func (m *MachinePoolScope) instanceGroupManagerStatefulPolicy() *compute.StatefulPolicy {
+	statefulDisks := m.GCPMachinePool.Spec.StatefulDisks
+	if len(statefulDisks) == 0 {
+		return nil
+	}
+
+	disks := make(map[string]compute.StatefulPolicyPreservedStateDiskDevice, len(statefulDisks))
+	for _, disk := range statefulDisks {
+		autoDelete := "ON_PERMANENT_INSTANCE_DELETION"
+		if disk.DeletionPolicy == expinfrav1.StatefulDiskDeletionPolicyRetain {
+			autoDelete = "NEVER"
+		}
+		disks[disk.DeviceName] = compute.StatefulPolicyPreservedStateDiskDevice{AutoDelete: autoDelete}
+	}
+	return &compute.StatefulPolicy{PreservedState: &compute.StatefulPolicyPreservedState{Disks: disks}}
+}
+
+// statefulDisk reports whether the additional disk is preserved by the instanceGroupManager.
+func (m *MachinePoolScope) statefulDisk(deviceName string) bool {
+	for _, disk := range m.GCPMachinePool.Spec.StatefulDisks {
+		if disk.DeviceName == deviceName {
+			return true
+		}
+	}
+	return false
+}
+
+// fixedOrPercent converts a number or a percentage, e.g. "20%", to its GCE representation.
+func fixedOrPercent(value intstr.IntOrString) (*compute.FixedOrPercent, error) {
+	if value.Type == intstr.Int {
+		// The zero value is sent explicitly, as it differs from the GCE default.
+		return &compute.FixedOrPercent{Fixed: int64(value.IntVal), ForceSendFields: []string{"Fixed"}}, nil
+	}
+
+	percent, err := strconv.ParseInt(strings.TrimSuffix(value.StrVal, "%"), 10, 64)
+	if err != nil || !strings.HasSuffix(value.StrVal, "%") || percent < 0 || percent > 100 {
+		return nil, fmt.Errorf("%q is not a number nor a percentage", value.StrVal)
+	}
+	return &compute.FixedOrPercent{Percent: percent, ForceSendFields: []string{"Percent"}}, nil
+}
+
+// ReplicasManagedByAutoscaler reports whether the GCE autoscaler sizes the instanceGroupManager, rather than the
+// replicas of the MachinePool.
+func (m *MachinePoolScope) ReplicasManagedByAutoscaler() bool {
+	return m.GCPMachinePool.Spec.Autoscaling != nil
+}
+
+// AutoscalerResourceName is the name to use for the autoscaler GCP resource, in the zone or region of the
+// instanceGroupManager it scales.
+func (m *MachinePoolScope) AutoscalerResourceName() (*meta.Key, error) {
+	return m.InstanceGroupManagerResourceName()
+}
+
+// AutoscalerResource is the desired state for the autoscaler GCP resource scaling the instanceGroupManager, nil when
+// autoscaling is disabled.
+func (m *MachinePoolScope) AutoscalerResource(instanceGroupManager *compute.InstanceGroupManager) *compute.Autoscaler {
+	autoscaling := m.GCPMachinePool.Spec.Autoscaling
+	if autoscaling == nil {
+		return nil
+	}
+
+	return &compute.Autoscaler{
+		Target: instanceGroupManager.SelfLink,
+		AutoscalingPolicy: &compute.AutoscalingPolicy{
+			MinNumReplicas: int64(autoscaling.MinReplicas),
+			MaxNumReplicas: int64(autoscaling.MaxReplicas),
+			CpuUtilization: &compute.AutoscalingPolicyCpuUtilization{
+				UtilizationTarget: float64(ptr.Deref(autoscaling.CPUTarget, defaultAutoscalingCPUTarget)) / 100,
+			},
+			// The zero minimum is sent explicitly, as it differs from the GCE default.
+			ForceSendFields: []string{"MinNumReplicas"},
+		},
+	}
+}
+
 // buildZoneSelfLink returns a fully-qualified zone link from a user-provided zone
 func buildZoneSelfLink(zone string) (string, error) {
 	tokens := strings.Split(zone, "/")
@@ -257,14 +423,8 @@ func (m *MachinePoolScope) InstanceTemplateResource(ctx context.Context) (*compu
 	}
 
 	instance := &compute.InstanceProperties{
-		MachineType: m.GCPMachinePool.Spec.InstanceType,
-		Tags: &compute.Tags{
-			Items: append(
-				m.GCPMachinePool.Spec.AdditionalNetworkTags,
-				fmt.Sprintf("%s-%s", m.ClusterGetter.Name(), m.Role()),
-				m.ClusterGetter.Name(),
-			),
-		},
+		MachineType:         m.GCPMachinePool.Spec.InstanceType,
+		Tags:                instanceNetworkTagsSpec(m.ClusterGetter.Name(), m.Role(), m.GCPMachinePool.Spec.AdditionalNetworkTags, m.ClusterGetter.AdditionalNetworkTags()),
 		ResourceManagerTags: shared.ResourceTagConvert(ctx, m.ResourceManagerTags()),
 		Labels: infrav1.Build(infrav1.BuildParams{
 			ClusterName: m.ClusterGetter.Name(),
@@ -272,7 +432,7 @@ func (m *MachinePoolScope) InstanceTemplateResource(ctx context.Context) (*compu
 			Role:        ptr.To[string](m.Role()),
 			//nolint: godox
 			// TODO: Check what needs to be added for the cloud provider label.
-			Additional: m.ClusterGetter.AdditionalLabels().AddLabels(m.GCPMachinePool.Spec.AdditionalLabels),
+			Additional: infrav1.Labels{}.AddLabels(m.ClusterGetter.AdditionalLabels()).AddLabels(m.GCPMachinePool.Spec.AdditionalLabels),
 		}),
 		Scheduling: &compute.Scheduling{
 			Preemptible: m.GCPMachinePool.Spec.Preemptible,
@@ -327,6 +487,10 @@ func (m *MachinePoolScope) InstanceTemplateResource(ctx context.Context) (*compu
 		instance.ConfidentialInstanceConfig = &compute.ConfidentialInstanceConfig{
 			EnableConfidentialCompute: enabled,
 		}
+		if enabled {
+			// Confidential VMs do not support live migration.
+			instance.Scheduling.OnHostMaintenance = onHostMaintenanceTerminate
+		}
 		switch *confidentialCompute {
 		case infrav1.ConfidentialComputePolicySEV:
 			instance.ConfidentialInstanceConfig.ConfidentialInstanceType = "SEV"
@@ -341,9 +505,14 @@ func (m *MachinePoolScope) InstanceTemplateResource(ctx context.Context) (*compu
 	instance.Disks = append(instance.Disks, m.InstanceImageSpec(ctx))
 	instance.Disks = append(instance.Disks, m.InstanceAdditionalDiskSpec()...)
 	instance.Metadata = InstanceAdditionalMetadataSpec(m.GCPMachinePool.Spec.AdditionalMetadata)
+	instanceWorkloadIdentityMetadataSpec(instance.Metadata, m.ClusterGetter.WorkloadIdentityProvider())
 	instance.ServiceAccounts = append(instance.ServiceAccounts, instanceServiceAccountsSpec(m.GCPMachinePool.Spec.ServiceAccount))
 	var aliasIPRanges []infrav1.AliasIPRange // Not supported by MachinePool
-	instance.NetworkInterfaces = append(instance.NetworkInterfaces, InstanceNetworkInterfaceSpec(m.ClusterGetter, m.GCPMachinePool.Spec.PublicIP, m.GCPMachinePool.Spec.Subnet, aliasIPRanges))
+	publicIP := m.GCPMachinePool.Spec.PublicIP
+	if publicIP == nil {
+		publicIP = m.ClusterGetter.MachinePublicIP()
+	}
+	instance.NetworkInterfaces = append(instance.NetworkInterfaces, InstanceNetworkInterfaceSpec(m.ClusterGetter, publicIP, m.GCPMachinePool.Spec.Subnet, aliasIPRanges))
 	instance.GuestAccelerators = instanceGuestAcceleratorsSpec(m.GCPMachinePool.Spec.GuestAccelerators)
 	if len(instance.GuestAccelerators) > 0 {
 		instance.Scheduling.OnHostMaintenance = onHostMaintenanceTerminate
@@ -389,7 +558,7 @@ func (m *MachinePoolScope) InstanceImageSpec(ctx context.Context) *compute.Attac
 			DiskType:            string(diskType),
 			ResourceManagerTags: shared.ResourceTagConvert(ctx, spec.ResourceManagerTags),
 			SourceImage:         sourceImage,
-			Labels:              m.ClusterGetter.AdditionalLabels().AddLabels(spec.AdditionalLabels),
+			Labels:              infrav1.Labels{}.AddLabels(m.ClusterGetter.AdditionalLabels()).AddLabels(spec.AdditionalLabels),
 		},
 	}
 
@@ -424,15 +593,24 @@ func (m *MachinePoolScope) InstanceAdditionalDiskSpec() []*compute.AttachedDisk
 		diskType := string(ValueOf(disk.DeviceType))
 
 		additionalDisk := &compute.AttachedDisk{
-			AutoDelete: true,
+			AutoDelete: ptr.Deref(disk.AutoDelete, true),
+			DeviceName: ptr.Deref(disk.DeviceName, ""),
 			InitializeParams: &compute.AttachedDiskInitializeParams{
 				DiskSizeGb:          ptr.Deref(disk.Size, 30),
 				DiskType:            diskType,
 				ResourceManagerTags: shared.ResourceTagConvert(context.TODO(), spec.ResourceManagerTags),
+				Labels:              infrav1.Labels{}.AddLabels(m.ClusterGetter.AdditionalLabels()).AddLabels(spec.AdditionalLabels).AddLabels(disk.Labels),
 			},
 		}
+		if m.statefulDisk(additionalDisk.DeviceName) {
+			// The instanceGroupManager deletes the stateful disks according to their deletion policy.
+			additionalDisk.AutoDelete = false
+		}
 		if strings.HasSuffix(additionalDisk.InitializeParams.DiskType, string(infrav1.LocalSsdDiskType)) {
 			additionalDisk.Type = "SCRATCH" // Default is PERSISTENT.
+			// Local SSDs can't outlive the instance.
+			additionalDisk.AutoDelete = true
+			additionalDisk.InitializeParams.Labels = nil
 			// Override the Disk size
 			additionalDisk.InitializeParams.DiskSizeGb = 375
 			// For local SSDs set interface to NVME (instead of default SCSI) which is faster.
@@ -442,20 +620,20 @@ func (m *MachinePoolScope) InstanceAdditionalDiskSpec() []*compute.AttachedDisk
 			additionalDisk.Interface = "NVME"
 		}
 		if disk.EncryptionKey != nil {
-			if spec.RootDiskEncryptionKey.KeyType == infrav1.CustomerManagedKey && spec.RootDiskEncryptionKey.ManagedKey != nil {
+			if disk.EncryptionKey.KeyType == infrav1.CustomerManagedKey && disk.EncryptionKey.ManagedKey != nil {
 				additionalDisk.DiskEncryptionKey = &compute.CustomerEncryptionKey{
-					KmsKeyName: spec.RootDiskEncryptionKey.ManagedKey.KMSKeyName,
+					KmsKeyName: disk.EncryptionKey.ManagedKey.KMSKeyName,
 				}
-				if spec.RootDiskEncryptionKey.KMSKeyServiceAccount != nil {
-					additionalDisk.DiskEncryptionKey.KmsKeyServiceAccount = *spec.RootDiskEncryptionKey.KMSKeyServiceAccount
+				if disk.EncryptionKey.KMSKeyServiceAccount != nil {
+					additionalDisk.DiskEncryptionKey.KmsKeyServiceAccount = *disk.EncryptionKey.KMSKeyServiceAccount
 				}
-			} else if spec.RootDiskEncryptionKey.KeyType == infrav1.CustomerSuppliedKey && spec.RootDiskEncryptionKey.SuppliedKey != nil {
+			} else if disk.EncryptionKey.KeyType == infrav1.CustomerSuppliedKey && disk.EncryptionKey.SuppliedKey != nil {
 				additionalDisk.DiskEncryptionKey = &compute.CustomerEncryptionKey{
-					RawKey:          string(spec.RootDiskEncryptionKey.SuppliedKey.RawKey),
-					RsaEncryptedKey: string(spec.RootDiskEncryptionKey.SuppliedKey.RSAEncryptedKey),
+					RawKey:          string(disk.EncryptionKey.SuppliedKey.RawKey),
+					RsaEncryptedKey: string(disk.EncryptionKey.SuppliedKey.RSAEncryptedKey),
 				}
-				if spec.RootDiskEncryptionKey.KMSKeyServiceAccount != nil {
-					additionalDisk.DiskEncryptionKey.KmsKeyServiceAccount = *spec.RootDiskEncryptionKey.KMSKeyServiceAccount
+				if disk.EncryptionKey.KMSKeyServiceAccount != nil {
+					additionalDisk.DiskEncryptionKey.KmsKeyServiceAccount = *disk.EncryptionKey.KMSKeyServiceAccount
 				}
 			}
 		}
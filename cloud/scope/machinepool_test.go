@@ -0,0 +1,203 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/compute/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-gcp/exp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+)
+
+func newTestMachinePoolScope(zones []string, rollingUpdate *expinfrav1.MachinePoolRollingUpdate) *MachinePoolScope {
+	return &MachinePoolScope{
+		ClusterGetter: &ClusterScope{
+			Cluster:    &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+			GCPCluster: &infrav1.GCPCluster{Spec: infrav1.GCPClusterSpec{Region: "us-central1"}},
+		},
+		MachinePool: &clusterv1.MachinePool{
+			Spec: clusterv1.MachinePoolSpec{Replicas: ptr.To[int32](3), FailureDomains: zones},
+		},
+		GCPMachinePool: &expinfrav1.GCPMachinePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-pool"},
+			Spec:       expinfrav1.GCPMachinePoolSpec{RollingUpdate: rollingUpdate},
+		},
+	}
+}
+
+func TestMachinePoolInstanceGroupManagerResourceName(t *testing.T) {
+	key, err := newTestMachinePoolScope([]string{"us-central1-a"}, nil).InstanceGroupManagerResourceName()
+	assert.NoError(t, err)
+	assert.Equal(t, meta.ZonalKey("my-cluster-my-pool", "us-central1-a"), key)
+
+	// The instanceGroupManager spanning several zones is regional.
+	key, err = newTestMachinePoolScope([]string{"us-central1-a", "us-central1-b"}, nil).InstanceGroupManagerResourceName()
+	assert.NoError(t, err)
+	assert.Equal(t, meta.RegionalKey("my-cluster-my-pool", "us-central1"), key)
+
+	// The instanceGroupManager with a distribution policy is regional, even with a single zone.
+	s := newTestMachinePoolScope([]string{"us-central1-a"}, nil)
+	s.GCPMachinePool.Spec.DistributionPolicy = &expinfrav1.MachinePoolDistributionPolicy{Zones: []string{"us-central1-b"}}
+	key, err = s.InstanceGroupManagerResourceName()
+	assert.NoError(t, err)
+	assert.Equal(t, meta.RegionalKey("my-cluster-my-pool", "us-central1"), key)
+
+	_, err = newTestMachinePoolScope(nil, nil).InstanceGroupManagerResourceName()
+	assert.Error(t, err)
+}
+
+func TestMachinePoolInstanceGroupManagerResourceDistributionPolicy(t *testing.T) {
+	s := newTestMachinePoolScope([]string{"us-central1-a", "us-central1-b"}, nil)
+	s.GCPMachinePool.Spec.DistributionPolicy = &expinfrav1.MachinePoolDistributionPolicy{
+		Zones:       []string{"us-central1-c"},
+		TargetShape: expinfrav1.DistributionTargetShapeAnySingleZone,
+	}
+
+	// The zones of the distribution policy take precedence over the failure domains.
+	igm, err := s.InstanceGroupManagerResource(meta.RegionalKey("my-pool-", "us-central1"))
+	assert.NoError(t, err)
+	assert.Equal(t, &compute.DistributionPolicy{
+		TargetShape: "ANY_SINGLE_ZONE",
+		Zones:       []*compute.DistributionPolicyZoneConfiguration{{Zone: "zones/us-central1-c"}},
+	}, igm.DistributionPolicy)
+	assert.Equal(t, "NONE", igm.UpdatePolicy.InstanceRedistributionType)
+
+	s.GCPMachinePool.Spec.DistributionPolicy.Zones = []string{"europe-west1-b"}
+	_, err = s.InstanceGroupManagerResource(meta.RegionalKey("my-pool-", "us-central1"))
+	assert.Error(t, err)
+}
+
+func TestMachinePoolInstanceGroupManagerResourceUpdatePolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		zones         []string
+		rollingUpdate *expinfrav1.MachinePoolRollingUpdate
+		want          *compute.InstanceGroupManagerUpdatePolicy
+		wantErr       bool
+	}{
+		{
+			name:  "defaults to one instance per zone",
+			zones: []string{"us-central1-a", "us-central1-b", "us-central1-c"},
+			want: &compute.InstanceGroupManagerUpdatePolicy{
+				Type:                       "PROACTIVE",
+				MinimalAction:              "REPLACE",
+				InstanceRedistributionType: "PROACTIVE",
+				ReplacementMethod:          "SUBSTITUTE",
+				MaxSurge:                   &compute.FixedOrPercent{Fixed: 3, ForceSendFields: []string{"Fixed"}},
+				MaxUnavailable:             &compute.FixedOrPercent{Fixed: 0, ForceSendFields: []string{"Fixed"}},
+			},
+		},
+		{
+			name:  "fixed and percent values",
+			zones: []string{"us-central1-a"},
+			rollingUpdate: &expinfrav1.MachinePoolRollingUpdate{
+				MaxSurge:       ptr.To(intstr.FromString("20%")),
+				MaxUnavailable: ptr.To(intstr.FromInt32(1)),
+			},
+			want: &compute.InstanceGroupManagerUpdatePolicy{
+				Type:              "PROACTIVE",
+				MinimalAction:     "REPLACE",
+				ReplacementMethod: "SUBSTITUTE",
+				MaxSurge:          &compute.FixedOrPercent{Percent: 20, ForceSendFields: []string{"Percent"}},
+				MaxUnavailable:    &compute.FixedOrPercent{Fixed: 1, ForceSendFields: []string{"Fixed"}},
+			},
+		},
+		{
+			name:  "invalid percent",
+			zones: []string{"us-central1-a"},
+			rollingUpdate: &expinfrav1.MachinePoolRollingUpdate{
+				MaxSurge: ptr.To(intstr.FromString("twenty")),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			igm, err := newTestMachinePoolScope(tt.zones, tt.rollingUpdate).InstanceGroupManagerResource(meta.RegionalKey("my-pool-", "us-central1"))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, igm.UpdatePolicy)
+			assert.Equal(t, len(tt.zones) > 1, igm.DistributionPolicy != nil)
+		})
+	}
+}
+
+func TestMachinePoolAutoscalerResource(t *testing.T) {
+	s := newTestMachinePoolScope([]string{"us-central1-a"}, nil)
+	igm := &compute.InstanceGroupManager{SelfLink: "projects/my-proj/zones/us-central1-a/instanceGroupManagers/my-cluster-my-pool"}
+	assert.False(t, s.ReplicasManagedByAutoscaler())
+	assert.Nil(t, s.AutoscalerResource(igm))
+
+	s.GCPMachinePool.Spec.Autoscaling = &expinfrav1.MachinePoolAutoscaling{MinReplicas: 0, MaxReplicas: 2}
+	assert.True(t, s.ReplicasManagedByAutoscaler())
+	assert.Equal(t, &compute.Autoscaler{
+		Target: igm.SelfLink,
+		AutoscalingPolicy: &compute.AutoscalingPolicy{
+			MinNumReplicas:  0,
+			MaxNumReplicas:  2,
+			CpuUtilization:  &compute.AutoscalingPolicyCpuUtilization{UtilizationTarget: 0.6},
+			ForceSendFields: []string{"MinNumReplicas"},
+		},
+	}, s.AutoscalerResource(igm))
+
+	// The instanceGroupManager is created within the autoscaler bounds.
+	desired, err := s.InstanceGroupManagerResource(meta.RegionalKey("my-pool-", "us-central1"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), desired.TargetSize)
+}
+
+func TestMachinePoolInstanceGroupManagerResourceStatefulDisks(t *testing.T) {
+	s := newTestMachinePoolScope([]string{"us-central1-a", "us-central1-b"}, nil)
+	s.GCPMachinePool.Spec.AdditionalDisks = []infrav1.AttachedDiskSpec{
+		{DeviceName: ptr.To("data")},
+		{DeviceName: ptr.To("logs")},
+		{DeviceName: ptr.To("scratch")},
+	}
+	s.GCPMachinePool.Spec.StatefulDisks = []expinfrav1.MachinePoolStatefulDisk{
+		{DeviceName: "data", DeletionPolicy: expinfrav1.StatefulDiskDeletionPolicyRetain},
+		{DeviceName: "logs", DeletionPolicy: expinfrav1.StatefulDiskDeletionPolicyDelete},
+	}
+
+	igm, err := s.InstanceGroupManagerResource(meta.RegionalKey("my-pool-", "us-central1"))
+	assert.NoError(t, err)
+	assert.Equal(t, &compute.StatefulPolicy{PreservedState: &compute.StatefulPolicyPreservedState{
+		Disks: map[string]compute.StatefulPolicyPreservedStateDiskDevice{
+			"data": {AutoDelete: "NEVER"},
+			"logs": {AutoDelete: "ON_PERMANENT_INSTANCE_DELETION"},
+		},
+	}}, igm.StatefulPolicy)
+	// The instances with stateful disks are recreated, one per zone at a time.
+	assert.Equal(t, "RECREATE", igm.UpdatePolicy.ReplacementMethod)
+	assert.Equal(t, &compute.FixedOrPercent{Fixed: 0, ForceSendFields: []string{"Fixed"}}, igm.UpdatePolicy.MaxSurge)
+	assert.Equal(t, &compute.FixedOrPercent{Fixed: 2, ForceSendFields: []string{"Fixed"}}, igm.UpdatePolicy.MaxUnavailable)
+
+	// The instances don't delete their stateful disks.
+	disks := s.InstanceAdditionalDiskSpec()
+	assert.False(t, disks[0].AutoDelete)
+	assert.False(t, disks[1].AutoDelete)
+	assert.True(t, disks[2].AutoDelete)
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/pkg/errors"
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/storage/v1"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud"
@@ -52,7 +53,7 @@ func NewManagedClusterScope(ctx context.Context, params ManagedClusterScopeParam
 	}
 
 	if params.Compute == nil {
-		computeSvc, err := newComputeService(ctx, params.GCPManagedCluster.Spec.CredentialsRef, params.Client, params.GCPManagedCluster.Spec.ServiceEndpoints)
+		computeSvc, err := newComputeService(ctx, params.GCPManagedCluster.Spec.CredentialsRef, params.Client, "", params.GCPManagedCluster.Spec.ServiceEndpoints)
 		if err != nil {
 			return nil, errors.Errorf("failed to create gcp compute client: %v", err)
 		}
@@ -98,6 +99,11 @@ func (s *ManagedClusterScope) NetworkCloud() cloud.Cloud {
 	return newCloud(s.NetworkProject(), s.GCPServices)
 }
 
+// ComputeService returns the compute service used by the cloud, for the operations the cloud doesn't support.
+func (s *ManagedClusterScope) ComputeService() *compute.Service {
+	return s.Compute
+}
+
 // Project returns the current project name.
 func (s *ManagedClusterScope) Project() string {
 	return s.GCPManagedCluster.Spec.Project
@@ -156,11 +162,90 @@ func (s *ManagedClusterScope) AdditionalLabels() infrav1.Labels {
 	return s.GCPManagedCluster.Spec.AdditionalLabels
 }
 
+// AdditionalNetworkTags returns the network tags applied to all the instances of the cluster, which is not supported for managed clusters.
+func (s *ManagedClusterScope) AdditionalNetworkTags() []string {
+	return nil
+}
+
 // LoadBalancer returns the LoadBalancer configuration.
 func (s *ManagedClusterScope) LoadBalancer() infrav1.LoadBalancerSpec {
 	return s.GCPManagedCluster.Spec.LoadBalancer
 }
 
+// DiskEncryptionKey returns the default encryption key of the machine disks, which is not supported for managed clusters.
+func (s *ManagedClusterScope) DiskEncryptionKey() *infrav1.CustomerEncryptionKey {
+	return nil
+}
+
+// MachinePublicIP returns whether the cluster machines get a public IP by default, which is not supported for managed clusters.
+func (s *ManagedClusterScope) MachinePublicIP() *bool {
+	return nil
+}
+
+// EnableOSLogin returns whether OS Login is enabled by default on the cluster machines, which is not supported for managed clusters.
+func (s *ManagedClusterScope) EnableOSLogin() *bool {
+	return nil
+}
+
+// EnableOSLogin2FA returns whether OS Login two-factor authentication is enabled by default on the cluster machines,
+// which is not supported for managed clusters.
+func (s *ManagedClusterScope) EnableOSLogin2FA() *bool {
+	return nil
+}
+
+// ImageLookupProject returns the default project to look up the images of the cluster machines in,
+// which is not supported for managed clusters.
+func (s *ManagedClusterScope) ImageLookupProject() *string {
+	return nil
+}
+
+// ImageLookupBaseOS returns the default base OS of the images looked up for the cluster machines,
+// which is not supported for managed clusters.
+func (s *ManagedClusterScope) ImageLookupBaseOS() *string {
+	return nil
+}
+
+// ImageLookupFormat returns the default name format of the images looked up for the cluster machines,
+// which is not supported for managed clusters.
+func (s *ManagedClusterScope) ImageLookupFormat() *string {
+	return nil
+}
+
+// ControlPlanePlacementPolicy returns the name of the compact placement policy of the control plane machines,
+// which is not supported for managed clusters.
+func (s *ManagedClusterScope) ControlPlanePlacementPolicy() *string {
+	return nil
+}
+
+// NodeServiceAccountEmail returns the email of the service account created for the worker machines of the cluster,
+// which is not supported for managed clusters.
+func (s *ManagedClusterScope) NodeServiceAccountEmail() string {
+	return ""
+}
+
+// BootstrapDataStorage returns where the bootstrap data of the machines is stored, which is always the instance
+// metadata for managed clusters.
+func (s *ManagedClusterScope) BootstrapDataStorage() *infrav1.BootstrapDataStorageSpec {
+	return nil
+}
+
+// WorkloadIdentityProvider returns the workload identity pool provider of the cluster, GKE clusters rely on the GKE
+// Workload Identity instead.
+func (s *ManagedClusterScope) WorkloadIdentityProvider() string {
+	return ""
+}
+
+// CredentialsIdentity identifies the credentials the GCP clients of the cluster are authenticated with.
+func (s *ManagedClusterScope) CredentialsIdentity() string {
+	return credentialsIdentity(s.GCPManagedCluster.Spec.CredentialsRef, "")
+}
+
+// StorageService returns the storage service used to upload the bootstrap data of the machines, which is not used
+// for managed clusters.
+func (s *ManagedClusterScope) StorageService() *storage.Service {
+	return nil
+}
+
 // ResourceManagerTags returns ResourceManagerTags from cluster. The returned value will never be nil.
 func (s *ManagedClusterScope) ResourceManagerTags() infrav1.ResourceManagerTags {
 	if len(s.GCPManagedCluster.Spec.ResourceManagerTags) == 0 {
@@ -205,6 +290,15 @@ func (s *ManagedClusterScope) SetFailureDomains(fd clusterv1beta1.FailureDomains
 	s.GCPManagedCluster.Status.FailureDomains = fd
 }
 
+// MarkConditionTrue does nothing, GCPManagedClusters don't report the state of their resources in conditions.
+func (s *ManagedClusterScope) MarkConditionTrue(clusterv1beta1.ConditionType) {}
+
+// MarkConditionFalse does nothing, GCPManagedClusters don't report the state of their resources in conditions.
+func (s *ManagedClusterScope) MarkConditionFalse(clusterv1beta1.ConditionType, string, error) {}
+
+// DeleteCondition does nothing, GCPManagedClusters don't report the state of their resources in conditions.
+func (s *ManagedClusterScope) DeleteCondition(clusterv1beta1.ConditionType) {}
+
 // SetControlPlaneEndpoint sets cluster control-plane endpoint.
 func (s *ManagedClusterScope) SetControlPlaneEndpoint(endpoint clusterv1.APIEndpoint) {
 	s.GCPManagedCluster.Spec.ControlPlaneEndpoint = clusterv1beta1.APIEndpoint{
@@ -232,18 +326,12 @@ func (s *ManagedClusterScope) NetworkSpec() *compute.Network {
 
 // NatRouterSpec returns google compute nat router spec.
 func (s *ManagedClusterScope) NatRouterSpec() *compute.Router {
-	networkSpec := s.NetworkSpec()
-	return &compute.Router{
-		Name: fmt.Sprintf("%s-%s", networkSpec.Name, "router"),
-		Nats: []*compute.RouterNat{
-			{
-				Name:                          fmt.Sprintf("%s-%s", networkSpec.Name, "nat"),
-				NatIpAllocateOption:           "AUTO_ONLY",
-				SourceSubnetworkIpRangesToNat: "ALL_SUBNETWORKS_ALL_IP_RANGES",
-				MinPortsPerVm:                 s.GCPManagedCluster.Spec.Network.MinPortsPerVM,
-			},
-		},
-	}
+	return natRouterSpec(s.NetworkSpec().Name, s.GCPManagedCluster.Spec.Network)
+}
+
+// NetworkPeeringSpecs returns google compute network peerings spec.
+func (s *ManagedClusterScope) NetworkPeeringSpecs() []*compute.NetworkPeering {
+	return networkPeeringSpecs(s.GCPManagedCluster.Spec.Network)
 }
 
 // ANCHOR_END: ClusterNetworkSpec
@@ -256,7 +344,7 @@ func (s *ManagedClusterScope) SubnetSpecs() []*compute.Subnetwork {
 		for rangeName, secondaryCidrBlock := range subnetwork.SecondaryCidrBlocks {
 			secondaryIPRanges = append(secondaryIPRanges, &compute.SubnetworkSecondaryRange{RangeName: rangeName, IpCidrRange: secondaryCidrBlock})
 		}
-		subnets = append(subnets, &compute.Subnetwork{
+		subnet := &compute.Subnetwork{
 			Name:                  subnetwork.Name,
 			Region:                subnetwork.Region,
 			EnableFlowLogs:        ptr.Deref(subnetwork.EnableFlowLogs, false),
@@ -268,7 +356,12 @@ func (s *ManagedClusterScope) SubnetSpecs() []*compute.Subnetwork {
 			Purpose:               ptr.Deref(subnetwork.Purpose, "PRIVATE_RFC_1918"),
 			Role:                  "ACTIVE",
 			StackType:             subnetwork.StackType,
-		})
+			Ipv6AccessType:        string(ptr.Deref(subnetwork.Ipv6AccessType, "")),
+		}
+		if subnet.LogConfig = subnetLogConfig(subnetwork.FlowLogs); subnet.LogConfig != nil {
+			subnet.EnableFlowLogs = subnet.LogConfig.Enable
+		}
+		subnets = append(subnets, subnet)
 	}
 
 	return subnets
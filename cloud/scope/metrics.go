@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/api/googleapi"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// gcpAPIRequestsTotal counts the GCP API requests, per operation and result.
+	gcpAPIRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "capg_gcp_api_requests_total",
+		Help: "Number of GCP API requests, per operation and result.",
+	}, []string{"operation", "result"})
+
+	// gcpAPIRequestDuration tracks the latency of the GCP API requests, per operation.
+	gcpAPIRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "capg_gcp_api_request_duration_seconds",
+		Help:    "Latency of the GCP API requests, per operation.",
+		Buckets: prometheus.ExponentialBuckets(0.05, 2, 10),
+	}, []string{"operation"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(gcpAPIRequestsTotal, gcpAPIRequestDuration)
+}
+
+// apiOperation returns the name of the operation of a GCP API request, e.g. instances.insert. Only the final poll of
+// a long-running operation is observed, as operations.wait.
+func apiOperation(key *cloud.RateLimitKey) string {
+	if key.Service == "Operations" && key.Operation == "Get" {
+		return "operations.wait"
+	}
+	operation := key.Operation
+	if operation != "" {
+		operation = strings.ToLower(operation[:1]) + operation[1:]
+	}
+	return strings.ToLower(key.Service) + "." + operation
+}
+
+// apiResult returns the result of a GCP API request: success, the HTTP status code of Google API errors, e.g. 429
+// for requests exceeding a rate limit, or error.
+func apiResult(err error) string {
+	if err == nil {
+		return "success"
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return strconv.Itoa(apiErr.Code)
+	}
+	return "error"
+}
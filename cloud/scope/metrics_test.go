@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+)
+
+func TestAPIOperation(t *testing.T) {
+	assert.Equal(t, "instances.insert", apiOperation(&cloud.RateLimitKey{Service: "Instances", Operation: "Insert"}))
+	assert.Equal(t, "instances.setLabels", apiOperation(&cloud.RateLimitKey{Service: "Instances", Operation: "SetLabels"}))
+	assert.Equal(t, "firewalls.list", apiOperation(&cloud.RateLimitKey{Service: "Firewalls", Operation: "List"}))
+	assert.Equal(t, "operations.wait", apiOperation(&cloud.RateLimitKey{Service: "Operations", Operation: "Get"}))
+}
+
+func TestAPIResult(t *testing.T) {
+	assert.Equal(t, "success", apiResult(nil))
+	assert.Equal(t, "429", apiResult(&googleapi.Error{Code: http.StatusTooManyRequests}))
+	assert.Equal(t, "404", apiResult(fmt.Errorf("getting instance: %w", &googleapi.Error{Code: http.StatusNotFound})))
+	assert.Equal(t, "error", apiResult(errors.New("connection reset")))
+}
+
+// TestGCPRateLimiterMetrics verifies that the rate limiter records the result of every request and the latency of
+// the requests it accepted.
+func TestGCPRateLimiterMetrics(t *testing.T) {
+	ctx := context.TODO()
+	rl := &GCPRateLimiter{}
+	requests := gcpAPIRequestsTotal.WithLabelValues("addresses.get", "success")
+	notFound := gcpAPIRequestsTotal.WithLabelValues("addresses.get", "404")
+	before := testutil.CollectAndCount(gcpAPIRequestDuration)
+
+	key := &cloud.RateLimitKey{Service: "Addresses", Operation: "Get"}
+	assert.NoError(t, rl.Accept(ctx, key))
+	rl.Observe(ctx, nil, key)
+	assert.InDelta(t, 1, testutil.ToFloat64(requests), 0)
+	assert.Equal(t, before+1, testutil.CollectAndCount(gcpAPIRequestDuration))
+
+	key = &cloud.RateLimitKey{Service: "Addresses", Operation: "Get"}
+	assert.NoError(t, rl.Accept(ctx, key))
+	rl.Observe(ctx, &googleapi.Error{Code: http.StatusNotFound}, key)
+	assert.InDelta(t, 1, testutil.ToFloat64(notFound), 0)
+
+	// No request is in flight anymore.
+	rl.starts.Range(func(k, _ any) bool {
+		t.Errorf("GCPRateLimiter has a request in flight: %v", k)
+		return true
+	})
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscalers
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/compute/v1"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/gcperrors"
+	"sigs.k8s.io/cluster-api-provider-gcp/pkg/gcp"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Reconcile creates or updates the autoscaler of the instanceGroupManager, or deletes it when autoscaling is disabled.
+func (s *Service) Reconcile(ctx context.Context, instanceGroupManager *compute.InstanceGroupManager) error {
+	desired := s.scope.AutoscalerResource(instanceGroupManager)
+	if desired == nil {
+		return s.Delete(ctx)
+	}
+
+	key, err := s.scope.AutoscalerResourceName()
+	if err != nil {
+		return err
+	}
+	selfLink := gcp.FormatKey("autoscalers", key)
+	log := log.FromContext(ctx).WithValues("autoscaler", selfLink)
+	log.Info("Reconciling autoscaler resources")
+
+	autoscalers := s.autoscalersFor(key)
+	actual, err := autoscalers.Get(ctx, key)
+	if err != nil {
+		if !gcperrors.IsNotFound(err) {
+			return fmt.Errorf("getting autoscaler %v: %w", selfLink, err)
+		}
+
+		log.V(2).Info("Creating autoscaler")
+		if err := autoscalers.Insert(ctx, key, desired); err != nil {
+			return fmt.Errorf("creating autoscaler %v: %w", selfLink, err)
+		}
+		return nil
+	}
+
+	if !autoscalingPolicyEqual(desired.AutoscalingPolicy, actual.AutoscalingPolicy) {
+		log.V(2).Info("Updating autoscaler policy")
+		if err := autoscalers.Update(ctx, key, desired); err != nil {
+			return fmt.Errorf("updating autoscaler %v: %w", selfLink, err)
+		}
+	}
+
+	return nil
+}
+
+// Delete deletes the autoscaler, it must be deleted before the instanceGroupManager it scales.
+func (s *Service) Delete(ctx context.Context) error {
+	key, err := s.scope.AutoscalerResourceName()
+	if err != nil {
+		return err
+	}
+	selfLink := gcp.FormatKey("autoscalers", key)
+	log := log.FromContext(ctx).WithValues("autoscaler", selfLink)
+
+	autoscalers := s.autoscalersFor(key)
+	if _, err := autoscalers.Get(ctx, key); err != nil {
+		if gcperrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("getting autoscaler %v: %w", selfLink, err)
+	}
+
+	log.Info("Deleting autoscaler")
+	if err := autoscalers.Delete(ctx, key); err != nil && !gcperrors.IsNotFound(err) {
+		return fmt.Errorf("deleting autoscaler %v: %w", selfLink, err)
+	}
+
+	return nil
+}
+
+// autoscalingPolicyEqual reports whether the autoscaling policies have the same bounds and CPU utilization target.
+func autoscalingPolicyEqual(desired, actual *compute.AutoscalingPolicy) bool {
+	if actual == nil || actual.CpuUtilization == nil {
+		return false
+	}
+	return desired.MinNumReplicas == actual.MinNumReplicas &&
+		desired.MaxNumReplicas == actual.MaxNumReplicas &&
+		desired.CpuUtilization.UtilizationTarget == actual.CpuUtilization.UtilizationTarget
+}
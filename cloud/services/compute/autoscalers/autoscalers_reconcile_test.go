@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscalers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+type testScope struct {
+	key     *meta.Key
+	desired *compute.Autoscaler
+}
+
+func (s *testScope) ComputeService() *compute.Service           { return nil }
+func (s *testScope) Project() string                            { return "my-proj" }
+func (s *testScope) AutoscalerResourceName() (*meta.Key, error) { return s.key, nil }
+func (s *testScope) AutoscalerResource(_ *compute.InstanceGroupManager) *compute.Autoscaler {
+	if s.desired == nil {
+		return nil
+	}
+	desired := *s.desired
+	return &desired
+}
+
+// fakeAutoscalers records the calls on the autoscalers.
+type fakeAutoscalers struct {
+	autoscalers map[meta.Key]*compute.Autoscaler
+	calls       []string
+}
+
+func (f *fakeAutoscalers) Get(_ context.Context, key *meta.Key) (*compute.Autoscaler, error) {
+	autoscaler, ok := f.autoscalers[*key]
+	if !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound}
+	}
+	return autoscaler, nil
+}
+
+func (f *fakeAutoscalers) Insert(_ context.Context, key *meta.Key, obj *compute.Autoscaler) error {
+	f.calls = append(f.calls, "Insert")
+	f.autoscalers[*key] = obj
+	return nil
+}
+
+func (f *fakeAutoscalers) Update(_ context.Context, key *meta.Key, obj *compute.Autoscaler) error {
+	f.calls = append(f.calls, "Update")
+	f.autoscalers[*key] = obj
+	return nil
+}
+
+func (f *fakeAutoscalers) Delete(_ context.Context, key *meta.Key) error {
+	f.calls = append(f.calls, "Delete")
+	delete(f.autoscalers, *key)
+	return nil
+}
+
+func newDesiredAutoscaler(minReplicas, maxReplicas int64) *compute.Autoscaler {
+	return &compute.Autoscaler{
+		Target: "projects/my-proj/zones/us-central1-a/instanceGroupManagers/my-cluster-my-pool",
+		AutoscalingPolicy: &compute.AutoscalingPolicy{
+			MinNumReplicas: minReplicas,
+			MaxNumReplicas: maxReplicas,
+			CpuUtilization: &compute.AutoscalingPolicyCpuUtilization{UtilizationTarget: 0.6},
+		},
+	}
+}
+
+func TestService_Reconcile(t *testing.T) {
+	g := NewWithT(t)
+	key := meta.ZonalKey("my-cluster-my-pool", "us-central1-a")
+	zonal := &fakeAutoscalers{autoscalers: map[meta.Key]*compute.Autoscaler{}}
+	regional := &fakeAutoscalers{autoscalers: map[meta.Key]*compute.Autoscaler{}}
+	scope := &testScope{key: key, desired: newDesiredAutoscaler(1, 5)}
+	s := &Service{scope: scope, autoscalers: zonal, regionAutoscalers: regional}
+
+	g.Expect(s.Reconcile(context.TODO(), &compute.InstanceGroupManager{})).To(Succeed())
+	g.Expect(zonal.calls).To(Equal([]string{"Insert"}))
+
+	// The autoscaler isn't updated while its policy doesn't change.
+	g.Expect(s.Reconcile(context.TODO(), &compute.InstanceGroupManager{})).To(Succeed())
+	g.Expect(zonal.calls).To(Equal([]string{"Insert"}))
+
+	scope.desired = newDesiredAutoscaler(1, 10)
+	g.Expect(s.Reconcile(context.TODO(), &compute.InstanceGroupManager{})).To(Succeed())
+	g.Expect(zonal.calls).To(Equal([]string{"Insert", "Update"}))
+	g.Expect(zonal.autoscalers[*key].AutoscalingPolicy.MaxNumReplicas).To(Equal(int64(10)))
+
+	// The autoscaler is deleted once autoscaling is disabled.
+	scope.desired = nil
+	g.Expect(s.Reconcile(context.TODO(), &compute.InstanceGroupManager{})).To(Succeed())
+	g.Expect(zonal.calls).To(Equal([]string{"Insert", "Update", "Delete"}))
+	g.Expect(zonal.autoscalers).To(BeEmpty())
+	g.Expect(regional.calls).To(BeEmpty())
+}
+
+func TestService_Delete(t *testing.T) {
+	g := NewWithT(t)
+	key := meta.RegionalKey("my-cluster-my-pool", "us-central1")
+	zonal := &fakeAutoscalers{autoscalers: map[meta.Key]*compute.Autoscaler{}}
+	regional := &fakeAutoscalers{autoscalers: map[meta.Key]*compute.Autoscaler{*key: newDesiredAutoscaler(1, 5)}}
+	s := &Service{scope: &testScope{key: key}, autoscalers: zonal, regionAutoscalers: regional}
+
+	g.Expect(s.Delete(context.TODO())).To(Succeed())
+	g.Expect(regional.calls).To(Equal([]string{"Delete"}))
+
+	// Deleting an autoscaler that doesn't exist does nothing.
+	g.Expect(s.Delete(context.TODO())).To(Succeed())
+	g.Expect(regional.calls).To(Equal([]string{"Delete"}))
+	g.Expect(zonal.calls).To(BeEmpty())
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package autoscalers implements reconciliation for the autoscaler GCP resources of the managed instance groups.
+package autoscalers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	compute "google.golang.org/api/compute/v1"
+
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/shared"
+)
+
+type autoscalersClient interface {
+	Get(ctx context.Context, key *meta.Key) (*compute.Autoscaler, error)
+	Insert(ctx context.Context, key *meta.Key, obj *compute.Autoscaler) error
+	Update(ctx context.Context, key *meta.Key, obj *compute.Autoscaler) error
+	Delete(ctx context.Context, key *meta.Key) error
+}
+
+// Scope is an interfaces that hold used methods.
+type Scope interface {
+	// ComputeService returns the compute service, the cloud doesn't support the autoscalers.
+	ComputeService() *compute.Service
+	// Project returns the project of the autoscaler.
+	Project() string
+	// AutoscalerResourceName returns the autoscaler key, zonal or regional as the instanceGroupManager it scales.
+	AutoscalerResourceName() (*meta.Key, error)
+	// AutoscalerResource returns the desired autoscaler of the instanceGroupManager, nil when autoscaling is disabled.
+	AutoscalerResource(instanceGroupManager *compute.InstanceGroupManager) *compute.Autoscaler
+}
+
+// Service implements the autoscalers reconciler.
+type Service struct {
+	scope             Scope
+	autoscalers       autoscalersClient
+	regionAutoscalers autoscalersClient
+}
+
+// New returns Service from given scope.
+func New(scope Scope) *Service {
+	return &Service{
+		scope:             scope,
+		autoscalers:       &zonalAutoscalers{service: scope.ComputeService(), project: scope.Project()},
+		regionAutoscalers: &regionAutoscalers{service: scope.ComputeService(), project: scope.Project()},
+	}
+}
+
+// autoscalersFor returns the client of the zonal or regional autoscaler.
+func (s *Service) autoscalersFor(key *meta.Key) autoscalersClient {
+	if key.Type() == meta.Regional {
+		return s.regionAutoscalers
+	}
+	return s.autoscalers
+}
+
+// zonalAutoscalers implements the zonal autoscaler operations through the compute service, as the cloud doesn't
+// support autoscalers.
+type zonalAutoscalers struct {
+	service *compute.Service
+	project string
+}
+
+// Get returns the autoscaler.
+func (c *zonalAutoscalers) Get(ctx context.Context, key *meta.Key) (*compute.Autoscaler, error) {
+	return c.service.Autoscalers.Get(c.project, key.Zone, key.Name).Context(ctx).Do()
+}
+
+// Insert creates the autoscaler and waits for the operation to complete.
+func (c *zonalAutoscalers) Insert(ctx context.Context, key *meta.Key, obj *compute.Autoscaler) error {
+	obj.Name = key.Name
+	op, err := c.service.Autoscalers.Insert(c.project, key.Zone, obj).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// Update updates the autoscaler and waits for the operation to complete.
+func (c *zonalAutoscalers) Update(ctx context.Context, key *meta.Key, obj *compute.Autoscaler) error {
+	obj.Name = key.Name
+	op, err := c.service.Autoscalers.Update(c.project, key.Zone, obj).Autoscaler(key.Name).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// Delete deletes the autoscaler and waits for the operation to complete.
+func (c *zonalAutoscalers) Delete(ctx context.Context, key *meta.Key) error {
+	op, err := c.service.Autoscalers.Delete(c.project, key.Zone, key.Name).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+func (c *zonalAutoscalers) wait(ctx context.Context, key *meta.Key, op *compute.Operation) error {
+	op, err := shared.WaitForOperation(ctx, c.service, c.project, op)
+	if err != nil {
+		return err
+	}
+	return operationError(key, op)
+}
+
+// regionAutoscalers implements the regional autoscaler operations through the compute service, as the cloud doesn't
+// support autoscalers.
+type regionAutoscalers struct {
+	service *compute.Service
+	project string
+}
+
+// Get returns the regional autoscaler.
+func (c *regionAutoscalers) Get(ctx context.Context, key *meta.Key) (*compute.Autoscaler, error) {
+	return c.service.RegionAutoscalers.Get(c.project, key.Region, key.Name).Context(ctx).Do()
+}
+
+// Insert creates the regional autoscaler and waits for the operation to complete.
+func (c *regionAutoscalers) Insert(ctx context.Context, key *meta.Key, obj *compute.Autoscaler) error {
+	obj.Name = key.Name
+	op, err := c.service.RegionAutoscalers.Insert(c.project, key.Region, obj).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// Update updates the regional autoscaler and waits for the operation to complete.
+func (c *regionAutoscalers) Update(ctx context.Context, key *meta.Key, obj *compute.Autoscaler) error {
+	obj.Name = key.Name
+	op, err := c.service.RegionAutoscalers.Update(c.project, key.Region, obj).Autoscaler(key.Name).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// Delete deletes the regional autoscaler and waits for the operation to complete.
+func (c *regionAutoscalers) Delete(ctx context.Context, key *meta.Key) error {
+	op, err := c.service.RegionAutoscalers.Delete(c.project, key.Region, key.Name).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+func (c *regionAutoscalers) wait(ctx context.Context, key *meta.Key, op *compute.Operation) error {
+	op, err := shared.WaitForOperation(ctx, c.service, c.project, op)
+	if err != nil {
+		return err
+	}
+	return operationError(key, op)
+}
+
+// operationError returns the error of the completed operation on the autoscaler, if any.
+func operationError(key *meta.Key, op *compute.Operation) error {
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		return fmt.Errorf("operation %s on autoscaler %s failed: %s", op.OperationType, key.Name, op.Error.Errors[0].Message)
+	}
+
+	return nil
+}
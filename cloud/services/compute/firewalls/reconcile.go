@@ -17,10 +17,15 @@ limitations under the License.
 package firewalls
 
 import (
+	"cmp"
 	"context"
+	"slices"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/compute/v1"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/gcperrors"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -30,13 +35,27 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	log := log.FromContext(ctx)
 	if s.scope.SkipFirewallRulesManagement() {
 		log.V(2).Info("Ignore Reconciling firewall resources")
+		s.scope.DeleteCondition(infrav1.FirewallsReadyCondition)
 		return nil
 	}
 	log.Info("Reconciling firewall resources")
+	if err := s.reconcileFirewalls(ctx); err != nil {
+		s.scope.MarkConditionFalse(infrav1.FirewallsReadyCondition, infrav1.FirewallsReconciliationFailedReason, err)
+		return err
+	}
+	s.scope.MarkConditionTrue(infrav1.FirewallsReadyCondition)
+
+	return nil
+}
+
+// reconcileFirewalls creates the missing firewall rules and updates the outdated ones.
+func (s *Service) reconcileFirewalls(ctx context.Context) error {
+	log := log.FromContext(ctx)
 	for _, spec := range s.scope.FirewallRulesSpec() {
 		log.V(2).Info("Looking firewall", "name", spec.Name)
 		firewallKey := meta.GlobalKey(spec.Name)
-		if _, err := s.firewalls.Get(ctx, firewallKey); err != nil {
+		firewall, err := s.firewalls.Get(ctx, firewallKey)
+		if err != nil {
 			if !gcperrors.IsNotFound(err) {
 				return err
 			}
@@ -45,6 +64,15 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			if err := s.firewalls.Insert(ctx, firewallKey, spec); err != nil {
 				return err
 			}
+			continue
+		}
+
+		if !firewallUpToDate(firewall, spec) {
+			log.V(2).Info("Updating firewall", "name", spec.Name)
+			if err := s.firewalls.Update(ctx, firewallKey, spec); err != nil {
+				log.Error(err, "Error updating firewall", "name", spec.Name)
+				return err
+			}
 		}
 	}
 
@@ -72,3 +100,24 @@ func (s *Service) Delete(ctx context.Context) error {
 
 	return nil
 }
+
+// firewallUpToDate reports whether the firewall rule matches its spec, taking the GCE defaults into account.
+func firewallUpToDate(firewall, spec *compute.Firewall) bool {
+	return firewall.Description == spec.Description &&
+		firewall.Direction == cmp.Or(spec.Direction, "INGRESS") &&
+		firewall.Priority == cmp.Or(spec.Priority, 1000) &&
+		firewallRulesEqual(firewall.Allowed, spec.Allowed) &&
+		firewallRulesEqual(firewall.Denied, spec.Denied) &&
+		slices.Equal(firewall.SourceRanges, spec.SourceRanges) &&
+		slices.Equal(firewall.DestinationRanges, spec.DestinationRanges) &&
+		slices.Equal(firewall.SourceTags, spec.SourceTags) &&
+		slices.Equal(firewall.TargetTags, spec.TargetTags)
+}
+
+// firewallRulesEqual compares allowed or denied rules, GCE reports the protocols in lowercase.
+func firewallRulesEqual[T compute.FirewallAllowed | compute.FirewallDenied](a, b []*T) bool {
+	return slices.EqualFunc(a, b, func(x, y *T) bool {
+		rx, ry := compute.FirewallAllowed(*x), compute.FirewallAllowed(*y)
+		return strings.EqualFold(rx.IPProtocol, ry.IPProtocol) && slices.Equal(rx.Ports, ry.Ports)
+	})
+}
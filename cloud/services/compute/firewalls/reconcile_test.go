@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
@@ -33,6 +34,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/scope"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	v1beta1conditions "sigs.k8s.io/cluster-api/util/deprecated/v1beta1/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -322,6 +324,9 @@ func TestService_Reconcile(t *testing.T) {
 				if _, ok := fakeGCPCluster.Status.Network.FirewallRules[fwRule.Name]; !ok {
 					return errors.New("firewall rule was created but with wrong values")
 				}
+				if !v1beta1conditions.IsTrue(fakeGCPCluster, infrav1.FirewallsReadyCondition) {
+					return errors.New("firewalls ready condition was not marked true")
+				}
 				return nil
 			},
 		},
@@ -358,6 +363,12 @@ func TestService_Reconcile(t *testing.T) {
 				},
 			},
 			wantErr: true,
+			assert: func(_ context.Context, _ testCase) error {
+				if reason := v1beta1conditions.GetReason(fakeGCPCluster, infrav1.FirewallsReadyCondition); reason != infrav1.FirewallsReconciliationFailedReason {
+					return fmt.Errorf("firewalls ready condition has reason %q", reason)
+				}
+				return nil
+			},
 		},
 		{
 			name:  "firewall return no error using shared vpc",
@@ -368,6 +379,12 @@ func TestService_Reconcile(t *testing.T) {
 					*meta.GlobalKey(fmt.Sprintf("allow-%s-healthchecks", fakeGCPCluster.Name)): {},
 				},
 			},
+			assert: func(_ context.Context, _ testCase) error {
+				if v1beta1conditions.Has(fakeGCPClusterSharedVPC, infrav1.FirewallsReadyCondition) {
+					return errors.New("firewalls ready condition should not be reported when firewalls are not managed")
+				}
+				return nil
+			},
 		},
 		{
 			name:  "firewall return no error using unmanaged firewall settings",
@@ -399,6 +416,36 @@ func TestService_Reconcile(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name:  "firewall rule exists with drifted ports successful updating custom user specified rule",
+			scope: func() Scope { return clusterScopeCustomFirewalls },
+			mockFirewalls: &cloud.MockFirewalls{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "my-proj"},
+				Objects: map[meta.Key]*cloud.MockFirewallsObj{
+					*meta.GlobalKey("my-cluster-custom-fw-rule"): {Obj: &compute.Firewall{
+						Name:        "my-cluster-custom-fw-rule",
+						Description: "Custom Firewall Rule Description",
+						Allowed:     []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"80"}}},
+						Direction:   "INGRESS",
+						Priority:    1000,
+					}},
+				},
+				UpdateHook: func(_ context.Context, key *meta.Key, obj *compute.Firewall, m *cloud.MockFirewalls, _ ...cloud.Option) error {
+					m.Objects[*key] = &cloud.MockFirewallsObj{Obj: obj}
+					return nil
+				},
+			},
+			assert: func(ctx context.Context, t testCase) error {
+				fwRule, err := t.mockFirewalls.Get(ctx, meta.GlobalKey("my-cluster-custom-fw-rule"))
+				if err != nil {
+					return err
+				}
+				if len(fwRule.Allowed) != 1 || !reflect.DeepEqual(fwRule.Allowed[0].Ports, []string{"443"}) {
+					return errors.New("firewall rule was not updated to the spec")
+				}
+				return nil
+			},
+		},
 		{
 			name:  "firewall return no error using unmanaged firewall settings with custom user specified rules",
 			scope: func() Scope { return clusterScopeCustomFirewallsUnmanaged },
@@ -421,8 +468,7 @@ func TestService_Reconcile(t *testing.T) {
 				return
 			}
 			if tt.assert != nil {
-				err = tt.assert(ctx, tt)
-				if err != nil {
+				if err := tt.assert(ctx, tt); err != nil {
 					t.Errorf("firewall rule was not created as expected: %v", err)
 					return
 				}
@@ -24,6 +24,7 @@ import (
 	"google.golang.org/api/compute/v1"
 
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/shared"
 )
 
 type firewallsInterface interface {
@@ -35,7 +36,7 @@ type firewallsInterface interface {
 
 // Scope is an interfaces that hold used methods.
 type Scope interface {
-	cloud.ClusterGetter
+	cloud.Cluster
 	FirewallRulesSpec() []*compute.Firewall
 }
 
@@ -51,6 +52,38 @@ var _ cloud.Reconciler = &Service{}
 func New(scope Scope) *Service {
 	return &Service{
 		scope:     scope,
-		firewalls: scope.Cloud().Firewalls(),
+		firewalls: &cachedFirewalls{firewallsInterface: scope.Cloud().Firewalls(), identity: scope.CredentialsIdentity(), project: scope.Project()},
 	}
 }
+
+// cachedFirewalls caches the firewall rules read, invalidating them when they are modified.
+type cachedFirewalls struct {
+	firewallsInterface
+	identity string
+	project  string
+}
+
+// Get returns the firewall rule from the read cache, getting it when it isn't cached.
+func (c *cachedFirewalls) Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Firewall, error) {
+	return shared.CachedGet(ctx, c.identity, c.project, "firewalls", key, func(ctx context.Context) (*compute.Firewall, error) {
+		return c.firewallsInterface.Get(ctx, key, options...)
+	})
+}
+
+// Insert creates the firewall rule and invalidates its cached reads.
+func (c *cachedFirewalls) Insert(ctx context.Context, key *meta.Key, obj *compute.Firewall, options ...k8scloud.Option) error {
+	defer shared.InvalidateCache(c.project, "firewalls", key)
+	return c.firewallsInterface.Insert(ctx, key, obj, options...)
+}
+
+// Update updates the firewall rule and invalidates its cached reads.
+func (c *cachedFirewalls) Update(ctx context.Context, key *meta.Key, obj *compute.Firewall, options ...k8scloud.Option) error {
+	defer shared.InvalidateCache(c.project, "firewalls", key)
+	return c.firewallsInterface.Update(ctx, key, obj, options...)
+}
+
+// Delete deletes the firewall rule and invalidates its cached reads.
+func (c *cachedFirewalls) Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error {
+	defer shared.InvalidateCache(c.project, "firewalls", key)
+	return c.firewallsInterface.Delete(ctx, key, options...)
+}
@@ -19,6 +19,7 @@ package instancegroupmanagers
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
@@ -57,7 +58,8 @@ func (s *Service) Delete(ctx context.Context) error {
 	log.Info("Deleting instanceGroupManager resources")
 
 	log.V(2).Info("Looking for instanceGroupManager before deleting")
-	existing, err := s.instanceGroupManagers.Get(ctx, igmKey)
+	instanceGroupManagers := s.instanceGroupManagersFor(igmKey)
+	existing, err := instanceGroupManagers.Get(ctx, igmKey)
 	if err != nil {
 		if gcperrors.IsNotFound(err) {
 			existing = nil
@@ -74,7 +76,7 @@ func (s *Service) Delete(ctx context.Context) error {
 		// we own it.
 
 		log.V(2).Info("Deleting instanceGroupManager", "selfLink", existing.SelfLink)
-		if err := s.instanceGroupManagers.Delete(ctx, igmKey); err != nil {
+		if err := instanceGroupManagers.Delete(ctx, igmKey); err != nil {
 			if gcperrors.IsNotFound(err) {
 				log.V(2).Info("instanceGroupManager not found, assuming already deleted", "selfLink", existing.SelfLink)
 			} else {
@@ -106,7 +108,8 @@ func (s *Service) createOrGet(ctx context.Context, instanceTemplateKey *meta.Key
 	}
 
 	log.V(2).Info("Looking for instanceGroupManager")
-	actual, err := s.instanceGroupManagers.Get(ctx, igmKey)
+	instanceGroupManagers := s.instanceGroupManagersFor(igmKey)
+	actual, err := instanceGroupManagers.Get(ctx, igmKey)
 	if err != nil {
 		if !gcperrors.IsNotFound(err) {
 			log.Error(err, "Error looking for instanceGroupManager")
@@ -114,7 +117,7 @@ func (s *Service) createOrGet(ctx context.Context, instanceTemplateKey *meta.Key
 		}
 
 		log.V(2).Info("Creating instanceGroupManager")
-		if err := s.instanceGroupManagers.Insert(ctx, igmKey, desired); err != nil {
+		if err := instanceGroupManagers.Insert(ctx, igmKey, desired); err != nil {
 			log.Error(err, "creating instanceGroupManager")
 			return nil, fmt.Errorf("creating instanceGroupManager %v: %w", selfLink, err)
 		}
@@ -123,15 +126,16 @@ func (s *Service) createOrGet(ctx context.Context, instanceTemplateKey *meta.Key
 		// Also, we continue to go through the comparisons below so we have one code path,
 		// (there are no API calls so this is cheap),
 		// although in practice we don't expect to need further updates.
-		actual, err = s.instanceGroupManagers.Get(ctx, igmKey)
+		actual, err = instanceGroupManagers.Get(ctx, igmKey)
 		if err != nil {
 			return nil, fmt.Errorf("getting instanceGroupManager %v: %w", selfLink, err)
 		}
 	}
 
-	if desired.TargetSize != actual.TargetSize {
+	// The autoscaler sizes the instanceGroupManager once created.
+	if !s.scope.ReplicasManagedByAutoscaler() && desired.TargetSize != actual.TargetSize {
 		log.V(2).Info("resizing instanceGroupManager", "targetSize", desired.TargetSize)
-		if err := s.instanceGroupManagers.Resize(ctx, igmKey, desired.TargetSize); err != nil {
+		if err := instanceGroupManagers.Resize(ctx, igmKey, desired.TargetSize); err != nil {
 			log.Error(err, "resizing instanceGroupManager")
 			return nil, fmt.Errorf("resizing instanceGroupManager %v: %w", selfLink, err)
 		}
@@ -141,7 +145,7 @@ func (s *Service) createOrGet(ctx context.Context, instanceTemplateKey *meta.Key
 
 	if desired.InstanceTemplate != actual.InstanceTemplate {
 		log.V(2).Info("updating instanceTemplate for instanceGroupManager", "desired.instanceTemplate", desired.InstanceTemplate, "actual.instanceTemplate", actual.InstanceTemplate)
-		if err := s.instanceGroupManagers.SetInstanceTemplate(ctx, igmKey, &compute.InstanceGroupManagersSetInstanceTemplateRequest{
+		if err := instanceGroupManagers.SetInstanceTemplate(ctx, igmKey, &compute.InstanceGroupManagersSetInstanceTemplateRequest{
 			InstanceTemplate: desired.InstanceTemplate,
 		}); err != nil {
 			log.Error(err, "updating instanceTemplate for instanceGroupManager")
@@ -151,6 +155,34 @@ func (s *Service) createOrGet(ctx context.Context, instanceTemplateKey *meta.Key
 		actual.InstanceTemplate = desired.InstanceTemplate
 	}
 
+	if desired.DistributionPolicy != nil && !distributionZonesEqual(desired.DistributionPolicy, actual.DistributionPolicy) {
+		return nil, fmt.Errorf("the zones of instanceGroupManager %v can't be changed, it must be recreated to change them", selfLink)
+	}
+
+	if !updatePolicyEqual(desired.UpdatePolicy, actual.UpdatePolicy) || !targetShapeEqual(desired.DistributionPolicy, actual.DistributionPolicy) ||
+		!statefulDisksEqual(desired.StatefulPolicy, actual.StatefulPolicy) {
+		// The policies are patched together, as GCE only supports some combinations of them, e.g. stateful disks with
+		// instances recreated rather than substituted.
+		patch := &compute.InstanceGroupManager{
+			UpdatePolicy:   desired.UpdatePolicy,
+			StatefulPolicy: statefulPolicyPatch(desired.StatefulPolicy, actual.StatefulPolicy),
+		}
+		if desired.DistributionPolicy != nil {
+			patch.DistributionPolicy = &compute.DistributionPolicy{TargetShape: desired.DistributionPolicy.TargetShape}
+		}
+
+		log.V(2).Info("updating the policies of instanceGroupManager")
+		if err := instanceGroupManagers.Patch(ctx, igmKey, patch); err != nil {
+			log.Error(err, "updating the policies of instanceGroupManager")
+			return nil, fmt.Errorf("updating the policies of instanceGroupManager %v: %w", selfLink, err)
+		}
+		actual.UpdatePolicy = desired.UpdatePolicy
+		actual.StatefulPolicy = desired.StatefulPolicy
+		if actual.DistributionPolicy != nil && desired.DistributionPolicy != nil {
+			actual.DistributionPolicy.TargetShape = desired.DistributionPolicy.TargetShape
+		}
+	}
+
 	return actual, nil
 }
 
@@ -164,9 +196,12 @@ func (s *Service) ListInstances(ctx context.Context, instanceGroupManager *compu
 		instanceGroup = strings.TrimPrefix(instanceGroup, "https://www.googleapis.com/")
 		instanceGroup = strings.TrimPrefix(instanceGroup, "compute/v1/")
 		tokens := strings.Split(instanceGroup, "/")
-		if len(tokens) == 6 && tokens[0] == "projects" && tokens[2] == "zones" && tokens[4] == "instanceGroups" {
+		switch {
+		case len(tokens) == 6 && tokens[0] == "projects" && tokens[2] == "zones" && tokens[4] == "instanceGroups":
 			igKey = meta.ZonalKey(tokens[5], tokens[3])
-		} else {
+		case len(tokens) == 6 && tokens[0] == "projects" && tokens[2] == "regions" && tokens[4] == "instanceGroups":
+			igKey = meta.RegionalKey(tokens[5], tokens[3])
+		default:
 			return nil, fmt.Errorf("unexpected format for instanceGroup: %q", instanceGroup)
 		}
 	}
@@ -175,7 +210,7 @@ func (s *Service) ListInstances(ctx context.Context, instanceGroupManager *compu
 	listInstancesRequest := &compute.InstanceGroupsListInstancesRequest{
 		InstanceState: "ALL",
 	}
-	instances, err := s.instanceGroups.ListInstances(ctx, igKey, listInstancesRequest, filter.None)
+	instances, err := s.instanceGroupsFor(igKey).ListInstances(ctx, igKey, listInstancesRequest, filter.None)
 	if err != nil {
 		log.Error(err, "Error listing instances in instanceGroup", "instanceGroup", instanceGroupManager.InstanceGroup)
 		return nil, fmt.Errorf("listing instances in instanceGroup %q: %w", instanceGroupManager.InstanceGroup, err)
@@ -183,3 +218,143 @@ func (s *Service) ListInstances(ctx context.Context, instanceGroupManager *compu
 
 	return instances, nil
 }
+
+// ListManagedInstances lists the instances of the instanceGroupManager, along with the actions it is performing on
+// them, e.g. deleting them.
+func (s *Service) ListManagedInstances(ctx context.Context) ([]*compute.ManagedInstance, error) {
+	log := log.FromContext(ctx)
+
+	igmKey, err := s.scope.InstanceGroupManagerResourceName()
+	if err != nil {
+		return nil, err
+	}
+
+	selfLink := gcp.FormatKey("instanceGroupManagers", igmKey)
+
+	log.V(2).Info("Listing managed instances of instanceGroupManager", "instanceGroupManager", selfLink)
+	instances, err := s.instanceGroupManagersFor(igmKey).ListManagedInstances(ctx, igmKey)
+	if err != nil {
+		log.Error(err, "Error listing managed instances of instanceGroupManager", "instanceGroupManager", selfLink)
+		return nil, fmt.Errorf("listing managed instances of instanceGroupManager %v: %w", selfLink, err)
+	}
+
+	return instances, nil
+}
+
+// DeleteInstances deletes the instances from the instanceGroupManager, decreasing its target size accordingly. The
+// instances which aren't in the instanceGroupManager anymore, or that it is already deleting, are skipped.
+func (s *Service) DeleteInstances(ctx context.Context, instances []string) error {
+	log := log.FromContext(ctx)
+
+	igmKey, err := s.scope.InstanceGroupManagerResourceName()
+	if err != nil {
+		return err
+	}
+
+	selfLink := gcp.FormatKey("instanceGroupManagers", igmKey)
+
+	log.Info("Deleting instances from instanceGroupManager", "instanceGroupManager", selfLink, "instances", instances)
+	if err := s.instanceGroupManagersFor(igmKey).DeleteInstances(ctx, igmKey, &compute.InstanceGroupManagersDeleteInstancesRequest{
+		Instances:                      instances,
+		SkipInstancesOnValidationError: true,
+	}); err != nil {
+		log.Error(err, "Error deleting instances from instanceGroupManager", "instanceGroupManager", selfLink)
+		return fmt.Errorf("deleting instances from instanceGroupManager %v: %w", selfLink, err)
+	}
+
+	return nil
+}
+
+// updatePolicyEqual reports whether the update policies roll out the instance template changes the same way.
+func updatePolicyEqual(desired, actual *compute.InstanceGroupManagerUpdatePolicy) bool {
+	if actual == nil {
+		return false
+	}
+	// The instance redistribution type only applies to a regional instanceGroupManager.
+	return desired.Type == actual.Type &&
+		desired.MinimalAction == actual.MinimalAction &&
+		(desired.ReplacementMethod == "" || desired.ReplacementMethod == actual.ReplacementMethod) &&
+		(desired.InstanceRedistributionType == "" || desired.InstanceRedistributionType == actual.InstanceRedistributionType) &&
+		fixedOrPercentEqual(desired.MaxSurge, actual.MaxSurge) &&
+		fixedOrPercentEqual(desired.MaxUnavailable, actual.MaxUnavailable)
+}
+
+// fixedOrPercentEqual reports whether the values are the same number or percentage, ignoring the number GCE
+// calculates from a percentage.
+func fixedOrPercentEqual(desired, actual *compute.FixedOrPercent) bool {
+	if desired == nil || actual == nil {
+		return desired == actual
+	}
+	if desired.Percent != 0 || actual.Percent != 0 {
+		return desired.Percent == actual.Percent
+	}
+	return desired.Fixed == actual.Fixed
+}
+
+// distributionZonesEqual reports whether the distribution policies have the same zones, ignoring their order and
+// whether they are URLs or partial links.
+func distributionZonesEqual(desired, actual *compute.DistributionPolicy) bool {
+	if actual == nil || len(desired.Zones) != len(actual.Zones) {
+		return false
+	}
+	zones := make(map[string]bool, len(actual.Zones))
+	for _, zone := range actual.Zones {
+		zones[path.Base(zone.Zone)] = true
+	}
+	for _, zone := range desired.Zones {
+		if !zones[path.Base(zone.Zone)] {
+			return false
+		}
+	}
+	return true
+}
+
+// targetShapeEqual reports whether the distribution policies distribute the instances the same way.
+func targetShapeEqual(desired, actual *compute.DistributionPolicy) bool {
+	if desired == nil || desired.TargetShape == "" {
+		return true
+	}
+	return actual != nil && desired.TargetShape == actual.TargetShape
+}
+
+// statefulPolicyDisks returns the stateful disks of the policy, by device name.
+func statefulPolicyDisks(policy *compute.StatefulPolicy) map[string]compute.StatefulPolicyPreservedStateDiskDevice {
+	if policy == nil || policy.PreservedState == nil {
+		return nil
+	}
+	return policy.PreservedState.Disks
+}
+
+// statefulDisksEqual reports whether the stateful policies preserve the same disks, deleting them the same way.
+func statefulDisksEqual(desired, actual *compute.StatefulPolicy) bool {
+	desiredDisks, actualDisks := statefulPolicyDisks(desired), statefulPolicyDisks(actual)
+	if len(desiredDisks) != len(actualDisks) {
+		return false
+	}
+	for deviceName, disk := range desiredDisks {
+		if actualDisk, ok := actualDisks[deviceName]; !ok || actualDisk.AutoDelete != disk.AutoDelete {
+			return false
+		}
+	}
+	return true
+}
+
+// statefulPolicyPatch returns the stateful policy patching the actual one into the desired one, the disks no longer
+// stateful being removed with null values.
+func statefulPolicyPatch(desired, actual *compute.StatefulPolicy) *compute.StatefulPolicy {
+	desiredDisks, actualDisks := statefulPolicyDisks(desired), statefulPolicyDisks(actual)
+	preservedState := &compute.StatefulPolicyPreservedState{Disks: map[string]compute.StatefulPolicyPreservedStateDiskDevice{}}
+	for deviceName, disk := range desiredDisks {
+		preservedState.Disks[deviceName] = disk
+	}
+	for deviceName := range actualDisks {
+		if _, ok := desiredDisks[deviceName]; !ok {
+			preservedState.NullFields = append(preservedState.NullFields, "Disks."+deviceName)
+		}
+	}
+	if len(preservedState.Disks) == 0 && len(preservedState.NullFields) == 0 {
+		return nil
+	}
+	preservedState.ForceSendFields = []string{"Disks"}
+	return &compute.StatefulPolicy{PreservedState: preservedState}
+}
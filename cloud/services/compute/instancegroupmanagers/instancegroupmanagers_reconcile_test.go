@@ -0,0 +1,406 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroupmanagers
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"testing"
+
+	k8scloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud"
+)
+
+type testScope struct {
+	key        *meta.Key
+	desired    *compute.InstanceGroupManager
+	autoscaled bool
+}
+
+func (s *testScope) Cloud() cloud.Cloud                                   { return nil }
+func (s *testScope) ComputeService() *compute.Service                     { return nil }
+func (s *testScope) Project() string                                      { return "my-proj" }
+func (s *testScope) InstanceGroupManagerResourceName() (*meta.Key, error) { return s.key, nil }
+func (s *testScope) ReplicasManagedByAutoscaler() bool                    { return s.autoscaled }
+func (s *testScope) InstanceGroupManagerResource(_ *meta.Key) (*compute.InstanceGroupManager, error) {
+	desired := *s.desired
+	return &desired, nil
+}
+
+// fakeInstanceGroupManagers records the calls on the instanceGroupManagers.
+type fakeInstanceGroupManagers struct {
+	igms      map[meta.Key]*compute.InstanceGroupManager
+	instances []*compute.ManagedInstance
+	calls     []string
+}
+
+func (f *fakeInstanceGroupManagers) Get(_ context.Context, key *meta.Key, _ ...k8scloud.Option) (*compute.InstanceGroupManager, error) {
+	igm, ok := f.igms[*key]
+	if !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound}
+	}
+	return igm, nil
+}
+
+func (f *fakeInstanceGroupManagers) Insert(_ context.Context, key *meta.Key, obj *compute.InstanceGroupManager, _ ...k8scloud.Option) error {
+	f.calls = append(f.calls, "Insert")
+	f.igms[*key] = obj
+	return nil
+}
+
+func (f *fakeInstanceGroupManagers) Delete(_ context.Context, key *meta.Key, _ ...k8scloud.Option) error {
+	f.calls = append(f.calls, "Delete")
+	delete(f.igms, *key)
+	return nil
+}
+
+func (f *fakeInstanceGroupManagers) DeleteInstances(_ context.Context, key *meta.Key, req *compute.InstanceGroupManagersDeleteInstancesRequest, _ ...k8scloud.Option) error {
+	f.calls = append(f.calls, "DeleteInstances")
+	f.igms[*key].TargetSize -= int64(len(req.Instances))
+	return nil
+}
+
+func (f *fakeInstanceGroupManagers) ListManagedInstances(_ context.Context, _ *meta.Key) ([]*compute.ManagedInstance, error) {
+	f.calls = append(f.calls, "ListManagedInstances")
+	return f.instances, nil
+}
+
+func (f *fakeInstanceGroupManagers) Resize(_ context.Context, key *meta.Key, size int64, _ ...k8scloud.Option) error {
+	f.calls = append(f.calls, "Resize")
+	f.igms[*key].TargetSize = size
+	return nil
+}
+
+func (f *fakeInstanceGroupManagers) SetInstanceTemplate(_ context.Context, key *meta.Key, req *compute.InstanceGroupManagersSetInstanceTemplateRequest, _ ...k8scloud.Option) error {
+	f.calls = append(f.calls, "SetInstanceTemplate")
+	f.igms[*key].InstanceTemplate = req.InstanceTemplate
+	return nil
+}
+
+func (f *fakeInstanceGroupManagers) Patch(_ context.Context, key *meta.Key, obj *compute.InstanceGroupManager) error {
+	f.calls = append(f.calls, "Patch")
+	f.igms[*key].UpdatePolicy = obj.UpdatePolicy
+	if obj.DistributionPolicy != nil {
+		f.igms[*key].DistributionPolicy.TargetShape = obj.DistributionPolicy.TargetShape
+	}
+	if obj.StatefulPolicy != nil {
+		// The disks set to null are removed from the stateful policy.
+		data, err := obj.StatefulPolicy.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		patch := map[string]map[string]map[string]*compute.StatefulPolicyPreservedStateDiskDevice{}
+		if err := json.Unmarshal(data, &patch); err != nil {
+			return err
+		}
+		disks := map[string]compute.StatefulPolicyPreservedStateDiskDevice{}
+		if actual := f.igms[*key].StatefulPolicy; actual != nil {
+			maps.Copy(disks, actual.PreservedState.Disks)
+		}
+		for deviceName, disk := range patch["preservedState"]["disks"] {
+			if disk == nil {
+				delete(disks, deviceName)
+			} else {
+				disks[deviceName] = *disk
+			}
+		}
+		f.igms[*key].StatefulPolicy = &compute.StatefulPolicy{PreservedState: &compute.StatefulPolicyPreservedState{Disks: disks}}
+	}
+	return nil
+}
+
+type fakeInstanceGroups struct {
+	keys []meta.Key
+}
+
+func (f *fakeInstanceGroups) ListInstances(_ context.Context, key *meta.Key, _ *compute.InstanceGroupsListInstancesRequest, _ *filter.F, _ ...k8scloud.Option) ([]*compute.InstanceWithNamedPorts, error) {
+	f.keys = append(f.keys, *key)
+	return []*compute.InstanceWithNamedPorts{{Instance: "projects/my-proj/zones/us-central1-a/instances/my-pool-abcd"}}, nil
+}
+
+func newDesiredInstanceGroupManager() *compute.InstanceGroupManager {
+	return &compute.InstanceGroupManager{
+		InstanceTemplate: "regions/us-central1/instanceTemplates/my-pool-1234",
+		TargetSize:       3,
+		UpdatePolicy: &compute.InstanceGroupManagerUpdatePolicy{
+			Type:           "PROACTIVE",
+			MinimalAction:  "REPLACE",
+			MaxSurge:       &compute.FixedOrPercent{Fixed: 3},
+			MaxUnavailable: &compute.FixedOrPercent{Fixed: 0},
+		},
+	}
+}
+
+func TestService_Reconcile_regional(t *testing.T) {
+	g := NewWithT(t)
+	key := meta.RegionalKey("my-cluster-my-pool", "us-central1")
+	zonal := &fakeInstanceGroupManagers{igms: map[meta.Key]*compute.InstanceGroupManager{}}
+	regional := &fakeInstanceGroupManagers{igms: map[meta.Key]*compute.InstanceGroupManager{}}
+	s := &Service{
+		scope:                       &testScope{key: key, desired: newDesiredInstanceGroupManager()},
+		instanceGroupManagers:       zonal,
+		regionInstanceGroupManagers: regional,
+	}
+
+	_, err := s.Reconcile(context.TODO(), meta.RegionalKey("my-pool-1234", "us-central1"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(regional.calls).To(Equal([]string{"Insert"}))
+	g.Expect(zonal.calls).To(BeEmpty())
+
+	g.Expect(s.Delete(context.TODO())).To(Succeed())
+	g.Expect(regional.igms).To(BeEmpty())
+}
+
+func TestService_Reconcile_updatePolicy(t *testing.T) {
+	g := NewWithT(t)
+	key := meta.ZonalKey("my-cluster-my-pool", "us-central1-a")
+	actual := newDesiredInstanceGroupManager()
+	// GCE returns the number it calculates from a percentage.
+	actual.UpdatePolicy.MaxSurge = &compute.FixedOrPercent{Percent: 20, Calculated: 1}
+	zonal := &fakeInstanceGroupManagers{igms: map[meta.Key]*compute.InstanceGroupManager{*key: actual}}
+	scope := &testScope{key: key, desired: newDesiredInstanceGroupManager()}
+	scope.desired.UpdatePolicy.MaxSurge = &compute.FixedOrPercent{Percent: 20}
+	s := &Service{scope: scope, instanceGroupManagers: zonal}
+
+	_, err := s.Reconcile(context.TODO(), meta.RegionalKey("my-pool-1234", "us-central1"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(zonal.calls).To(BeEmpty())
+
+	scope.desired.UpdatePolicy.MaxSurge = &compute.FixedOrPercent{Fixed: 2}
+	igm, err := s.Reconcile(context.TODO(), meta.RegionalKey("my-pool-1234", "us-central1"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(zonal.calls).To(Equal([]string{"Patch"}))
+	g.Expect(igm.UpdatePolicy.MaxSurge.Fixed).To(Equal(int64(2)))
+}
+
+func TestService_Reconcile_distributionPolicy(t *testing.T) {
+	g := NewWithT(t)
+	key := meta.RegionalKey("my-cluster-my-pool", "us-central1")
+	actual := newDesiredInstanceGroupManager()
+	actual.UpdatePolicy.InstanceRedistributionType = "PROACTIVE"
+	// GCE returns the zone URLs.
+	actual.DistributionPolicy = &compute.DistributionPolicy{
+		TargetShape: "EVEN",
+		Zones: []*compute.DistributionPolicyZoneConfiguration{
+			{Zone: "https://www.googleapis.com/compute/v1/projects/my-proj/zones/us-central1-a"},
+			{Zone: "https://www.googleapis.com/compute/v1/projects/my-proj/zones/us-central1-b"},
+		},
+	}
+	regional := &fakeInstanceGroupManagers{igms: map[meta.Key]*compute.InstanceGroupManager{*key: actual}}
+	scope := &testScope{key: key, desired: newDesiredInstanceGroupManager()}
+	scope.desired.UpdatePolicy.InstanceRedistributionType = "PROACTIVE"
+	scope.desired.DistributionPolicy = &compute.DistributionPolicy{
+		TargetShape: "EVEN",
+		Zones:       []*compute.DistributionPolicyZoneConfiguration{{Zone: "zones/us-central1-b"}, {Zone: "zones/us-central1-a"}},
+	}
+	s := &Service{scope: scope, regionInstanceGroupManagers: regional}
+
+	_, err := s.Reconcile(context.TODO(), meta.RegionalKey("my-pool-1234", "us-central1"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(regional.calls).To(BeEmpty())
+
+	// The target shape is patched along with the instance redistribution type.
+	scope.desired.DistributionPolicy.TargetShape = "BALANCED"
+	scope.desired.UpdatePolicy = newDesiredInstanceGroupManager().UpdatePolicy
+	scope.desired.UpdatePolicy.InstanceRedistributionType = "NONE"
+	igm, err := s.Reconcile(context.TODO(), meta.RegionalKey("my-pool-1234", "us-central1"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(regional.calls).To(Equal([]string{"Patch"}))
+	g.Expect(igm.DistributionPolicy.TargetShape).To(Equal("BALANCED"))
+	g.Expect(igm.UpdatePolicy.InstanceRedistributionType).To(Equal("NONE"))
+
+	// GCE can't change the zones of the instanceGroupManager.
+	scope.desired.DistributionPolicy.Zones = []*compute.DistributionPolicyZoneConfiguration{{Zone: "zones/us-central1-c"}}
+	_, err = s.Reconcile(context.TODO(), meta.RegionalKey("my-pool-1234", "us-central1"))
+	g.Expect(err).To(MatchError(ContainSubstring("can't be changed")))
+	g.Expect(regional.calls).To(Equal([]string{"Patch"}))
+}
+
+func TestService_Reconcile_statefulPolicy(t *testing.T) {
+	g := NewWithT(t)
+	key := meta.ZonalKey("my-cluster-my-pool", "us-central1-a")
+	zonal := &fakeInstanceGroupManagers{igms: map[meta.Key]*compute.InstanceGroupManager{*key: newDesiredInstanceGroupManager()}}
+	scope := &testScope{key: key, desired: newDesiredInstanceGroupManager()}
+	scope.desired.UpdatePolicy.ReplacementMethod = "RECREATE"
+	scope.desired.StatefulPolicy = &compute.StatefulPolicy{PreservedState: &compute.StatefulPolicyPreservedState{
+		Disks: map[string]compute.StatefulPolicyPreservedStateDiskDevice{
+			"data": {AutoDelete: "NEVER"},
+			"logs": {AutoDelete: "ON_PERMANENT_INSTANCE_DELETION"},
+		},
+	}}
+	s := &Service{scope: scope, instanceGroupManagers: zonal}
+
+	// The stateful disks and the replacement method are patched together.
+	igm, err := s.Reconcile(context.TODO(), meta.RegionalKey("my-pool-1234", "us-central1"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(zonal.calls).To(Equal([]string{"Patch"}))
+	g.Expect(igm.UpdatePolicy.ReplacementMethod).To(Equal("RECREATE"))
+	g.Expect(zonal.igms[*key].StatefulPolicy.PreservedState.Disks).To(HaveLen(2))
+
+	_, err = s.Reconcile(context.TODO(), meta.RegionalKey("my-pool-1234", "us-central1"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(zonal.calls).To(Equal([]string{"Patch"}))
+
+	// The disks no longer stateful are removed from the stateful policy.
+	scope.desired.StatefulPolicy = &compute.StatefulPolicy{PreservedState: &compute.StatefulPolicyPreservedState{
+		Disks: map[string]compute.StatefulPolicyPreservedStateDiskDevice{"data": {AutoDelete: "ON_PERMANENT_INSTANCE_DELETION"}},
+	}}
+	_, err = s.Reconcile(context.TODO(), meta.RegionalKey("my-pool-1234", "us-central1"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(zonal.calls).To(Equal([]string{"Patch", "Patch"}))
+	g.Expect(zonal.igms[*key].StatefulPolicy.PreservedState.Disks).To(Equal(scope.desired.StatefulPolicy.PreservedState.Disks))
+
+	scope.desired.StatefulPolicy = nil
+	_, err = s.Reconcile(context.TODO(), meta.RegionalKey("my-pool-1234", "us-central1"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(zonal.calls).To(Equal([]string{"Patch", "Patch", "Patch"}))
+	g.Expect(zonal.igms[*key].StatefulPolicy).To(BeNil())
+}
+
+func TestStatefulPolicyPatch(t *testing.T) {
+	g := NewWithT(t)
+	actual := &compute.StatefulPolicy{PreservedState: &compute.StatefulPolicyPreservedState{
+		Disks: map[string]compute.StatefulPolicyPreservedStateDiskDevice{"data": {AutoDelete: "NEVER"}},
+	}}
+
+	g.Expect(statefulPolicyPatch(nil, nil)).To(BeNil())
+
+	// GCE removes the disks set to null from the stateful policy.
+	data, err := statefulPolicyPatch(nil, actual).MarshalJSON()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(MatchJSON(`{"preservedState": {"disks": {"data": null}}}`))
+}
+
+func TestService_ListInstances(t *testing.T) {
+	tests := []struct {
+		name          string
+		instanceGroup string
+		wantZonal     bool
+		wantKey       *meta.Key
+		wantErr       bool
+	}{
+		{
+			name:          "zonal instance group",
+			instanceGroup: "https://www.googleapis.com/compute/v1/projects/my-proj/zones/us-central1-a/instanceGroups/my-cluster-my-pool",
+			wantZonal:     true,
+			wantKey:       meta.ZonalKey("my-cluster-my-pool", "us-central1-a"),
+		},
+		{
+			name:          "regional instance group",
+			instanceGroup: "https://www.googleapis.com/compute/v1/projects/my-proj/regions/us-central1/instanceGroups/my-cluster-my-pool",
+			wantKey:       meta.RegionalKey("my-cluster-my-pool", "us-central1"),
+		},
+		{
+			name:          "unexpected instance group",
+			instanceGroup: "projects/my-proj/global/instanceGroups/my-cluster-my-pool",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			zonal, regional := &fakeInstanceGroups{}, &fakeInstanceGroups{}
+			s := &Service{instanceGroups: zonal, regionInstanceGroups: regional}
+
+			instances, err := s.ListInstances(context.TODO(), &compute.InstanceGroupManager{InstanceGroup: tt.instanceGroup})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(instances).To(HaveLen(1))
+			if tt.wantZonal {
+				g.Expect(zonal.keys).To(Equal([]meta.Key{*tt.wantKey}))
+				g.Expect(regional.keys).To(BeEmpty())
+			} else {
+				g.Expect(regional.keys).To(Equal([]meta.Key{*tt.wantKey}))
+				g.Expect(zonal.keys).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestService_Reconcile_autoscaled(t *testing.T) {
+	g := NewWithT(t)
+	key := meta.ZonalKey("my-cluster-my-pool", "us-central1-a")
+	actual := newDesiredInstanceGroupManager()
+	actual.TargetSize = 7
+	zonal := &fakeInstanceGroupManagers{igms: map[meta.Key]*compute.InstanceGroupManager{*key: actual}}
+	scope := &testScope{key: key, desired: newDesiredInstanceGroupManager(), autoscaled: true}
+	s := &Service{scope: scope, instanceGroupManagers: zonal}
+
+	// The target size set by the autoscaler is kept.
+	igm, err := s.Reconcile(context.TODO(), meta.RegionalKey("my-pool-1234", "us-central1"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(zonal.calls).To(BeEmpty())
+	g.Expect(igm.TargetSize).To(Equal(int64(7)))
+
+	scope.autoscaled = false
+	igm, err = s.Reconcile(context.TODO(), meta.RegionalKey("my-pool-1234", "us-central1"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(zonal.calls).To(Equal([]string{"Resize"}))
+	g.Expect(igm.TargetSize).To(Equal(int64(3)))
+}
+
+func TestService_DeleteInstances(t *testing.T) {
+	g := NewWithT(t)
+	key := meta.RegionalKey("my-cluster-my-pool", "us-central1")
+	regional := &fakeInstanceGroupManagers{igms: map[meta.Key]*compute.InstanceGroupManager{*key: newDesiredInstanceGroupManager()}}
+	s := &Service{
+		scope:                       &testScope{key: key, desired: newDesiredInstanceGroupManager()},
+		regionInstanceGroupManagers: regional,
+	}
+
+	g.Expect(s.DeleteInstances(context.TODO(), []string{"projects/my-proj/zones/us-central1-a/instances/my-pool-abcd"})).To(Succeed())
+	g.Expect(regional.calls).To(Equal([]string{"DeleteInstances"}))
+	g.Expect(regional.igms[*key].TargetSize).To(Equal(int64(2)))
+
+	// The instance deleted from the instanceGroupManager is replaced once it is resized to the replicas.
+	igm, err := s.Reconcile(context.TODO(), meta.RegionalKey("my-pool-1234", "us-central1"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(regional.calls).To(Equal([]string{"DeleteInstances", "Resize"}))
+	g.Expect(igm.TargetSize).To(Equal(int64(3)))
+}
+
+func TestService_ListManagedInstances(t *testing.T) {
+	g := NewWithT(t)
+	key := meta.ZonalKey("my-cluster-my-pool", "us-central1-a")
+	zonal := &fakeInstanceGroupManagers{instances: []*compute.ManagedInstance{{
+		Instance:      "https://www.googleapis.com/compute/v1/projects/my-proj/zones/us-central1-a/instances/my-pool-abcd",
+		CurrentAction: "DELETING",
+	}}}
+	regional := &fakeInstanceGroupManagers{}
+	s := &Service{
+		scope:                       &testScope{key: key},
+		instanceGroupManagers:       zonal,
+		regionInstanceGroupManagers: regional,
+	}
+
+	instances, err := s.ListManagedInstances(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(instances).To(Equal(zonal.instances))
+	g.Expect(zonal.calls).To(Equal([]string{"ListManagedInstances"}))
+	g.Expect(regional.calls).To(BeEmpty())
+}
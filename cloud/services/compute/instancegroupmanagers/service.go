@@ -19,38 +19,54 @@ package instancegroupmanagers
 
 import (
 	"context"
+	"fmt"
 
 	k8scloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	compute "google.golang.org/api/compute/v1"
 
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/shared"
 )
 
 type instanceGroupManagersClient interface {
 	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.InstanceGroupManager, error)
 	Insert(ctx context.Context, key *meta.Key, obj *compute.InstanceGroupManager, options ...k8scloud.Option) error
 	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
+	DeleteInstances(context.Context, *meta.Key, *compute.InstanceGroupManagersDeleteInstancesRequest, ...k8scloud.Option) error
+	ListManagedInstances(ctx context.Context, key *meta.Key) ([]*compute.ManagedInstance, error)
 	Resize(context.Context, *meta.Key, int64, ...k8scloud.Option) error
 	SetInstanceTemplate(context.Context, *meta.Key, *compute.InstanceGroupManagersSetInstanceTemplateRequest, ...k8scloud.Option) error
+	Patch(ctx context.Context, key *meta.Key, obj *compute.InstanceGroupManager) error
+}
+
+type instanceGroupsClient interface {
+	ListInstances(ctx context.Context, key *meta.Key, req *compute.InstanceGroupsListInstancesRequest, fl *filter.F, options ...k8scloud.Option) ([]*compute.InstanceWithNamedPorts, error)
 }
 
 // Scope is an interfaces that hold used methods.
 type Scope interface {
 	Cloud() cloud.Cloud
-
+	// ComputeService returns the compute service, for the operations the cloud doesn't support.
+	ComputeService() *compute.Service
+	// Project returns the project of the instanceGroupManager.
+	Project() string
 	// InstanceGroupManagerResource returns the desired instanceGroupManager
 	InstanceGroupManagerResource(instanceTemplateKey *meta.Key) (*compute.InstanceGroupManager, error)
-
 	// InstanceGroupManagerResourceName returns the instanceGroupManager selfLink
 	InstanceGroupManagerResourceName() (*meta.Key, error)
+	// ReplicasManagedByAutoscaler returns whether the autoscaler sizes the instanceGroupManager
+	ReplicasManagedByAutoscaler() bool
 }
 
 // Service implements managed instance groups reconciler.
 type Service struct {
-	scope                 Scope
-	instanceGroupManagers instanceGroupManagersClient
-	instanceGroups        k8scloud.InstanceGroups
+	scope                       Scope
+	instanceGroupManagers       instanceGroupManagersClient
+	regionInstanceGroupManagers instanceGroupManagersClient
+	instanceGroups              instanceGroupsClient
+	regionInstanceGroups        instanceGroupsClient
 }
 
 // var _ cloud.Reconciler = &Service{}
@@ -58,10 +74,192 @@ type Service struct {
 // New returns Service from given scope.
 func New(scope Scope) *Service {
 	cloudScope := scope.Cloud()
-
+	computeService := scope.ComputeService()
+	project := scope.Project()
 	return &Service{
-		scope:                 scope,
-		instanceGroupManagers: cloudScope.InstanceGroupManagers(),
-		instanceGroups:        cloudScope.InstanceGroups(),
+		scope: scope,
+		instanceGroupManagers: &zonalInstanceGroupManagers{
+			InstanceGroupManagers: cloudScope.InstanceGroupManagers(),
+			service:               computeService,
+			project:               project,
+		},
+		regionInstanceGroupManagers: &regionInstanceGroupManagers{service: computeService, project: project},
+		instanceGroups:              cloudScope.InstanceGroups(),
+		regionInstanceGroups:        &regionInstanceGroups{service: computeService, project: project},
+	}
+}
+
+// instanceGroupManagersFor returns the client of the zonal or regional instanceGroupManager.
+func (s *Service) instanceGroupManagersFor(key *meta.Key) instanceGroupManagersClient {
+	if key.Type() == meta.Regional {
+		return s.regionInstanceGroupManagers
+	}
+	return s.instanceGroupManagers
+}
+
+// instanceGroupsFor returns the client of the zonal or regional instanceGroup.
+func (s *Service) instanceGroupsFor(key *meta.Key) instanceGroupsClient {
+	if key.Type() == meta.Regional {
+		return s.regionInstanceGroups
+	}
+	return s.instanceGroups
+}
+
+// zonalInstanceGroupManagers adds the patch operation, that the cloud doesn't support, to the zonal
+// instanceGroupManagers of the cloud.
+type zonalInstanceGroupManagers struct {
+	k8scloud.InstanceGroupManagers
+	service *compute.Service
+	project string
+}
+
+// Patch patches the instanceGroupManager and waits for the operation to complete.
+func (c *zonalInstanceGroupManagers) Patch(ctx context.Context, key *meta.Key, obj *compute.InstanceGroupManager) error {
+	op, err := c.service.InstanceGroupManagers.Patch(c.project, key.Zone, key.Name, obj).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	op, err = shared.WaitForOperation(ctx, c.service, c.project, op)
+	if err != nil {
+		return err
+	}
+	return operationError(key, op)
+}
+
+// ListManagedInstances lists the instances of the instanceGroupManager, along with the actions it is performing on
+// them.
+func (c *zonalInstanceGroupManagers) ListManagedInstances(ctx context.Context, key *meta.Key) ([]*compute.ManagedInstance, error) {
+	var instances []*compute.ManagedInstance
+	err := c.service.InstanceGroupManagers.ListManagedInstances(c.project, key.Zone, key.Name).Pages(ctx, func(page *compute.InstanceGroupManagersListManagedInstancesResponse) error {
+		instances = append(instances, page.ManagedInstances...)
+		return nil
+	})
+	return instances, err
+}
+
+// regionInstanceGroupManagers implements the regional instanceGroupManager operations through the compute service,
+// as the cloud doesn't support regional instanceGroupManagers.
+type regionInstanceGroupManagers struct {
+	service *compute.Service
+	project string
+}
+
+// Get returns the regional instanceGroupManager.
+func (c *regionInstanceGroupManagers) Get(ctx context.Context, key *meta.Key, _ ...k8scloud.Option) (*compute.InstanceGroupManager, error) {
+	return c.service.RegionInstanceGroupManagers.Get(c.project, key.Region, key.Name).Context(ctx).Do()
+}
+
+// Insert creates the regional instanceGroupManager and waits for the operation to complete.
+func (c *regionInstanceGroupManagers) Insert(ctx context.Context, key *meta.Key, obj *compute.InstanceGroupManager, _ ...k8scloud.Option) error {
+	obj.Name = key.Name
+	op, err := c.service.RegionInstanceGroupManagers.Insert(c.project, key.Region, obj).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// Delete deletes the regional instanceGroupManager and waits for the operation to complete.
+func (c *regionInstanceGroupManagers) Delete(ctx context.Context, key *meta.Key, _ ...k8scloud.Option) error {
+	op, err := c.service.RegionInstanceGroupManagers.Delete(c.project, key.Region, key.Name).Context(ctx).Do()
+	if err != nil {
+		return err
 	}
+
+	return c.wait(ctx, key, op)
+}
+
+// DeleteInstances deletes the instances of the regional instanceGroupManager and waits for the operation to complete.
+func (c *regionInstanceGroupManagers) DeleteInstances(ctx context.Context, key *meta.Key, req *compute.InstanceGroupManagersDeleteInstancesRequest, _ ...k8scloud.Option) error {
+	op, err := c.service.RegionInstanceGroupManagers.DeleteInstances(c.project, key.Region, key.Name, &compute.RegionInstanceGroupManagersDeleteInstancesRequest{
+		Instances:                      req.Instances,
+		SkipInstancesOnValidationError: req.SkipInstancesOnValidationError,
+	}).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// ListManagedInstances lists the instances of the regional instanceGroupManager, along with the actions it is
+// performing on them.
+func (c *regionInstanceGroupManagers) ListManagedInstances(ctx context.Context, key *meta.Key) ([]*compute.ManagedInstance, error) {
+	var instances []*compute.ManagedInstance
+	err := c.service.RegionInstanceGroupManagers.ListManagedInstances(c.project, key.Region, key.Name).Pages(ctx, func(page *compute.RegionInstanceGroupManagersListInstancesResponse) error {
+		instances = append(instances, page.ManagedInstances...)
+		return nil
+	})
+	return instances, err
+}
+
+// Resize sets the target size of the regional instanceGroupManager and waits for the operation to complete.
+func (c *regionInstanceGroupManagers) Resize(ctx context.Context, key *meta.Key, size int64, _ ...k8scloud.Option) error {
+	op, err := c.service.RegionInstanceGroupManagers.Resize(c.project, key.Region, key.Name, size).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// SetInstanceTemplate sets the instance template of the regional instanceGroupManager and waits for the operation
+// to complete.
+func (c *regionInstanceGroupManagers) SetInstanceTemplate(ctx context.Context, key *meta.Key, req *compute.InstanceGroupManagersSetInstanceTemplateRequest, _ ...k8scloud.Option) error {
+	op, err := c.service.RegionInstanceGroupManagers.SetInstanceTemplate(c.project, key.Region, key.Name, &compute.RegionInstanceGroupManagersSetTemplateRequest{
+		InstanceTemplate: req.InstanceTemplate,
+	}).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// Patch patches the regional instanceGroupManager and waits for the operation to complete.
+func (c *regionInstanceGroupManagers) Patch(ctx context.Context, key *meta.Key, obj *compute.InstanceGroupManager) error {
+	op, err := c.service.RegionInstanceGroupManagers.Patch(c.project, key.Region, key.Name, obj).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+func (c *regionInstanceGroupManagers) wait(ctx context.Context, key *meta.Key, op *compute.Operation) error {
+	op, err := shared.WaitForOperation(ctx, c.service, c.project, op)
+	if err != nil {
+		return err
+	}
+	return operationError(key, op)
+}
+
+// regionInstanceGroups implements the regional instanceGroup operations through the compute service, as the cloud
+// doesn't support regional instanceGroups.
+type regionInstanceGroups struct {
+	service *compute.Service
+	project string
+}
+
+// ListInstances lists the instances of the regional instanceGroup.
+func (c *regionInstanceGroups) ListInstances(ctx context.Context, key *meta.Key, req *compute.InstanceGroupsListInstancesRequest, _ *filter.F, _ ...k8scloud.Option) ([]*compute.InstanceWithNamedPorts, error) {
+	var instances []*compute.InstanceWithNamedPorts
+	err := c.service.RegionInstanceGroups.ListInstances(c.project, key.Region, key.Name, &compute.RegionInstanceGroupsListInstancesRequest{
+		InstanceState: req.InstanceState,
+	}).Pages(ctx, func(page *compute.RegionInstanceGroupsListInstances) error {
+		instances = append(instances, page.Items...)
+		return nil
+	})
+	return instances, err
+}
+
+// operationError returns the error of the completed operation on the instanceGroupManager, if any.
+func operationError(key *meta.Key, op *compute.Operation) error {
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		return fmt.Errorf("operation %s on instanceGroupManager %s failed: %s", op.OperationType, key.Name, op.Error.Errors[0].Message)
+	}
+
+	return nil
 }
@@ -0,0 +1,312 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instances
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/pkg/errors"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/gcperrors"
+)
+
+const (
+	// metadataValueMaxSize is the maximum size of an instance metadata value.
+	metadataValueMaxSize = 256 * 1024
+
+	// defaultBootstrapDataTTL is how long bootstrap data objects are kept after their instance is created by default.
+	defaultBootstrapDataTTL = time.Hour
+)
+
+var (
+	// cloudConfigFetchTemplate fetches the bootstrap data object with the token of the instance service account in a
+	// boothook, run before cloud-init processes the included file. The object is fetched again when its URL changed,
+	// i.e. when the instance was re-bootstrapped with new bootstrap data.
+	cloudConfigFetchTemplate = template.Must(template.New("cloud-config").Parse(`Content-Type: multipart/mixed; boundary="==BOUNDARY=="
+MIME-Version: 1.0
+
+--==BOUNDARY==
+Content-Type: text/cloud-boothook; charset="us-ascii"
+
+#!/bin/bash
+url='{{.URL}}'
+if [ ! -s /etc/capg-bootstrap-data ] || [ "$(cat /etc/capg-bootstrap-data.url 2>/dev/null)" != "${url}" ]; then
+  umask 077
+  token=$(curl -sSf --retry 10 -H 'Metadata-Flavor: Google' 'http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token' | sed -E 's/.*"access_token": *"([^"]+)".*/\1/')
+  curl -sSf --retry 10 -H "Authorization: Bearer ${token}" -o /etc/capg-bootstrap-data "${url}" && echo "${url}" > /etc/capg-bootstrap-data.url
+fi
+
+--==BOUNDARY==
+Content-Type: text/x-include-url; charset="us-ascii"
+
+file:///etc/capg-bootstrap-data
+--==BOUNDARY==--
+`))
+
+	// windowsFetchTemplate fetches the bootstrap data object with the token of the instance service account and runs it.
+	windowsFetchTemplate = template.Must(template.New("windows").Parse(`$ErrorActionPreference = 'Stop'
+$token = (Invoke-RestMethod -Headers @{'Metadata-Flavor' = 'Google'} -Uri 'http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token').access_token
+$script = Invoke-RestMethod -Headers @{Authorization = "Bearer $token"} -Uri '{{.URL}}'
+Invoke-Expression $script
+`))
+)
+
+// storeBootstrapData uploads the bootstrap data to a GCS object when the cluster stores it in GCS, and replaces it in
+// the instance metadata with a script fetching the object. Otherwise it makes sure the bootstrap data fits in the
+// instance metadata, GCE rejecting larger values with an opaque error.
+func (s *Service) storeBootstrapData(ctx context.Context, instance *compute.Instance, bootstrapData, format string) error {
+	var item *compute.MetadataItems
+	for _, i := range instance.Metadata.Items {
+		if i.Key == s.scope.BootstrapDataMetadataKey() {
+			item = i
+		}
+	}
+	if item == nil {
+		return nil
+	}
+
+	storage := s.scope.BootstrapDataStorage()
+	if storage == nil || storage.Type != infrav1.BootstrapDataStorageGCS {
+		if len(bootstrapData) > metadataValueMaxSize {
+			err := errors.Errorf("bootstrap data of %d bytes exceeds the %d bytes limit of the instance metadata values, "+
+				"set the GCPCluster bootstrapDataStorage to GCS to store it in a GCS bucket", len(bootstrapData), metadataValueMaxSize)
+			s.scope.SetFailureReason("InvalidConfiguration")
+			s.scope.SetFailureMessage(err)
+			return &BootstrapDataError{Reason: infrav1.BootstrapDataInvalidReason, err: err}
+		}
+		return nil
+	}
+
+	name := bootstrapDataObjectName(s.scope.Namespace(), instance.Name, bootstrapData)
+	if err := s.uploadBootstrapData(ctx, storage.Bucket, name, bootstrapData); err != nil {
+		return err
+	}
+
+	stub, err := bootstrapDataFetchStub(storage.Bucket, name, format, s.scope.IsWindows())
+	if err != nil {
+		return err
+	}
+	item.Value = ptr.To(stub)
+	return nil
+}
+
+// bootstrapDataObjectName returns the name of the GCS object holding the bootstrap data of an instance. It is suffixed
+// with a hash of the bootstrap data so that the fetch stub changes with it.
+func bootstrapDataObjectName(namespace, instance, bootstrapData string) string {
+	sum := sha256.Sum256([]byte(bootstrapData))
+	return fmt.Sprintf("%s/%s-%x", namespace, instance, sum[:4])
+}
+
+// uploadBootstrapData uploads the bootstrap data to the GCS object of the bucket.
+func (s *Service) uploadBootstrapData(ctx context.Context, bucket, name, bootstrapData string) error {
+	log := log.FromContext(ctx)
+	log.V(2).Info("Uploading bootstrap data", "bucket", bucket, "object", name)
+	if err := s.objects.Insert(ctx, bucket, name, []byte(bootstrapData)); err != nil {
+		log.Error(err, "Error uploading bootstrap data", "bucket", bucket, "object", name)
+		return &BootstrapDataError{Reason: infrav1.BootstrapDataStorageFailedReason, err: errors.Wrapf(err, "failed to upload bootstrap data to bucket %s", bucket)}
+	}
+	s.scope.SetBootstrapDataObject(fmt.Sprintf("gs://%s/%s", bucket, name))
+	return nil
+}
+
+// bootstrapDataFetchStub returns the bootstrap data fetching the GCS object holding the actual bootstrap data.
+func bootstrapDataFetchStub(bucket, name, format string, windows bool) (string, error) {
+	if format == bootstrapDataFormatIgnition {
+		// Ignition natively fetches gs:// URLs with the instance service account.
+		stub, err := json.Marshal(map[string]any{
+			"ignition": map[string]any{
+				"version": "3.0.0",
+				"config": map[string]any{
+					"replace": map[string]any{"source": fmt.Sprintf("gs://%s/%s", bucket, name)},
+				},
+			},
+		})
+		return string(stub), err
+	}
+
+	tmpl := cloudConfigFetchTemplate
+	if windows {
+		tmpl = windowsFetchTemplate
+	}
+	var stub bytes.Buffer
+	objectURL := fmt.Sprintf("https://storage.googleapis.com/storage/v1/b/%s/o/%s?alt=media", url.PathEscape(bucket), url.PathEscape(name))
+	if err := tmpl.Execute(&stub, struct{ URL string }{URL: objectURL}); err != nil {
+		return "", errors.Wrap(err, "failed to generate bootstrap data fetch script")
+	}
+	return stub.String(), nil
+}
+
+// reconcileBootstrapDataObject deletes the GCS object holding the bootstrap data once the TTL has elapsed since the
+// instance was created, the instance is expected to have fetched it by then. The object is kept until the machine
+// joins the cluster when it can be re-bootstrapped, as the instance fetches it again on reset.
+func (s *Service) reconcileBootstrapDataObject(ctx context.Context, instance *compute.Instance) error {
+	if s.scope.BootstrapDataObject() == "" {
+		return nil
+	}
+	if s.scope.RebootstrapPolicy() != infrav1.RebootstrapPolicyNever && !s.scope.HasNodeRef() {
+		return nil
+	}
+
+	ttl := defaultBootstrapDataTTL
+	if storage := s.scope.BootstrapDataStorage(); storage != nil && storage.TTL != nil {
+		ttl = storage.TTL.Duration
+	}
+	created, err := time.Parse(time.RFC3339, instance.CreationTimestamp)
+	if err != nil || time.Since(created) < ttl {
+		return nil
+	}
+
+	return s.deleteBootstrapDataObject(ctx)
+}
+
+// deleteBootstrapDataObject deletes the GCS object holding the bootstrap data, if any.
+func (s *Service) deleteBootstrapDataObject(ctx context.Context) error {
+	object := s.scope.BootstrapDataObject()
+	if object == "" {
+		return nil
+	}
+
+	bucket, name, ok := strings.Cut(strings.TrimPrefix(object, "gs://"), "/")
+	if !ok {
+		// Not an object the controller uploaded, don't try to delete it.
+		s.scope.SetBootstrapDataObject("")
+		return nil
+	}
+
+	log := log.FromContext(ctx)
+	log.V(2).Info("Deleting bootstrap data", "bucket", bucket, "object", name)
+	if err := gcperrors.IgnoreNotFound(s.objects.Delete(ctx, bucket, name)); err != nil {
+		log.Error(err, "Error deleting bootstrap data", "bucket", bucket, "object", name)
+		return errors.Wrapf(err, "failed to delete bootstrap data object %s", object)
+	}
+	s.scope.SetBootstrapDataObject("")
+	return nil
+}
+
+// reconcileBootstrapData updates the bootstrap data in the metadata of an instance whose machine hasn't joined the
+// cluster yet when the bootstrap secret changed, e.g. after the bootstrap provider rotated an expired join token, and
+// resets the instance so it bootstraps again when the rebootstrap policy asks for it. Bootstrap data stored in GCS is
+// uploaded to a new object, the metadata holding the script fetching it. It returns the refreshed instance so the
+// following reconcilers use the current metadata fingerprint.
+func (s *Service) reconcileBootstrapData(ctx context.Context, instance *compute.Instance) (*compute.Instance, error) {
+	policy := s.scope.RebootstrapPolicy()
+	if policy == infrav1.RebootstrapPolicyNever || s.scope.HasNodeRef() || instance.Metadata == nil {
+		return instance, nil
+	}
+
+	bootstrapDataKey := s.scope.BootstrapDataMetadataKey()
+	var item *compute.MetadataItems
+	for _, i := range instance.Metadata.Items {
+		if i.Key == bootstrapDataKey {
+			item = i
+		}
+	}
+	if item == nil {
+		return instance, nil
+	}
+
+	bootstrapData, format, err := s.scope.GetBootstrapDataWithFormat(ctx)
+	if err != nil {
+		return nil, &BootstrapDataError{Reason: infrav1.WaitingForBootstrapDataReason, err: errors.Wrap(err, "failed to retrieve bootstrap data")}
+	}
+	value := bootstrapData
+	storage := s.scope.BootstrapDataStorage()
+	gcs := storage != nil && storage.Type == infrav1.BootstrapDataStorageGCS
+	var objectName string
+	if gcs {
+		objectName = bootstrapDataObjectName(s.scope.Namespace(), instance.Name, bootstrapData)
+		if value, err = bootstrapDataFetchStub(storage.Bucket, objectName, format, s.scope.IsWindows()); err != nil {
+			return nil, err
+		}
+	}
+	if ptr.Deref(item.Value, "") == value {
+		return instance, nil
+	}
+	if err := s.validateBootstrapDataFormat(bootstrapData, format); err != nil {
+		return nil, &BootstrapDataError{Reason: infrav1.BootstrapDataInvalidReason, err: err}
+	}
+	if gcs {
+		// The stale object isn't fetched anymore once the metadata is updated.
+		if err := s.deleteBootstrapDataObject(ctx); err != nil {
+			return nil, err
+		}
+		if err := s.uploadBootstrapData(ctx, storage.Bucket, objectName, bootstrapData); err != nil {
+			return nil, err
+		}
+	} else if len(bootstrapData) > metadataValueMaxSize {
+		return nil, &BootstrapDataError{Reason: infrav1.BootstrapDataInvalidReason,
+			err: errors.Errorf("bootstrap data of %d bytes exceeds the %d bytes limit of the instance metadata values", len(bootstrapData), metadataValueMaxSize)}
+	}
+
+	metadata := &compute.Metadata{Fingerprint: instance.Metadata.Fingerprint}
+	for _, i := range instance.Metadata.Items {
+		if i.Key == bootstrapDataKey {
+			i = &compute.MetadataItems{Key: bootstrapDataKey, Value: ptr.To(value)}
+		}
+		metadata.Items = append(metadata.Items, i)
+	}
+
+	log := log.FromContext(ctx)
+	key := meta.ZonalKey(instance.Name, s.scope.Zone())
+	log.Info("Updating instance bootstrap data", "name", instance.Name, "zone", s.scope.Zone(), "policy", policy)
+	if err := s.computeInstances.SetMetadata(ctx, key, metadata); err != nil {
+		if isMetadataFingerprintMismatch(err) {
+			// The metadata changed since the instance was read, the next reconcile retries with the new fingerprint.
+			return nil, errors.Wrapf(err, "metadata of instance %s changed while updating its bootstrap data", instance.Name)
+		}
+		log.Error(err, "Error updating instance bootstrap data", "name", instance.Name, "zone", s.scope.Zone())
+		return nil, err
+	}
+
+	// The metadata now matches the bootstrap data, a failed reset isn't retried and the instance picks up the new
+	// bootstrap data on its next boot instead.
+	if policy == infrav1.RebootstrapPolicyReset && instance.Status == string(infrav1.InstanceStatusRunning) {
+		log.Info("Resetting instance to bootstrap it again", "name", instance.Name, "zone", s.scope.Zone())
+		if err := s.computeInstances.Reset(ctx, key); err != nil {
+			log.Error(err, "Error resetting instance", "name", instance.Name, "zone", s.scope.Zone())
+			return nil, err
+		}
+	}
+
+	return s.instances.Get(ctx, key)
+}
+
+// isMetadataFingerprintMismatch reports whether err is a Google API error caused by the
+// metadata fingerprint of a setMetadata request being out of date.
+func isMetadataFingerprintMismatch(err error) bool {
+	ae, ok := err.(*googleapi.Error)
+	if !ok || ae.Code != http.StatusPreconditionFailed {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(ae.Message), "fingerprint")
+}
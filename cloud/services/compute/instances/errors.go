@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instances
+
+import (
+	"fmt"
+)
+
+// ZoneResourcePoolExhaustedError is used when the zone of the instance doesn't have enough resources to create it.
+type ZoneResourcePoolExhaustedError struct {
+	Zone string
+	err  error
+}
+
+func (e *ZoneResourcePoolExhaustedError) Error() string {
+	return fmt.Sprintf("zone %s doesn't have enough resources to create the instance: %v", e.Zone, e.err)
+}
+
+func (e *ZoneResourcePoolExhaustedError) Unwrap() error {
+	return e.err
+}
+
+// ResourceManagerTagsError is used when the resource manager tags of the instance can't be resolved or bound to it,
+// usually because the controller service account lacks permissions on the tags.
+type ResourceManagerTagsError struct {
+	err error
+}
+
+func (e *ResourceManagerTagsError) Error() string {
+	return fmt.Sprintf("failed to bind the resource manager tags to the instance, make sure the controller service account "+
+		"has the roles/resourcemanager.tagUser role on the tag values: %v", e.err)
+}
+
+func (e *ResourceManagerTagsError) Unwrap() error {
+	return e.err
+}
+
+// ImageNotFoundError is used when the image of the instance, or the latest image of its image family or image lookup,
+// doesn't exist.
+type ImageNotFoundError struct {
+	err error
+}
+
+func (e *ImageNotFoundError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ImageNotFoundError) Unwrap() error {
+	return e.err
+}
+
+// BootstrapDataError is used when the bootstrap data can't be delivered to the instance, its reason is reported in the
+// BootstrapDataReady condition of the machine.
+type BootstrapDataError struct {
+	Reason string
+	err    error
+}
+
+func (e *BootstrapDataError) Error() string {
+	return e.err.Error()
+}
+
+func (e *BootstrapDataError) Unwrap() error {
+	return e.err
+}
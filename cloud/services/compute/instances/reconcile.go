@@ -18,20 +18,33 @@ package instances
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
+	k8scloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/gcperrors"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/providerid"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -41,9 +54,80 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	log.Info("Reconciling instance resources")
 	instance, err := s.createOrGetInstance(ctx)
 	if err != nil {
+		var bootstrapErr *BootstrapDataError
+		if errors.As(err, &bootstrapErr) {
+			s.scope.MarkConditionFalse(infrav1.BootstrapDataReadyCondition, bootstrapErr.Reason, err)
+		}
+		s.scope.MarkConditionFalse(infrav1.InstanceProvisionedCondition, instanceProvisioningFailureReason(err), err)
+		return err
+	}
+	s.scope.MarkConditionTrue(infrav1.InstanceProvisionedCondition)
+	s.scope.MarkConditionTrue(infrav1.BootstrapDataReadyCondition)
+
+	// The drift of the instance from the machine spec is converged against a single spec.
+	spec := s.scope.InstanceSpec(log)
+	s.drifted = nil
+	if err := s.reconcileLabels(ctx, instance, spec); err != nil {
+		return err
+	}
+
+	if err := s.reconcileNetworkTags(ctx, instance, spec); err != nil {
+		return err
+	}
+
+	instance, err = s.reconcileBootstrapData(ctx, instance)
+	if err != nil {
+		reason := infrav1.BootstrapDataSyncFailedReason
+		var bootstrapErr *BootstrapDataError
+		if errors.As(err, &bootstrapErr) {
+			reason = bootstrapErr.Reason
+		}
+		s.scope.MarkConditionFalse(infrav1.BootstrapDataReadyCondition, reason, err)
+		return err
+	}
+
+	if err := s.reconcileMetadata(ctx, instance, spec); err != nil {
+		return err
+	}
+
+	if err := s.reconcileDeletionProtection(ctx, instance, spec); err != nil {
+		return err
+	}
+
+	s.drifted = append(s.drifted, immutableDrift(instance, spec)...)
+	s.scope.SetDrift(s.drifted)
+
+	if err := s.reconcileImage(ctx, instance); err != nil {
+		return err
+	}
+
+	zone := s.scope.Zone()
+	if instance.Zone != "" {
+		zone = path.Base(instance.Zone)
+	}
+
+	s.scope.SetProviderID()
+	s.scope.SetAddresses(s.instanceAddresses(instance))
+	s.scope.SetInstanceStatus(infrav1.InstanceStatus(instance.Status))
+	s.scope.SetInstanceID(strconv.FormatUint(instance.Id, 10))
+	s.scope.SetZone(zone)
+
+	if err := s.reconcileBootstrapDataObject(ctx, instance); err != nil {
 		return err
 	}
 
+	if s.scope.IsControlPlane() {
+		if err := s.registerControlPlaneInstance(ctx, instance); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// instanceAddresses returns the addresses of the instance: the internal and external IPs of all its network
+// interfaces, its internal DNS names and its hostname.
+func (s *Service) instanceAddresses(instance *compute.Instance) []corev1.NodeAddress {
 	addresses := make([]corev1.NodeAddress, 0, len(instance.NetworkInterfaces))
 	for _, iface := range instance.NetworkInterfaces {
 		addresses = append(addresses, corev1.NodeAddress{
@@ -52,6 +136,10 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		})
 
 		for _, ac := range iface.AccessConfigs {
+			if ac.NatIP == "" {
+				// The external IP is not allocated yet.
+				continue
+			}
 			addresses = append(addresses, corev1.NodeAddress{
 				Type:    corev1.NodeExternalIP,
 				Address: ac.NatIP,
@@ -84,23 +172,27 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		Address: machineName,
 	})
 
-	s.scope.SetProviderID()
-	s.scope.SetAddresses(addresses)
-	s.scope.SetInstanceStatus(infrav1.InstanceStatus(instance.Status))
-
-	if s.scope.IsControlPlane() {
-		if err := s.registerControlPlaneInstance(ctx, instance); err != nil {
-			return err
-		}
+	// The hostname of the instance is its name, unless a custom hostname is set.
+	hostname := machineName
+	if instance.Hostname != "" {
+		hostname = instance.Hostname
 	}
+	addresses = append(addresses, corev1.NodeAddress{
+		Type:    corev1.NodeHostName,
+		Address: hostname,
+	})
 
-	return nil
+	return addresses
 }
 
 // Delete delete machine instance.
 func (s *Service) Delete(ctx context.Context) error {
 	log := log.FromContext(ctx)
 	log.Info("Deleting instance resources")
+	if err := s.deleteBootstrapDataObject(ctx); err != nil {
+		return err
+	}
+
 	instanceSpec := s.scope.InstanceSpec(log)
 	instanceName := instanceSpec.Name
 	instanceKey := meta.ZonalKey(instanceName, s.scope.Zone())
@@ -112,7 +204,11 @@ func (s *Service) Delete(ctx context.Context) error {
 			return err
 		}
 
-		return nil
+		if err := s.deleteLeftoverDisks(ctx, instanceSpec); err != nil {
+			return err
+		}
+
+		return s.releaseInternalAddress(ctx)
 	}
 
 	if s.scope.IsControlPlane() {
@@ -121,27 +217,178 @@ func (s *Service) Delete(ctx context.Context) error {
 		}
 	}
 
+	// The machine is being deleted, lift the protection against out of band deletions.
+	if instance.DeletionProtection {
+		log.V(2).Info("Disabling instance deletion protection", "name", instanceName, "zone", s.scope.Zone())
+		if err := s.computeInstances.SetDeletionProtection(ctx, instanceKey, false); err != nil {
+			log.Error(err, "Error disabling instance deletion protection", "name", instanceName, "zone", s.scope.Zone())
+			return err
+		}
+		s.scope.SetDeletionProtection(false)
+	}
+
 	log.V(2).Info("Deleting instance", "name", instanceName, "zone", s.scope.Zone())
-	return gcperrors.IgnoreNotFound(s.instances.Delete(ctx, instanceKey))
+	if err := gcperrors.IgnoreNotFound(s.instances.Delete(ctx, instanceKey)); err != nil {
+		return err
+	}
+
+	if err := s.deleteLeftoverDisks(ctx, instanceSpec); err != nil {
+		return err
+	}
+
+	return s.releaseInternalAddress(ctx)
+}
+
+// Deregister removes the instance from the instance groups managed by the provider, so that the load balancers stop
+// sending it new connections and drain the existing ones before the instance is deleted.
+func (s *Service) Deregister(ctx context.Context) error {
+	log := log.FromContext(ctx)
+	if !s.scope.IsControlPlane() {
+		return nil
+	}
+
+	instanceSpec := s.scope.InstanceSpec(log)
+	instance, err := s.instances.Get(ctx, meta.ZonalKey(instanceSpec.Name, s.scope.Zone()))
+	if err != nil {
+		return gcperrors.IgnoreNotFound(err)
+	}
+
+	return s.deregisterControlPlaneInstance(ctx, instance)
+}
+
+// deleteLeftoverDisks makes sure the auto-deleted additional disks and the regional boot disk of the instance are
+// gone, deleting any disk left behind, e.g. by an instance that failed to be created.
+func (s *Service) deleteLeftoverDisks(ctx context.Context, instance *compute.Instance) error {
+	log := log.FromContext(ctx)
+	for _, disk := range instance.Disks {
+		if !disk.AutoDelete || disk.InitializeParams == nil || disk.InitializeParams.DiskName == "" {
+			continue
+		}
+		if disk.Boot && len(disk.InitializeParams.ReplicaZones) == 0 {
+			continue
+		}
+
+		diskName := disk.InitializeParams.DiskName
+		existing, err := s.getInstanceDisk(ctx, disk)
+		if err != nil {
+			if gcperrors.IsNotFound(err) {
+				continue
+			}
+			log.Error(err, "Error looking for disk before deleting", "name", diskName, "zone", s.scope.Zone())
+			return err
+		}
+
+		if !isOwnedDisk(existing, disk) {
+			log.Info("Skipping the deletion of a disk not owned by the machine", "name", diskName, "zone", s.scope.Zone())
+			continue
+		}
+
+		if len(existing.Users) > 0 {
+			return errors.Errorf("disk %s is still attached to %s", diskName, strings.Join(existing.Users, ", "))
+		}
+
+		log.V(2).Info("Deleting leftover disk", "name", diskName, "zone", s.scope.Zone())
+		if err := gcperrors.IgnoreNotFound(s.deleteInstanceDisk(ctx, disk)); err != nil {
+			log.Error(err, "Error deleting disk", "name", diskName, "zone", s.scope.Zone())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isOwnedDisk reports whether the persistent disk carries the cluster and role ownership labels of the disk of the
+// instance, i.e. it was created for the machine rather than being another disk of the same name.
+func isOwnedDisk(existing *compute.Disk, disk *compute.AttachedDisk) bool {
+	for key, value := range disk.InitializeParams.Labels {
+		if (strings.HasPrefix(key, infrav1.NameGCPProviderOwned) || key == infrav1.NameGCPClusterAPIRole) && existing.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// getInstanceDisk returns the persistent disk of a disk of the instance. Regional disks, i.e. with
+// replica zones, are looked up in the region of the instance.
+func (s *Service) getInstanceDisk(ctx context.Context, disk *compute.AttachedDisk) (*compute.Disk, error) {
+	if len(disk.InitializeParams.ReplicaZones) > 0 {
+		return s.regionDisks.Get(ctx, meta.RegionalKey(disk.InitializeParams.DiskName, s.scope.Region()))
+	}
+	return s.disks.Get(ctx, meta.ZonalKey(disk.InitializeParams.DiskName, s.scope.Zone()))
+}
+
+// deleteInstanceDisk deletes the persistent disk of a disk of the instance, using the regional disks
+// API for regional disks.
+func (s *Service) deleteInstanceDisk(ctx context.Context, disk *compute.AttachedDisk) error {
+	if len(disk.InitializeParams.ReplicaZones) > 0 {
+		return s.regionDisks.Delete(ctx, meta.RegionalKey(disk.InitializeParams.DiskName, s.scope.Region()))
+	}
+	return s.disks.Delete(ctx, meta.ZonalKey(disk.InitializeParams.DiskName, s.scope.Zone()))
+}
+
+const (
+	// bootstrapDataFormatCloudConfig and bootstrapDataFormatIgnition are the formats bootstrap providers set in the
+	// "format" key of the bootstrap data secret.
+	bootstrapDataFormatCloudConfig = "cloud-config"
+	bootstrapDataFormatIgnition    = "ignition"
+)
+
+// validateBootstrapDataFormat checks the bootstrap data can be consumed by the image of the instance, Ignition configs
+// must be valid JSON and aren't supported by Windows Server images.
+func (s *Service) validateBootstrapDataFormat(bootstrapData, format string) error {
+	var err error
+	switch format {
+	case "", bootstrapDataFormatCloudConfig:
+		return nil
+	case bootstrapDataFormatIgnition:
+		if s.scope.IsWindows() {
+			err = errors.New("ignition bootstrap data is not supported by Windows machines")
+		} else if !json.Valid([]byte(bootstrapData)) {
+			err = errors.New("ignition bootstrap data is not valid JSON")
+		}
+	default:
+		err = errors.Errorf("unsupported bootstrap data format %q, must be %s or %s", format, bootstrapDataFormatCloudConfig, bootstrapDataFormatIgnition)
+	}
+	if err != nil {
+		s.scope.SetFailureReason("InvalidConfiguration")
+		s.scope.SetFailureMessage(err)
+	}
+	return err
 }
 
 func (s *Service) createOrGetInstance(ctx context.Context) (*compute.Instance, error) {
 	log := log.FromContext(ctx)
 	log.V(2).Info("Getting bootstrap data for machine")
-	bootstrapData, err := s.scope.GetBootstrapData(ctx)
+	bootstrapData, bootstrapDataFormat, err := s.scope.GetBootstrapDataWithFormat(ctx)
 	if err != nil {
 		log.Error(err, "Error getting bootstrap data for machine")
-		return nil, errors.Wrap(err, "failed to retrieve bootstrap data")
+		return nil, &BootstrapDataError{Reason: infrav1.WaitingForBootstrapDataReason, err: errors.Wrap(err, "failed to retrieve bootstrap data")}
+	}
+	if err := s.validateBootstrapDataFormat(bootstrapData, bootstrapDataFormat); err != nil {
+		return nil, &BootstrapDataError{Reason: infrav1.BootstrapDataInvalidReason, err: err}
 	}
 
 	instanceSpec := s.scope.InstanceSpec(log)
 	instanceName := instanceSpec.Name
 	instanceKey := meta.ZonalKey(instanceName, s.scope.Zone())
-	instanceSpec.Metadata.Items = append(instanceSpec.Metadata.Items, &compute.MetadataItems{
-		Key:   "user-data",
+	// Never let additional metadata overwrite the bootstrap data.
+	bootstrapDataKey := s.scope.BootstrapDataMetadataKey()
+	items := make([]*compute.MetadataItems, 0, len(instanceSpec.Metadata.Items)+1)
+	for _, item := range instanceSpec.Metadata.Items {
+		if item.Key != bootstrapDataKey {
+			items = append(items, item)
+		}
+	}
+	// The bootstrap data is set unmodified whatever its format, cloud-init and Ignition both read it from the metadata as is.
+	instanceSpec.Metadata.Items = append(items, &compute.MetadataItems{
+		Key:   bootstrapDataKey,
 		Value: ptr.To[string](bootstrapData),
 	})
 
+	if err := s.validateProviderID(instanceName); err != nil {
+		return nil, err
+	}
+
 	log.V(2).Info("Looking for instance", "name", instanceName, "zone", s.scope.Zone())
 	instance, err := s.instances.Get(ctx, instanceKey)
 	if err != nil {
@@ -150,21 +397,1354 @@ func (s *Service) createOrGetInstance(ctx context.Context) (*compute.Instance, e
 			return nil, err
 		}
 
+		if providerID := s.scope.GetProviderID(); providerID != "" {
+			// The instance was created or adopted before, or was meant to be adopted, don't create another one.
+			s.scope.SetFailureReason("InvalidConfiguration")
+			s.scope.SetFailureMessage(errors.Errorf("instance %s referenced by the provider ID doesn't exist", providerID))
+			return nil, errors.Wrapf(err, "instance %s referenced by the provider ID doesn't exist", providerID)
+		}
+
+		if err := s.validateZone(); err != nil {
+			return nil, err
+		}
+
+		if err := s.resolveImageFamily(ctx, instanceSpec); err != nil {
+			return nil, err
+		}
+
+		if err := s.lookupImage(ctx, instanceSpec); err != nil {
+			return nil, err
+		}
+
+		if err := s.validateSecureBootImage(ctx, instanceSpec); err != nil {
+			return nil, err
+		}
+
+		if err := s.validateGVNICImage(ctx, instanceSpec); err != nil {
+			return nil, err
+		}
+
+		if err := s.validateImageArchitecture(ctx, instanceSpec); err != nil {
+			return nil, err
+		}
+
+		if err := s.validateAliasIPRanges(instanceSpec); err != nil {
+			return nil, err
+		}
+
+		if err := s.validateAdditionalAliasIPRanges(ctx, instanceSpec); err != nil {
+			return nil, err
+		}
+
+		if err := s.validateStackType(ctx, instanceSpec); err != nil {
+			return nil, err
+		}
+
+		if err := s.validateAttachedDisks(ctx, instanceSpec); err != nil {
+			return nil, err
+		}
+
+		if err := s.validateReplicaZones(instanceSpec); err != nil {
+			return nil, err
+		}
+
+		if err := s.validateResourceManagerTags(instanceSpec); err != nil {
+			return nil, err
+		}
+
+		if err := s.storeBootstrapData(ctx, instanceSpec, bootstrapData, bootstrapDataFormat); err != nil {
+			return nil, err
+		}
+
+		if err := s.reserveInternalAddress(ctx, instanceSpec); err != nil {
+			return nil, err
+		}
+
+		if err := s.attachExistingDisks(ctx, instanceSpec); err != nil {
+			return nil, err
+		}
+
+		if err := s.createRegionalBootDisk(ctx, instanceSpec); err != nil {
+			return nil, err
+		}
+
 		log.V(2).Info("Creating an instance", "name", instanceName, "zone", s.scope.Zone())
 		if err := s.instances.Insert(ctx, instanceKey, instanceSpec); err != nil {
 			log.Error(err, "Error creating an instance", "name", instanceName, "zone", s.scope.Zone())
-			return nil, err
+			return nil, s.instanceCreationError(instanceSpec, err)
 		}
 
 		instance, err = s.instances.Get(ctx, instanceKey)
 		if err != nil {
 			return nil, err
 		}
+	} else if s.scope.GetProviderID() != "" && !isOwnedInstance(instance, instanceSpec) {
+		if err := s.validateAdoptedInstance(instance, instanceSpec); err != nil {
+			return nil, err
+		}
+		log.Info("Adopting instance", "name", instanceName, "zone", s.scope.Zone())
 	}
 
 	return instance, nil
 }
 
+// instanceCreationError classifies the error failing to create the instance. The errors which may go away on their
+// own are returned to be retried, the others also set the failure reason and message of the machine.
+func (s *Service) instanceCreationError(instanceSpec *compute.Instance, err error) error {
+	var failureReason string
+	var failureMessage error
+	metric, quota := quotaExceeded(err)
+	switch {
+	case isResourceManagerTagDenied(err):
+		// The permissions on the tags may be granted, keep retrying.
+		return &ResourceManagerTagsError{err: err}
+	case len(instanceSpec.ResourcePolicies) > 0 && isPlacementUnavailable(err):
+		// Capacity may become available for the placement policy, keep retrying.
+		return errors.Wrap(err, "the instance can't be placed according to its placement policy, retrying")
+	case isZoneResourcePoolExhausted(err):
+		// Resources may become available in the zone, keep retrying.
+		return &ZoneResourcePoolExhaustedError{Zone: s.scope.Zone(), err: err}
+	case len(instanceSpec.Scheduling.NodeAffinities) > 0 && isNoSoleTenantCapacity(err):
+		// Capacity may become available on the sole-tenant nodes, keep retrying.
+		return errors.Wrap(err, "no sole-tenant node matching the node affinities has enough capacity for the instance, retrying")
+	case quota:
+		// Quotas are only raised on request, retrying won't help.
+		if metric == "" {
+			metric = "of the instance resources"
+		}
+		failureReason = "InsufficientResources"
+		failureMessage = errors.Errorf("quota %s exceeded in project %s, request a quota increase: %v", metric, s.scope.Project(), err)
+	case len(instanceSpec.GuestAccelerators) > 0 && isAcceleratorUnavailable(err):
+		types := make([]string, 0, len(instanceSpec.GuestAccelerators))
+		for _, accelerator := range instanceSpec.GuestAccelerators {
+			types = append(types, path.Base(accelerator.AcceleratorType))
+		}
+		failureReason = "InvalidConfiguration"
+		failureMessage = errors.Errorf("accelerator type %s is not available in zone %s: %v", strings.Join(types, ", "), s.scope.Zone(), err)
+	case isInvalidMachineType(err):
+		failureReason = "InvalidConfiguration"
+		failureMessage = errors.Errorf("machine type %s is not available in zone %s: %v", path.Base(instanceSpec.MachineType), s.scope.Zone(), err)
+	case isImageNotFound(err):
+		failureReason = "InvalidConfiguration"
+		failureMessage = errors.Errorf("image of the instance doesn't exist: %v", err)
+		err = &ImageNotFoundError{err: err}
+	case isRootDiskTooSmall(err):
+		failureReason = "InvalidConfiguration"
+		failureMessage = errors.Errorf("root device size is smaller than the image size: %v", err)
+	case isServiceAccountUserDenied(err):
+		failureReason = "InvalidConfiguration"
+		failureMessage = errors.Errorf("failed to attach the service account to the instance, make sure the controller "+
+			"service account has the roles/iam.serviceAccountUser role on it: %v", err)
+	case hasCustomerManagedKey(instanceSpec) && isKMSKeyError(err):
+		failureReason = "InvalidConfiguration"
+		failureMessage = errors.Errorf("failed to use the Cloud KMS key for the instance disks, make sure the key exists "+
+			"and the Compute Engine service agent has the roles/cloudkms.cryptoKeyEncrypterDecrypter role on it: %v", err)
+	default:
+		return err
+	}
+
+	s.scope.SetFailureReason(failureReason)
+	s.scope.SetFailureMessage(failureMessage)
+	return err
+}
+
+// validateProviderID makes sure the provider ID of the machine, when set before the instance was created e.g. to
+// adopt an existing instance, references the instance the machine would manage, otherwise the instance would be
+// looked up, updated and deleted under another name or zone.
+func (s *Service) validateProviderID(instanceName string) error {
+	id := s.scope.GetProviderID()
+	if id == "" {
+		return nil
+	}
+
+	providerID, err := providerid.Parse(id)
+	if err != nil {
+		s.scope.SetFailureReason("InvalidConfiguration")
+		s.scope.SetFailureMessage(err)
+		return err
+	}
+	if providerID.Project() != s.scope.Project() || providerID.Location() != s.scope.Zone() || providerID.Name() != instanceName {
+		err := errors.Errorf("provider ID %s must reference instance %s in project %s and zone %s", id, instanceName, s.scope.Project(), s.scope.Zone())
+		s.scope.SetFailureReason("InvalidConfiguration")
+		s.scope.SetFailureMessage(err)
+		return err
+	}
+	return nil
+}
+
+// isOwnedInstance reports whether the instance carries the ownership labels of the cluster, i.e. it was created or
+// already adopted by the machine.
+func isOwnedInstance(instance, instanceSpec *compute.Instance) bool {
+	for key, value := range instanceSpec.Labels {
+		if strings.HasPrefix(key, infrav1.NameGCPProviderOwned) && instance.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// validateAdoptedInstance makes sure an instance created outside of the provider can be adopted by the machine. The
+// ownership labels are applied to the instance once adopted.
+func (s *Service) validateAdoptedInstance(instance, instanceSpec *compute.Instance) error {
+	if len(instance.NetworkInterfaces) == 0 || len(instanceSpec.NetworkInterfaces) == 0 ||
+		!strings.HasSuffix(instance.NetworkInterfaces[0].Network, instanceSpec.NetworkInterfaces[0].Network) {
+		err := errors.Errorf("instance %s to adopt isn't attached to the cluster network", instance.Name)
+		s.scope.SetFailureReason("InvalidConfiguration")
+		s.scope.SetFailureMessage(err)
+		return err
+	}
+	return nil
+}
+
+// reconcileLabels updates the labels of the instance when they drifted from the machine spec. Only the labels built
+// by CAPG and the user-declared labels are managed: user-declared labels last applied to the instance are removed when
+// they are no longer declared, while labels set by other tools are preserved.
+func (s *Service) reconcileLabels(ctx context.Context, instance, spec *compute.Instance) error {
+	log := log.FromContext(ctx)
+	desired := spec.Labels
+	labels := infrav1.Labels{}.AddLabels(instance.Labels)
+	for key := range s.scope.LastAppliedLabels() {
+		if _, ok := desired[key]; !ok {
+			delete(labels, key)
+		}
+	}
+	labels = labels.AddLabels(desired)
+
+	userLabels := infrav1.Labels{}
+	for key, value := range desired {
+		if !strings.HasPrefix(key, infrav1.NameGCPProviderPrefix) {
+			userLabels[key] = value
+		}
+	}
+
+	if labels.Equals(instance.Labels) {
+		s.scope.SetLastAppliedLabels(userLabels)
+		return nil
+	}
+	if !s.correctDrift("labels") {
+		return nil
+	}
+
+	log.V(2).Info("Updating instance labels", "name", instance.Name, "zone", s.scope.Zone())
+	if err := s.computeInstances.SetLabels(ctx, meta.ZonalKey(instance.Name, s.scope.Zone()), &compute.InstancesSetLabelsRequest{
+		Labels:           labels,
+		LabelFingerprint: instance.LabelFingerprint,
+	}); err != nil {
+		log.Error(err, "Error updating instance labels", "name", instance.Name, "zone", s.scope.Zone())
+		return err
+	}
+
+	s.scope.RecordDriftCorrected("labels")
+	s.scope.SetLastAppliedLabels(userLabels)
+	return nil
+}
+
+// reconcileDeletionProtection enables or disables the deletion protection of the instance when it drifted from
+// the machine spec, and reports it in the machine status.
+func (s *Service) reconcileDeletionProtection(ctx context.Context, instance, spec *compute.Instance) error {
+	log := log.FromContext(ctx)
+	enabled := spec.DeletionProtection
+	if instance.DeletionProtection != enabled {
+		if !s.correctDrift("deletion protection") {
+			s.scope.SetDeletionProtection(instance.DeletionProtection)
+			return nil
+		}
+		log.V(2).Info("Updating instance deletion protection", "name", instance.Name, "zone", s.scope.Zone(), "enabled", enabled)
+		if err := s.computeInstances.SetDeletionProtection(ctx, meta.ZonalKey(instance.Name, s.scope.Zone()), enabled); err != nil {
+			log.Error(err, "Error updating instance deletion protection", "name", instance.Name, "zone", s.scope.Zone())
+			return err
+		}
+		s.scope.RecordDriftCorrected("deletion protection")
+	}
+
+	s.scope.SetDeletionProtection(enabled)
+	return nil
+}
+
+// correctDrift returns true if the drift of the instance field from the machine spec must be converged, or records
+// the field as drifted when drift remediation is disabled.
+func (s *Service) correctDrift(field string) bool {
+	if s.scope.DriftRemediationDisabled() {
+		s.drifted = append(s.drifted, field)
+		return false
+	}
+	return true
+}
+
+// immutableDrift returns the immutable instance fields that drifted from the machine spec. They can't be converged
+// without recreating the instance, so they are only reported.
+func immutableDrift(instance, spec *compute.Instance) []string {
+	var drifted []string
+	if path.Base(instance.MachineType) != path.Base(spec.MachineType) {
+		drifted = append(drifted, "machine type")
+	}
+	for _, disk := range instance.Disks {
+		if !disk.Boot {
+			continue
+		}
+		// The boot disk is named after the instance unless the spec names it, a different disk means the boot
+		// disk, and so the image, was replaced.
+		name := instance.Name
+		for _, specDisk := range spec.Disks {
+			if specDisk.Boot && specDisk.InitializeParams != nil && specDisk.InitializeParams.DiskName != "" {
+				name = specDisk.InitializeParams.DiskName
+			}
+		}
+		if disk.Source != "" && path.Base(disk.Source) != name {
+			drifted = append(drifted, "boot disk")
+		}
+	}
+	if len(instance.Disks) != len(spec.Disks) {
+		drifted = append(drifted, "disks")
+	}
+	return drifted
+}
+
+// reconcileImage records the image the boot disk of the instance was created from in the machine status, when it
+// wasn't already recorded while resolving the image of the instance.
+func (s *Service) reconcileImage(ctx context.Context, instance *compute.Instance) error {
+	if s.scope.GetImage() != nil {
+		return nil
+	}
+
+	log := log.FromContext(ctx)
+	for _, disk := range instance.Disks {
+		if !disk.Boot || disk.Source == "" {
+			continue
+		}
+
+		diskName := path.Base(disk.Source)
+		var bootDisk *compute.Disk
+		var err error
+		if strings.Contains(disk.Source, "/regions/") {
+			bootDisk, err = s.regionDisks.Get(ctx, meta.RegionalKey(diskName, s.scope.Region()))
+		} else {
+			bootDisk, err = s.disks.Get(ctx, meta.ZonalKey(diskName, s.scope.Zone()))
+		}
+		if err != nil {
+			log.Error(err, "Error looking for boot disk", "name", diskName, "zone", s.scope.Zone())
+			return err
+		}
+		if bootDisk.SourceImage != "" {
+			s.scope.SetImage(bootDisk.SourceImage)
+		}
+	}
+
+	return nil
+}
+
+// reconcileNetworkTags updates the network tags of the instance when they drifted from the machine and cluster spec.
+func (s *Service) reconcileNetworkTags(ctx context.Context, instance, spec *compute.Instance) error {
+	log := log.FromContext(ctx)
+	tags := spec.Tags
+	var current []string
+	if instance.Tags != nil {
+		current = instance.Tags.Items
+	}
+	if sets.New(tags.Items...).Equal(sets.New(current...)) {
+		return nil
+	}
+
+	if !s.correctDrift("network tags") {
+		return nil
+	}
+
+	if instance.Tags != nil {
+		tags.Fingerprint = instance.Tags.Fingerprint
+	}
+	log.V(2).Info("Updating instance network tags", "name", instance.Name, "zone", s.scope.Zone())
+	if err := s.computeInstances.SetTags(ctx, meta.ZonalKey(instance.Name, s.scope.Zone()), tags); err != nil {
+		log.Error(err, "Error updating instance network tags", "name", instance.Name, "zone", s.scope.Zone())
+		return err
+	}
+
+	s.scope.RecordDriftCorrected("network tags")
+	return nil
+}
+
+// reconcileMetadata updates the metadata of the instance when it drifted from the machine spec. Only the metadata of
+// the machine spec is managed: the keys last applied to the instance are removed when they are no longer in the spec,
+// while the bootstrap data set when the instance was created and the metadata set by other tools, e.g. the ssh-keys of
+// the guest environment, are preserved.
+func (s *Service) reconcileMetadata(ctx context.Context, instance, spec *compute.Instance) error {
+	log := log.FromContext(ctx)
+	bootstrapDataKey := s.scope.BootstrapDataMetadataKey()
+	desired := map[string]*compute.MetadataItems{}
+	var keys []string
+	for _, item := range spec.Metadata.Items {
+		if item.Key != bootstrapDataKey {
+			desired[item.Key] = item
+			keys = append(keys, item.Key)
+		}
+	}
+	lastApplied := sets.New(s.scope.LastAppliedMetadataKeys()...)
+
+	metadata := &compute.Metadata{}
+	var current []*compute.MetadataItems
+	if instance.Metadata != nil {
+		metadata.Fingerprint = instance.Metadata.Fingerprint
+		current = instance.Metadata.Items
+	}
+	applied := sets.New[string]()
+	for _, item := range current {
+		if desiredItem, ok := desired[item.Key]; ok {
+			metadata.Items = append(metadata.Items, desiredItem)
+			applied.Insert(item.Key)
+		} else if item.Key == bootstrapDataKey || !lastApplied.Has(item.Key) {
+			metadata.Items = append(metadata.Items, item)
+		}
+	}
+	for _, key := range keys {
+		if !applied.Has(key) {
+			metadata.Items = append(metadata.Items, desired[key])
+		}
+	}
+	slices.Sort(keys)
+
+	if maps.Equal(metadataItemsMap(metadata.Items), metadataItemsMap(current)) {
+		s.scope.SetLastAppliedMetadataKeys(keys)
+		return nil
+	}
+	if !s.correctDrift("metadata") {
+		return nil
+	}
+
+	log.V(2).Info("Updating instance metadata", "name", instance.Name, "zone", s.scope.Zone())
+	if err := s.computeInstances.SetMetadata(ctx, meta.ZonalKey(instance.Name, s.scope.Zone()), metadata); err != nil {
+		log.Error(err, "Error updating instance metadata", "name", instance.Name, "zone", s.scope.Zone())
+		return err
+	}
+
+	s.scope.RecordDriftCorrected("metadata")
+	s.scope.SetLastAppliedMetadataKeys(keys)
+	return nil
+}
+
+func metadataItemsMap(items []*compute.MetadataItems) map[string]string {
+	out := make(map[string]string, len(items))
+	for _, item := range items {
+		out[item.Key] = ptr.Deref(item.Value, "")
+	}
+
+	return out
+}
+
+// isAcceleratorUnavailable reports whether err is a Google API error
+// caused by a guest accelerator type that the zone does not offer.
+func isAcceleratorUnavailable(err error) bool {
+	ae, ok := err.(*googleapi.Error)
+	if !ok {
+		return false
+	}
+
+	for _, field := range invalidFields(ae) {
+		if strings.HasPrefix(field, "resource.guestAccelerators") {
+			return true
+		}
+	}
+	return slices.Contains(notFoundCollections(ae), "acceleratorTypes")
+}
+
+// instanceProvisioningFailureReason returns the reason of the InstanceProvisioned condition for the error preventing
+// the instance from being created.
+func instanceProvisioningFailureReason(err error) string {
+	var bootstrapErr *BootstrapDataError
+	if errors.As(err, &bootstrapErr) {
+		return bootstrapErr.Reason
+	}
+	var imageErr *ImageNotFoundError
+	if errors.As(err, &imageErr) {
+		return infrav1.ImageNotFoundReason
+	}
+	var exhausted *ZoneResourcePoolExhaustedError
+	if errors.As(err, &exhausted) {
+		return infrav1.ZoneResourcePoolExhaustedReason
+	}
+	var tagsErr *ResourceManagerTagsError
+	if errors.As(err, &tagsErr) {
+		return infrav1.ResourceManagerTagsBindingFailedReason
+	}
+	var ae *googleapi.Error
+	if errors.As(err, &ae) {
+		if _, ok := quotaExceeded(ae); ok {
+			return infrav1.InsufficientResourcesReason
+		}
+		if isInvalidMachineType(ae) {
+			return infrav1.InvalidMachineTypeReason
+		}
+	}
+	return infrav1.InstanceCreationFailedReason
+}
+
+// isInvalidMachineType reports whether err is a Google API error caused by
+// a machine type that doesn't exist in the zone of the instance.
+func isInvalidMachineType(err error) bool {
+	ae, ok := err.(*googleapi.Error)
+	if !ok {
+		return false
+	}
+
+	return slices.Contains(invalidFields(ae), "resource.machineType") || slices.Contains(notFoundCollections(ae), "machineTypes")
+}
+
+// isImageNotFound reports whether err is a Google API error caused by
+// a source image of the boot disk that doesn't exist.
+func isImageNotFound(err error) bool {
+	ae, ok := err.(*googleapi.Error)
+	if !ok {
+		return false
+	}
+
+	return slices.Contains(notFoundCollections(ae), "images")
+}
+
+// isZoneResourcePoolExhausted reports whether err is a Google API error caused by
+// the zone not having enough resources to create the instance.
+func isZoneResourcePoolExhausted(err error) bool {
+	ae, ok := err.(*googleapi.Error)
+	if !ok {
+		return false
+	}
+
+	for _, reason := range errorReasons(ae) {
+		if strings.HasPrefix(reason, "ZONE_RESOURCE_POOL_EXHAUSTED") {
+			return true
+		}
+	}
+	return false
+}
+
+// operationErrorCodeRegexp matches the code of the failed operation in the message of a Google API error, e.g.
+// "ZONE_RESOURCE_POOL_EXHAUSTED - The zone 'projects/proj-id/zones/us-central1-c' does not have enough resources".
+var operationErrorCodeRegexp = regexp.MustCompile(`^([A-Z][A-Z0-9_]*) - `)
+
+// errorReasons returns the reasons of the error items of a Google API error, and the code of the operation error
+// it was converted from as the operation errors come without error items.
+func errorReasons(ae *googleapi.Error) []string {
+	reasons := make([]string, 0, len(ae.Errors)+1)
+	if match := operationErrorCodeRegexp.FindStringSubmatch(ae.Message); match != nil {
+		reasons = append(reasons, match[1])
+	}
+	for _, item := range ae.Errors {
+		reasons = append(reasons, item.Reason)
+	}
+	return reasons
+}
+
+// invalidFieldRegexp matches the field rejected by a bad request, e.g.
+// "Invalid value for field 'resource.machineType': 'zones/us-central1-c/machineTypes/n2-standard-1'.".
+var invalidFieldRegexp = regexp.MustCompile(`^Invalid value for field '([^']+)'`)
+
+// invalidFields returns the fields of the instance rejected by a bad request.
+func invalidFields(ae *googleapi.Error) []string {
+	if ae.Code != http.StatusBadRequest {
+		return nil
+	}
+
+	messages := []string{ae.Message}
+	for _, item := range ae.Errors {
+		messages = append(messages, item.Message)
+	}
+	var fields []string
+	for _, message := range messages {
+		if match := invalidFieldRegexp.FindStringSubmatch(message); match != nil {
+			fields = append(fields, match[1])
+		}
+	}
+	return fields
+}
+
+// notFoundResourceRegexp matches the resource missing from a not found error, e.g.
+// "The resource 'projects/proj-id/global/images/my-image' was not found".
+var notFoundResourceRegexp = regexp.MustCompile(`^The resource '([^']+)' was not found`)
+
+// notFoundCollections returns the collections of the resources missing from a not found error, e.g. "images" for
+// "projects/proj-id/global/images/my-image".
+func notFoundCollections(ae *googleapi.Error) []string {
+	if ae.Code != http.StatusNotFound {
+		return nil
+	}
+
+	messages := []string{ae.Message}
+	for _, item := range ae.Errors {
+		messages = append(messages, item.Message)
+	}
+	var collections []string
+	for _, message := range messages {
+		if match := notFoundResourceRegexp.FindStringSubmatch(message); match != nil {
+			collections = append(collections, path.Base(path.Dir(match[1])))
+		}
+	}
+	return collections
+}
+
+// quotaMetricRegexp matches the quota metric in the message of a Google API error, e.g.
+// "Quota 'CPUS' exceeded. Limit: 24.0 in region us-central1.".
+var quotaMetricRegexp = regexp.MustCompile(`Quota '([^']+)' exceeded`)
+
+// quotaExceeded reports whether err is a Google API error caused by a quota of the project being exceeded, and returns
+// the exceeded quota metric when the error mentions it. Rate limits are not reported as they reset on their own.
+func quotaExceeded(err error) (string, bool) {
+	ae, ok := err.(*googleapi.Error)
+	if !ok || ae.Code == http.StatusTooManyRequests {
+		return "", false
+	}
+
+	exceeded := strings.Contains(ae.Message, "QUOTA_EXCEEDED")
+	messages := []string{ae.Message}
+	for _, item := range ae.Errors {
+		if item.Reason == "rateLimitExceeded" || item.Reason == "userRateLimitExceeded" {
+			return "", false
+		}
+		if item.Reason == "quotaExceeded" || item.Reason == "QUOTA_EXCEEDED" {
+			exceeded = true
+		}
+		messages = append(messages, item.Message)
+	}
+
+	for _, message := range messages {
+		if match := quotaMetricRegexp.FindStringSubmatch(message); match != nil {
+			return match[1], true
+		}
+	}
+	return "", exceeded
+}
+
+// isNoSoleTenantCapacity reports whether err is a Google API error caused by
+// no sole-tenant node matching the node affinities of the instance having enough capacity.
+func isNoSoleTenantCapacity(err error) bool {
+	ae, ok := err.(*googleapi.Error)
+	if !ok || ae.Code != http.StatusBadRequest {
+		return false
+	}
+
+	return strings.Contains(ae.Message, "No feasible nodes found")
+}
+
+// isPlacementUnavailable reports whether err is a Google API error caused by
+// the instance not fitting the placement policy it is attached to.
+func isPlacementUnavailable(err error) bool {
+	ae, ok := err.(*googleapi.Error)
+	if !ok {
+		return false
+	}
+
+	for _, reason := range errorReasons(ae) {
+		if strings.HasPrefix(reason, "ZONE_RESOURCE_POOL_EXHAUSTED") {
+			return true
+		}
+	}
+	for _, field := range invalidFields(ae) {
+		if strings.HasPrefix(field, "resource.resourcePolicies") {
+			return true
+		}
+	}
+	return false
+}
+
+// isRootDiskTooSmall reports whether err is a Google API error caused by
+// a boot disk size smaller than the size of its source image.
+func isRootDiskTooSmall(err error) bool {
+	ae, ok := err.(*googleapi.Error)
+	if !ok || ae.Code != http.StatusBadRequest {
+		return false
+	}
+
+	return strings.Contains(ae.Message, "cannot be smaller than the image size")
+}
+
+// isServiceAccountUserDenied reports whether err is a Google API error caused by the
+// controller not being allowed to act as the service account of the instance.
+func isServiceAccountUserDenied(err error) bool {
+	ae, ok := err.(*googleapi.Error)
+	if !ok || (ae.Code != http.StatusBadRequest && ae.Code != http.StatusForbidden) {
+		return false
+	}
+
+	return strings.Contains(ae.Message, "iam.serviceAccountUser") || strings.Contains(ae.Message, "iam.serviceAccounts.actAs")
+}
+
+// isResourceManagerTagDenied reports whether err is a Google API error caused by the controller service account not
+// being allowed to bind the resource manager tags to the instance.
+func isResourceManagerTagDenied(err error) bool {
+	ae, ok := err.(*googleapi.Error)
+	if !ok || (ae.Code != http.StatusBadRequest && ae.Code != http.StatusForbidden) {
+		return false
+	}
+
+	return strings.Contains(ae.Message, "tagValues/") || strings.Contains(ae.Message, "resourcemanager.tag")
+}
+
+// hasCustomerManagedKey reports whether any of the instance disks is encrypted with a Cloud KMS key.
+func hasCustomerManagedKey(instance *compute.Instance) bool {
+	for _, disk := range instance.Disks {
+		if disk.DiskEncryptionKey != nil && disk.DiskEncryptionKey.KmsKeyName != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isKMSKeyError reports whether err is a Google API error caused by a Cloud KMS key
+// that does not exist or that the service account is not allowed to use.
+func isKMSKeyError(err error) bool {
+	ae, ok := err.(*googleapi.Error)
+	if !ok || (ae.Code != http.StatusBadRequest && ae.Code != http.StatusForbidden && ae.Code != http.StatusNotFound) {
+		return false
+	}
+
+	return strings.Contains(ae.Message, "Cloud KMS") || strings.Contains(ae.Message, "cloudkms") || strings.Contains(ae.Message, "cryptoKeys/")
+}
+
+// imageFamilyCacheTTL is how long the latest image of an image family is cached for.
+const imageFamilyCacheTTL = 5 * time.Minute
+
+// imageFamilyCache caches the latest image of the image families, so that the machines created at the same time,
+// e.g. by a MachineDeployment, don't all look up the same image family.
+var imageFamilyCache = cache.NewLRUExpireCache(256)
+
+// validateZone fails when the zone of the machine, i.e. the failure domain of its Machine, isn't in the region of
+// the cluster, as the instance can't use the cluster network from another region.
+func (s *Service) validateZone() error {
+	zone := s.scope.Zone()
+	if zone == "" {
+		return nil
+	}
+
+	if idx := strings.LastIndex(zone, "-"); idx < 0 || zone[:idx] != s.scope.Region() {
+		err := errors.Errorf("zone %s of the machine is not in the region %s of the cluster", zone, s.scope.Region())
+		s.scope.SetFailureReason("InvalidConfiguration")
+		s.scope.SetFailureMessage(err)
+		return err
+	}
+
+	return nil
+}
+
+// resolveImageFamily replaces the image family of the instance boot disk with its latest image, so that the
+// image the instance was created from is recorded in the machine status.
+func (s *Service) resolveImageFamily(ctx context.Context, instance *compute.Instance) error {
+	log := log.FromContext(ctx)
+	family := s.scope.ImageFamily()
+	if family == nil {
+		return nil
+	}
+
+	sourceImage, ok := imageFamilyCache.Get(*family)
+	if !ok {
+		log.V(2).Info("Looking for the latest image of the image family", "family", *family)
+		image, err := s.getImage(ctx, *family)
+		if err != nil {
+			log.Error(err, "Error looking for the latest image of the image family", "family", *family)
+			if gcperrors.IsNotFound(err) {
+				s.scope.SetFailureReason("InvalidConfiguration")
+				s.scope.SetFailureMessage(errors.Errorf("image family %s has no image available: %v", *family, err))
+				return &ImageNotFoundError{err: err}
+			}
+			return err
+		}
+		if image == nil {
+			return nil
+		}
+		sourceImage = image.SelfLink
+		imageFamilyCache.Add(*family, sourceImage, imageFamilyCacheTTL)
+	}
+
+	for _, disk := range instance.Disks {
+		if disk.Boot && disk.InitializeParams != nil {
+			disk.InitializeParams.SourceImage = sourceImage.(string)
+		}
+	}
+	s.scope.SetImage(sourceImage.(string))
+	return nil
+}
+
+// lookupImage replaces the image of the instance boot disk with the newest non-deprecated image matching the image
+// lookup of the machine, and records it in the machine status.
+func (s *Service) lookupImage(ctx context.Context, instance *compute.Instance) error {
+	log := log.FromContext(ctx)
+	project, fl, err := s.scope.ImageLookup()
+	if err != nil {
+		s.scope.SetFailureReason("InvalidConfiguration")
+		s.scope.SetFailureMessage(err)
+		return err
+	}
+	if project == "" {
+		return nil
+	}
+
+	log.V(2).Info("Looking up image", "project", project, "filter", fl.String())
+	images, err := s.images.List(ctx, fl, k8scloud.ForceProjectID(project))
+	if err != nil {
+		log.Error(err, "Error looking up image", "project", project, "filter", fl.String())
+		return err
+	}
+
+	var newest *compute.Image
+	for _, image := range images {
+		if image.Deprecated != nil && image.Deprecated.State != "" && image.Deprecated.State != "ACTIVE" {
+			continue
+		}
+		if newest == nil || isNewerImage(image, newest) {
+			newest = image
+		}
+	}
+	if newest == nil {
+		err := errors.Errorf("no image found in project %s matching filter %s", project, fl.String())
+		s.scope.SetFailureReason("InvalidConfiguration")
+		s.scope.SetFailureMessage(err)
+		return &ImageNotFoundError{err: err}
+	}
+
+	for _, disk := range instance.Disks {
+		if disk.Boot && disk.InitializeParams != nil {
+			disk.InitializeParams.SourceImage = newest.SelfLink
+		}
+	}
+	s.scope.SetImage(newest.SelfLink)
+	return nil
+}
+
+// isNewerImage returns true if image a was created after image b.
+func isNewerImage(a, b *compute.Image) bool {
+	createdA, errA := time.Parse(time.RFC3339, a.CreationTimestamp)
+	createdB, errB := time.Parse(time.RFC3339, b.CreationTimestamp)
+	if errA != nil || errB != nil {
+		return a.CreationTimestamp > b.CreationTimestamp
+	}
+
+	return createdA.After(createdB)
+}
+
+// validateSecureBootImage makes sure the boot image of an instance with Secure Boot enabled supports UEFI,
+// otherwise the instance would fail to boot.
+func (s *Service) validateSecureBootImage(ctx context.Context, instance *compute.Instance) error {
+	if instance.ShieldedInstanceConfig == nil || !instance.ShieldedInstanceConfig.EnableSecureBoot {
+		return nil
+	}
+
+	sourceImage, supported, err := s.bootImageSupports(ctx, instance, "UEFI_COMPATIBLE")
+	if err != nil || supported {
+		return err
+	}
+
+	err = errors.Errorf("image %s does not support UEFI, disable Secure Boot or use an image with the UEFI_COMPATIBLE guest OS feature", sourceImage)
+	s.scope.SetFailureReason("InvalidConfiguration")
+	s.scope.SetFailureMessage(err)
+	return err
+}
+
+// validateGVNICImage makes sure the boot image of an instance with a gVNIC network interface supports gVNIC,
+// otherwise the instance would have no network connectivity.
+func (s *Service) validateGVNICImage(ctx context.Context, instance *compute.Instance) error {
+	if !slices.ContainsFunc(instance.NetworkInterfaces, func(nic *compute.NetworkInterface) bool {
+		return nic.NicType == string(infrav1.NicTypeGVNIC)
+	}) {
+		return nil
+	}
+
+	sourceImage, supported, err := s.bootImageSupports(ctx, instance, "GVNIC")
+	if err != nil || supported {
+		return err
+	}
+
+	err = errors.Errorf("image %s does not support gVNIC, use the VIRTIO_NET NicType or an image with the GVNIC guest OS feature", sourceImage)
+	s.scope.SetFailureReason("InvalidConfiguration")
+	s.scope.SetFailureMessage(err)
+	return err
+}
+
+// validateImageArchitecture makes sure the boot image of an instance is built for the architecture of its machine
+// type, e.g. Arm images for Arm machine types and x86-64 images for the others, otherwise the instance would fail to
+// boot. Images without architecture are assumed to match.
+func (s *Service) validateImageArchitecture(ctx context.Context, instance *compute.Instance) error {
+	arch := infrav1.MachineTypeArchitecture(instance.MachineType)
+	log := log.FromContext(ctx)
+	for _, disk := range instance.Disks {
+		if !disk.Boot || disk.InitializeParams == nil || disk.InitializeParams.SourceImage == "" {
+			continue
+		}
+
+		sourceImage := disk.InitializeParams.SourceImage
+		image, err := s.getImage(ctx, sourceImage)
+		if err != nil {
+			if gcperrors.IsNotFound(err) {
+				// The instance creation reports the missing image.
+				return nil
+			}
+			log.Error(err, "Error looking for boot image", "image", sourceImage)
+			return err
+		}
+		if image == nil || image.Architecture == "" || image.Architecture == "ARCHITECTURE_UNSPECIFIED" || infrav1.Architecture(image.Architecture) == arch {
+			return nil
+		}
+
+		err = errors.Errorf("image %s is built for the %s architecture, machine type %s requires %s images", sourceImage, image.Architecture, path.Base(instance.MachineType), arch)
+		s.scope.SetFailureReason("InvalidConfiguration")
+		s.scope.SetFailureMessage(err)
+		return err
+	}
+
+	return nil
+}
+
+// bootImageSupports reports whether the boot image of an instance has the given guest OS feature, along with the
+// boot image. Boot images that can't be looked up are assumed to support it.
+func (s *Service) bootImageSupports(ctx context.Context, instance *compute.Instance, feature string) (string, bool, error) {
+	log := log.FromContext(ctx)
+	for _, disk := range instance.Disks {
+		if !disk.Boot || disk.InitializeParams == nil || disk.InitializeParams.SourceImage == "" {
+			continue
+		}
+
+		sourceImage := disk.InitializeParams.SourceImage
+		image, err := s.getImage(ctx, sourceImage)
+		if err != nil {
+			log.Error(err, "Error looking for boot image", "image", sourceImage)
+			return sourceImage, false, err
+		}
+		if image == nil {
+			continue
+		}
+
+		for _, guestOSFeature := range image.GuestOsFeatures {
+			if guestOSFeature.Type == feature {
+				return sourceImage, true, nil
+			}
+		}
+		return sourceImage, false, nil
+	}
+
+	return "", true, nil
+}
+
+// validateAliasIPRanges makes sure the secondary ranges referenced by the alias IP ranges of an instance exist
+// when its subnet is managed by the cluster, otherwise the instance creation would fail.
+func (s *Service) validateAliasIPRanges(instance *compute.Instance) error {
+	for _, networkInterface := range instance.NetworkInterfaces {
+		if networkInterface.Subnetwork == "" || len(networkInterface.AliasIpRanges) == 0 {
+			continue
+		}
+
+		subnetName := path.Base(networkInterface.Subnetwork)
+		for _, subnet := range s.scope.SubnetSpecs() {
+			if subnet.Name != subnetName {
+				continue
+			}
+
+			rangeNames := make(map[string]bool, len(subnet.SecondaryIpRanges))
+			for _, secondaryRange := range subnet.SecondaryIpRanges {
+				rangeNames[secondaryRange.RangeName] = true
+			}
+			for _, aliasIPRange := range networkInterface.AliasIpRanges {
+				if aliasIPRange.SubnetworkRangeName != "" && !rangeNames[aliasIPRange.SubnetworkRangeName] {
+					err := errors.Errorf("alias IP range references secondary range %s which is not defined in the secondaryCidrBlocks of subnet %s", aliasIPRange.SubnetworkRangeName, subnetName)
+					s.scope.SetFailureReason("InvalidConfiguration")
+					s.scope.SetFailureMessage(err)
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateAdditionalAliasIPRanges makes sure the secondary ranges referenced by the alias IP ranges of the
+// additional network interfaces of an instance exist on their subnetworks, otherwise the instance creation would fail.
+func (s *Service) validateAdditionalAliasIPRanges(ctx context.Context, instance *compute.Instance) error {
+	log := log.FromContext(ctx)
+	for i, networkInterface := range instance.NetworkInterfaces {
+		if i == 0 || networkInterface.Subnetwork == "" {
+			continue
+		}
+
+		var subnet *compute.Subnetwork
+		for _, aliasIPRange := range networkInterface.AliasIpRanges {
+			if aliasIPRange.SubnetworkRangeName == "" {
+				continue
+			}
+
+			if subnet == nil {
+				var err error
+				subnet, err = s.getSubnetwork(ctx, networkInterface.Subnetwork)
+				if err != nil {
+					log.Error(err, "Error looking for subnetwork", "subnetwork", networkInterface.Subnetwork)
+					return err
+				}
+				if subnet == nil {
+					break
+				}
+			}
+
+			found := false
+			for _, secondaryRange := range subnet.SecondaryIpRanges {
+				if secondaryRange.RangeName == aliasIPRange.SubnetworkRangeName {
+					found = true
+					break
+				}
+			}
+			if !found {
+				err := errors.Errorf("alias IP range references secondary range %s which is not defined on subnetwork %s", aliasIPRange.SubnetworkRangeName, networkInterface.Subnetwork)
+				s.scope.SetFailureReason("InvalidConfiguration")
+				s.scope.SetFailureMessage(err)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateStackType makes sure the subnet of the primary network interface of a dual-stack instance supports IPv6
+// with the same access type, otherwise the instance creation would fail.
+func (s *Service) validateStackType(ctx context.Context, instance *compute.Instance) error {
+	log := log.FromContext(ctx)
+	if len(instance.NetworkInterfaces) == 0 {
+		return nil
+	}
+
+	networkInterface := instance.NetworkInterfaces[0]
+	if networkInterface.StackType != string(infrav1.StackTypeIPv4IPv6) || networkInterface.Subnetwork == "" {
+		return nil
+	}
+
+	subnet, err := s.getSubnetwork(ctx, networkInterface.Subnetwork)
+	if err != nil {
+		log.Error(err, "Error looking for subnetwork", "subnetwork", networkInterface.Subnetwork)
+		return err
+	}
+	if subnet == nil {
+		return nil
+	}
+
+	if subnet.StackType != string(infrav1.StackTypeIPv4IPv6) && subnet.StackType != string(infrav1.StackTypeIPv6Only) {
+		err := errors.Errorf("subnetwork %s does not support IPv6, set its stackType to IPV4_IPV6 or use the IPV4_ONLY stackType", subnet.Name)
+		s.scope.SetFailureReason("InvalidConfiguration")
+		s.scope.SetFailureMessage(err)
+		return err
+	}
+
+	external := len(networkInterface.Ipv6AccessConfigs) > 0
+	if external != (subnet.Ipv6AccessType == string(infrav1.Ipv6AccessTypeExternal)) {
+		err := errors.Errorf("ipv6AccessType of the instance must match the %s ipv6AccessType of subnetwork %s", subnet.Ipv6AccessType, subnet.Name)
+		s.scope.SetFailureReason("InvalidConfiguration")
+		s.scope.SetFailureMessage(err)
+		return err
+	}
+
+	return nil
+}
+
+// getSubnetwork returns the subnetwork referenced by selfLink, i.e. projects/<project>/regions/<region>/subnetworks/<subnetwork>.
+// It returns nil if selfLink can't be parsed.
+func (s *Service) getSubnetwork(ctx context.Context, selfLink string) (*compute.Subnetwork, error) {
+	idx := strings.Index(selfLink, "projects/")
+	if idx < 0 {
+		return nil, nil
+	}
+
+	parts := strings.Split(selfLink[idx:], "/")
+	if len(parts) != 6 || parts[2] != "regions" || parts[4] != "subnetworks" {
+		return nil, nil
+	}
+
+	return s.subnetworks.Get(ctx, meta.RegionalKey(parts[5], parts[3]), k8scloud.ForceProjectID(parts[1]))
+}
+
+// reserveInternalAddress reserves the static internal address of the instance, or reuses the existing one,
+// and assigns it to the primary network interface.
+func (s *Service) reserveInternalAddress(ctx context.Context, instance *compute.Instance) error {
+	log := log.FromContext(ctx)
+	spec := s.scope.InternalAddressSpec()
+	if spec == nil {
+		return nil
+	}
+
+	addressKey := meta.RegionalKey(spec.Name, s.scope.Region())
+	log.V(2).Info("Looking for internal address", "name", spec.Name, "region", s.scope.Region())
+	address, err := s.addresses.Get(ctx, addressKey)
+	if err != nil {
+		if !gcperrors.IsNotFound(err) {
+			log.Error(err, "Error looking for internal address", "name", spec.Name)
+			return err
+		}
+
+		log.V(2).Info("Reserving internal address", "name", spec.Name, "region", s.scope.Region())
+		if err := s.addresses.Insert(ctx, addressKey, spec); err != nil {
+			log.Error(err, "Error reserving internal address", "name", spec.Name)
+			return err
+		}
+
+		address, err = s.addresses.Get(ctx, addressKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	if address.AddressType != "" && address.AddressType != "INTERNAL" {
+		err := errors.Errorf("address %s is not an internal address", spec.Name)
+		s.scope.SetFailureReason("InvalidConfiguration")
+		s.scope.SetFailureMessage(err)
+		return err
+	}
+
+	for _, user := range address.Users {
+		if !strings.HasSuffix(user, "/instances/"+instance.Name) {
+			err := errors.Errorf("internal address %s (%s) is already in use by %s", spec.Name, address.Address, user)
+			s.scope.SetFailureReason("InvalidConfiguration")
+			s.scope.SetFailureMessage(err)
+			return err
+		}
+	}
+
+	instance.NetworkInterfaces[0].NetworkIP = address.Address
+	return nil
+}
+
+// releaseInternalAddress releases the static internal address of the instance if it was reserved by
+// the provider. Addresses reserved outside of the provider are left untouched.
+func (s *Service) releaseInternalAddress(ctx context.Context) error {
+	log := log.FromContext(ctx)
+	spec := s.scope.InternalAddressSpec()
+	if spec == nil {
+		return nil
+	}
+
+	addressKey := meta.RegionalKey(spec.Name, s.scope.Region())
+	address, err := s.addresses.Get(ctx, addressKey)
+	if err != nil {
+		return gcperrors.IgnoreNotFound(err)
+	}
+
+	for key, value := range spec.Labels {
+		// Only the labels built by CAPG mark the ownership, the additional labels may have changed since the
+		// address was reserved.
+		if !strings.HasPrefix(key, infrav1.NameGCPProviderPrefix) {
+			continue
+		}
+		if address.Labels[key] != value {
+			log.V(2).Info("Internal address is not owned by the cluster, skipping release", "name", spec.Name)
+			return nil
+		}
+	}
+
+	log.V(2).Info("Releasing internal address", "name", spec.Name, "region", s.scope.Region())
+	if err := gcperrors.IgnoreNotFound(s.addresses.Delete(ctx, addressKey)); err != nil {
+		log.Error(err, "Error releasing internal address", "name", spec.Name)
+		return err
+	}
+
+	return nil
+}
+
+// getImage returns the image or the latest image of the image family referenced by sourceImage,
+// i.e. projects/<project>/global/images/<image> or projects/<project>/global/images/family/<family>.
+// It returns nil if sourceImage can't be parsed.
+func (s *Service) getImage(ctx context.Context, sourceImage string) (*compute.Image, error) {
+	idx := strings.Index(sourceImage, "projects/")
+	if idx < 0 {
+		return nil, nil
+	}
+
+	parts := strings.Split(sourceImage[idx:], "/")
+	if len(parts) < 5 || parts[2] != "global" || parts[3] != "images" {
+		return nil, nil
+	}
+
+	project := parts[1]
+	switch {
+	case len(parts) == 5:
+		return s.images.Get(ctx, meta.GlobalKey(parts[4]), k8scloud.ForceProjectID(project))
+	case len(parts) == 6 && parts[4] == "family":
+		return s.images.GetFromFamily(ctx, meta.GlobalKey(parts[5]), k8scloud.ForceProjectID(project))
+	default:
+		return nil, nil
+	}
+}
+
+// validateAttachedDisks makes sure the pre-existing disks attached to an instance are in its zone, otherwise the
+// instance creation would fail, and that the disks attached in read-write mode are not in use by another instance.
+func (s *Service) validateAttachedDisks(ctx context.Context, instance *compute.Instance) error {
+	log := log.FromContext(ctx)
+	for _, disk := range instance.Disks {
+		if disk.Boot || disk.InitializeParams != nil || disk.Source == "" {
+			continue
+		}
+
+		idx := strings.Index(disk.Source, "projects/")
+		parts := strings.Split(disk.Source[max(idx, 0):], "/")
+		if idx < 0 || len(parts) != 6 || parts[2] != "zones" || parts[4] != "disks" {
+			err := errors.Errorf("attached disk %s is not a valid disk reference, expected projects/<project>/zones/<zone>/disks/<disk>", disk.Source)
+			s.scope.SetFailureReason("InvalidConfiguration")
+			s.scope.SetFailureMessage(err)
+			return err
+		}
+
+		project, zone, diskName := parts[1], parts[3], parts[5]
+		if zone != s.scope.Zone() {
+			err := errors.Errorf("attached disk %s is in zone %s but the instance is in zone %s, disks can only be attached to instances in the same zone", diskName, zone, s.scope.Zone())
+			s.scope.SetFailureReason("InvalidConfiguration")
+			s.scope.SetFailureMessage(err)
+			return err
+		}
+
+		existing, err := s.disks.Get(ctx, meta.ZonalKey(diskName, zone), k8scloud.ForceProjectID(project))
+		if err != nil {
+			if gcperrors.IsNotFound(err) {
+				// The disk may still be being created, keep retrying.
+				return errors.Errorf("attached disk %s does not exist in project %s and zone %s, retrying", diskName, project, zone)
+			}
+			log.Error(err, "Error looking for attached disk", "name", diskName, "zone", zone)
+			return err
+		}
+
+		if disk.Mode != "READ_ONLY" && len(existing.Users) > 0 {
+			// The disk may be detached from the other instance, e.g. when it is being replaced, keep retrying.
+			return errors.Errorf("attached disk %s is in use by %s and can't be attached in read-write mode, retrying", diskName, strings.Join(existing.Users, ", "))
+		}
+	}
+
+	return nil
+}
+
+// validateResourceManagerTags makes sure all the resource manager tags of the machine were resolved, as the tags that
+// can't be resolved, e.g. because of missing permissions, would be silently left out of the instance.
+func (s *Service) validateResourceManagerTags(instance *compute.Instance) error {
+	tagKeys := sets.New[string]()
+	for _, tag := range s.scope.ResourceManagerTags() {
+		tagKeys.Insert(path.Join(tag.ParentID, tag.Key))
+	}
+	var resolved int
+	if instance.Params != nil {
+		resolved = len(instance.Params.ResourceManagerTags)
+	}
+	if resolved < tagKeys.Len() {
+		return &ResourceManagerTagsError{err: errors.Errorf("resolved %d of the %d resource manager tag keys", resolved, tagKeys.Len())}
+	}
+	return nil
+}
+
+// validateReplicaZones makes sure the replica zones of the regional disks of an instance are in its region and
+// include its zone, otherwise the instance creation would fail.
+func (s *Service) validateReplicaZones(instance *compute.Instance) error {
+	for _, disk := range instance.Disks {
+		if disk.InitializeParams == nil || len(disk.InitializeParams.ReplicaZones) == 0 {
+			continue
+		}
+
+		zones := make([]string, 0, len(disk.InitializeParams.ReplicaZones))
+		for _, replicaZone := range disk.InitializeParams.ReplicaZones {
+			zone := path.Base(replicaZone)
+			if !strings.HasPrefix(zone, s.scope.Region()+"-") {
+				err := errors.Errorf("replica zone %s of disk %s is not in the region %s of the instance", zone, disk.InitializeParams.DiskName, s.scope.Region())
+				s.scope.SetFailureReason("InvalidConfiguration")
+				s.scope.SetFailureMessage(err)
+				return err
+			}
+			zones = append(zones, zone)
+		}
+
+		if !slices.Contains(zones, s.scope.Zone()) {
+			err := errors.Errorf("replica zones %s of disk %s must include the zone %s of the instance", strings.Join(zones, ", "), disk.InitializeParams.DiskName, s.scope.Zone())
+			s.scope.SetFailureReason("InvalidConfiguration")
+			s.scope.SetFailureMessage(err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createRegionalBootDisk creates the boot disk of the instance as a regional persistent disk when it has replica
+// zones, as regional boot disks can't be created along with the instance, and attaches it to the instance instead.
+// A disk left behind by a previous failed instance creation is attached as is.
+func (s *Service) createRegionalBootDisk(ctx context.Context, instance *compute.Instance) error {
+	log := log.FromContext(ctx)
+	for _, disk := range instance.Disks {
+		if !disk.Boot || disk.InitializeParams == nil || len(disk.InitializeParams.ReplicaZones) == 0 {
+			continue
+		}
+
+		params := disk.InitializeParams
+		diskKey := meta.RegionalKey(params.DiskName, s.scope.Region())
+		log.V(2).Info("Looking for regional boot disk", "name", params.DiskName, "region", s.scope.Region())
+		bootDisk, err := s.regionDisks.Get(ctx, diskKey)
+		if err != nil {
+			if !gcperrors.IsNotFound(err) {
+				log.Error(err, "Error looking for regional boot disk", "name", params.DiskName, "region", s.scope.Region())
+				return err
+			}
+
+			replicaZones := make([]string, 0, len(params.ReplicaZones))
+			for _, replicaZone := range params.ReplicaZones {
+				replicaZones = append(replicaZones, path.Join("projects", s.scope.Project(), replicaZone))
+			}
+			log.V(2).Info("Creating regional boot disk", "name", params.DiskName, "region", s.scope.Region())
+			if err := s.regionDisks.Insert(ctx, diskKey, &compute.Disk{
+				Name:                  params.DiskName,
+				SizeGb:                params.DiskSizeGb,
+				Type:                  path.Join("projects", s.scope.Project(), params.DiskType),
+				SourceImage:           params.SourceImage,
+				ReplicaZones:          replicaZones,
+				Labels:                params.Labels,
+				ProvisionedIops:       params.ProvisionedIops,
+				ProvisionedThroughput: params.ProvisionedThroughput,
+				DiskEncryptionKey:     disk.DiskEncryptionKey,
+				Params:                &compute.DiskParams{ResourceManagerTags: params.ResourceManagerTags},
+			}); err != nil {
+				log.Error(err, "Error creating regional boot disk", "name", params.DiskName, "region", s.scope.Region())
+				return err
+			}
+
+			bootDisk, err = s.regionDisks.Get(ctx, diskKey)
+			if err != nil {
+				return err
+			}
+		} else if !isOwnedDisk(bootDisk, disk) {
+			return errors.Errorf("regional boot disk %s already exists and isn't owned by the machine", params.DiskName)
+		}
+
+		disk.Source = bootDisk.SelfLink
+		disk.InitializeParams = nil
+	}
+
+	return nil
+}
+
+// attachExistingDisks replaces the initialize params of the non-boot disks that already exist,
+// e.g. left behind by a previous failed instance creation, with a reference to the existing disk. A disk of the same
+// name without the ownership labels of the machine is never attached.
+func (s *Service) attachExistingDisks(ctx context.Context, instance *compute.Instance) error {
+	log := log.FromContext(ctx)
+	for _, disk := range instance.Disks {
+		if disk.Boot || disk.InitializeParams == nil || disk.InitializeParams.DiskName == "" {
+			continue
+		}
+
+		diskName := disk.InitializeParams.DiskName
+		existing, err := s.getInstanceDisk(ctx, disk)
+		if err != nil {
+			if gcperrors.IsNotFound(err) {
+				continue
+			}
+			log.Error(err, "Error looking for disk", "name", diskName, "zone", s.scope.Zone())
+			return err
+		}
+
+		if !isOwnedDisk(existing, disk) {
+			return errors.Errorf("disk %s already exists and isn't owned by the machine", diskName)
+		}
+
+		log.V(2).Info("Attaching existing disk", "name", diskName, "zone", s.scope.Zone())
+		disk.Source = existing.SelfLink
+		disk.InitializeParams = nil
+	}
+
+	return nil
+}
+
 func (s *Service) registerControlPlaneInstance(ctx context.Context, instance *compute.Instance) error {
 	log := log.FromContext(ctx)
 	instancegroupName := s.scope.ControlPlaneGroupName()
@@ -18,15 +18,21 @@ package instances
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/api/compute/v1"
 	"google.golang.org/api/googleapi"
+	"google.golang.org/api/storage/v1"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -37,6 +43,8 @@ import (
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/scope"
 	clusterv1beta1 "sigs.k8s.io/cluster-api/api/core/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	v1beta1conditions "sigs.k8s.io/cluster-api/util/deprecated/v1beta1/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -190,6 +198,8 @@ func TestService_createOrGetInstance(t *testing.T) {
 		name         string
 		scope        func() Scope
 		mockInstance *cloud.MockInstances
+		mockDisks    *cloud.MockDisks
+		mockImages   *cloud.MockImages
 		want         *compute.Instance
 		wantErr      bool
 	}{
@@ -239,7 +249,9 @@ func TestService_createOrGetInstance(t *testing.T) {
 							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
 							ResourceManagerTags: map[string]string{},
 							Labels: map[string]string{
-								"foo": "bar",
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
 							},
 						},
 					},
@@ -307,7 +319,9 @@ func TestService_createOrGetInstance(t *testing.T) {
 							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
 							ResourceManagerTags: map[string]string{},
 							Labels: map[string]string{
-								"foo": "bar",
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
 							},
 						},
 					},
@@ -364,6 +378,12 @@ func TestService_createOrGetInstance(t *testing.T) {
 				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
 				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
 			},
+			mockImages: &cloud.MockImages{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				GetFromFamilyHook: func(_ context.Context, _ *meta.Key, _ *cloud.MockImages, _ ...cloud.Option) (*compute.Image, error) {
+					return &compute.Image{GuestOsFeatures: []*compute.GuestOsFeature{{Type: "UEFI_COMPATIBLE"}}}, nil
+				},
+			},
 			want: &compute.Instance{
 				Name:                   "my-machine",
 				CanIpForward:           true,
@@ -377,7 +397,9 @@ func TestService_createOrGetInstance(t *testing.T) {
 							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
 							ResourceManagerTags: map[string]string{},
 							Labels: map[string]string{
-								"foo": "bar",
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
 							},
 						},
 					},
@@ -447,7 +469,9 @@ func TestService_createOrGetInstance(t *testing.T) {
 							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
 							ResourceManagerTags: map[string]string{},
 							Labels: map[string]string{
-								"foo": "bar",
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
 							},
 						},
 					},
@@ -522,7 +546,9 @@ func TestService_createOrGetInstance(t *testing.T) {
 							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
 							ResourceManagerTags: map[string]string{},
 							Labels: map[string]string{
-								"foo": "bar",
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
 							},
 						},
 					},
@@ -598,7 +624,9 @@ func TestService_createOrGetInstance(t *testing.T) {
 							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
 							ResourceManagerTags: map[string]string{},
 							Labels: map[string]string{
-								"foo": "bar",
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
 							},
 						},
 					},
@@ -674,7 +702,9 @@ func TestService_createOrGetInstance(t *testing.T) {
 							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
 							ResourceManagerTags: map[string]string{},
 							Labels: map[string]string{
-								"foo": "bar",
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
 							},
 						},
 					},
@@ -748,7 +778,9 @@ func TestService_createOrGetInstance(t *testing.T) {
 							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
 							ResourceManagerTags: map[string]string{},
 							Labels: map[string]string{
-								"foo": "bar",
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
 							},
 						},
 					},
@@ -814,7 +846,9 @@ func TestService_createOrGetInstance(t *testing.T) {
 							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
 							ResourceManagerTags: map[string]string{},
 							Labels: map[string]string{
-								"foo": "bar",
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
 							},
 						},
 					},
@@ -887,7 +921,9 @@ func TestService_createOrGetInstance(t *testing.T) {
 							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
 							ResourceManagerTags: map[string]string{},
 							Labels: map[string]string{
-								"foo": "bar",
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
 							},
 						},
 						DiskEncryptionKey: &compute.CustomerEncryptionKey{
@@ -963,7 +999,9 @@ func TestService_createOrGetInstance(t *testing.T) {
 							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
 							ResourceManagerTags: map[string]string{},
 							Labels: map[string]string{
-								"foo": "bar",
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
 							},
 						},
 						DiskEncryptionKey: &compute.CustomerEncryptionKey{
@@ -1039,7 +1077,9 @@ func TestService_createOrGetInstance(t *testing.T) {
 							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
 							ResourceManagerTags: map[string]string{},
 							Labels: map[string]string{
-								"foo": "bar",
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
 							},
 						},
 						DiskEncryptionKey: &compute.CustomerEncryptionKey{
@@ -1086,20 +1126,3852 @@ func TestService_createOrGetInstance(t *testing.T) {
 				Zone: "us-central1-c",
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			ctx := context.TODO()
-			s := New(tt.scope())
-			s.instances = tt.mockInstance
-			got, err := s.createOrGetInstance(ctx)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Service.createOrGetInstance() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if d := cmp.Diff(tt.want, got); d != "" {
-				t.Errorf("Service.createOrGetInstance() mismatch (-want +got):\n%s", d)
+		{
+			name: "instance does not exist (should create instance) preemptible",
+			scope: func() Scope {
+				machineScope.GCPMachine = getFakeGCPMachine()
+				machineScope.GCPMachine.Spec.Preemptible = true
+				return machineScope
+			},
+			mockInstance: &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			},
+			want: &compute.Instance{
+				Name:         "my-machine",
+				CanIpForward: true,
+				Disks: []*compute.AttachedDisk{
+					{
+						AutoDelete: true,
+						Boot:       true,
+						InitializeParams: &compute.AttachedDiskInitializeParams{
+							DiskType:            "zones/us-central1-c/diskTypes/pd-standard",
+							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
+							ResourceManagerTags: map[string]string{},
+							Labels: map[string]string{
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
+							},
+						},
+					},
+				},
+				Labels: map[string]string{
+					"capg-role":               "node",
+					"capg-cluster-my-cluster": "owned",
+					"foo":                     "bar",
+				},
+				MachineType: "zones/us-central1-c/machineTypes",
+				Metadata: &compute.Metadata{
+					Items: []*compute.MetadataItems{
+						{
+							Key:   "user-data",
+							Value: ptr.To[string]("Zm9vCg=="),
+						},
+					},
+				},
+				NetworkInterfaces: []*compute.NetworkInterface{
+					{
+						Network: "projects/my-proj/global/networks/default",
+					},
+				},
+				Params: &compute.InstanceParams{
+					ResourceManagerTags: map[string]string{},
+				},
+				SelfLink: "https://www.googleapis.com/compute/v1/projects/proj-id/zones/us-central1-c/instances/my-machine",
+				Scheduling: &compute.Scheduling{
+					Preemptible:       true,
+					AutomaticRestart:  ptr.To(false),
+					OnHostMaintenance: "TERMINATE",
+				},
+				ServiceAccounts: []*compute.ServiceAccount{
+					{
+						Email:  "default",
+						Scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
+					},
+				},
+				Tags: &compute.Tags{
+					Items: []string{
+						"my-cluster-node",
+						"my-cluster",
+					},
+				},
+				Zone: "us-central1-c",
+			},
+		},
+		{
+			name: "instance does not exist (should create instance) Spot with Delete termination action",
+			scope: func() Scope {
+				machineScope.GCPMachine = getFakeGCPMachine()
+				machineScope.GCPMachine.Spec.ProvisioningModel = ptr.To(infrav1.ProvisioningModelSpot)
+				machineScope.GCPMachine.Spec.TerminationAction = ptr.To(infrav1.InstanceTerminationActionDelete)
+				return machineScope
+			},
+			mockInstance: &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			},
+			want: &compute.Instance{
+				Name:         "my-machine",
+				CanIpForward: true,
+				Disks: []*compute.AttachedDisk{
+					{
+						AutoDelete: true,
+						Boot:       true,
+						InitializeParams: &compute.AttachedDiskInitializeParams{
+							DiskType:            "zones/us-central1-c/diskTypes/pd-standard",
+							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
+							ResourceManagerTags: map[string]string{},
+							Labels: map[string]string{
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
+							},
+						},
+					},
+				},
+				Labels: map[string]string{
+					"capg-role":               "node",
+					"capg-cluster-my-cluster": "owned",
+					"foo":                     "bar",
+				},
+				MachineType: "zones/us-central1-c/machineTypes",
+				Metadata: &compute.Metadata{
+					Items: []*compute.MetadataItems{
+						{
+							Key:   "user-data",
+							Value: ptr.To[string]("Zm9vCg=="),
+						},
+					},
+				},
+				NetworkInterfaces: []*compute.NetworkInterface{
+					{
+						Network: "projects/my-proj/global/networks/default",
+					},
+				},
+				Params: &compute.InstanceParams{
+					ResourceManagerTags: map[string]string{},
+				},
+				SelfLink: "https://www.googleapis.com/compute/v1/projects/proj-id/zones/us-central1-c/instances/my-machine",
+				Scheduling: &compute.Scheduling{
+					ProvisioningModel:         "SPOT",
+					InstanceTerminationAction: "DELETE",
+					AutomaticRestart:          ptr.To(false),
+					OnHostMaintenance:         "TERMINATE",
+				},
+				ServiceAccounts: []*compute.ServiceAccount{
+					{
+						Email:  "default",
+						Scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
+					},
+				},
+				Tags: &compute.Tags{
+					Items: []string{
+						"my-cluster-node",
+						"my-cluster",
+					},
+				},
+				Zone: "us-central1-c",
+			},
+		},
+		{
+			name: "instance does not exist (should create instance) with confidential compute enabled and default OnHostMaintenance (should force TERMINATE)",
+			scope: func() Scope {
+				machineScope.GCPMachine = getFakeGCPMachine()
+				confidentialComputePolicyEnabled := infrav1.ConfidentialComputePolicyEnabled
+				machineScope.GCPMachine.Spec.ConfidentialCompute = &confidentialComputePolicyEnabled
+				return machineScope
+			},
+			mockInstance: &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			},
+			want: &compute.Instance{
+				Name:         "my-machine",
+				CanIpForward: true,
+				Disks: []*compute.AttachedDisk{
+					{
+						AutoDelete: true,
+						Boot:       true,
+						InitializeParams: &compute.AttachedDiskInitializeParams{
+							DiskType:            "zones/us-central1-c/diskTypes/pd-standard",
+							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
+							ResourceManagerTags: map[string]string{},
+							Labels: map[string]string{
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
+							},
+						},
+					},
+				},
+				Labels: map[string]string{
+					"capg-role":               "node",
+					"capg-cluster-my-cluster": "owned",
+					"foo":                     "bar",
+				},
+				MachineType: "zones/us-central1-c/machineTypes",
+				Metadata: &compute.Metadata{
+					Items: []*compute.MetadataItems{
+						{
+							Key:   "user-data",
+							Value: ptr.To[string]("Zm9vCg=="),
+						},
+					},
+				},
+				NetworkInterfaces: []*compute.NetworkInterface{
+					{
+						Network: "projects/my-proj/global/networks/default",
+					},
+				},
+				Params: &compute.InstanceParams{
+					ResourceManagerTags: map[string]string{},
+				},
+				SelfLink: "https://www.googleapis.com/compute/v1/projects/proj-id/zones/us-central1-c/instances/my-machine",
+				ConfidentialInstanceConfig: &compute.ConfidentialInstanceConfig{
+					EnableConfidentialCompute: true,
+				},
+				Scheduling: &compute.Scheduling{
+					OnHostMaintenance: strings.ToUpper(string(infrav1.HostMaintenancePolicyTerminate)),
+				},
+				ServiceAccounts: []*compute.ServiceAccount{
+					{
+						Email:  "default",
+						Scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
+					},
+				},
+				Tags: &compute.Tags{
+					Items: []string{
+						"my-cluster-node",
+						"my-cluster",
+					},
+				},
+				Zone: "us-central1-c",
+			},
+		},
+		{
+			name: "instance does not exist with an additional disk of the same name not owned by the machine (should fail)",
+			scope: func() Scope {
+				machineScope.GCPMachine = getFakeGCPMachine()
+				machineScope.GCPMachine.Spec.AdditionalDisks = []infrav1.AttachedDiskSpec{
+					{DeviceName: ptr.To[string]("data")},
+				}
+				return machineScope
+			},
+			mockInstance: &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			},
+			mockDisks: &cloud.MockDisks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects: map[meta.Key]*cloud.MockDisksObj{
+					{Name: "my-machine-data", Zone: "us-central1-c"}: {Obj: &compute.Disk{Name: "my-machine-data"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "instance does not exist (should create instance) with additional disks (should reuse the existing disk)",
+			scope: func() Scope {
+				machineScope.GCPMachine = getFakeGCPMachine()
+				machineScope.GCPMachine.Spec.AdditionalDisks = []infrav1.AttachedDiskSpec{
+					{
+						DeviceName: ptr.To[string]("data"),
+						AutoDelete: ptr.To(false),
+					},
+					{
+						DeviceType: ptr.To(infrav1.PdSsdDiskType),
+					},
+				}
+				return machineScope
+			},
+			mockInstance: &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			},
+			mockDisks: &cloud.MockDisks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects: map[meta.Key]*cloud.MockDisksObj{
+					{Name: "my-machine-data", Zone: "us-central1-c"}: {Obj: &compute.Disk{
+						Name:     "my-machine-data",
+						SelfLink: "https://www.googleapis.com/compute/v1/projects/proj-id/zones/us-central1-c/disks/my-machine-data",
+						Labels:   ownedDiskLabels,
+					}},
+				},
+			},
+			want: &compute.Instance{
+				Name:         "my-machine",
+				CanIpForward: true,
+				Disks: []*compute.AttachedDisk{
+					{
+						AutoDelete: true,
+						Boot:       true,
+						InitializeParams: &compute.AttachedDiskInitializeParams{
+							DiskType:            "zones/us-central1-c/diskTypes/pd-standard",
+							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
+							ResourceManagerTags: map[string]string{},
+							Labels: map[string]string{
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
+							},
+						},
+					},
+					{
+						DeviceName: "data",
+						Source:     "https://www.googleapis.com/compute/v1/projects/proj-id/zones/us-central1-c/disks/my-machine-data",
+					},
+					{
+						AutoDelete: true,
+						InitializeParams: &compute.AttachedDiskInitializeParams{
+							DiskName:            "my-machine-disk-1",
+							DiskSizeGb:          30,
+							DiskType:            "zones/us-central1-c/diskTypes/pd-ssd",
+							ResourceManagerTags: map[string]string{},
+							Labels: map[string]string{
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
+							},
+						},
+					},
+				},
+				Labels: map[string]string{
+					"capg-role":               "node",
+					"capg-cluster-my-cluster": "owned",
+					"foo":                     "bar",
+				},
+				MachineType: "zones/us-central1-c/machineTypes",
+				Metadata: &compute.Metadata{
+					Items: []*compute.MetadataItems{
+						{
+							Key:   "user-data",
+							Value: ptr.To[string]("Zm9vCg=="),
+						},
+					},
+				},
+				NetworkInterfaces: []*compute.NetworkInterface{
+					{
+						Network: "projects/my-proj/global/networks/default",
+					},
+				},
+				Params: &compute.InstanceParams{
+					ResourceManagerTags: map[string]string{},
+				},
+				SelfLink:   "https://www.googleapis.com/compute/v1/projects/proj-id/zones/us-central1-c/instances/my-machine",
+				Scheduling: &compute.Scheduling{},
+				ServiceAccounts: []*compute.ServiceAccount{
+					{
+						Email:  "default",
+						Scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
+					},
+				},
+				Tags: &compute.Tags{
+					Items: []string{
+						"my-cluster-node",
+						"my-cluster",
+					},
+				},
+				Zone: "us-central1-c",
+			},
+		},
+		{
+			name: "instance does not exist (should not create instance) and SecureBoot enabled on an image without UEFI support",
+			scope: func() Scope {
+				machineScope.GCPMachine = getFakeGCPMachine()
+				machineScope.GCPMachine.Spec.ShieldedInstanceConfig = &infrav1.GCPShieldedInstanceConfig{
+					SecureBoot: infrav1.SecureBootPolicyEnabled,
+				}
+				return machineScope
+			},
+			mockInstance: &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			},
+			mockImages: &cloud.MockImages{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				GetFromFamilyHook: func(_ context.Context, _ *meta.Key, _ *cloud.MockImages, _ ...cloud.Option) (*compute.Image, error) {
+					return &compute.Image{}, nil
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "instance does not exist (should create instance) with MinCPUPlatform",
+			scope: func() Scope {
+				machineScope.GCPMachine = getFakeGCPMachine()
+				machineScope.GCPMachine.Spec.MinCPUPlatform = ptr.To[string]("Intel Ice Lake")
+				return machineScope
+			},
+			mockInstance: &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			},
+			want: &compute.Instance{
+				Name:           "my-machine",
+				CanIpForward:   true,
+				MinCpuPlatform: "Intel Ice Lake",
+				Disks: []*compute.AttachedDisk{
+					{
+						AutoDelete: true,
+						Boot:       true,
+						InitializeParams: &compute.AttachedDiskInitializeParams{
+							DiskType:            "zones/us-central1-c/diskTypes/pd-standard",
+							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
+							ResourceManagerTags: map[string]string{},
+							Labels: map[string]string{
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
+							},
+						},
+					},
+				},
+				Labels: map[string]string{
+					"capg-role":               "node",
+					"capg-cluster-my-cluster": "owned",
+					"foo":                     "bar",
+				},
+				MachineType: "zones/us-central1-c/machineTypes",
+				Metadata: &compute.Metadata{
+					Items: []*compute.MetadataItems{
+						{
+							Key:   "user-data",
+							Value: ptr.To[string]("Zm9vCg=="),
+						},
+					},
+				},
+				NetworkInterfaces: []*compute.NetworkInterface{
+					{
+						Network: "projects/my-proj/global/networks/default",
+					},
+				},
+				Params: &compute.InstanceParams{
+					ResourceManagerTags: map[string]string{},
+				},
+				SelfLink:   "https://www.googleapis.com/compute/v1/projects/proj-id/zones/us-central1-c/instances/my-machine",
+				Scheduling: &compute.Scheduling{},
+				ServiceAccounts: []*compute.ServiceAccount{
+					{
+						Email:  "default",
+						Scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
+					},
+				},
+				Tags: &compute.Tags{
+					Items: []string{
+						"my-cluster-node",
+						"my-cluster",
+					},
+				},
+				Zone: "us-central1-c",
+			},
+		},
+		{
+			name: "instance does not exist (should create instance) with AdvancedMachineFeatures",
+			scope: func() Scope {
+				machineScope.GCPMachine = getFakeGCPMachine()
+				machineScope.GCPMachine.Spec.AdvancedMachineFeatures = &infrav1.AdvancedMachineFeatures{
+					EnableNestedVirtualization: ptr.To(true),
+					ThreadsPerCore:             ptr.To[int64](1),
+				}
+				return machineScope
+			},
+			mockInstance: &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			},
+			want: &compute.Instance{
+				Name:         "my-machine",
+				CanIpForward: true,
+				AdvancedMachineFeatures: &compute.AdvancedMachineFeatures{
+					EnableNestedVirtualization: true,
+					ThreadsPerCore:             1,
+				},
+				Disks: []*compute.AttachedDisk{
+					{
+						AutoDelete: true,
+						Boot:       true,
+						InitializeParams: &compute.AttachedDiskInitializeParams{
+							DiskType:            "zones/us-central1-c/diskTypes/pd-standard",
+							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
+							ResourceManagerTags: map[string]string{},
+							Labels: map[string]string{
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
+							},
+						},
+					},
+				},
+				Labels: map[string]string{
+					"capg-role":               "node",
+					"capg-cluster-my-cluster": "owned",
+					"foo":                     "bar",
+				},
+				MachineType: "zones/us-central1-c/machineTypes",
+				Metadata: &compute.Metadata{
+					Items: []*compute.MetadataItems{
+						{
+							Key:   "user-data",
+							Value: ptr.To[string]("Zm9vCg=="),
+						},
+					},
+				},
+				NetworkInterfaces: []*compute.NetworkInterface{
+					{
+						Network: "projects/my-proj/global/networks/default",
+					},
+				},
+				Params: &compute.InstanceParams{
+					ResourceManagerTags: map[string]string{},
+				},
+				SelfLink:   "https://www.googleapis.com/compute/v1/projects/proj-id/zones/us-central1-c/instances/my-machine",
+				Scheduling: &compute.Scheduling{},
+				ServiceAccounts: []*compute.ServiceAccount{
+					{
+						Email:  "default",
+						Scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
+					},
+				},
+				Tags: &compute.Tags{
+					Items: []string{
+						"my-cluster-node",
+						"my-cluster",
+					},
+				},
+				Zone: "us-central1-c",
+			},
+		},
+		{
+			name: "instance does not exist (should create instance) with sole-tenant NodeAffinities",
+			scope: func() Scope {
+				machineScope.GCPMachine = getFakeGCPMachine()
+				machineScope.GCPMachine.Spec.NodeAffinities = []infrav1.NodeAffinity{
+					{Key: "compute.googleapis.com/node-group-name", Operator: infrav1.NodeAffinityOperatorNotIn, Values: []string{"my-node-group"}},
+				}
+				return machineScope
+			},
+			mockInstance: &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			},
+			want: &compute.Instance{
+				Name:         "my-machine",
+				CanIpForward: true,
+				Disks: []*compute.AttachedDisk{
+					{
+						AutoDelete: true,
+						Boot:       true,
+						InitializeParams: &compute.AttachedDiskInitializeParams{
+							DiskType:            "zones/us-central1-c/diskTypes/pd-standard",
+							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
+							ResourceManagerTags: map[string]string{},
+							Labels: map[string]string{
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
+							},
+						},
+					},
+				},
+				Labels: map[string]string{
+					"capg-role":               "node",
+					"capg-cluster-my-cluster": "owned",
+					"foo":                     "bar",
+				},
+				MachineType: "zones/us-central1-c/machineTypes",
+				Metadata: &compute.Metadata{
+					Items: []*compute.MetadataItems{
+						{
+							Key:   "user-data",
+							Value: ptr.To[string]("Zm9vCg=="),
+						},
+					},
+				},
+				NetworkInterfaces: []*compute.NetworkInterface{
+					{
+						Network: "projects/my-proj/global/networks/default",
+					},
+				},
+				Params: &compute.InstanceParams{
+					ResourceManagerTags: map[string]string{},
+				},
+				SelfLink: "https://www.googleapis.com/compute/v1/projects/proj-id/zones/us-central1-c/instances/my-machine",
+				Scheduling: &compute.Scheduling{
+					NodeAffinities: []*compute.SchedulingNodeAffinity{
+						{Key: "compute.googleapis.com/node-group-name", Operator: "NOT_IN", Values: []string{"my-node-group"}},
+					},
+				},
+				ServiceAccounts: []*compute.ServiceAccount{
+					{
+						Email:  "default",
+						Scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
+					},
+				},
+				Tags: &compute.Tags{
+					Items: []string{
+						"my-cluster-node",
+						"my-cluster",
+					},
+				},
+				Zone: "us-central1-c",
+			},
+		},
+		{
+			name: "instance does not exist (should create instance) with TERMINATE OnHostMaintenance and AutomaticRestart disabled",
+			scope: func() Scope {
+				machineScope.GCPMachine = getFakeGCPMachine()
+				hostMaintenancePolicyTerminate := infrav1.HostMaintenancePolicyTerminate
+				machineScope.GCPMachine.Spec.OnHostMaintenance = &hostMaintenancePolicyTerminate
+				machineScope.GCPMachine.Spec.AutomaticRestart = ptr.To(false)
+				return machineScope
+			},
+			mockInstance: &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			},
+			want: &compute.Instance{
+				Name:         "my-machine",
+				CanIpForward: true,
+				Disks: []*compute.AttachedDisk{
+					{
+						AutoDelete: true,
+						Boot:       true,
+						InitializeParams: &compute.AttachedDiskInitializeParams{
+							DiskType:            "zones/us-central1-c/diskTypes/pd-standard",
+							SourceImage:         "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
+							ResourceManagerTags: map[string]string{},
+							Labels: map[string]string{
+								"capg-cluster-my-cluster": "owned",
+								"capg-role":               "node",
+								"foo":                     "bar",
+							},
+						},
+					},
+				},
+				Labels: map[string]string{
+					"capg-role":               "node",
+					"capg-cluster-my-cluster": "owned",
+					"foo":                     "bar",
+				},
+				MachineType: "zones/us-central1-c/machineTypes",
+				Metadata: &compute.Metadata{
+					Items: []*compute.MetadataItems{
+						{
+							Key:   "user-data",
+							Value: ptr.To[string]("Zm9vCg=="),
+						},
+					},
+				},
+				NetworkInterfaces: []*compute.NetworkInterface{
+					{
+						Network: "projects/my-proj/global/networks/default",
+					},
+				},
+				Params: &compute.InstanceParams{
+					ResourceManagerTags: map[string]string{},
+				},
+				SelfLink: "https://www.googleapis.com/compute/v1/projects/proj-id/zones/us-central1-c/instances/my-machine",
+				Scheduling: &compute.Scheduling{
+					OnHostMaintenance: "TERMINATE",
+					AutomaticRestart:  ptr.To(false),
+				},
+				ServiceAccounts: []*compute.ServiceAccount{
+					{
+						Email:  "default",
+						Scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
+					},
+				},
+				Tags: &compute.Tags{
+					Items: []string{
+						"my-cluster-node",
+						"my-cluster",
+					},
+				},
+				Zone: "us-central1-c",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			s := New(tt.scope())
+			s.instances = tt.mockInstance
+			if tt.mockDisks != nil {
+				s.disks = tt.mockDisks
+			}
+			s.images = fakeImages()
+			if tt.mockImages != nil {
+				s.images = tt.mockImages
+			}
+			got, err := s.createOrGetInstance(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.createOrGetInstance() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("Service.createOrGetInstance() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestService_createOrGetInstance_errors(t *testing.T) {
+	tests := []struct {
+		name               string
+		gcpCluster         func(*infrav1.GCPCluster)
+		machine            func(*clusterv1.Machine)
+		gcpMachine         func(*infrav1.GCPMachine)
+		setup              func(*Service)
+		insertErr          error
+		wantErr            string
+		wantErrReason      string
+		wantFailureReason  string
+		wantFailureMessage string
+	}{
+		{
+			name: "accelerator unavailable",
+			gcpMachine: func(m *infrav1.GCPMachine) {
+				m.Spec.GuestAccelerators = []infrav1.Accelerator{{Type: "nvidia-tesla-a100", Count: 1}}
+			},
+			insertErr: &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid value for field 'resource.guestAccelerators[0].acceleratorType': 'nvidia-tesla-a100'.",
+			},
+			wantFailureReason:  "InvalidConfiguration",
+			wantFailureMessage: "accelerator type nvidia-tesla-a100 is not available in zone us-central1-c",
+		},
+		{
+			name: "Cloud KMS key error",
+			gcpMachine: func(m *infrav1.GCPMachine) {
+				m.Spec.RootDiskEncryptionKey = &infrav1.CustomerEncryptionKey{
+					KeyType: infrav1.CustomerManagedKey,
+					ManagedKey: &infrav1.ManagedKey{
+						KMSKeyName: "projects/my-proj/locations/us-central1/keyRings/my-ring/cryptoKeys/my-key",
+					},
+				}
+			},
+			insertErr: &googleapi.Error{
+				Code:    http.StatusForbidden,
+				Message: "Cloud KMS error when using key projects/my-proj/locations/us-central1/keyRings/my-ring/cryptoKeys/my-key: Permission 'cloudkms.cryptoKeyVersions.useToEncrypt' denied on resource.",
+			},
+			wantFailureReason:  "InvalidConfiguration",
+			wantFailureMessage: "failed to use the Cloud KMS key for the instance disks",
+		},
+		{
+			name: "service account user denied",
+			gcpMachine: func(m *infrav1.GCPMachine) {
+				m.Spec.ServiceAccount = &infrav1.ServiceAccount{Email: "workers@my-proj.iam.gserviceaccount.com"}
+			},
+			insertErr: &googleapi.Error{
+				Code:    http.StatusForbidden,
+				Message: "The user does not have access to service account 'workers@my-proj.iam.gserviceaccount.com'.  User: 'capg@my-proj.iam.gserviceaccount.com'.  Ask a project owner to grant you the iam.serviceAccountUser role on the service account",
+			},
+			wantFailureReason:  "InvalidConfiguration",
+			wantFailureMessage: "roles/iam.serviceAccountUser",
+		},
+		{
+			name: "root disk too small",
+			gcpMachine: func(m *infrav1.GCPMachine) {
+				m.Spec.RootDeviceSize = 5
+			},
+			insertErr: &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid value for field 'resource.disks[0].initializeParams.diskSizeGb': '5'. Requested disk size cannot be smaller than the image size (10 GB)",
+			},
+			wantFailureReason:  "InvalidConfiguration",
+			wantFailureMessage: "root device size is smaller than the image size",
+		},
+		{
+			name: "alias IP range of an undefined secondary range",
+			gcpCluster: func(c *infrav1.GCPCluster) {
+				c.Spec.Network.Subnets = infrav1.Subnets{{
+					Name:                "my-subnet",
+					CidrBlock:           "10.0.0.0/20",
+					SecondaryCidrBlocks: map[string]string{"services": "10.1.0.0/20"},
+				}}
+			},
+			gcpMachine: func(m *infrav1.GCPMachine) {
+				m.Spec.Subnet = ptr.To("my-subnet")
+				m.Spec.AliasIPRanges = []infrav1.AliasIPRange{{IPCidrRange: "/24", SubnetworkRangeName: "pods"}}
+			},
+			wantFailureReason:  "InvalidConfiguration",
+			wantFailureMessage: "secondary range pods which is not defined",
+		},
+		{
+			name: "no sole-tenant capacity",
+			gcpMachine: func(m *infrav1.GCPMachine) {
+				m.Spec.NodeAffinities = []infrav1.NodeAffinity{
+					{Key: "compute.googleapis.com/node-group-name", Operator: infrav1.NodeAffinityOperatorIn, Values: []string{"my-node-group"}},
+				}
+			},
+			insertErr: &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: "No feasible nodes found for the instance given its node affinities and resource requirements.",
+			},
+			wantErr: "no sole-tenant node matching the node affinities has enough capacity",
+		},
+		{
+			name: "zone resource pool exhausted",
+			insertErr: &googleapi.Error{
+				Code: http.StatusServiceUnavailable,
+				Message: "ZONE_RESOURCE_POOL_EXHAUSTED - The zone 'projects/proj-id/zones/us-central1-c' does not have enough resources " +
+					"available to fulfill the request. Try a different zone, or try again later.",
+			},
+			wantErr:       "zone us-central1-c doesn't have enough resources to create the instance",
+			wantErrReason: infrav1.ZoneResourcePoolExhaustedReason,
+		},
+		{
+			name: "quota exceeded",
+			insertErr: &googleapi.Error{
+				Code:    http.StatusForbidden,
+				Message: "QUOTA_EXCEEDED - Quota 'CPUS' exceeded.  Limit: 24.0 in region us-central1.",
+			},
+			wantFailureReason:  "InsufficientResources",
+			wantFailureMessage: "quota CPUS exceeded in project my-proj",
+		},
+		{
+			name: "resource manager tag denied",
+			insertErr: &googleapi.Error{
+				Code:    http.StatusForbidden,
+				Message: "Permission 'resourcemanager.tagValueBindings.create' denied on resource 'tagValues/281484187224371'.",
+			},
+			wantErrReason: infrav1.ResourceManagerTagsBindingFailedReason,
+		},
+		{
+			name: "zone outside of the cluster region",
+			machine: func(m *clusterv1.Machine) {
+				m.Spec.FailureDomain = "europe-west1-b"
+			},
+			wantErr:           "zone europe-west1-b of the machine is not in the region us-central1 of the cluster",
+			wantFailureReason: "InvalidConfiguration",
+		},
+		{
+			name: "placement unavailable",
+			gcpMachine: func(m *infrav1.GCPMachine) {
+				m.Spec.ResourcePolicies = []string{"my-placement-policy"}
+			},
+			insertErr: &googleapi.Error{
+				Code:    http.StatusServiceUnavailable,
+				Message: "ZONE_RESOURCE_POOL_EXHAUSTED - The zone 'projects/proj-id/zones/us-central1-c' does not have enough resources available to fulfill the request.",
+			},
+			wantErr: "can't be placed according to its placement policy",
+		},
+		{
+			name: "internal address in use",
+			gcpMachine: func(m *infrav1.GCPMachine) {
+				m.Spec.InternalAddress = &infrav1.InternalAddressSpec{Name: "my-address"}
+			},
+			setup: func(s *Service) {
+				s.addresses = &cloud.MockAddresses{
+					ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+					Objects: map[meta.Key]*cloud.MockAddressesObj{
+						{Name: "my-address", Region: "us-central1"}: {Obj: &compute.Address{
+							Name:        "my-address",
+							Address:     "10.0.0.10",
+							AddressType: "INTERNAL",
+							Status:      "IN_USE",
+							Users:       []string{"projects/proj-id/zones/us-central1-c/instances/other-machine"},
+						}},
+					},
+				}
+			},
+			wantFailureReason:  "InvalidConfiguration",
+			wantFailureMessage: "already in use by",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakec := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(fakeBootstrapSecret).
+				Build()
+
+			gcpCluster := fakeGCPCluster.DeepCopy()
+			if tt.gcpCluster != nil {
+				tt.gcpCluster(gcpCluster)
+			}
+			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+				Client:     fakec,
+				Cluster:    fakeCluster,
+				GCPCluster: gcpCluster,
+				GCPServices: scope.GCPServices{
+					Compute: &compute.Service{},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			machine := fakeMachine.DeepCopy()
+			if tt.machine != nil {
+				tt.machine(machine)
+			}
+			gcpMachine := getFakeGCPMachine()
+			if tt.gcpMachine != nil {
+				tt.gcpMachine(gcpMachine)
+			}
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       machine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := New(machineScope)
+			s.images = fakeImages()
+			s.instances = &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+				InsertHook: func(_ context.Context, key *meta.Key, _ *compute.Instance, _ *cloud.MockInstances, _ ...cloud.Option) (bool, error) {
+					if tt.insertErr == nil {
+						t.Errorf("Service.createOrGetInstance() created instance %s, want an error before creating it", key.Name)
+					}
+					return true, tt.insertErr
+				},
+			}
+			if tt.setup != nil {
+				tt.setup(s)
+			}
+
+			_, err = s.createOrGetInstance(context.TODO())
+			if err == nil {
+				t.Fatal("Service.createOrGetInstance() error = nil, want an error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Service.createOrGetInstance() error = %v, want %q", err, tt.wantErr)
+			}
+			if tt.wantErrReason != "" {
+				if got := instanceProvisioningFailureReason(err); got != tt.wantErrReason {
+					t.Errorf("instanceProvisioningFailureReason() = %q, want %q", got, tt.wantErrReason)
+				}
+			}
+			if got := ptr.Deref(gcpMachine.Status.FailureReason, ""); got != tt.wantFailureReason {
+				t.Errorf("Service.createOrGetInstance() FailureReason = %q, want %q", got, tt.wantFailureReason)
+			}
+			if got := ptr.Deref(gcpMachine.Status.FailureMessage, ""); !strings.Contains(got, tt.wantFailureMessage) {
+				t.Errorf("Service.createOrGetInstance() FailureMessage = %q, want %q", got, tt.wantFailureMessage)
+			}
+		})
+	}
+}
+
+func TestService_createOrGetInstance_attachedDisks(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		attachedDisks  []infrav1.ExistingDiskSpec
+		wantReason     string
+		wantErrMessage string
+	}{
+		{
+			name:           "disk in another zone",
+			attachedDisks:  []infrav1.ExistingDiskSpec{{Name: "projects/proj-id/zones/us-central1-a/disks/shared"}},
+			wantReason:     "InvalidConfiguration",
+			wantErrMessage: "is in zone us-central1-a but the instance is in zone us-central1-c",
+		},
+		{
+			name:           "disk does not exist",
+			attachedDisks:  []infrav1.ExistingDiskSpec{{Name: "missing"}},
+			wantErrMessage: "attached disk missing does not exist",
+		},
+		{
+			name:           "disk in use in read-write mode",
+			attachedDisks:  []infrav1.ExistingDiskSpec{{Name: "in-use"}},
+			wantErrMessage: "attached disk in-use is in use by other-instance",
+		},
+		{
+			name:          "disk in use in read-only mode",
+			attachedDisks: []infrav1.ExistingDiskSpec{{Name: "in-use", Mode: ptr.To(infrav1.DiskModeReadOnly)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gcpMachine := getFakeGCPMachine()
+			gcpMachine.Spec.AttachedDisks = tt.attachedDisks
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       fakeMachine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := New(machineScope)
+			s.images = fakeImages()
+			s.instances = &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			}
+			s.disks = &cloud.MockDisks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects: map[meta.Key]*cloud.MockDisksObj{
+					{Name: "in-use", Zone: "us-central1-c"}: {Obj: &compute.Disk{Name: "in-use", Users: []string{"other-instance"}}},
+				},
+			}
+
+			_, err = s.createOrGetInstance(context.TODO())
+			if tt.wantErrMessage == "" {
+				if err != nil {
+					t.Fatalf("Service.createOrGetInstance() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErrMessage) {
+				t.Fatalf("Service.createOrGetInstance() error = %v, want %q", err, tt.wantErrMessage)
+			}
+			if got := ptr.Deref(gcpMachine.Status.FailureReason, ""); got != tt.wantReason {
+				t.Errorf("Service.createOrGetInstance() FailureReason = %q, want %q", got, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestService_createOrGetInstance_imageFamily(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imageFamilyCache.Remove("projects/my-images/global/images/family/capi-ubuntu-2204")
+	gcpMachine := getFakeGCPMachine()
+	gcpMachine.Spec.ImageFamily = ptr.To("projects/my-images/global/images/family/capi-ubuntu-2204")
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:        fakec,
+		Machine:       fakeMachine,
+		GCPMachine:    gcpMachine,
+		ClusterGetter: clusterScope,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const selfLink = "https://www.googleapis.com/compute/v1/projects/my-images/global/images/capi-ubuntu-2204-v20260101"
+	var family *meta.Key
+	lookups := 0
+	s := New(machineScope)
+	s.instances = &cloud.MockInstances{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+	}
+	s.images = &cloud.MockImages{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		GetFromFamilyHook: func(_ context.Context, key *meta.Key, _ *cloud.MockImages, _ ...cloud.Option) (*compute.Image, error) {
+			family = key
+			lookups++
+			return &compute.Image{SelfLink: selfLink}, nil
+		},
+	}
+
+	instance, err := s.createOrGetInstance(context.TODO())
+	if err != nil {
+		t.Fatalf("Service.createOrGetInstance() error = %v", err)
+	}
+	if family == nil || family.Name != "capi-ubuntu-2204" {
+		t.Errorf("Service.createOrGetInstance() resolved image family %v, want capi-ubuntu-2204", family)
+	}
+	if got := instance.Disks[0].InitializeParams.SourceImage; got != selfLink {
+		t.Errorf("Service.createOrGetInstance() boot disk SourceImage = %q, want %q", got, selfLink)
+	}
+	if got := ptr.Deref(gcpMachine.Status.Image, ""); got != selfLink {
+		t.Errorf("Service.createOrGetInstance() status Image = %q, want %q", got, selfLink)
+	}
+
+	// The latest image of the family is cached for the next machines.
+	instanceSpec := machineScope.InstanceSpec(logr.Discard())
+	if err := s.resolveImageFamily(context.TODO(), instanceSpec); err != nil {
+		t.Fatalf("Service.resolveImageFamily() error = %v", err)
+	}
+	if lookups != 1 {
+		t.Errorf("Service.resolveImageFamily() looked up the image family %d times, want 1", lookups)
+	}
+	if got := instanceSpec.Disks[0].InitializeParams.SourceImage; got != selfLink {
+		t.Errorf("Service.resolveImageFamily() boot disk SourceImage = %q, want %q", got, selfLink)
+	}
+}
+
+func TestService_createOrGetInstance_imageLookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		images   []*compute.Image
+		want     string
+		wantFail bool
+	}{
+		{
+			name: "picks the newest non-deprecated image",
+			images: []*compute.Image{
+				{SelfLink: "projects/my-images/global/images/old", CreationTimestamp: "2026-01-01T00:00:00.000-07:00"},
+				{SelfLink: "projects/my-images/global/images/new", CreationTimestamp: "2026-03-01T00:00:00.000-07:00"},
+				{
+					SelfLink:          "projects/my-images/global/images/deprecated",
+					CreationTimestamp: "2026-04-01T00:00:00.000-07:00",
+					Deprecated:        &compute.DeprecationStatus{State: "DEPRECATED"},
+				},
+			},
+			want: "projects/my-images/global/images/new",
+		},
+		{
+			name:     "fails when no image matches",
+			images:   []*compute.Image{},
+			wantFail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakec := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(fakeBootstrapSecret).
+				Build()
+
+			gcpCluster := fakeGCPCluster.DeepCopy()
+			gcpCluster.Spec.ImageLookupProject = ptr.To("my-images")
+			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+				Client:     fakec,
+				Cluster:    fakeCluster,
+				GCPCluster: gcpCluster,
+				GCPServices: scope.GCPServices{
+					Compute: &compute.Service{},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gcpMachine := getFakeGCPMachine()
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       fakeMachine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := New(machineScope)
+			s.instances = &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			}
+			s.images = &cloud.MockImages{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				ListHook: func(_ context.Context, _ *filter.F, _ *cloud.MockImages, _ ...cloud.Option) (bool, []*compute.Image, error) {
+					return true, tt.images, nil
+				},
+			}
+
+			instance, err := s.createOrGetInstance(context.TODO())
+			if tt.wantFail {
+				if err == nil {
+					t.Fatal("Service.createOrGetInstance() expected an error")
+				}
+				if got := ptr.Deref(gcpMachine.Status.FailureMessage, ""); !strings.Contains(got, "labels.k8s-version eq") {
+					t.Errorf("Service.createOrGetInstance() FailureMessage = %q, want the filter", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Service.createOrGetInstance() error = %v", err)
+			}
+			if got := instance.Disks[0].InitializeParams.SourceImage; got != tt.want {
+				t.Errorf("Service.createOrGetInstance() boot disk SourceImage = %q, want %q", got, tt.want)
+			}
+			if got := ptr.Deref(gcpMachine.Status.Image, ""); got != tt.want {
+				t.Errorf("Service.createOrGetInstance() status Image = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_createOrGetInstance_additionalAliasIPRangesUndefinedSecondaryRange(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gcpMachine := getFakeGCPMachine()
+	gcpMachine.Spec.AdditionalNetworkInterfaces = []infrav1.NetworkInterfaceSpec{
+		{
+			Network:       "appliance",
+			Subnetwork:    ptr.To("projects/appliances/regions/us-central1/subnetworks/appliance-subnet"),
+			AliasIPRanges: []infrav1.AliasIPRange{{IPCidrRange: "/24", SubnetworkRangeName: "pods"}},
+		},
+	}
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:        fakec,
+		Machine:       fakeMachine,
+		GCPMachine:    gcpMachine,
+		ClusterGetter: clusterScope,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var subnetKey *meta.Key
+	s := New(machineScope)
+	s.images = fakeImages()
+	s.instances = &cloud.MockInstances{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+	}
+	s.subnetworks = &cloud.MockSubnetworks{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects:       map[meta.Key]*cloud.MockSubnetworksObj{},
+		GetHook: func(_ context.Context, key *meta.Key, _ *cloud.MockSubnetworks, _ ...cloud.Option) (bool, *compute.Subnetwork, error) {
+			subnetKey = key
+			return true, &compute.Subnetwork{
+				Name:              "appliance-subnet",
+				SecondaryIpRanges: []*compute.SubnetworkSecondaryRange{{RangeName: "services", IpCidrRange: "10.20.0.0/20"}},
+			}, nil
+		},
+	}
+
+	if _, err := s.createOrGetInstance(context.TODO()); err == nil {
+		t.Fatal("Service.createOrGetInstance() expected an error")
+	}
+	if subnetKey == nil || subnetKey.Name != "appliance-subnet" || subnetKey.Region != "us-central1" {
+		t.Errorf("Service.createOrGetInstance() looked up subnetwork %v, want appliance-subnet in us-central1", subnetKey)
+	}
+	if got := ptr.Deref(gcpMachine.Status.FailureMessage, ""); !strings.Contains(got, "secondary range pods which is not defined") {
+		t.Errorf("Service.createOrGetInstance() FailureMessage = %q", got)
+	}
+}
+
+func TestService_createOrGetInstance_stackType(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		ipv6AccessType *infrav1.Ipv6AccessType
+		subnet         *compute.Subnetwork
+		wantErrMessage string
+	}{
+		{
+			name:           "IPv4 only subnet",
+			subnet:         &compute.Subnetwork{Name: "my-subnet", StackType: "IPV4_ONLY"},
+			wantErrMessage: "subnetwork my-subnet does not support IPv6",
+		},
+		{
+			name:           "external IPv6 on an internal IPv6 subnet",
+			ipv6AccessType: ptr.To(infrav1.Ipv6AccessTypeExternal),
+			subnet:         &compute.Subnetwork{Name: "my-subnet", StackType: "IPV4_IPV6", Ipv6AccessType: "INTERNAL"},
+			wantErrMessage: "must match the INTERNAL ipv6AccessType of subnetwork my-subnet",
+		},
+		{
+			name:           "external IPv6 on an external IPv6 subnet",
+			ipv6AccessType: ptr.To(infrav1.Ipv6AccessTypeExternal),
+			subnet:         &compute.Subnetwork{Name: "my-subnet", StackType: "IPV4_IPV6", Ipv6AccessType: "EXTERNAL"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gcpMachine := getFakeGCPMachine()
+			gcpMachine.Spec.Subnet = ptr.To("my-subnet")
+			gcpMachine.Spec.StackType = ptr.To(infrav1.StackTypeIPv4IPv6)
+			gcpMachine.Spec.Ipv6AccessType = tt.ipv6AccessType
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       fakeMachine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := New(machineScope)
+			s.images = fakeImages()
+			s.instances = &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			}
+			s.subnetworks = &cloud.MockSubnetworks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockSubnetworksObj{},
+				GetHook: func(_ context.Context, _ *meta.Key, _ *cloud.MockSubnetworks, _ ...cloud.Option) (bool, *compute.Subnetwork, error) {
+					return true, tt.subnet, nil
+				},
+			}
+
+			_, err = s.createOrGetInstance(context.TODO())
+			if tt.wantErrMessage == "" {
+				if err != nil {
+					t.Fatalf("Service.createOrGetInstance() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Service.createOrGetInstance() expected an error")
+			}
+			if got := ptr.Deref(gcpMachine.Status.FailureMessage, ""); !strings.Contains(got, tt.wantErrMessage) {
+				t.Errorf("Service.createOrGetInstance() FailureMessage = %q, want %q", got, tt.wantErrMessage)
+			}
+		})
+	}
+}
+
+func TestIsZoneResourcePoolExhausted(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "operation error",
+			err: &googleapi.Error{
+				Code:    http.StatusServiceUnavailable,
+				Message: "ZONE_RESOURCE_POOL_EXHAUSTED - The zone 'projects/proj-id/zones/us-central1-c' does not have enough resources available to fulfill the request.",
+			},
+			want: true,
+		},
+		{
+			name: "operation error with details",
+			err: &googleapi.Error{
+				Code:    http.StatusServiceUnavailable,
+				Message: "ZONE_RESOURCE_POOL_EXHAUSTED_WITH_DETAILS - The zone 'projects/proj-id/zones/us-central1-c' does not have enough resources available to fulfill the request.",
+			},
+			want: true,
+		},
+		{
+			name: "error item reason",
+			err: &googleapi.Error{
+				Code:   http.StatusServiceUnavailable,
+				Errors: []googleapi.ErrorItem{{Reason: "ZONE_RESOURCE_POOL_EXHAUSTED"}},
+			},
+			want: true,
+		},
+		{
+			name: "quota exceeded",
+			err: &googleapi.Error{
+				Code:    http.StatusForbidden,
+				Message: "QUOTA_EXCEEDED - Quota 'CPUS' exceeded.  Limit: 24.0 in region us-central1.",
+			},
+			want: false,
+		},
+		{
+			name: "not a Google API error",
+			err:  errors.New("ZONE_RESOURCE_POOL_EXHAUSTED"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isZoneResourcePoolExhausted(tt.err); got != tt.want {
+				t.Errorf("isZoneResourcePoolExhausted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuotaExceeded(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantMetric string
+		want       bool
+	}{
+		{
+			name: "operation error",
+			err: &googleapi.Error{
+				Code:    http.StatusForbidden,
+				Message: "QUOTA_EXCEEDED - Quota 'CPUS' exceeded.  Limit: 24.0 in region us-central1.",
+			},
+			wantMetric: "CPUS",
+			want:       true,
+		},
+		{
+			name: "error item reason",
+			err: &googleapi.Error{
+				Code: http.StatusForbidden,
+				Errors: []googleapi.ErrorItem{{
+					Reason:  "quotaExceeded",
+					Message: "Quota 'SSD_TOTAL_GB' exceeded.  Limit: 500.0 in region us-central1.",
+				}},
+			},
+			wantMetric: "SSD_TOTAL_GB",
+			want:       true,
+		},
+		{
+			name: "quota exceeded without metric",
+			err: &googleapi.Error{
+				Code:    http.StatusForbidden,
+				Message: "QUOTA_EXCEEDED - Quota exceeded.",
+			},
+			want: true,
+		},
+		{
+			name: "rate limit exceeded",
+			err: &googleapi.Error{
+				Code: http.StatusForbidden,
+				Errors: []googleapi.ErrorItem{{
+					Reason:  "rateLimitExceeded",
+					Message: "Quota exceeded for quota metric 'Queries' and limit 'Queries per minute' of service 'compute.googleapis.com'.",
+				}},
+			},
+			want: false,
+		},
+		{
+			name: "too many requests",
+			err: &googleapi.Error{
+				Code:    http.StatusTooManyRequests,
+				Message: "Quota 'CPUS' exceeded.",
+			},
+			want: false,
+		},
+		{
+			name: "zone resource pool exhausted",
+			err: &googleapi.Error{
+				Code:    http.StatusServiceUnavailable,
+				Message: "ZONE_RESOURCE_POOL_EXHAUSTED - The zone 'projects/proj-id/zones/us-central1-c' does not have enough resources available to fulfill the request.",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric, got := quotaExceeded(tt.err)
+			if got != tt.want || metric != tt.wantMetric {
+				t.Errorf("quotaExceeded() = (%q, %v), want (%q, %v)", metric, got, tt.wantMetric, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsInvalidMachineType(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "invalid machine type",
+			err: &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid value for field 'resource.machineType': 'zones/us-central1-c/machineTypes/n2-standard-1'. Machine type with name 'n2-standard-1' does not exist in zone 'us-central1-c'.",
+			},
+			want: true,
+		},
+		{
+			name: "machine type not found",
+			err: &googleapi.Error{
+				Code:    http.StatusNotFound,
+				Message: "The resource 'projects/proj-id/zones/us-central1-c/machineTypes/n2-standard-1' was not found",
+			},
+			want: true,
+		},
+		{
+			name: "invalid accelerator type for the machine type",
+			err: &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid value for field 'resource.guestAccelerators[0].acceleratorType': 'nvidia-tesla-a100'. The accelerator isn't supported by the machineType.",
+			},
+			want: false,
+		},
+		{
+			name: "image not found",
+			err: &googleapi.Error{
+				Code:    http.StatusNotFound,
+				Message: "The resource 'projects/proj-id/global/images/machineType-image' was not found",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isInvalidMachineType(tt.err); got != tt.want {
+				t.Errorf("isInvalidMachineType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstanceProvisioningFailureReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "missing bootstrap data",
+			err:  &BootstrapDataError{Reason: infrav1.WaitingForBootstrapDataReason, err: errors.New("secret not found")},
+			want: infrav1.WaitingForBootstrapDataReason,
+		},
+		{
+			name: "image not found",
+			err:  &ImageNotFoundError{err: errors.New("no image found")},
+			want: infrav1.ImageNotFoundReason,
+		},
+		{
+			name: "zone resource pool exhausted",
+			err:  &ZoneResourcePoolExhaustedError{Zone: "us-central1-c", err: errors.New("exhausted")},
+			want: infrav1.ZoneResourcePoolExhaustedReason,
+		},
+		{
+			name: "resource manager tags denied",
+			err:  &ResourceManagerTagsError{err: errors.New("denied")},
+			want: infrav1.ResourceManagerTagsBindingFailedReason,
+		},
+		{
+			name: "quota exceeded",
+			err: &googleapi.Error{
+				Code:    http.StatusForbidden,
+				Message: "QUOTA_EXCEEDED - Quota 'CPUS' exceeded.  Limit: 24.0 in region us-central1.",
+			},
+			want: infrav1.InsufficientResourcesReason,
+		},
+		{
+			name: "machine type not found",
+			err: &googleapi.Error{
+				Code:    http.StatusNotFound,
+				Message: "The resource 'projects/proj-id/zones/us-central1-c/machineTypes/n2-standard-1' was not found",
+			},
+			want: infrav1.InvalidMachineTypeReason,
+		},
+		{
+			name: "other error",
+			err:  &googleapi.Error{Code: http.StatusInternalServerError},
+			want: infrav1.InstanceCreationFailedReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := instanceProvisioningFailureReason(tt.err); got != tt.want {
+				t.Errorf("instanceProvisioningFailureReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_Reconcile_conditions(t *testing.T) {
+	tests := []struct {
+		name                  string
+		objects               []client.Object
+		insertErr             error
+		wantProvisionedReason string
+		wantBootstrapReason   string
+		wantFailureReason     string
+	}{
+		{
+			name:                  "missing bootstrap data",
+			wantProvisionedReason: infrav1.WaitingForBootstrapDataReason,
+			wantBootstrapReason:   infrav1.WaitingForBootstrapDataReason,
+		},
+		{
+			name:    "invalid machine type",
+			objects: []client.Object{fakeBootstrapSecret},
+			insertErr: &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid value for field 'resource.machineType': 'zones/us-central1-c/machineTypes/n2-standard-1'. Machine type with name 'n2-standard-1' does not exist in zone 'us-central1-c'.",
+			},
+			wantProvisionedReason: infrav1.InvalidMachineTypeReason,
+			wantFailureReason:     "InvalidConfiguration",
+		},
+		{
+			name:    "image not found",
+			objects: []client.Object{fakeBootstrapSecret},
+			insertErr: &googleapi.Error{
+				Code:    http.StatusNotFound,
+				Message: "The resource 'projects/proj-id/global/images/my-image' was not found",
+			},
+			wantProvisionedReason: infrav1.ImageNotFoundReason,
+			wantFailureReason:     "InvalidConfiguration",
+		},
+		{
+			name:    "quota of the machine type exceeded",
+			objects: []client.Object{fakeBootstrapSecret},
+			insertErr: &googleapi.Error{
+				Code:    http.StatusForbidden,
+				Message: "QUOTA_EXCEEDED - Quota 'N2_CPUS' exceeded.  Limit: 24.0 in region us-central1 for the requested machineType.",
+			},
+			wantProvisionedReason: infrav1.InsufficientResourcesReason,
+			wantFailureReason:     "InsufficientResources",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakec := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(tt.objects...).
+				Build()
+
+			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+				Client:     fakec,
+				Cluster:    fakeCluster,
+				GCPCluster: fakeGCPCluster,
+				GCPServices: scope.GCPServices{
+					Compute: &compute.Service{},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gcpMachine := getFakeGCPMachine()
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       fakeMachine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := New(machineScope)
+			s.images = fakeImages()
+			s.instances = &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+				InsertHook: func(_ context.Context, _ *meta.Key, _ *compute.Instance, _ *cloud.MockInstances, _ ...cloud.Option) (bool, error) {
+					return true, tt.insertErr
+				},
+			}
+
+			if err := s.Reconcile(context.TODO()); err == nil {
+				t.Fatal("Service.Reconcile() error = nil, want an error")
+			}
+			if got := v1beta1conditions.GetReason(gcpMachine, infrav1.InstanceProvisionedCondition); got != tt.wantProvisionedReason {
+				t.Errorf("InstanceProvisioned reason = %q, want %q", got, tt.wantProvisionedReason)
+			}
+			if got := v1beta1conditions.GetReason(gcpMachine, infrav1.BootstrapDataReadyCondition); got != tt.wantBootstrapReason {
+				t.Errorf("BootstrapDataReady reason = %q, want %q", got, tt.wantBootstrapReason)
+			}
+			if got := ptr.Deref(gcpMachine.Status.FailureReason, ""); got != tt.wantFailureReason {
+				t.Errorf("FailureReason = %q, want %q", got, tt.wantFailureReason)
+			}
+		})
+	}
+}
+
+func TestService_createOrGetInstance_adoption(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:        fakec,
+		Machine:       fakeMachine,
+		GCPMachine:    getFakeGCPMachine(),
+		ClusterGetter: clusterScope,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	network := "https://www.googleapis.com/compute/v1/" + machineScope.InstanceSpec(logr.Discard()).NetworkInterfaces[0].Network
+	tests := []struct {
+		name       string
+		providerID string
+		instance   *compute.Instance
+		wantErr    bool
+	}{
+		{
+			name:       "instance is adopted",
+			providerID: "gce://my-proj/us-central1-c/my-machine",
+			instance: &compute.Instance{
+				Name:              "my-machine",
+				NetworkInterfaces: []*compute.NetworkInterface{{Network: network}},
+			},
+		},
+		{
+			name:       "instance to adopt doesn't exist",
+			providerID: "gce://my-proj/us-central1-c/my-machine",
+			wantErr:    true,
+		},
+		{
+			name:       "instance to adopt is in another network",
+			providerID: "gce://my-proj/us-central1-c/my-machine",
+			instance: &compute.Instance{
+				Name:              "my-machine",
+				NetworkInterfaces: []*compute.NetworkInterface{{Network: "https://www.googleapis.com/compute/v1/projects/my-proj/global/networks/other"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "provider ID references another instance",
+			providerID: "gce://my-proj/us-central1-c/other-machine",
+			instance: &compute.Instance{
+				Name:              "my-machine",
+				NetworkInterfaces: []*compute.NetworkInterface{{Network: network}},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "provider ID references another zone",
+			providerID: "gce://my-proj/us-central1-a/my-machine",
+			instance: &compute.Instance{
+				Name:              "my-machine",
+				NetworkInterfaces: []*compute.NetworkInterface{{Network: network}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gcpMachine := getFakeGCPMachine()
+			gcpMachine.Spec.ProviderID = ptr.To(tt.providerID)
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       fakeMachine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			objects := map[meta.Key]*cloud.MockInstancesObj{}
+			if tt.instance != nil {
+				objects[*meta.ZonalKey(tt.instance.Name, "us-central1-c")] = &cloud.MockInstancesObj{Obj: tt.instance}
+			}
+			s := New(machineScope)
+			s.instances = &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "my-proj"},
+				Objects:       objects,
+				InsertHook: func(_ context.Context, key *meta.Key, _ *compute.Instance, _ *cloud.MockInstances, _ ...cloud.Option) (bool, error) {
+					t.Errorf("Service.createOrGetInstance() created instance %s instead of adopting it", key.Name)
+					return true, nil
+				},
+			}
+
+			instance, err := s.createOrGetInstance(context.TODO())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Service.createOrGetInstance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if ptr.Deref(gcpMachine.Status.FailureReason, "") != "InvalidConfiguration" {
+					t.Errorf("Service.createOrGetInstance() FailureReason = %v, want InvalidConfiguration", gcpMachine.Status.FailureReason)
+				}
+				return
+			}
+			if instance.Name != tt.instance.Name {
+				t.Errorf("Service.createOrGetInstance() instance = %s, want %s", instance.Name, tt.instance.Name)
+			}
+		})
+	}
+}
+
+func TestIsPlacementUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "operation error",
+			err: &googleapi.Error{
+				Code:    http.StatusServiceUnavailable,
+				Message: "ZONE_RESOURCE_POOL_EXHAUSTED - The zone 'projects/proj-id/zones/us-central1-c' does not have enough resources available to fulfill the request.",
+			},
+			want: true,
+		},
+		{
+			name: "invalid resource policy",
+			err: &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid value for field 'resource.resourcePolicies[0]': 'projects/proj-id/regions/us-central1/resourcePolicies/my-placement-policy'. The placement policy is full.",
+			},
+			want: true,
+		},
+		{
+			name: "invalid machine type mentioning a placement",
+			err: &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid value for field 'resource.machineType': 'n2-standard-1'. The machine type doesn't support compact placement.",
+			},
+			want: false,
+		},
+		{
+			name: "not a Google API error",
+			err:  errors.New("ZONE_RESOURCE_POOL_EXHAUSTED - placement"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPlacementUnavailable(tt.err); got != tt.want {
+				t.Errorf("isPlacementUnavailable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_createOrGetInstance_gvnic(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   *compute.Image
+		wantErr bool
+	}{
+		{
+			name:  "image supports gVNIC (should create instance)",
+			image: &compute.Image{GuestOsFeatures: []*compute.GuestOsFeature{{Type: "GVNIC"}}},
+		},
+		{
+			name:    "image does not support gVNIC (should not create instance)",
+			image:   &compute.Image{GuestOsFeatures: []*compute.GuestOsFeature{{Type: "UEFI_COMPATIBLE"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakec := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(fakeBootstrapSecret).
+				Build()
+
+			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+				Client:     fakec,
+				Cluster:    fakeCluster,
+				GCPCluster: fakeGCPCluster,
+				GCPServices: scope.GCPServices{
+					Compute: &compute.Service{},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gcpMachine := getFakeGCPMachine()
+			gcpMachine.Spec.NicType = ptr.To(infrav1.NicTypeGVNIC)
+			gcpMachine.Spec.NetworkPerformanceConfig = &infrav1.NetworkPerformanceConfig{
+				TotalEgressBandwidthTier: ptr.To(infrav1.EgressBandwidthTier1),
+			}
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       fakeMachine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := New(machineScope)
+			s.instances = &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			}
+			s.images = &cloud.MockImages{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				GetFromFamilyHook: func(_ context.Context, _ *meta.Key, _ *cloud.MockImages, _ ...cloud.Option) (*compute.Image, error) {
+					return tt.image, nil
+				},
+			}
+
+			instance, err := s.createOrGetInstance(context.TODO())
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "does not support gVNIC") {
+					t.Fatalf("Service.createOrGetInstance() error = %v", err)
+				}
+				if ptr.Deref(gcpMachine.Status.FailureReason, "") != "InvalidConfiguration" {
+					t.Errorf("Service.createOrGetInstance() FailureReason = %v, want InvalidConfiguration", gcpMachine.Status.FailureReason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Service.createOrGetInstance() error = %v", err)
+			}
+			if got := instance.NetworkInterfaces[0].NicType; got != "GVNIC" {
+				t.Errorf("Service.createOrGetInstance() NicType = %q, want GVNIC", got)
+			}
+			if d := cmp.Diff(&compute.NetworkPerformanceConfig{TotalEgressBandwidthTier: "TIER_1"}, instance.NetworkPerformanceConfig); d != "" {
+				t.Errorf("Service.createOrGetInstance() NetworkPerformanceConfig mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestService_createOrGetInstance_architecture(t *testing.T) {
+	tests := []struct {
+		name         string
+		instanceType string
+		image        *compute.Image
+		wantErr      string
+	}{
+		{
+			name:         "arm64 image on arm64 machine type (should create instance)",
+			instanceType: "t2a-standard-4",
+			image:        &compute.Image{Architecture: "ARM64"},
+		},
+		{
+			name:         "image without architecture on arm64 machine type (should create instance)",
+			instanceType: "t2a-standard-4",
+			image:        &compute.Image{},
+		},
+		{
+			name:         "x86-64 image on arm64 machine type (should not create instance)",
+			instanceType: "t2a-standard-4",
+			image:        &compute.Image{Architecture: "X86_64"},
+			wantErr:      "requires ARM64 images",
+		},
+		{
+			name:         "x86-64 image on x86-64 machine type (should create instance)",
+			instanceType: "n2-standard-4",
+			image:        &compute.Image{Architecture: "X86_64"},
+		},
+		{
+			name:         "image without architecture on x86-64 machine type (should create instance)",
+			instanceType: "n2-standard-4",
+			image:        &compute.Image{},
+		},
+		{
+			name:         "arm64 image on x86-64 machine type (should not create instance)",
+			instanceType: "n2-standard-4",
+			image:        &compute.Image{Architecture: "ARM64"},
+			wantErr:      "requires X86_64 images",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakec := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(fakeBootstrapSecret).
+				Build()
+
+			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+				Client:     fakec,
+				Cluster:    fakeCluster,
+				GCPCluster: fakeGCPCluster,
+				GCPServices: scope.GCPServices{
+					Compute: &compute.Service{},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gcpMachine := getFakeGCPMachine()
+			gcpMachine.Spec.InstanceType = tt.instanceType
+			gcpMachine.Spec.ShieldedInstanceConfig = &infrav1.GCPShieldedInstanceConfig{}
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       fakeMachine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := New(machineScope)
+			s.instances = &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			}
+			s.images = &cloud.MockImages{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				GetFromFamilyHook: func(_ context.Context, _ *meta.Key, _ *cloud.MockImages, _ ...cloud.Option) (*compute.Image, error) {
+					return tt.image, nil
+				},
+			}
+
+			instance, err := s.createOrGetInstance(context.TODO())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Service.createOrGetInstance() error = %v", err)
+				}
+				if ptr.Deref(gcpMachine.Status.FailureReason, "") != "InvalidConfiguration" {
+					t.Errorf("Service.createOrGetInstance() FailureReason = %v, want InvalidConfiguration", gcpMachine.Status.FailureReason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Service.createOrGetInstance() error = %v", err)
+			}
+			// Arm machine types reject integrity monitoring, it's only enabled by default on x86-64 instances.
+			wantIntegrityMonitoring := tt.instanceType == "n2-standard-4"
+			if got := instance.ShieldedInstanceConfig.EnableIntegrityMonitoring; got != wantIntegrityMonitoring {
+				t.Errorf("Service.createOrGetInstance() EnableIntegrityMonitoring = %v, want %v", got, wantIntegrityMonitoring)
+			}
+		})
+	}
+}
+
+func TestService_createOrGetInstance_bootstrapDataFormat(t *testing.T) {
+	ignition := `{"ignition":{"version":"3.4.0"},"storage":{"files":[{"path":"/etc/hostname","contents":{"source":"data:,my-machine"}}]}}`
+	tests := []struct {
+		name        string
+		value       string
+		format      string
+		metadataKey *string
+		osFamily    *infrav1.OSFamily
+		wantKey     string
+		wantErr     string
+	}{
+		{
+			name:    "cloud-config bootstrap data (should set user-data)",
+			value:   "#cloud-config\nruncmd: []\n",
+			format:  "cloud-config",
+			wantKey: "user-data",
+		},
+		{
+			name:    "ignition bootstrap data (should set the raw JSON to user-data)",
+			value:   ignition,
+			format:  "ignition",
+			wantKey: "user-data",
+		},
+		{
+			name:        "ignition bootstrap data with a metadata key (should set the raw JSON to the metadata key)",
+			value:       ignition,
+			format:      "ignition",
+			metadataKey: ptr.To("ignition-config"),
+			wantKey:     "ignition-config",
+		},
+		{
+			name:    "invalid ignition bootstrap data (should not create instance)",
+			value:   "#cloud-config",
+			format:  "ignition",
+			wantErr: "not valid JSON",
+		},
+		{
+			name:     "ignition bootstrap data on Windows (should not create instance)",
+			value:    ignition,
+			format:   "ignition",
+			osFamily: ptr.To(infrav1.OSFamilyWindows),
+			wantErr:  "not supported by Windows machines",
+		},
+		{
+			name:    "unsupported bootstrap data format (should not create instance)",
+			value:   "foo",
+			format:  "foo",
+			wantErr: "unsupported bootstrap data format",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bootstrapSecret := fakeBootstrapSecret.DeepCopy()
+			bootstrapSecret.Data = map[string][]byte{
+				"value":  []byte(tt.value),
+				"format": []byte(tt.format),
+			}
+			fakec := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(bootstrapSecret).
+				Build()
+
+			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+				Client:     fakec,
+				Cluster:    fakeCluster,
+				GCPCluster: fakeGCPCluster,
+				GCPServices: scope.GCPServices{
+					Compute: &compute.Service{},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gcpMachine := getFakeGCPMachine()
+			gcpMachine.Spec.BootstrapDataMetadataKey = tt.metadataKey
+			gcpMachine.Spec.OSFamily = tt.osFamily
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       fakeMachine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := New(machineScope)
+			s.images = fakeImages()
+			s.instances = &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			}
+
+			instance, err := s.createOrGetInstance(context.TODO())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Service.createOrGetInstance() error = %v, want %s", err, tt.wantErr)
+				}
+				if ptr.Deref(gcpMachine.Status.FailureReason, "") != "InvalidConfiguration" {
+					t.Errorf("Service.createOrGetInstance() FailureReason = %v, want InvalidConfiguration", gcpMachine.Status.FailureReason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Service.createOrGetInstance() error = %v", err)
+			}
+			got := map[string]string{}
+			for _, item := range instance.Metadata.Items {
+				got[item.Key] = ptr.Deref(item.Value, "")
+			}
+			if got[tt.wantKey] != tt.value {
+				t.Errorf("Service.createOrGetInstance() metadata %s = %q, want %q", tt.wantKey, got[tt.wantKey], tt.value)
+			}
+			if tt.wantKey != "user-data" {
+				if _, ok := got["user-data"]; ok {
+					t.Errorf("Service.createOrGetInstance() metadata user-data is set, want only %s", tt.wantKey)
+				}
+			}
+		})
+	}
+}
+
+// fakeImages returns the images of the image families without architecture, i.e. matching every machine type.
+func fakeImages() *cloud.MockImages {
+	return &cloud.MockImages{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		GetFromFamilyHook: func(_ context.Context, _ *meta.Key, _ *cloud.MockImages, _ ...cloud.Option) (*compute.Image, error) {
+			return &compute.Image{}, nil
+		},
+	}
+}
+
+// fakeObjects keeps the GCS objects in memory, keyed by bucket/name.
+type fakeObjects struct {
+	objects map[string][]byte
+}
+
+func (f *fakeObjects) Insert(_ context.Context, bucket, name string, data []byte) error {
+	f.objects[bucket+"/"+name] = data
+	return nil
+}
+
+func (f *fakeObjects) Delete(_ context.Context, bucket, name string) error {
+	if _, ok := f.objects[bucket+"/"+name]; !ok {
+		return &googleapi.Error{Code: http.StatusNotFound}
+	}
+	delete(f.objects, bucket+"/"+name)
+	return nil
+}
+
+func TestService_createOrGetInstance_bootstrapDataStorage(t *testing.T) {
+	gcsStorage := &infrav1.BootstrapDataStorageSpec{Type: infrav1.BootstrapDataStorageGCS, Bucket: "my-bucket"}
+	largeBootstrapData := "#cloud-config\n" + strings.Repeat("#", 300*1024)
+	tests := []struct {
+		name         string
+		storage      *infrav1.BootstrapDataStorageSpec
+		value        string
+		format       string
+		wantMetadata []string
+		wantErr      string
+	}{
+		{
+			name:         "bootstrap data stored in the metadata (should set user-data)",
+			value:        "#cloud-config\n",
+			wantMetadata: []string{"#cloud-config\n"},
+		},
+		{
+			name:    "large bootstrap data stored in the metadata (should not create instance)",
+			value:   largeBootstrapData,
+			wantErr: "exceeds the 262144 bytes limit",
+		},
+		{
+			name:    "large bootstrap data stored in GCS (should upload bootstrap data and set a cloud-init fetch script)",
+			storage: gcsStorage,
+			value:   largeBootstrapData,
+			wantMetadata: []string{
+				"Content-Type: text/cloud-boothook",
+				"https://storage.googleapis.com/storage/v1/b/my-bucket/o/default%2Fmy-machine-",
+				"file:///etc/capg-bootstrap-data",
+			},
+		},
+		{
+			name:         "ignition bootstrap data stored in GCS (should upload bootstrap data and set an ignition config replaced by the object)",
+			storage:      gcsStorage,
+			value:        `{"ignition":{"version":"3.4.0"}}`,
+			format:       "ignition",
+			wantMetadata: []string{`{"ignition":{"config":{"replace":{"source":"gs://my-bucket/default/my-machine-`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bootstrapSecret := fakeBootstrapSecret.DeepCopy()
+			bootstrapSecret.Data = map[string][]byte{
+				"value":  []byte(tt.value),
+				"format": []byte(tt.format),
+			}
+			fakec := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(bootstrapSecret).
+				Build()
+
+			gcpCluster := fakeGCPCluster.DeepCopy()
+			gcpCluster.Spec.BootstrapDataStorage = tt.storage
+			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+				Client:     fakec,
+				Cluster:    fakeCluster,
+				GCPCluster: gcpCluster,
+				GCPServices: scope.GCPServices{
+					Compute: &compute.Service{},
+					Storage: &storage.Service{},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gcpMachine := getFakeGCPMachine()
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       fakeMachine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := New(machineScope)
+			s.images = fakeImages()
+			s.instances = &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			}
+			objects := &fakeObjects{objects: map[string][]byte{}}
+			s.objects = objects
+
+			instance, err := s.createOrGetInstance(context.TODO())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Service.createOrGetInstance() error = %v, want %s", err, tt.wantErr)
+				}
+				if ptr.Deref(gcpMachine.Status.FailureReason, "") != "InvalidConfiguration" {
+					t.Errorf("Service.createOrGetInstance() FailureReason = %v, want InvalidConfiguration", gcpMachine.Status.FailureReason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Service.createOrGetInstance() error = %v", err)
+			}
+			var bootstrapData string
+			for _, item := range instance.Metadata.Items {
+				if item.Key == "user-data" {
+					bootstrapData = ptr.Deref(item.Value, "")
+				}
+			}
+			for _, want := range tt.wantMetadata {
+				if !strings.Contains(bootstrapData, want) {
+					t.Errorf("Service.createOrGetInstance() metadata user-data = %q, want it to contain %q", bootstrapData, want)
+				}
+			}
+
+			if tt.storage == nil {
+				if len(objects.objects) != 0 || gcpMachine.Status.BootstrapDataObject != nil {
+					t.Errorf("Service.createOrGetInstance() objects = %v, BootstrapDataObject = %v, want none", objects.objects, gcpMachine.Status.BootstrapDataObject)
+				}
+				return
+			}
+			// The object holds the bootstrap data unmodified.
+			name := bootstrapDataObjectName("default", "my-machine", tt.value)
+			if got := string(objects.objects["my-bucket/"+name]); got != tt.value {
+				t.Errorf("Service.createOrGetInstance() object my-bucket/%s = %q, want %q", name, got, tt.value)
+			}
+			if got := ptr.Deref(gcpMachine.Status.BootstrapDataObject, ""); got != "gs://my-bucket/"+name {
+				t.Errorf("Service.createOrGetInstance() BootstrapDataObject = %q, want gs://my-bucket/%s", got, name)
+			}
+		})
+	}
+}
+
+func TestService_reconcileBootstrapDataObject(t *testing.T) {
+	joinedMachine := fakeMachine.DeepCopy()
+	joinedMachine.Status.NodeRef = clusterv1.MachineNodeReference{Name: "my-machine"}
+
+	tests := []struct {
+		name        string
+		created     time.Time
+		ttl         *metav1.Duration
+		policy      *infrav1.RebootstrapPolicy
+		machine     *clusterv1.Machine
+		objects     map[string][]byte
+		wantObjects int
+	}{
+		{
+			name:        "TTL not elapsed (should keep object)",
+			created:     time.Now().Add(-30 * time.Minute),
+			objects:     map[string][]byte{"my-bucket/default/my-machine": []byte("#cloud-config")},
+			wantObjects: 1,
+		},
+		{
+			name:    "default TTL elapsed (should delete object)",
+			created: time.Now().Add(-2 * time.Hour),
+			objects: map[string][]byte{"my-bucket/default/my-machine": []byte("#cloud-config")},
+		},
+		{
+			name:    "TTL elapsed (should delete object)",
+			created: time.Now().Add(-10 * time.Minute),
+			ttl:     &metav1.Duration{Duration: 5 * time.Minute},
+			objects: map[string][]byte{"my-bucket/default/my-machine": []byte("#cloud-config")},
+		},
+		{
+			name:        "TTL elapsed before the machine joined with a rebootstrap policy (should keep object)",
+			created:     time.Now().Add(-2 * time.Hour),
+			policy:      ptr.To(infrav1.RebootstrapPolicyReset),
+			objects:     map[string][]byte{"my-bucket/default/my-machine": []byte("#cloud-config")},
+			wantObjects: 1,
+		},
+		{
+			name:    "TTL elapsed after the machine joined with a rebootstrap policy (should delete object)",
+			created: time.Now().Add(-2 * time.Hour),
+			policy:  ptr.To(infrav1.RebootstrapPolicyReset),
+			machine: joinedMachine,
+			objects: map[string][]byte{"my-bucket/default/my-machine": []byte("#cloud-config")},
+		},
+		{
+			name:    "object already deleted (should unset object)",
+			created: time.Now().Add(-2 * time.Hour),
+			objects: map[string][]byte{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakec := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(fakeBootstrapSecret).
+				Build()
+
+			gcpCluster := fakeGCPCluster.DeepCopy()
+			gcpCluster.Spec.BootstrapDataStorage = &infrav1.BootstrapDataStorageSpec{Type: infrav1.BootstrapDataStorageGCS, Bucket: "my-bucket", TTL: tt.ttl}
+			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+				Client:     fakec,
+				Cluster:    fakeCluster,
+				GCPCluster: gcpCluster,
+				GCPServices: scope.GCPServices{
+					Compute: &compute.Service{},
+					Storage: &storage.Service{},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gcpMachine := getFakeGCPMachine()
+			gcpMachine.Spec.RebootstrapPolicy = tt.policy
+			gcpMachine.Status.BootstrapDataObject = ptr.To("gs://my-bucket/default/my-machine")
+			machine := fakeMachine
+			if tt.machine != nil {
+				machine = tt.machine
+			}
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       machine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := New(machineScope)
+			objects := &fakeObjects{objects: tt.objects}
+			s.objects = objects
+
+			instance := &compute.Instance{Name: "my-machine", CreationTimestamp: tt.created.Format(time.RFC3339)}
+			if err := s.reconcileBootstrapDataObject(context.TODO(), instance); err != nil {
+				t.Fatalf("Service.reconcileBootstrapDataObject() error = %v", err)
+			}
+			if len(objects.objects) != tt.wantObjects {
+				t.Errorf("Service.reconcileBootstrapDataObject() objects = %v, want %d", objects.objects, tt.wantObjects)
+			}
+			if got := gcpMachine.Status.BootstrapDataObject != nil; got != (tt.wantObjects > 0) {
+				t.Errorf("Service.reconcileBootstrapDataObject() BootstrapDataObject = %v", gcpMachine.Status.BootstrapDataObject)
+			}
+		})
+	}
+}
+
+// ownedDiskLabels are the ownership labels of the disks created for the machine.
+var ownedDiskLabels = map[string]string{
+	"capg-cluster-my-cluster": "owned",
+	"capg-role":               "node",
+}
+
+func TestService_Delete_additionalDisks(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gcpMachine := getFakeGCPMachine()
+	gcpMachine.Spec.AdditionalDisks = []infrav1.AttachedDiskSpec{
+		{DeviceName: ptr.To[string]("data")},
+		{DeviceName: ptr.To[string]("keep"), AutoDelete: ptr.To(false)},
+		{DeviceName: ptr.To[string]("logs")},
+	}
+	gcpMachine.Spec.AttachedDisks = []infrav1.ExistingDiskSpec{
+		{Name: "shared"},
+	}
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:        fakec,
+		Machine:       fakeMachine,
+		GCPMachine:    gcpMachine,
+		ClusterGetter: clusterScope,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockDisks := &cloud.MockDisks{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects: map[meta.Key]*cloud.MockDisksObj{
+			{Name: "my-machine-data", Zone: "us-central1-c"}: {Obj: &compute.Disk{Name: "my-machine-data", Labels: ownedDiskLabels}},
+			{Name: "my-machine-keep", Zone: "us-central1-c"}: {Obj: &compute.Disk{Name: "my-machine-keep", Labels: ownedDiskLabels}},
+			// A disk of the same name not created for the machine.
+			{Name: "my-machine-logs", Zone: "us-central1-c"}: {Obj: &compute.Disk{Name: "my-machine-logs", Labels: map[string]string{"foo": "bar"}}},
+			{Name: "shared", Zone: "us-central1-c"}:          {Obj: &compute.Disk{Name: "shared"}},
+		},
+	}
+	s := New(machineScope)
+	s.instances = &cloud.MockInstances{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+	}
+	s.disks = mockDisks
+
+	if err := s.Delete(context.TODO()); err != nil {
+		t.Fatalf("Service.Delete() error = %v", err)
+	}
+	if _, ok := mockDisks.Objects[meta.Key{Name: "my-machine-data", Zone: "us-central1-c"}]; ok {
+		t.Error("Service.Delete() expected leftover disk my-machine-data to be deleted")
+	}
+	if _, ok := mockDisks.Objects[meta.Key{Name: "my-machine-keep", Zone: "us-central1-c"}]; !ok {
+		t.Error("Service.Delete() expected disk my-machine-keep with auto-delete disabled to be kept")
+	}
+	if _, ok := mockDisks.Objects[meta.Key{Name: "my-machine-logs", Zone: "us-central1-c"}]; !ok {
+		t.Error("Service.Delete() expected disk my-machine-logs without the ownership labels to be kept")
+	}
+	if _, ok := mockDisks.Objects[meta.Key{Name: "shared", Zone: "us-central1-c"}]; !ok {
+		t.Error("Service.Delete() expected attached disk shared to be kept")
+	}
+}
+
+func TestService_Delete_regionalDisks(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gcpMachine := getFakeGCPMachine()
+	gcpMachine.Spec.AdditionalDisks = []infrav1.AttachedDiskSpec{
+		{DeviceName: ptr.To[string]("data"), ReplicaZones: []string{"us-central1-c", "us-central1-f"}},
+	}
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:        fakec,
+		Machine:       fakeMachine,
+		GCPMachine:    gcpMachine,
+		ClusterGetter: clusterScope,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockRegionDisks := &cloud.MockRegionDisks{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects: map[meta.Key]*cloud.MockRegionDisksObj{
+			{Name: "my-machine-data", Region: "us-central1"}: {Obj: &compute.Disk{Name: "my-machine-data", Labels: ownedDiskLabels}},
+		},
+	}
+	s := New(machineScope)
+	s.instances = &cloud.MockInstances{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+	}
+	s.disks = &cloud.MockDisks{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects:       map[meta.Key]*cloud.MockDisksObj{},
+		GetHook: func(_ context.Context, key *meta.Key, _ *cloud.MockDisks, _ ...cloud.Option) (bool, *compute.Disk, error) {
+			t.Errorf("Service.Delete() looked up regional disk %s as a zonal disk", key.Name)
+			return false, nil, nil
+		},
+	}
+	s.regionDisks = mockRegionDisks
+
+	if err := s.Delete(context.TODO()); err != nil {
+		t.Fatalf("Service.Delete() error = %v", err)
+	}
+	if _, ok := mockRegionDisks.Objects[meta.Key{Name: "my-machine-data", Region: "us-central1"}]; ok {
+		t.Error("Service.Delete() expected leftover regional disk my-machine-data to be deleted")
+	}
+}
+
+func TestService_createOrGetInstance_replicaZones(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		replicaZones   []string
+		wantErrMessage string
+	}{
+		{
+			name:           "replica zones without the instance zone",
+			replicaZones:   []string{"us-central1-a", "us-central1-b"},
+			wantErrMessage: "must include the zone us-central1-c of the instance",
+		},
+		{
+			name:           "replica zone in another region",
+			replicaZones:   []string{"us-central1-c", "us-east1-b"},
+			wantErrMessage: "is not in the region us-central1 of the instance",
+		},
+		{
+			name:         "replica zones with the instance zone",
+			replicaZones: []string{"us-central1-c", "us-central1-f"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gcpMachine := getFakeGCPMachine()
+			gcpMachine.Spec.AdditionalDisks = []infrav1.AttachedDiskSpec{
+				{DeviceName: ptr.To[string]("data"), DeviceType: ptr.To(infrav1.PdBalancedDiskType), ReplicaZones: tt.replicaZones},
+			}
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       fakeMachine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := New(machineScope)
+			s.images = fakeImages()
+			s.instances = &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			}
+			s.regionDisks = &cloud.MockRegionDisks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockRegionDisksObj{},
+			}
+
+			instance, err := s.createOrGetInstance(context.TODO())
+			if tt.wantErrMessage == "" {
+				if err != nil {
+					t.Fatalf("Service.createOrGetInstance() error = %v", err)
+				}
+				want := []string{"zones/us-central1-c", "zones/us-central1-f"}
+				if diff := cmp.Diff(want, instance.Disks[1].InitializeParams.ReplicaZones); diff != "" {
+					t.Errorf("Service.createOrGetInstance() ReplicaZones mismatch (-want +got):\n%s", diff)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Service.createOrGetInstance() expected an error")
+			}
+			if got := ptr.Deref(gcpMachine.Status.FailureMessage, ""); !strings.Contains(got, tt.wantErrMessage) {
+				t.Errorf("Service.createOrGetInstance() FailureMessage = %q, want %q", got, tt.wantErrMessage)
+			}
+		})
+	}
+}
+
+func TestService_createOrGetInstance_regionalBootDisk(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const leftoverSelfLink = "https://www.googleapis.com/compute/v1/projects/proj-id/regions/us-central1/disks/my-machine"
+	tests := []struct {
+		name         string
+		replicaZones []string
+		existing     bool
+		wantErr      string
+	}{
+		{
+			name:         "regional boot disk is created",
+			replicaZones: []string{"us-central1-c", "us-central1-f"},
+		},
+		{
+			name:         "leftover regional boot disk is attached",
+			replicaZones: []string{"us-central1-c", "us-central1-f"},
+			existing:     true,
+		},
+		{
+			name:         "replica zones without the instance zone",
+			replicaZones: []string{"us-central1-a", "us-central1-b"},
+			wantErr:      "must include the zone us-central1-c of the instance",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gcpMachine := getFakeGCPMachine()
+			gcpMachine.Spec.RootDeviceType = ptr.To(infrav1.PdBalancedDiskType)
+			gcpMachine.Spec.RootDeviceReplicaZones = tt.replicaZones
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       fakeMachine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			mockRegionDisks := &cloud.MockRegionDisks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockRegionDisksObj{},
+			}
+			if tt.existing {
+				mockRegionDisks.Objects[meta.Key{Name: "my-machine", Region: "us-central1"}] = &cloud.MockRegionDisksObj{
+					Obj: &compute.Disk{Name: "my-machine", SelfLink: leftoverSelfLink, Labels: ownedDiskLabels},
+				}
+				mockRegionDisks.InsertHook = func(_ context.Context, key *meta.Key, _ *compute.Disk, _ *cloud.MockRegionDisks, _ ...cloud.Option) (bool, error) {
+					t.Errorf("Service.createOrGetInstance() created regional disk %s that already exists", key.Name)
+					return true, nil
+				}
+			}
+			s := New(machineScope)
+			s.images = fakeImages()
+			s.instances = &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			}
+			s.regionDisks = mockRegionDisks
+
+			instance, err := s.createOrGetInstance(context.TODO())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Service.createOrGetInstance() error = %v, want %q", err, tt.wantErr)
+				}
+				if len(mockRegionDisks.Objects) > 0 {
+					t.Error("Service.createOrGetInstance() created a regional boot disk for an invalid configuration")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Service.createOrGetInstance() error = %v", err)
+			}
+
+			diskObj, ok := mockRegionDisks.Objects[meta.Key{Name: "my-machine", Region: "us-central1"}]
+			if !ok {
+				t.Fatal("Service.createOrGetInstance() expected regional boot disk my-machine to be created")
+			}
+			disk := diskObj.ToGA()
+			bootDisk := instance.Disks[0]
+			if bootDisk.InitializeParams != nil || bootDisk.Source != disk.SelfLink || !bootDisk.Boot || !bootDisk.AutoDelete {
+				t.Errorf("Service.createOrGetInstance() boot disk = %+v, want the regional disk %s attached", bootDisk, disk.SelfLink)
+			}
+			if tt.existing {
+				return
+			}
+			want := &compute.Disk{
+				Name:         "my-machine",
+				Type:         "projects/my-proj/regions/us-central1/diskTypes/pd-balanced",
+				SourceImage:  "projects/my-proj/global/images/family/capi-ubuntu-1804-k8s-v1-19",
+				ReplicaZones: []string{"projects/my-proj/zones/us-central1-c", "projects/my-proj/zones/us-central1-f"},
+			}
+			got := &compute.Disk{Name: disk.Name, Type: disk.Type, SourceImage: disk.SourceImage, ReplicaZones: disk.ReplicaZones}
+			if d := cmp.Diff(want, got); d != "" {
+				t.Errorf("Service.createOrGetInstance() regional boot disk mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestService_Delete_regionalBootDisk(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gcpMachine := getFakeGCPMachine()
+	gcpMachine.Spec.RootDeviceReplicaZones = []string{"us-central1-c", "us-central1-f"}
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:        fakec,
+		Machine:       fakeMachine,
+		GCPMachine:    gcpMachine,
+		ClusterGetter: clusterScope,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockRegionDisks := &cloud.MockRegionDisks{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects: map[meta.Key]*cloud.MockRegionDisksObj{
+			{Name: "my-machine", Region: "us-central1"}: {Obj: &compute.Disk{Name: "my-machine", Labels: ownedDiskLabels}},
+		},
+	}
+	s := New(machineScope)
+	s.instances = &cloud.MockInstances{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+	}
+	s.regionDisks = mockRegionDisks
+
+	if err := s.Delete(context.TODO()); err != nil {
+		t.Fatalf("Service.Delete() error = %v", err)
+	}
+	if _, ok := mockRegionDisks.Objects[meta.Key{Name: "my-machine", Region: "us-central1"}]; ok {
+		t.Error("Service.Delete() expected leftover regional boot disk my-machine to be deleted")
+	}
+}
+
+func TestService_Delete_deletionProtection(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gcpMachine := getFakeGCPMachine()
+	gcpMachine.Spec.DeletionProtection = ptr.To(true)
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:        fakec,
+		Machine:       fakeMachine,
+		GCPMachine:    gcpMachine,
+		ClusterGetter: clusterScope,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	computeInstances := &fakeComputeInstances{}
+	s := New(machineScope)
+	s.computeInstances = computeInstances
+	s.instances = &cloud.MockInstances{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects: map[meta.Key]*cloud.MockInstancesObj{
+			{Name: "my-machine", Zone: "us-central1-c"}: {Obj: &compute.Instance{Name: "my-machine", DeletionProtection: true}},
+		},
+		DeleteHook: func(_ context.Context, _ *meta.Key, _ *cloud.MockInstances, _ ...cloud.Option) (bool, error) {
+			if computeInstances.deletionProtection == nil || *computeInstances.deletionProtection {
+				return true, &googleapi.Error{Code: http.StatusBadRequest, Message: "Invalid resource usage: 'Resource cannot be deleted if it's protected against deletion.'."}
+			}
+			return false, nil
+		},
+	}
+	s.disks = &cloud.MockDisks{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects:       map[meta.Key]*cloud.MockDisksObj{},
+	}
+	s.addresses = &cloud.MockAddresses{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects:       map[meta.Key]*cloud.MockAddressesObj{},
+	}
+
+	if err := s.Delete(context.TODO()); err != nil {
+		t.Fatalf("Service.Delete() error = %v", err)
+	}
+	if ptr.Deref(gcpMachine.Status.DeletionProtection, true) {
+		t.Error("Service.Delete() expected the deletion protection to be disabled in the status")
+	}
+}
+
+func TestService_Delete_internalAddress(t *testing.T) {
+	tests := []struct {
+		name       string
+		labels     map[string]string
+		wantExists bool
+	}{
+		{
+			name: "releases the address reserved by the provider",
+			labels: map[string]string{
+				"capg-cluster-my-cluster": "owned",
+				"capg-role":               "node",
+			},
+			wantExists: false,
+		},
+		{
+			name:       "keeps the address reserved outside of the provider",
+			labels:     map[string]string{"team": "network"},
+			wantExists: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakec := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(fakeBootstrapSecret).
+				Build()
+
+			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+				Client:     fakec,
+				Cluster:    fakeCluster,
+				GCPCluster: fakeGCPCluster,
+				GCPServices: scope.GCPServices{
+					Compute: &compute.Service{},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gcpMachine := getFakeGCPMachine()
+			gcpMachine.Spec.InternalAddress = &infrav1.InternalAddressSpec{Name: "my-address"}
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       fakeMachine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			addressKey := meta.Key{Name: "my-address", Region: "us-central1"}
+			mockAddresses := &cloud.MockAddresses{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects: map[meta.Key]*cloud.MockAddressesObj{
+					addressKey: {Obj: &compute.Address{Name: "my-address", Labels: tt.labels}},
+				},
+			}
+			s := New(machineScope)
+			s.instances = &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockInstancesObj{},
+			}
+			s.disks = &cloud.MockDisks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockDisksObj{},
+			}
+			s.addresses = mockAddresses
+
+			if err := s.Delete(context.TODO()); err != nil {
+				t.Fatalf("Service.Delete() error = %v", err)
+			}
+			if _, ok := mockAddresses.Objects[addressKey]; ok != tt.wantExists {
+				t.Errorf("Service.Delete() address exists = %v, want %v", ok, tt.wantExists)
+			}
+		})
+	}
+}
+
+type fakeComputeInstances struct {
+	labels             *compute.InstancesSetLabelsRequest
+	tags               *compute.Tags
+	metadata           *compute.Metadata
+	deletionProtection *bool
+	reset              bool
+	setMetadataErr     error
+}
+
+func (f *fakeComputeInstances) SetLabels(_ context.Context, _ *meta.Key, req *compute.InstancesSetLabelsRequest) error {
+	f.labels = req
+	return nil
+}
+
+func (f *fakeComputeInstances) SetTags(_ context.Context, _ *meta.Key, tags *compute.Tags) error {
+	f.tags = tags
+	return nil
+}
+
+func (f *fakeComputeInstances) SetMetadata(_ context.Context, _ *meta.Key, metadata *compute.Metadata) error {
+	if f.setMetadataErr != nil {
+		return f.setMetadataErr
+	}
+	f.metadata = metadata
+	return nil
+}
+
+func (f *fakeComputeInstances) SetDeletionProtection(_ context.Context, _ *meta.Key, enabled bool) error {
+	f.deletionProtection = ptr.To(enabled)
+	return nil
+}
+
+func (f *fakeComputeInstances) Reset(_ context.Context, _ *meta.Key) error {
+	f.reset = true
+	return nil
+}
+
+func TestService_reconcileDeletionProtection(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name               string
+		deletionProtection *bool
+		instance           *compute.Instance
+		want               *bool
+	}{
+		{
+			name:     "deletion protection is up to date",
+			instance: &compute.Instance{Name: "my-machine"},
+		},
+		{
+			name:               "deletion protection is enabled",
+			deletionProtection: ptr.To(true),
+			instance:           &compute.Instance{Name: "my-machine"},
+			want:               ptr.To(true),
+		},
+		{
+			name:     "deletion protection is disabled",
+			instance: &compute.Instance{Name: "my-machine", DeletionProtection: true},
+			want:     ptr.To(false),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gcpMachine := getFakeGCPMachine()
+			gcpMachine.Spec.DeletionProtection = tt.deletionProtection
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       fakeMachine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			computeInstances := &fakeComputeInstances{}
+			s := New(machineScope)
+			s.computeInstances = computeInstances
+			if err := s.reconcileDeletionProtection(context.TODO(), tt.instance, machineScope.InstanceSpec(logr.Discard())); err != nil {
+				t.Fatalf("Service.reconcileDeletionProtection() error = %v", err)
+			}
+			if d := cmp.Diff(tt.want, computeInstances.deletionProtection); d != "" {
+				t.Errorf("Service.reconcileDeletionProtection() mismatch (-want +got):\n%s", d)
+			}
+			if got, want := ptr.Deref(gcpMachine.Status.DeletionProtection, false), ptr.Deref(tt.deletionProtection, false); got != want {
+				t.Errorf("Service.reconcileDeletionProtection() status = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestService_reconcileImage(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		bootDiskSource = "https://www.googleapis.com/compute/v1/projects/proj-id/zones/us-central1-c/disks/my-machine"
+		sourceImage    = "https://www.googleapis.com/compute/v1/projects/my-images/global/images/capi-ubuntu-2204-v20260101"
+		familyImage    = "https://www.googleapis.com/compute/v1/projects/my-images/global/images/capi-ubuntu-2204-v20260201"
+	)
+	tests := []struct {
+		name        string
+		statusImage *string
+		want        string
+		wantLookups int
+	}{
+		{
+			name:        "image is recorded from the boot disk",
+			want:        sourceImage,
+			wantLookups: 1,
+		},
+		{
+			name:        "image is already recorded",
+			statusImage: ptr.To(familyImage),
+			want:        familyImage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gcpMachine := getFakeGCPMachine()
+			gcpMachine.Status.Image = tt.statusImage
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       fakeMachine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			lookups := 0
+			s := New(machineScope)
+			s.disks = &cloud.MockDisks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockDisksObj{},
+				GetHook: func(_ context.Context, key *meta.Key, _ *cloud.MockDisks, _ ...cloud.Option) (bool, *compute.Disk, error) {
+					lookups++
+					if key.Name != "my-machine" || key.Zone != "us-central1-c" {
+						t.Errorf("Service.reconcileImage() looked up disk %v, want my-machine in us-central1-c", key)
+					}
+					return true, &compute.Disk{Name: key.Name, SourceImage: sourceImage}, nil
+				},
+			}
+
+			instance := &compute.Instance{
+				Name: "my-machine",
+				Disks: []*compute.AttachedDisk{
+					{Boot: true, Source: bootDiskSource},
+					{Source: "https://www.googleapis.com/compute/v1/projects/proj-id/zones/us-central1-c/disks/my-machine-data"},
+				},
+			}
+			if err := s.reconcileImage(context.TODO(), instance); err != nil {
+				t.Fatalf("Service.reconcileImage() error = %v", err)
+			}
+			if lookups != tt.wantLookups {
+				t.Errorf("Service.reconcileImage() looked up %d disks, want %d", lookups, tt.wantLookups)
+			}
+			if got := ptr.Deref(gcpMachine.Status.Image, ""); got != tt.want {
+				t.Errorf("Service.reconcileImage() status Image = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_instanceAddresses(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:        fakec,
+		Machine:       fakeMachine,
+		GCPMachine:    getFakeGCPMachine(),
+		ClusterGetter: clusterScope,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dnsAddresses := []corev1.NodeAddress{
+		{Type: corev1.NodeInternalDNS, Address: "my-machine.us-central1-c.c.my-proj.internal"},
+		{Type: corev1.NodeInternalDNS, Address: "my-machine.c.my-proj.internal"},
+		{Type: corev1.NodeInternalDNS, Address: "my-machine"},
+	}
+	tests := []struct {
+		name     string
+		instance *compute.Instance
+		want     []corev1.NodeAddress
+	}{
+		{
+			name: "instance without external IP",
+			instance: &compute.Instance{
+				NetworkInterfaces: []*compute.NetworkInterface{
+					{NetworkIP: "10.0.0.2"},
+				},
+			},
+			want: slices.Concat([]corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: "10.0.0.2"},
+			}, dnsAddresses, []corev1.NodeAddress{
+				{Type: corev1.NodeHostName, Address: "my-machine"},
+			}),
+		},
+		{
+			name: "instance with external IP not allocated yet",
+			instance: &compute.Instance{
+				NetworkInterfaces: []*compute.NetworkInterface{
+					{NetworkIP: "10.0.0.2", AccessConfigs: []*compute.AccessConfig{{Name: "External NAT"}}},
+				},
+			},
+			want: slices.Concat([]corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: "10.0.0.2"},
+			}, dnsAddresses, []corev1.NodeAddress{
+				{Type: corev1.NodeHostName, Address: "my-machine"},
+			}),
+		},
+		{
+			name: "instance with multiple network interfaces",
+			instance: &compute.Instance{
+				NetworkInterfaces: []*compute.NetworkInterface{
+					{NetworkIP: "10.0.0.2", AccessConfigs: []*compute.AccessConfig{{Name: "External NAT", NatIP: "34.1.2.3"}}},
+					{NetworkIP: "10.1.0.2"},
+				},
+			},
+			want: slices.Concat([]corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: "10.0.0.2"},
+				{Type: corev1.NodeExternalIP, Address: "34.1.2.3"},
+				{Type: corev1.NodeInternalIP, Address: "10.1.0.2"},
+			}, dnsAddresses, []corev1.NodeAddress{
+				{Type: corev1.NodeHostName, Address: "my-machine"},
+			}),
+		},
+		{
+			name: "instance with custom hostname",
+			instance: &compute.Instance{
+				Hostname: "node-1.example.com",
+				NetworkInterfaces: []*compute.NetworkInterface{
+					{NetworkIP: "10.0.0.2"},
+				},
+			},
+			want: slices.Concat([]corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: "10.0.0.2"},
+			}, dnsAddresses, []corev1.NodeAddress{
+				{Type: corev1.NodeHostName, Address: "node-1.example.com"},
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(machineScope)
+			if d := cmp.Diff(tt.want, s.instanceAddresses(tt.instance)); d != "" {
+				t.Errorf("Service.instanceAddresses() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestService_reconcileLabels(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:        fakec,
+		Machine:       fakeMachine,
+		GCPMachine:    getFakeGCPMachine(),
+		ClusterGetter: clusterScope,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels := machineScope.InstanceSpec(logr.Discard()).Labels
+
+	tests := []struct {
+		name        string
+		lastApplied string
+		instance    *compute.Instance
+		want        *compute.InstancesSetLabelsRequest
+	}{
+		{
+			name: "labels are up to date",
+			instance: &compute.Instance{
+				Name:   "my-machine",
+				Labels: infrav1.Labels{}.AddLabels(labels).AddLabels(infrav1.Labels{"goog-ops-agent-policy": "v2"}),
+			},
+		},
+		{
+			name:        "labels drifted",
+			lastApplied: `{"removed":"true"}`,
+			instance: &compute.Instance{
+				Name:             "my-machine",
+				Labels:           map[string]string{"removed": "true", "cost-center": "42", "goog-ops-agent-policy": "v2"},
+				LabelFingerprint: "fingerprint",
+			},
+			want: &compute.InstancesSetLabelsRequest{
+				Labels:           infrav1.Labels{}.AddLabels(labels).AddLabels(infrav1.Labels{"cost-center": "42", "goog-ops-agent-policy": "v2"}),
+				LabelFingerprint: "fingerprint",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machineScope.GCPMachine.Annotations = map[string]string{}
+			if tt.lastApplied != "" {
+				machineScope.GCPMachine.Annotations[infrav1.LastAppliedLabelsAnnotation] = tt.lastApplied
+			}
+			computeInstances := &fakeComputeInstances{}
+			s := New(machineScope)
+			s.computeInstances = computeInstances
+			if err := s.reconcileLabels(context.TODO(), tt.instance, machineScope.InstanceSpec(logr.Discard())); err != nil {
+				t.Fatalf("Service.reconcileLabels() error = %v", err)
+			}
+			if d := cmp.Diff(tt.want, computeInstances.labels); d != "" {
+				t.Errorf("Service.reconcileLabels() mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(infrav1.Labels{"foo": "bar"}, machineScope.LastAppliedLabels()); d != "" {
+				t.Errorf("MachineScope.LastAppliedLabels() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestService_reconcileMetadata(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gcpMachine := getFakeGCPMachine()
+	gcpMachine.Spec.AdditionalMetadata = []infrav1.MetadataItem{
+		{Key: "block-project-ssh-keys", Value: ptr.To("true")},
+	}
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:        fakec,
+		Machine:       fakeMachine,
+		GCPMachine:    gcpMachine,
+		ClusterGetter: clusterScope,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bootstrapData := &compute.MetadataItems{Key: "user-data", Value: ptr.To("Zm9vCg==")}
+	sshKeys := &compute.MetadataItems{Key: "ssh-keys", Value: ptr.To("admin:ssh-ed25519 AAAA admin")}
+	tests := []struct {
+		name        string
+		lastApplied string
+		instance    *compute.Instance
+		want        *compute.Metadata
+	}{
+		{
+			name: "metadata is up to date",
+			instance: &compute.Instance{
+				Name: "my-machine",
+				Metadata: &compute.Metadata{
+					Items: []*compute.MetadataItems{
+						bootstrapData,
+						{Key: "block-project-ssh-keys", Value: ptr.To("true")},
+					},
+				},
+			},
+		},
+		{
+			name: "metadata set by other tools is preserved",
+			instance: &compute.Instance{
+				Name: "my-machine",
+				Metadata: &compute.Metadata{
+					Items: []*compute.MetadataItems{
+						bootstrapData,
+						sshKeys,
+						{Key: "block-project-ssh-keys", Value: ptr.To("true")},
+					},
+				},
+			},
+		},
+		{
+			name:        "metadata drifted",
+			lastApplied: `["block-project-ssh-keys","http-proxy"]`,
+			instance: &compute.Instance{
+				Name: "my-machine",
+				Metadata: &compute.Metadata{
+					Fingerprint: "fingerprint",
+					Items: []*compute.MetadataItems{
+						bootstrapData,
+						sshKeys,
+						{Key: "block-project-ssh-keys", Value: ptr.To("false")},
+						{Key: "http-proxy", Value: ptr.To("http://proxy:3128")},
+					},
+				},
+			},
+			want: &compute.Metadata{
+				Fingerprint: "fingerprint",
+				Items: []*compute.MetadataItems{
+					bootstrapData,
+					sshKeys,
+					{Key: "block-project-ssh-keys", Value: ptr.To("true")},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machineScope.GCPMachine.Annotations = map[string]string{}
+			if tt.lastApplied != "" {
+				machineScope.GCPMachine.Annotations[infrav1.LastAppliedMetadataAnnotation] = tt.lastApplied
+			}
+			computeInstances := &fakeComputeInstances{}
+			s := New(machineScope)
+			s.computeInstances = computeInstances
+			if err := s.reconcileMetadata(context.TODO(), tt.instance, machineScope.InstanceSpec(logr.Discard())); err != nil {
+				t.Fatalf("Service.reconcileMetadata() error = %v", err)
+			}
+			if d := cmp.Diff(tt.want, computeInstances.metadata); d != "" {
+				t.Errorf("Service.reconcileMetadata() mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff([]string{"block-project-ssh-keys"}, machineScope.LastAppliedMetadataKeys()); d != "" {
+				t.Errorf("MachineScope.LastAppliedMetadataKeys() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestService_reconcileBootstrapData(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	joinedMachine := fakeMachine.DeepCopy()
+	joinedMachine.Status.NodeRef = clusterv1.MachineNodeReference{Name: "my-machine"}
+
+	staleInstance := func(status string) *compute.Instance {
+		return &compute.Instance{
+			Name:   "my-machine",
+			Status: status,
+			Metadata: &compute.Metadata{
+				Fingerprint: "fingerprint",
+				Items: []*compute.MetadataItems{
+					{Key: "user-data", Value: ptr.To("expired")},
+					{Key: "block-project-ssh-keys", Value: ptr.To("true")},
+				},
+			},
+		}
+	}
+	wantMetadata := &compute.Metadata{
+		Fingerprint: "fingerprint",
+		Items: []*compute.MetadataItems{
+			{Key: "user-data", Value: ptr.To("Zm9vCg==")},
+			{Key: "block-project-ssh-keys", Value: ptr.To("true")},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		policy         *infrav1.RebootstrapPolicy
+		machine        *clusterv1.Machine
+		instance       *compute.Instance
+		setMetadataErr error
+		wantMetadata   *compute.Metadata
+		wantReset      bool
+		wantErr        bool
+	}{
+		{
+			name:     "policy defaults to never",
+			machine:  fakeMachine,
+			instance: staleInstance("RUNNING"),
+		},
+		{
+			name:     "machine already joined the cluster",
+			policy:   ptr.To(infrav1.RebootstrapPolicyReset),
+			machine:  joinedMachine,
+			instance: staleInstance("RUNNING"),
+		},
+		{
+			name:    "bootstrap data is up to date",
+			policy:  ptr.To(infrav1.RebootstrapPolicyReset),
+			machine: fakeMachine,
+			instance: &compute.Instance{
+				Name:   "my-machine",
+				Status: "RUNNING",
+				Metadata: &compute.Metadata{
+					Items: []*compute.MetadataItems{{Key: "user-data", Value: ptr.To("Zm9vCg==")}},
+				},
+			},
+		},
+		{
+			name:         "bootstrap data changed, update metadata",
+			policy:       ptr.To(infrav1.RebootstrapPolicyUpdateMetadata),
+			machine:      fakeMachine,
+			instance:     staleInstance("RUNNING"),
+			wantMetadata: wantMetadata,
+		},
+		{
+			name:         "bootstrap data changed, reset running instance",
+			policy:       ptr.To(infrav1.RebootstrapPolicyReset),
+			machine:      fakeMachine,
+			instance:     staleInstance("RUNNING"),
+			wantMetadata: wantMetadata,
+			wantReset:    true,
+		},
+		{
+			name:         "bootstrap data changed, don't reset stopped instance",
+			policy:       ptr.To(infrav1.RebootstrapPolicyReset),
+			machine:      fakeMachine,
+			instance:     staleInstance("TERMINATED"),
+			wantMetadata: wantMetadata,
+		},
+		{
+			name:     "metadata fingerprint is out of date",
+			policy:   ptr.To(infrav1.RebootstrapPolicyReset),
+			machine:  fakeMachine,
+			instance: staleInstance("RUNNING"),
+			setMetadataErr: &googleapi.Error{
+				Code:    http.StatusPreconditionFailed,
+				Message: "Supplied fingerprint does not match current metadata fingerprint.",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gcpMachine := getFakeGCPMachine()
+			gcpMachine.Spec.RebootstrapPolicy = tt.policy
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        fakec,
+				Machine:       tt.machine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			computeInstances := &fakeComputeInstances{setMetadataErr: tt.setMetadataErr}
+			s := New(machineScope)
+			s.computeInstances = computeInstances
+			s.instances = &cloud.MockInstances{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects: map[meta.Key]*cloud.MockInstancesObj{
+					{Name: "my-machine", Zone: "us-central1-c"}: {Obj: &compute.Instance{Name: "my-machine"}},
+				},
+			}
+
+			instance, err := s.reconcileBootstrapData(context.TODO(), tt.instance)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Service.reconcileBootstrapData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				var ae *googleapi.Error
+				if !errors.As(err, &ae) || !isMetadataFingerprintMismatch(ae) {
+					t.Errorf("Service.reconcileBootstrapData() error = %v, want a fingerprint mismatch", err)
+				}
+				return
+			}
+			if instance == nil {
+				t.Fatal("Service.reconcileBootstrapData() returned a nil instance")
+			}
+			if d := cmp.Diff(tt.wantMetadata, computeInstances.metadata); d != "" {
+				t.Errorf("Service.reconcileBootstrapData() metadata mismatch (-want +got):\n%s", d)
+			}
+			if computeInstances.reset != tt.wantReset {
+				t.Errorf("Service.reconcileBootstrapData() reset = %v, want %v", computeInstances.reset, tt.wantReset)
+			}
+		})
+	}
+}
+
+func TestService_reconcileBootstrapData_bootstrapDataStorage(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	gcpCluster := fakeGCPCluster.DeepCopy()
+	gcpCluster.Spec.BootstrapDataStorage = &infrav1.BootstrapDataStorageSpec{Type: infrav1.BootstrapDataStorageGCS, Bucket: "my-bucket"}
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: gcpCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+			Storage: &storage.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gcpMachine := getFakeGCPMachine()
+	gcpMachine.Spec.RebootstrapPolicy = ptr.To(infrav1.RebootstrapPolicyReset)
+	gcpMachine.Status.BootstrapDataObject = ptr.To("gs://my-bucket/default/my-machine-expired")
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:        fakec,
+		Machine:       fakeMachine,
+		GCPMachine:    gcpMachine,
+		ClusterGetter: clusterScope,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	staleStub, err := bootstrapDataFetchStub("my-bucket", "default/my-machine-expired", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	computeInstances := &fakeComputeInstances{}
+	objects := &fakeObjects{objects: map[string][]byte{"my-bucket/default/my-machine-expired": []byte("expired")}}
+	s := New(machineScope)
+	s.computeInstances = computeInstances
+	s.objects = objects
+	s.instances = &cloud.MockInstances{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects: map[meta.Key]*cloud.MockInstancesObj{
+			{Name: "my-machine", Zone: "us-central1-c"}: {Obj: &compute.Instance{Name: "my-machine"}},
+		},
+	}
+
+	instance := &compute.Instance{
+		Name:   "my-machine",
+		Status: "RUNNING",
+		Metadata: &compute.Metadata{
+			Items: []*compute.MetadataItems{{Key: "user-data", Value: ptr.To(staleStub)}},
+		},
+	}
+	if _, err := s.reconcileBootstrapData(context.TODO(), instance); err != nil {
+		t.Fatalf("Service.reconcileBootstrapData() error = %v", err)
+	}
+
+	// The new bootstrap data is uploaded to a new object, replacing the stale one, and the stub fetches it.
+	name := bootstrapDataObjectName("default", "my-machine", "Zm9vCg==")
+	if d := cmp.Diff(map[string][]byte{"my-bucket/" + name: []byte("Zm9vCg==")}, objects.objects); d != "" {
+		t.Errorf("Service.reconcileBootstrapData() objects mismatch (-want +got):\n%s", d)
+	}
+	if got := ptr.Deref(gcpMachine.Status.BootstrapDataObject, ""); got != "gs://my-bucket/"+name {
+		t.Errorf("Service.reconcileBootstrapData() BootstrapDataObject = %q, want gs://my-bucket/%s", got, name)
+	}
+	wantStub, err := bootstrapDataFetchStub("my-bucket", name, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if computeInstances.metadata == nil || ptr.Deref(computeInstances.metadata.Items[0].Value, "") != wantStub {
+		t.Errorf("Service.reconcileBootstrapData() metadata = %v, want the stub fetching %s", computeInstances.metadata, name)
+	}
+	if !computeInstances.reset {
+		t.Error("Service.reconcileBootstrapData() didn't reset the instance")
+	}
+
+	// The stub is up to date, the instance isn't reset again.
+	computeInstances.reset = false
+	instance.Metadata.Items[0].Value = ptr.To(wantStub)
+	if _, err := s.reconcileBootstrapData(context.TODO(), instance); err != nil {
+		t.Fatalf("Service.reconcileBootstrapData() error = %v", err)
+	}
+	if computeInstances.reset {
+		t.Error("Service.reconcileBootstrapData() reset the instance with up to date bootstrap data")
+	}
+}
+
+func TestService_reconcileNetworkTags(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	gcpCluster := fakeGCPCluster.DeepCopy()
+	gcpCluster.Spec.AdditionalNetworkTags = []string{"allow-ssh"}
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: gcpCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:        fakec,
+		Machine:       fakeMachine,
+		GCPMachine:    getFakeGCPMachine(),
+		ClusterGetter: clusterScope,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		instance *compute.Instance
+		want     *compute.Tags
+	}{
+		{
+			name: "network tags are up to date",
+			instance: &compute.Instance{
+				Name: "my-machine",
+				Tags: &compute.Tags{Items: []string{"my-cluster", "allow-ssh", "my-cluster-node"}},
+			},
+		},
+		{
+			name: "cluster network tags changed",
+			instance: &compute.Instance{
+				Name: "my-machine",
+				Tags: &compute.Tags{
+					Items:       []string{"allow-http", "my-cluster-node", "my-cluster"},
+					Fingerprint: "fingerprint",
+				},
+			},
+			want: &compute.Tags{
+				Items:       []string{"allow-ssh", "my-cluster-node", "my-cluster"},
+				Fingerprint: "fingerprint",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			computeInstances := &fakeComputeInstances{}
+			s := New(machineScope)
+			s.computeInstances = computeInstances
+			if err := s.reconcileNetworkTags(context.TODO(), tt.instance, machineScope.InstanceSpec(logr.Discard())); err != nil {
+				t.Fatalf("Service.reconcileNetworkTags() error = %v", err)
+			}
+			if d := cmp.Diff(tt.want, computeInstances.tags); d != "" {
+				t.Errorf("Service.reconcileNetworkTags() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestService_drift(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(fakeBootstrapSecret).
+		Build()
+
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: fakeGCPCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:                  fakec,
+		Machine:                 fakeMachine,
+		GCPMachine:              getFakeGCPMachine(),
+		ClusterGetter:           clusterScope,
+		DisableDriftRemediation: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := machineScope.InstanceSpec(logr.Discard())
+
+	t.Run("mutable fields are reported when drift remediation is disabled", func(t *testing.T) {
+		computeInstances := &fakeComputeInstances{}
+		s := New(machineScope)
+		s.computeInstances = computeInstances
+		instance := &compute.Instance{
+			Name:   "my-machine",
+			Labels: map[string]string{"stale": "true"},
+			Tags:   &compute.Tags{Items: []string{"stale"}},
+		}
+		if err := s.reconcileLabels(context.TODO(), instance, spec); err != nil {
+			t.Fatalf("Service.reconcileLabels() error = %v", err)
+		}
+		if err := s.reconcileNetworkTags(context.TODO(), instance, spec); err != nil {
+			t.Fatalf("Service.reconcileNetworkTags() error = %v", err)
+		}
+		if computeInstances.labels != nil || computeInstances.tags != nil {
+			t.Errorf("Service converged the instance drift, want it reported only")
+		}
+		if d := cmp.Diff([]string{"labels", "network tags"}, s.drifted); d != "" {
+			t.Errorf("Service drifted fields mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		instance *compute.Instance
+		want     []string
+	}{
+		{
+			name: "instance matches the spec",
+			instance: &compute.Instance{
+				Name:        "my-machine",
+				MachineType: "https://www.googleapis.com/compute/v1/" + spec.MachineType,
+				Disks:       []*compute.AttachedDisk{{Boot: true, Source: "zones/us-central1-c/disks/my-machine"}},
+			},
+		},
+		{
+			name: "immutable fields drifted",
+			instance: &compute.Instance{
+				Name:        "my-machine",
+				MachineType: "zones/us-central1-c/machineTypes/e2-highmem-16",
+				Disks: []*compute.AttachedDisk{
+					{Boot: true, Source: "zones/us-central1-c/disks/replaced"},
+					{Source: "zones/us-central1-c/disks/attached"},
+				},
+			},
+			want: []string{"machine type", "boot disk", "disks"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := cmp.Diff(tt.want, immutableDrift(tt.instance, spec)); d != "" {
+				t.Errorf("immutableDrift() mismatch (-want +got):\n%s", d)
 			}
 		})
 	}
@@ -17,15 +17,20 @@ limitations under the License.
 package instances
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 
 	k8scloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/go-logr/logr"
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/storage/v1"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/shared"
 )
 
 type instancesInterface interface {
@@ -34,23 +39,100 @@ type instancesInterface interface {
 	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
 }
 
+type disksInterface interface {
+	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Disk, error)
+	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
+}
+
+type regionDisksInterface interface {
+	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Disk, error)
+	Insert(ctx context.Context, key *meta.Key, obj *compute.Disk, options ...k8scloud.Option) error
+	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
+}
+
+type addressesInterface interface {
+	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Address, error)
+	Insert(ctx context.Context, key *meta.Key, obj *compute.Address, options ...k8scloud.Option) error
+	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
+}
+
+type subnetworksInterface interface {
+	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Subnetwork, error)
+}
+
+type imagesInterface interface {
+	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Image, error)
+	GetFromFamily(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Image, error)
+	List(ctx context.Context, fl *filter.F, options ...k8scloud.Option) ([]*compute.Image, error)
+}
+
+type computeInstancesInterface interface {
+	SetLabels(ctx context.Context, key *meta.Key, req *compute.InstancesSetLabelsRequest) error
+	SetTags(ctx context.Context, key *meta.Key, tags *compute.Tags) error
+	SetMetadata(ctx context.Context, key *meta.Key, metadata *compute.Metadata) error
+	SetDeletionProtection(ctx context.Context, key *meta.Key, enabled bool) error
+	Reset(ctx context.Context, key *meta.Key) error
+}
+
 type instancegroupsInterface interface {
 	AddInstances(ctx context.Context, key *meta.Key, req *compute.InstanceGroupsAddInstancesRequest, options ...k8scloud.Option) error
 	ListInstances(ctx context.Context, key *meta.Key, req *compute.InstanceGroupsListInstancesRequest, fl *filter.F, options ...k8scloud.Option) ([]*compute.InstanceWithNamedPorts, error)
 	RemoveInstances(ctx context.Context, key *meta.Key, req *compute.InstanceGroupsRemoveInstancesRequest, options ...k8scloud.Option) error
 }
 
+type objectsInterface interface {
+	Insert(ctx context.Context, bucket, name string, data []byte) error
+	Delete(ctx context.Context, bucket, name string) error
+}
+
 // Scope is an interfaces that hold used methods.
 type Scope interface {
 	cloud.Machine
 	InstanceSpec(log logr.Logger) *compute.Instance
+	SubnetSpecs() []*compute.Subnetwork
+	Region() string
+	InternalAddressSpec() *compute.Address
+	ImageFamily() *string
+	ImageLookup() (string, *filter.F, error)
+	GetImage() *string
+	SetImage(image string)
+	SetInstanceID(id string)
+	SetZone(zone string)
+	ResourceManagerTags() infrav1.ResourceManagerTags
+	IsWindows() bool
+	BootstrapDataMetadataKey() string
+	GetBootstrapDataWithFormat(ctx context.Context) (string, string, error)
+	BootstrapDataStorage() *infrav1.BootstrapDataStorageSpec
+	StorageService() *storage.Service
+	BootstrapDataObject() string
+	SetBootstrapDataObject(url string)
+	RebootstrapPolicy() infrav1.RebootstrapPolicy
+	HasNodeRef() bool
+	LastAppliedLabels() infrav1.Labels
+	SetLastAppliedLabels(labels infrav1.Labels)
+	LastAppliedMetadataKeys() []string
+	SetLastAppliedMetadataKeys(keys []string)
+	SetDeletionProtection(enabled bool)
+	DriftRemediationDisabled() bool
+	SetDrift(fields []string)
+	RecordDriftCorrected(field string)
 }
 
 // Service implements instances reconciler.
 type Service struct {
-	scope          Scope
-	instances      instancesInterface
-	instancegroups instancegroupsInterface
+	scope            Scope
+	instances        instancesInterface
+	instancegroups   instancegroupsInterface
+	disks            disksInterface
+	regionDisks      regionDisksInterface
+	images           imagesInterface
+	addresses        addressesInterface
+	subnetworks      subnetworksInterface
+	computeInstances computeInstancesInterface
+	objects          objectsInterface
+
+	// drifted holds the instance fields that drifted from the machine spec and weren't converged.
+	drifted []string
 }
 
 var _ cloud.Reconciler = &Service{}
@@ -58,8 +140,100 @@ var _ cloud.Reconciler = &Service{}
 // New returns Service from given scope.
 func New(scope Scope) *Service {
 	return &Service{
-		scope:          scope,
-		instances:      scope.Cloud().Instances(),
-		instancegroups: scope.Cloud().InstanceGroups(),
+		scope:            scope,
+		instances:        scope.Cloud().Instances(),
+		instancegroups:   scope.Cloud().InstanceGroups(),
+		disks:            scope.Cloud().Disks(),
+		regionDisks:      scope.Cloud().RegionDisks(),
+		images:           scope.Cloud().Images(),
+		addresses:        scope.Cloud().Addresses(),
+		subnetworks:      scope.Cloud().Subnetworks(),
+		computeInstances: &computeInstances{service: scope.ComputeService(), project: scope.Project()},
+		objects:          &storageObjects{service: scope.StorageService()},
 	}
 }
+
+// storageObjects implements the GCS object operations used to store the bootstrap data.
+type storageObjects struct {
+	service *storage.Service
+}
+
+// Insert uploads the data to the object of the bucket, replacing it if it exists.
+func (o *storageObjects) Insert(ctx context.Context, bucket, name string, data []byte) error {
+	_, err := o.service.Objects.Insert(bucket, &storage.Object{Name: name, ContentType: "application/octet-stream"}).Media(bytes.NewReader(data)).Context(ctx).Do()
+	return err
+}
+
+// Delete deletes the object of the bucket.
+func (o *storageObjects) Delete(ctx context.Context, bucket, name string) error {
+	return o.service.Objects.Delete(bucket, name).Context(ctx).Do()
+}
+
+// computeInstances implements the instance operations the cloud doesn't support through the compute service.
+type computeInstances struct {
+	service *compute.Service
+	project string
+}
+
+// SetLabels sets the labels of the instance and waits for the operation to complete.
+func (c *computeInstances) SetLabels(ctx context.Context, key *meta.Key, req *compute.InstancesSetLabelsRequest) error {
+	op, err := c.service.Instances.SetLabels(c.project, key.Zone, key.Name, req).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// SetTags sets the network tags of the instance and waits for the operation to complete.
+func (c *computeInstances) SetTags(ctx context.Context, key *meta.Key, tags *compute.Tags) error {
+	op, err := c.service.Instances.SetTags(c.project, key.Zone, key.Name, tags).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// SetMetadata sets the metadata of the instance and waits for the operation to complete.
+func (c *computeInstances) SetMetadata(ctx context.Context, key *meta.Key, metadata *compute.Metadata) error {
+	op, err := c.service.Instances.SetMetadata(c.project, key.Zone, key.Name, metadata).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// SetDeletionProtection enables or disables the deletion protection of the instance and waits for the operation
+// to complete.
+func (c *computeInstances) SetDeletionProtection(ctx context.Context, key *meta.Key, enabled bool) error {
+	op, err := c.service.Instances.SetDeletionProtection(c.project, key.Zone, key.Name).DeletionProtection(enabled).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// Reset resets the instance, like a hard reboot, and waits for the operation to complete.
+func (c *computeInstances) Reset(ctx context.Context, key *meta.Key) error {
+	op, err := c.service.Instances.Reset(c.project, key.Zone, key.Name).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+func (c *computeInstances) wait(ctx context.Context, key *meta.Key, op *compute.Operation) error {
+	op, err := shared.WaitForOperation(ctx, c.service, c.project, op)
+	if err != nil {
+		return err
+	}
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		return fmt.Errorf("operation %s on instance %s failed: %s", op.OperationType, key.Name, op.Error.Errors[0].Message)
+	}
+
+	return nil
+}
@@ -20,8 +20,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"regexp"
 	"strings"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"google.golang.org/api/compute/v1"
 	"k8s.io/utils/ptr"
@@ -50,7 +53,17 @@ const (
 func (s *Service) Reconcile(ctx context.Context) error {
 	log := log.FromContext(ctx)
 	log.Info("Reconciling loadbalancer resources")
+	if err := s.reconcileLoadBalancers(ctx); err != nil {
+		s.scope.MarkConditionFalse(infrav1.LoadBalancerReadyCondition, infrav1.LoadBalancerReconciliationFailedReason, err)
+		return err
+	}
+	s.scope.MarkConditionTrue(infrav1.LoadBalancerReadyCondition)
+
+	return nil
+}
 
+// reconcileLoadBalancers creates the instance groups and the configured control-plane load balancer(s).
+func (s *Service) reconcileLoadBalancers(ctx context.Context) error {
 	// Creates instance groups used by load balancer(s)
 	instancegroups, err := s.createOrGetInstanceGroups(ctx)
 	if err != nil {
@@ -118,8 +131,11 @@ func (s *Service) deleteExternalLoadBalancer(ctx context.Context) error {
 	}
 	s.scope.Network().APIServerForwardingRule = nil
 
-	if err := s.deleteAddress(ctx, name); err != nil {
-		return fmt.Errorf("deleting Address: %w", err)
+	// A provided address outlives the cluster.
+	if s.scope.LoadBalancer().APIServerAddress == nil {
+		if err := s.deleteAddress(ctx, name); err != nil {
+			return fmt.Errorf("deleting Address: %w", err)
+		}
 	}
 	s.scope.Network().APIServerAddress = nil
 
@@ -196,7 +212,12 @@ func (s *Service) createExternalLoadBalancer(ctx context.Context, lbType infrav1
 	}
 	s.scope.Network().APIServerTargetProxy = ptr.To[string](target.SelfLink)
 
-	addr, err := s.createOrGetAddress(ctx, name)
+	var addr *compute.Address
+	if address := s.scope.LoadBalancer().APIServerAddress; address != nil {
+		addr, err = s.getAPIServerAddress(ctx, *address)
+	} else {
+		addr, err = s.createOrGetAddress(ctx, name)
+	}
 	if err != nil {
 		return err
 	}
@@ -316,6 +337,15 @@ func (s *Service) createOrGetHealthCheck(ctx context.Context, lbname string) (*c
 		}
 	}
 
+	if !healthCheckUpToDate(healthcheck, healthcheckSpec) {
+		log.V(2).Info("Updating a healthcheck", "name", healthcheckSpec.Name)
+		setHealthCheckParameters(healthcheck, healthcheckSpec)
+		if err := s.healthchecks.Update(ctx, key, healthcheck); err != nil {
+			log.Error(err, "Error updating a healthcheck", "name", healthcheckSpec.Name)
+			return nil, err
+		}
+	}
+
 	return healthcheck, nil
 }
 
@@ -344,9 +374,41 @@ func (s *Service) createOrGetRegionalHealthCheck(ctx context.Context, lbname str
 		}
 	}
 
+	if !healthCheckUpToDate(healthcheck, healthcheckSpec) {
+		log.V(2).Info("Updating a regional healthcheck", "name", healthcheckSpec.Name)
+		setHealthCheckParameters(healthcheck, healthcheckSpec)
+		if err := s.regionalhealthchecks.Update(ctx, key, healthcheck); err != nil {
+			log.Error(err, "Error updating a regional healthcheck", "name", healthcheckSpec.Name)
+			return nil, err
+		}
+	}
+
 	return healthcheck, nil
 }
 
+// healthCheckUpToDate reports whether the probing parameters of the health check match its spec.
+func healthCheckUpToDate(healthcheck, spec *compute.HealthCheck) bool {
+	if healthcheck.HttpsHealthCheck == nil {
+		return false
+	}
+	return healthcheck.HttpsHealthCheck.Port == spec.HttpsHealthCheck.Port &&
+		healthcheck.HttpsHealthCheck.RequestPath == spec.HttpsHealthCheck.RequestPath &&
+		healthcheck.CheckIntervalSec == spec.CheckIntervalSec &&
+		healthcheck.TimeoutSec == spec.TimeoutSec &&
+		healthcheck.HealthyThreshold == spec.HealthyThreshold &&
+		healthcheck.UnhealthyThreshold == spec.UnhealthyThreshold
+}
+
+// setHealthCheckParameters copies the probing parameters of the spec to the health check.
+func setHealthCheckParameters(healthcheck, spec *compute.HealthCheck) {
+	healthcheck.Type = spec.Type
+	healthcheck.HttpsHealthCheck = spec.HttpsHealthCheck
+	healthcheck.CheckIntervalSec = spec.CheckIntervalSec
+	healthcheck.TimeoutSec = spec.TimeoutSec
+	healthcheck.HealthyThreshold = spec.HealthyThreshold
+	healthcheck.UnhealthyThreshold = spec.UnhealthyThreshold
+}
+
 func (s *Service) createOrGetBackendService(ctx context.Context, lbname string, mode loadBalancingMode, instancegroups []*compute.InstanceGroup, healthcheck *compute.HealthCheck) (*compute.BackendService, error) {
 	log := log.FromContext(ctx)
 	backends := make([]*compute.Backend, 0, len(instancegroups))
@@ -510,6 +572,39 @@ func (s *Service) createOrGetAddress(ctx context.Context, lbname string) (*compu
 	return addr, nil
 }
 
+// getAPIServerAddress looks up the pre-reserved global address provided for the External Load Balancer,
+// by name or by IP address.
+func (s *Service) getAPIServerAddress(ctx context.Context, address string) (*compute.Address, error) {
+	log := log.FromContext(ctx)
+	log.V(2).Info("Looking for provided address", "address", address)
+	if net.ParseIP(address) != nil {
+		addrs, err := s.addresses.List(ctx, filter.Regexp("address", regexp.QuoteMeta(address)))
+		if err != nil {
+			log.Error(err, "Error looking for provided address", "address", address)
+			return nil, err
+		}
+		if len(addrs) == 0 {
+			return nil, fmt.Errorf("APIServerAddress %s is not a global static address reserved in the project", address)
+		}
+		return addrs[0], nil
+	}
+
+	addr, err := s.addresses.Get(ctx, meta.GlobalKey(address))
+	if err != nil {
+		if gcperrors.IsNotFound(err) {
+			return nil, fmt.Errorf("APIServerAddress %s is not a global static address reserved in the project, "+
+				"the External Load Balancer can't use regional addresses", address)
+		}
+		log.Error(err, "Error looking for provided address", "address", address)
+		return nil, err
+	}
+	if addr.AddressType != "" && addr.AddressType != "EXTERNAL" {
+		return nil, fmt.Errorf("APIServerAddress %s must be an external address, got %s", address, addr.AddressType)
+	}
+
+	return addr, nil
+}
+
 // createOrGetInternalAddress is used to obtain an internal address.
 func (s *Service) createOrGetInternalAddress(ctx context.Context, lbname string) (*compute.Address, error) {
 	log := log.FromContext(ctx)
@@ -807,6 +902,10 @@ func (s *Service) getSubnet(ctx context.Context) (*compute.Subnetwork, error) {
 		if region == "" {
 			region = s.scope.Region()
 		}
+		if region != s.scope.Region() {
+			// Internal Load Balancers are regional, their address must come from a subnet of the cluster region.
+			continue
+		}
 
 		subnetKey := meta.RegionalKey(subnetSpec.Name, region)
 		subnet, err := s.subnets.Get(ctx, subnetKey)
@@ -236,6 +236,96 @@ func TestService_createOrGetHealthCheck(t *testing.T) {
 				UnhealthyThreshold: 3,
 			},
 		},
+		{
+			name: "health check probes the load balancer backend port",
+			scope: func(s *scope.ClusterScope) Scope {
+				s.GCPCluster.Spec.Network.LoadBalancerBackendPort = ptr.To[int32](8443)
+				return s
+			},
+			lbName: infrav1.APIServerRoleTagValue,
+			mockHealthChecks: &cloud.MockHealthChecks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockHealthChecksObj{},
+			},
+			want: &compute.HealthCheck{
+				CheckIntervalSec:   10,
+				HealthyThreshold:   5,
+				HttpsHealthCheck:   &compute.HTTPSHealthCheck{Port: 8443, PortSpecification: "USE_FIXED_PORT", RequestPath: "/readyz"},
+				Name:               "my-cluster-apiserver",
+				SelfLink:           "https://www.googleapis.com/compute/v1/projects/proj-id/global/healthChecks/my-cluster-apiserver",
+				TimeoutSec:         5,
+				Type:               "HTTPS",
+				UnhealthyThreshold: 3,
+			},
+		},
+		{
+			name: "health check uses the configured parameters",
+			scope: func(s *scope.ClusterScope) Scope {
+				s.GCPCluster.Spec.LoadBalancer.HealthCheck = &infrav1.HealthCheck{
+					CheckIntervalSec:   ptr.To[int64](30),
+					TimeoutSec:         ptr.To[int64](10),
+					UnhealthyThreshold: ptr.To[int64](6),
+					Port:               ptr.To[int32](6444),
+					RequestPath:        ptr.To("/livez"),
+				}
+				return s
+			},
+			lbName: infrav1.APIServerRoleTagValue,
+			mockHealthChecks: &cloud.MockHealthChecks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockHealthChecksObj{},
+			},
+			want: &compute.HealthCheck{
+				CheckIntervalSec:   30,
+				HealthyThreshold:   5,
+				HttpsHealthCheck:   &compute.HTTPSHealthCheck{Port: 6444, PortSpecification: "USE_FIXED_PORT", RequestPath: "/livez"},
+				Name:               "my-cluster-apiserver",
+				SelfLink:           "https://www.googleapis.com/compute/v1/projects/proj-id/global/healthChecks/my-cluster-apiserver",
+				TimeoutSec:         10,
+				Type:               "HTTPS",
+				UnhealthyThreshold: 6,
+			},
+		},
+		{
+			name: "existing health check is updated to the configured parameters",
+			scope: func(s *scope.ClusterScope) Scope {
+				s.GCPCluster.Spec.LoadBalancer.HealthCheck = &infrav1.HealthCheck{
+					CheckIntervalSec: ptr.To[int64](30),
+					HealthyThreshold: ptr.To[int64](2),
+				}
+				return s
+			},
+			lbName: infrav1.APIServerRoleTagValue,
+			mockHealthChecks: &cloud.MockHealthChecks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects: map[meta.Key]*cloud.MockHealthChecksObj{
+					*meta.GlobalKey("my-cluster-apiserver"): {Obj: &compute.HealthCheck{
+						CheckIntervalSec:   10,
+						HealthyThreshold:   5,
+						HttpsHealthCheck:   &compute.HTTPSHealthCheck{Port: 6443, PortSpecification: "USE_FIXED_PORT", RequestPath: "/readyz"},
+						Name:               "my-cluster-apiserver",
+						SelfLink:           "https://www.googleapis.com/compute/v1/projects/proj-id/global/healthChecks/my-cluster-apiserver",
+						TimeoutSec:         5,
+						Type:               "HTTPS",
+						UnhealthyThreshold: 3,
+					}},
+				},
+				UpdateHook: func(_ context.Context, key *meta.Key, obj *compute.HealthCheck, m *cloud.MockHealthChecks, _ ...cloud.Option) error {
+					m.Objects[*key] = &cloud.MockHealthChecksObj{Obj: obj}
+					return nil
+				},
+			},
+			want: &compute.HealthCheck{
+				CheckIntervalSec:   30,
+				HealthyThreshold:   2,
+				HttpsHealthCheck:   &compute.HTTPSHealthCheck{Port: 6443, PortSpecification: "USE_FIXED_PORT", RequestPath: "/readyz"},
+				Name:               "my-cluster-apiserver",
+				SelfLink:           "https://www.googleapis.com/compute/v1/projects/proj-id/global/healthChecks/my-cluster-apiserver",
+				TimeoutSec:         5,
+				Type:               "HTTPS",
+				UnhealthyThreshold: 3,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -254,6 +344,11 @@ func TestService_createOrGetHealthCheck(t *testing.T) {
 			if d := cmp.Diff(tt.want, got); d != "" {
 				t.Errorf("Service s.createOrGetHealthCheck() mismatch (-want +got):\n%s", d)
 			}
+			if stored, err := tt.mockHealthChecks.Get(ctx, meta.GlobalKey(tt.want.Name)); err == nil {
+				if d := cmp.Diff(tt.want, stored); d != "" {
+					t.Errorf("Service s.createOrGetHealthCheck() stored mismatch (-want +got):\n%s", d)
+				}
+			}
 		})
 	}
 }
@@ -535,6 +630,91 @@ func TestService_createOrGetAddress(t *testing.T) {
 	}
 }
 
+func TestService_getAPIServerAddress(t *testing.T) {
+	reserved := &compute.Address{
+		Address:     "34.120.10.10",
+		AddressType: "EXTERNAL",
+		Name:        "my-apiserver-ip",
+		SelfLink:    "https://www.googleapis.com/compute/v1/projects/proj-id/global/addresses/my-apiserver-ip",
+	}
+	tests := []struct {
+		name    string
+		address string
+		want    *compute.Address
+		wantErr bool
+	}{
+		{
+			name:    "address provided by name",
+			address: "my-apiserver-ip",
+			want:    reserved,
+		},
+		{
+			name:    "address provided as an IP address",
+			address: "34.120.10.10",
+			want:    reserved,
+		},
+		{
+			name:    "address name not reserved globally",
+			address: "my-regional-ip",
+			wantErr: true,
+		},
+		{
+			name:    "IP address not reserved",
+			address: "34.120.10.11",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			clusterScope, err := getBaseClusterScope()
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := New(clusterScope)
+			s.addresses = &cloud.MockGlobalAddresses{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects: map[meta.Key]*cloud.MockGlobalAddressesObj{
+					*meta.GlobalKey(reserved.Name): {Obj: reserved},
+				},
+			}
+			got, err := s.getAPIServerAddress(ctx, tt.address)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service s.getAPIServerAddress() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("Service s.getAPIServerAddress() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestService_deleteExternalLoadBalancer_providedAddress(t *testing.T) {
+	clusterScope, err := getBaseClusterScope()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clusterScope.GCPCluster.Spec.LoadBalancer.APIServerAddress = ptr.To("my-apiserver-ip")
+	s := New(clusterScope)
+	s.addresses = &cloud.MockGlobalAddresses{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+		Objects:       map[meta.Key]*cloud.MockGlobalAddressesObj{},
+		DeleteHook: func(_ context.Context, key *meta.Key, _ *cloud.MockGlobalAddresses, _ ...cloud.Option) (bool, error) {
+			t.Errorf("Service s.deleteExternalLoadBalancer() deleted address %s", key.Name)
+			return true, nil
+		},
+	}
+	s.forwardingrules = &cloud.MockGlobalForwardingRules{ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"}}
+	s.targettcpproxies = &cloud.MockTargetTcpProxies{ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"}}
+	s.backendservices = &cloud.MockBackendServices{ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"}}
+	s.healthchecks = &cloud.MockHealthChecks{ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"}}
+
+	if err := s.deleteExternalLoadBalancer(context.TODO()); err != nil {
+		t.Fatalf("Service s.deleteExternalLoadBalancer() error = %v", err)
+	}
+}
+
 func TestService_createOrGetInternalAddress(t *testing.T) {
 	address := &compute.Address{
 		IpVersion:   "IPV4",
@@ -656,6 +836,28 @@ func TestService_createOrGetInternalAddress(t *testing.T) {
 			want:      staticAddress,
 			sharedVPC: true,
 		},
+		{
+			name: "no subnet in the cluster region for internal load balancer (should return an error)",
+			scope: func(s *scope.ClusterScope) Scope {
+				s.GCPCluster.Spec.LoadBalancer = infrav1.LoadBalancerSpec{
+					LoadBalancerType: &lbTypeInternal,
+				}
+				s.GCPCluster.Spec.Network.Subnets[0].Region = "europe-west1"
+				return s
+			},
+			lbName: infrav1.InternalRoleTagValue,
+			mockAddress: &cloud.MockAddresses{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "proj-id"},
+				Objects:       map[meta.Key]*cloud.MockAddressesObj{},
+			},
+			mockSubnetworks: &cloud.MockSubnetworks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "my-proj"},
+				Objects: map[meta.Key]*cloud.MockSubnetworksObj{
+					*meta.RegionalKey("control-plane", "europe-west1"): {},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
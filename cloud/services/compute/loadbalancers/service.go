@@ -33,6 +33,11 @@ type addressesInterface interface {
 	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
 }
 
+type globaladdressesInterface interface {
+	addressesInterface
+	List(ctx context.Context, fl *filter.F, options ...k8scloud.Option) ([]*compute.Address, error)
+}
+
 type backendservicesInterface interface {
 	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.BackendService, error)
 	Insert(ctx context.Context, key *meta.Key, obj *compute.BackendService, options ...k8scloud.Option) error
@@ -57,6 +62,7 @@ type regionalforwardingrulesInterface interface {
 type healthchecksInterface interface {
 	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.HealthCheck, error)
 	Insert(ctx context.Context, key *meta.Key, obj *compute.HealthCheck, options ...k8scloud.Option) error
+	Update(ctx context.Context, key *meta.Key, obj *compute.HealthCheck, options ...k8scloud.Option) error
 	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
 }
 
@@ -92,7 +98,7 @@ type Scope interface {
 // Service implements loadbalancers reconciler.
 type Service struct {
 	scope                   Scope
-	addresses               addressesInterface
+	addresses               globaladdressesInterface
 	internaladdresses       addressesInterface
 	backendservices         backendservicesInterface
 	regionalbackendservices backendservicesInterface
@@ -18,6 +18,8 @@ package networks
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"google.golang.org/api/compute/v1"
@@ -33,21 +35,75 @@ import (
 func (s *Service) Reconcile(ctx context.Context) error {
 	log := log.FromContext(ctx)
 	log.Info("Reconciling network resources")
-	network, err := s.createOrGetNetwork(ctx)
+	network, err := s.reconcileNetwork(ctx)
+	if err != nil {
+		s.scope.MarkConditionFalse(infrav1.NetworkReadyCondition, infrav1.NetworkReconciliationFailedReason, err)
+		return err
+	}
+	s.scope.MarkConditionTrue(infrav1.NetworkReadyCondition)
+
+	if network.Description != infrav1.ClusterTagKey(s.scope.Name()) {
+		s.scope.DeleteCondition(infrav1.RouterReadyCondition)
+		return nil
+	}
+
+	router, err := s.createOrGetRouter(ctx, network)
 	if err != nil {
+		s.scope.MarkConditionFalse(infrav1.RouterReadyCondition, infrav1.RouterReconciliationFailedReason, err)
 		return err
 	}
+	s.scope.MarkConditionTrue(infrav1.RouterReadyCondition)
+
+	s.scope.Network().Router = ptr.To[string](router.SelfLink)
+	return nil
+}
+
+// reconcileNetwork creates or gets the network and makes sure its MTU and peerings are up to date.
+func (s *Service) reconcileNetwork(ctx context.Context) (*compute.Network, error) {
+	if s.scope.IsSharedVpc() {
+		if err := s.validateSharedVpcAttachment(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	network, err := s.createOrGetNetwork(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	if network.Description == infrav1.ClusterTagKey(s.scope.Name()) {
-		router, err := s.createOrGetRouter(ctx, network)
-		if err != nil {
-			return err
+		if err := s.reconcileNetworkMtu(ctx, network); err != nil {
+			return nil, err
 		}
+	}
 
-		s.scope.Network().Router = ptr.To[string](router.SelfLink)
+	if !s.scope.IsSharedVpc() {
+		if err := s.reconcileNetworkPeerings(ctx, network); err != nil {
+			return nil, err
+		}
 	}
 
 	s.scope.Network().SelfLink = ptr.To[string](network.SelfLink)
+	return network, nil
+}
+
+// reconcileNetworkMtu updates the MTU of the cluster managed network when it differs from the spec.
+// GCP only allows changing the MTU while no instances are attached to the network.
+func (s *Service) reconcileNetworkMtu(ctx context.Context, network *compute.Network) error {
+	log := log.FromContext(ctx)
+	mtu := s.scope.NetworkSpec().Mtu
+	if network.Mtu == mtu {
+		return nil
+	}
+
+	log.Info("Updating network MTU", "name", network.Name, "from", network.Mtu, "to", mtu)
+	networkKey := meta.GlobalKey(network.Name)
+	if err := s.computeNetworks.Patch(ctx, networkKey, &compute.Network{Mtu: mtu, ForceSendFields: []string{"Mtu"}}); err != nil {
+		log.Error(err, "Error updating network MTU", "name", network.Name)
+		return fmt.Errorf("updating MTU of network %s to %d, the MTU can only be changed while no instances are attached to the network: %w", network.Name, mtu, err)
+	}
+
+	network.Mtu = mtu
 	return nil
 }
 
@@ -68,6 +124,10 @@ func (s *Service) Delete(ctx context.Context) error {
 		return gcperrors.IgnoreNotFound(err)
 	}
 
+	if err := s.deleteNetworkPeerings(ctx, network); err != nil {
+		return err
+	}
+
 	if network.Description != infrav1.ClusterTagKey(s.scope.Name()) {
 		return nil
 	}
@@ -95,6 +155,119 @@ func (s *Service) Delete(ctx context.Context) error {
 
 	s.scope.Network().Router = nil
 	s.scope.Network().SelfLink = nil
+	s.scope.Network().Peerings = nil
+	return nil
+}
+
+// reconcileNetworkPeerings creates the peerings of the network or updates the existing ones, and records their state.
+// Peerings which are not part of the spec are left untouched as they may have been created outside of the provider.
+func (s *Service) reconcileNetworkPeerings(ctx context.Context, network *compute.Network) error {
+	log := log.FromContext(ctx)
+	specs := s.scope.NetworkPeeringSpecs()
+	if len(specs) == 0 {
+		s.scope.Network().Peerings = nil
+		return nil
+	}
+
+	networkKey := meta.GlobalKey(network.Name)
+	changed := false
+	for _, spec := range specs {
+		peering := findNetworkPeering(network, spec.Name)
+		switch {
+		case peering == nil:
+			log.V(2).Info("Creating a network peering", "name", spec.Name, "peer", spec.Network)
+			if err := s.computeNetworks.AddPeering(ctx, networkKey, spec); err != nil {
+				log.Error(err, "Error creating a network peering", "name", spec.Name)
+				return err
+			}
+			changed = true
+		case !sameNetwork(peering.Network, spec.Network):
+			return fmt.Errorf("network peering %s already exists with peer network %s", spec.Name, peering.Network)
+		case peering.ExportCustomRoutes != spec.ExportCustomRoutes || peering.ImportCustomRoutes != spec.ImportCustomRoutes:
+			log.V(2).Info("Updating a network peering", "name", spec.Name, "peer", spec.Network)
+			if err := s.computeNetworks.UpdatePeering(ctx, networkKey, spec); err != nil {
+				log.Error(err, "Error updating a network peering", "name", spec.Name)
+				return err
+			}
+			changed = true
+		}
+	}
+
+	if changed {
+		var err error
+		network, err = s.networks.Get(ctx, networkKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	peerings := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		if peering := findNetworkPeering(network, spec.Name); peering != nil {
+			peerings[spec.Name] = peering.State
+		}
+	}
+	s.scope.Network().Peerings = peerings
+	return nil
+}
+
+// deleteNetworkPeerings removes the peerings of the spec from the network.
+func (s *Service) deleteNetworkPeerings(ctx context.Context, network *compute.Network) error {
+	log := log.FromContext(ctx)
+	networkKey := meta.GlobalKey(network.Name)
+	for _, spec := range s.scope.NetworkPeeringSpecs() {
+		if findNetworkPeering(network, spec.Name) == nil {
+			continue
+		}
+
+		log.V(2).Info("Deleting a network peering", "name", spec.Name)
+		if err := gcperrors.IgnoreNotFound(s.computeNetworks.RemovePeering(ctx, networkKey, spec.Name)); err != nil {
+			log.Error(err, "Error deleting a network peering", "name", spec.Name)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findNetworkPeering returns the peering of the network with the given name, or nil if there is none.
+func findNetworkPeering(network *compute.Network, name string) *compute.NetworkPeering {
+	for _, peering := range network.Peerings {
+		if peering.Name == name {
+			return peering
+		}
+	}
+
+	return nil
+}
+
+// sameNetwork returns true if both full or partial network URLs reference the same network.
+func sameNetwork(a, b string) bool {
+	trim := func(url string) string {
+		if idx := strings.Index(url, "projects/"); idx >= 0 {
+			return url[idx:]
+		}
+		return url
+	}
+
+	return trim(a) == trim(b)
+}
+
+// validateSharedVpcAttachment makes sure the cluster project is a service project attached to the shared VPC
+// host project, as instances can't use the shared subnetworks otherwise.
+func (s *Service) validateSharedVpcAttachment(ctx context.Context) error {
+	log := log.FromContext(ctx)
+	log.V(2).Info("Looking for the shared VPC host project", "project", s.scope.Project())
+	host, err := s.projects.GetXpnHost(ctx, s.scope.Project())
+	if err != nil {
+		log.Error(err, "Error looking for the shared VPC host project", "project", s.scope.Project())
+		return err
+	}
+
+	if host == nil || host.Name != s.scope.NetworkProject() {
+		return fmt.Errorf("project %s is not a service project attached to the shared VPC host project %s", s.scope.Project(), s.scope.NetworkProject())
+	}
+
 	return nil
 }
 
@@ -162,5 +335,61 @@ func (s *Service) createOrGetRouter(ctx context.Context, network *compute.Networ
 		}
 	}
 
+	if router.Description == infrav1.ClusterTagKey(s.scope.Name()) {
+		if err := s.updateRouterNat(ctx, routerKey, router, spec.Nats[0]); err != nil {
+			return nil, err
+		}
+	}
+
 	return router, nil
 }
+
+// updateRouterNat updates the cloudnat config of the router when it drifted from the spec.
+func (s *Service) updateRouterNat(ctx context.Context, routerKey *meta.Key, router *compute.Router, spec *compute.RouterNat) error {
+	log := log.FromContext(ctx)
+	nats := make([]*compute.RouterNat, 0, len(router.Nats))
+	changed := false
+	for _, nat := range router.Nats {
+		if nat.Name == spec.Name && !routerNatEqual(nat, spec) {
+			updated := *nat
+			updated.MinPortsPerVm = spec.MinPortsPerVm
+			updated.MaxPortsPerVm = spec.MaxPortsPerVm
+			updated.EnableDynamicPortAllocation = spec.EnableDynamicPortAllocation
+			updated.LogConfig = spec.LogConfig
+			if updated.LogConfig == nil {
+				updated.LogConfig = &compute.RouterNatLogConfig{Enable: false, ForceSendFields: []string{"Enable"}}
+			}
+			updated.ForceSendFields = []string{"EnableDynamicPortAllocation", "MaxPortsPerVm"}
+			nat = &updated
+			changed = true
+		}
+		nats = append(nats, nat)
+	}
+	if !changed {
+		return nil
+	}
+
+	log.V(2).Info("Updating cloudnat config", "name", router.Name)
+	if err := s.routers.Patch(ctx, routerKey, &compute.Router{Nats: nats}); err != nil {
+		log.Error(err, "Error updating cloudnat config", "name", router.Name)
+		return err
+	}
+	router.Nats = nats
+
+	return nil
+}
+
+// routerNatEqual reports whether the cloudnat config managed by the provider is the same.
+func routerNatEqual(current, desired *compute.RouterNat) bool {
+	if current.MinPortsPerVm != desired.MinPortsPerVm ||
+		current.MaxPortsPerVm != desired.MaxPortsPerVm ||
+		current.EnableDynamicPortAllocation != desired.EnableDynamicPortAllocation {
+		return false
+	}
+
+	currentLog, desiredLog := current.LogConfig, desired.LogConfig
+	if currentLog == nil || !currentLog.Enable {
+		return desiredLog == nil || !desiredLog.Enable
+	}
+	return desiredLog != nil && desiredLog.Enable && currentLog.Filter == desiredLog.Filter
+}
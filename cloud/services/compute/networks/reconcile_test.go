@@ -18,8 +18,10 @@ package networks
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
@@ -329,6 +331,253 @@ func TestService_createOrGetRouter(t *testing.T) {
 	}
 }
 
+func TestService_createOrGetRouter_updateNat(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		Build()
+
+	gcpCluster := fakeGCPCluster.DeepCopy()
+	gcpCluster.Spec.Network.MinPortsPerVM = 64
+	gcpCluster.Spec.Network.CloudNAT = &infrav1.CloudNATSpec{
+		MaxPortsPerVM: ptr.To[int64](1024),
+		LogFilter:     ptr.To(infrav1.CloudNATLogFilterAll),
+	}
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: gcpCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var patched *compute.Router
+	s := New(clusterScope)
+	s.routers = &cloud.MockRouters{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "my-proj"},
+		Objects:       map[meta.Key]*cloud.MockRoutersObj{},
+		GetHook: func(_ context.Context, _ *meta.Key, _ *cloud.MockRouters, _ ...cloud.Option) (bool, *compute.Router, error) {
+			return true, &compute.Router{
+				Name:        "my-network-router",
+				Description: infrav1.ClusterTagKey("my-cluster"),
+				Nats: []*compute.RouterNat{
+					{Name: "my-network-nat", MinPortsPerVm: 64},
+				},
+			}, nil
+		},
+		PatchHook: func(_ context.Context, _ *meta.Key, router *compute.Router, _ *cloud.MockRouters, _ ...cloud.Option) error {
+			patched = router
+			return nil
+		},
+	}
+
+	if _, err := s.createOrGetRouter(context.TODO(), &compute.Network{}); err != nil {
+		t.Fatalf("Service.createOrGetRouter error = %v", err)
+	}
+	if patched == nil || len(patched.Nats) != 1 {
+		t.Fatalf("Service.createOrGetRouter expected the cloudnat config to be patched, got %v", patched)
+	}
+	nat := patched.Nats[0]
+	if !nat.EnableDynamicPortAllocation || nat.MaxPortsPerVm != 1024 || nat.LogConfig == nil || nat.LogConfig.Filter != "ALL" {
+		t.Errorf("Service.createOrGetRouter unexpected cloudnat config %+v", nat)
+	}
+}
+
+type fakeComputeNetworks struct {
+	patched  *compute.Network
+	patchErr error
+	added    []*compute.NetworkPeering
+	updated  []*compute.NetworkPeering
+	removed  []string
+}
+
+func (f *fakeComputeNetworks) Patch(_ context.Context, _ *meta.Key, network *compute.Network) error {
+	if f.patchErr != nil {
+		return f.patchErr
+	}
+	f.patched = network
+	return nil
+}
+
+func (f *fakeComputeNetworks) AddPeering(_ context.Context, _ *meta.Key, peering *compute.NetworkPeering) error {
+	f.added = append(f.added, peering)
+	return nil
+}
+
+func (f *fakeComputeNetworks) UpdatePeering(_ context.Context, _ *meta.Key, peering *compute.NetworkPeering) error {
+	f.updated = append(f.updated, peering)
+	return nil
+}
+
+func (f *fakeComputeNetworks) RemovePeering(_ context.Context, _ *meta.Key, name string) error {
+	f.removed = append(f.removed, name)
+	return nil
+}
+
+func TestService_reconcileNetworkPeerings(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		Build()
+
+	gcpCluster := fakeGCPCluster.DeepCopy()
+	gcpCluster.Spec.Network.NetworkPeerings = []infrav1.NetworkPeering{
+		{Name: "transit", PeerNetwork: "projects/my-transit-project/global/networks/transit", ImportCustomRoutes: ptr.To(true)},
+		{Name: "services", PeerNetwork: "projects/my-services-project/global/networks/services"},
+		{Name: "legacy", PeerNetwork: "projects/my-legacy-project/global/networks/legacy"},
+	}
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: gcpCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	network := &compute.Network{
+		Name: "my-network",
+		Peerings: []*compute.NetworkPeering{
+			{Name: "services", Network: "https://www.googleapis.com/compute/v1/projects/my-services-project/global/networks/services", State: "ACTIVE"},
+			{Name: "legacy", Network: "https://www.googleapis.com/compute/v1/projects/my-legacy-project/global/networks/legacy", ExportCustomRoutes: true, State: "ACTIVE"},
+			{Name: "external", Network: "https://www.googleapis.com/compute/v1/projects/other-project/global/networks/other", State: "ACTIVE"},
+		},
+	}
+	peerings := &fakeComputeNetworks{}
+	s := New(clusterScope)
+	s.computeNetworks = peerings
+	s.networks = &cloud.MockNetworks{
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "my-proj"},
+		Objects:       map[meta.Key]*cloud.MockNetworksObj{},
+		GetHook: func(_ context.Context, _ *meta.Key, _ *cloud.MockNetworks, _ ...cloud.Option) (bool, *compute.Network, error) {
+			updated := network.Peerings[:2]
+			updated = append(updated, &compute.NetworkPeering{Name: "transit", Network: "projects/my-transit-project/global/networks/transit", State: "INACTIVE"})
+			return true, &compute.Network{Name: "my-network", Peerings: updated}, nil
+		},
+	}
+
+	if err := s.reconcileNetworkPeerings(context.TODO(), network); err != nil {
+		t.Fatalf("Service.reconcileNetworkPeerings() error = %v", err)
+	}
+	if len(peerings.added) != 1 || peerings.added[0].Name != "transit" || !peerings.added[0].ImportCustomRoutes {
+		t.Errorf("Service.reconcileNetworkPeerings() expected the transit peering to be added, got %v", peerings.added)
+	}
+	if len(peerings.updated) != 1 || peerings.updated[0].Name != "legacy" || peerings.updated[0].ExportCustomRoutes {
+		t.Errorf("Service.reconcileNetworkPeerings() expected the legacy peering to be updated, got %v", peerings.updated)
+	}
+	want := map[string]string{"transit": "INACTIVE", "services": "ACTIVE", "legacy": "ACTIVE"}
+	if got := clusterScope.Network().Peerings; !reflect.DeepEqual(got, want) {
+		t.Errorf("Service.reconcileNetworkPeerings() Peerings = %v, want %v", got, want)
+	}
+}
+
+func TestService_reconcileNetworkMtu(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		Build()
+
+	gcpCluster := fakeGCPCluster.DeepCopy()
+	gcpCluster.Spec.Network.Mtu = 8896
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: gcpCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	computeNetworks := &fakeComputeNetworks{}
+	s := New(clusterScope)
+	s.computeNetworks = computeNetworks
+
+	network := &compute.Network{Name: "my-network", Mtu: 1460}
+	if err := s.reconcileNetworkMtu(context.TODO(), network); err != nil {
+		t.Fatalf("Service.reconcileNetworkMtu() error = %v", err)
+	}
+	if computeNetworks.patched == nil || computeNetworks.patched.Mtu != 8896 {
+		t.Errorf("Service.reconcileNetworkMtu() expected the network MTU to be patched to 8896, got %v", computeNetworks.patched)
+	}
+
+	computeNetworks.patched = nil
+	if err := s.reconcileNetworkMtu(context.TODO(), network); err != nil {
+		t.Fatalf("Service.reconcileNetworkMtu() error = %v", err)
+	}
+	if computeNetworks.patched != nil {
+		t.Errorf("Service.reconcileNetworkMtu() expected no patch when the MTU matches, got %v", computeNetworks.patched)
+	}
+
+	computeNetworks.patchErr = errors.New("network is in use")
+	if err := s.reconcileNetworkMtu(context.TODO(), &compute.Network{Name: "my-network", Mtu: 1460}); err == nil {
+		t.Errorf("Service.reconcileNetworkMtu() expected an error when the patch fails")
+	}
+}
+
+type fakeProjects struct {
+	host *compute.Project
+}
+
+func (f *fakeProjects) GetXpnHost(_ context.Context, _ string) (*compute.Project, error) {
+	return f.host, nil
+}
+
+func TestService_validateSharedVpcAttachment(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    *compute.Project
+		wantErr bool
+	}{
+		{
+			name:    "project attached to the host project",
+			host:    &compute.Project{Name: "my-shared-vpc-project"},
+			wantErr: false,
+		},
+		{
+			name:    "project not attached to any host project",
+			host:    &compute.Project{},
+			wantErr: true,
+		},
+		{
+			name:    "project attached to another host project",
+			host:    &compute.Project{Name: "other-host-project"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakec := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				Build()
+
+			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+				Client:     fakec,
+				Cluster:    fakeCluster,
+				GCPCluster: fakeGCPClusterSharedVPC,
+				GCPServices: scope.GCPServices{
+					Compute: &compute.Service{},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := New(clusterScope)
+			s.projects = &fakeProjects{host: tt.host}
+			if err := s.validateSharedVpcAttachment(context.TODO()); (err != nil) != tt.wantErr {
+				t.Errorf("Service.validateSharedVpcAttachment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestService_Delete(t *testing.T) {
 	fakec := fake.NewClientBuilder().
 		WithScheme(scheme.Scheme).
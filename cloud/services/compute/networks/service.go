@@ -18,12 +18,14 @@ package networks
 
 import (
 	"context"
+	"fmt"
 
 	k8scloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"google.golang.org/api/compute/v1"
 
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/shared"
 )
 
 type networksInterface interface {
@@ -36,6 +38,18 @@ type routersInterface interface {
 	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Router, error)
 	Insert(ctx context.Context, key *meta.Key, obj *compute.Router, options ...k8scloud.Option) error
 	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
+	Patch(ctx context.Context, key *meta.Key, obj *compute.Router, options ...k8scloud.Option) error
+}
+
+type computeNetworksInterface interface {
+	Patch(ctx context.Context, key *meta.Key, network *compute.Network) error
+	AddPeering(ctx context.Context, key *meta.Key, peering *compute.NetworkPeering) error
+	UpdatePeering(ctx context.Context, key *meta.Key, peering *compute.NetworkPeering) error
+	RemovePeering(ctx context.Context, key *meta.Key, name string) error
+}
+
+type projectsInterface interface {
+	GetXpnHost(ctx context.Context, project string) (*compute.Project, error)
 }
 
 // Scope is an interfaces that hold used methods.
@@ -43,13 +57,16 @@ type Scope interface {
 	cloud.Cluster
 	NetworkSpec() *compute.Network
 	NatRouterSpec() *compute.Router
+	NetworkPeeringSpecs() []*compute.NetworkPeering
 }
 
 // Service implements networks reconciler.
 type Service struct {
-	scope    Scope
-	networks networksInterface
-	routers  routersInterface
+	scope           Scope
+	networks        networksInterface
+	routers         routersInterface
+	computeNetworks computeNetworksInterface
+	projects        projectsInterface
 }
 
 var _ cloud.Reconciler = &Service{}
@@ -62,8 +79,105 @@ func New(scope Scope) *Service {
 	}
 
 	return &Service{
-		scope:    scope,
-		networks: scopeCloud.Networks(),
-		routers:  scopeCloud.Routers(),
+		scope:           scope,
+		networks:        &cachedNetworks{networksInterface: scopeCloud.Networks(), identity: scope.CredentialsIdentity(), project: scope.NetworkProject()},
+		routers:         scopeCloud.Routers(),
+		computeNetworks: &computeNetworks{service: scope.ComputeService(), project: scope.NetworkProject()},
+		projects:        &computeProjects{service: scope.ComputeService()},
+	}
+}
+
+// cachedNetworks caches the networks read, invalidating them when they are modified.
+type cachedNetworks struct {
+	networksInterface
+	identity string
+	project  string
+}
+
+// Get returns the network from the read cache, getting it when it isn't cached.
+func (c *cachedNetworks) Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Network, error) {
+	return shared.CachedGet(ctx, c.identity, c.project, "networks", key, func(ctx context.Context) (*compute.Network, error) {
+		return c.networksInterface.Get(ctx, key, options...)
+	})
+}
+
+// Insert creates the network and invalidates its cached reads.
+func (c *cachedNetworks) Insert(ctx context.Context, key *meta.Key, obj *compute.Network, options ...k8scloud.Option) error {
+	defer shared.InvalidateCache(c.project, "networks", key)
+	return c.networksInterface.Insert(ctx, key, obj, options...)
+}
+
+// Delete deletes the network and invalidates its cached reads.
+func (c *cachedNetworks) Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error {
+	defer shared.InvalidateCache(c.project, "networks", key)
+	return c.networksInterface.Delete(ctx, key, options...)
+}
+
+// computeNetworks implements the network operations the cloud doesn't support through the compute service.
+type computeNetworks struct {
+	service *compute.Service
+	project string
+}
+
+// Patch patches the network and waits for the operation to complete.
+func (c *computeNetworks) Patch(ctx context.Context, key *meta.Key, network *compute.Network) error {
+	op, err := c.service.Networks.Patch(c.project, key.Name, network).Context(ctx).Do()
+	if err != nil {
+		return err
 	}
+
+	return c.wait(ctx, key, op)
+}
+
+// AddPeering adds the peering to the network and waits for the operation to complete.
+func (c *computeNetworks) AddPeering(ctx context.Context, key *meta.Key, peering *compute.NetworkPeering) error {
+	op, err := c.service.Networks.AddPeering(c.project, key.Name, &compute.NetworksAddPeeringRequest{NetworkPeering: peering}).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// UpdatePeering updates the peering of the network and waits for the operation to complete.
+func (c *computeNetworks) UpdatePeering(ctx context.Context, key *meta.Key, peering *compute.NetworkPeering) error {
+	op, err := c.service.Networks.UpdatePeering(c.project, key.Name, &compute.NetworksUpdatePeeringRequest{NetworkPeering: peering}).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// RemovePeering removes the peering from the network and waits for the operation to complete.
+func (c *computeNetworks) RemovePeering(ctx context.Context, key *meta.Key, name string) error {
+	op, err := c.service.Networks.RemovePeering(c.project, key.Name, &compute.NetworksRemovePeeringRequest{Name: name}).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+func (c *computeNetworks) wait(ctx context.Context, key *meta.Key, op *compute.Operation) error {
+	defer shared.InvalidateCache(c.project, "networks", key)
+	op, err := shared.WaitForOperation(ctx, c.service, c.project, op)
+	if err != nil {
+		return err
+	}
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		return fmt.Errorf("operation %s on network %s failed: %s", op.OperationType, key.Name, op.Error.Errors[0].Message)
+	}
+
+	return nil
+}
+
+// computeProjects implements the project operations the cloud doesn't support through the compute service.
+type computeProjects struct {
+	service *compute.Service
+}
+
+// GetXpnHost returns the shared VPC host project the project is attached to.
+func (c *computeProjects) GetXpnHost(ctx context.Context, project string) (*compute.Project, error) {
+	return c.service.Projects.GetXpnHost(project).Context(ctx).Do()
 }
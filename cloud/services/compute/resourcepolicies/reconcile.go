@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcepolicies
+
+import (
+	"context"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/gcperrors"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Reconcile reconciles the compact placement policy of the control plane machines.
+func (s *Service) Reconcile(ctx context.Context) error {
+	log := log.FromContext(ctx)
+	if s.scope.ControlPlanePlacementPolicy() == nil {
+		return nil
+	}
+
+	log.Info("Reconciling resource policy resources")
+	spec := s.scope.PlacementPolicySpec()
+	key := meta.RegionalKey(spec.Name, s.scope.Region())
+	log.V(2).Info("Looking for resource policy", "name", spec.Name)
+	if _, err := s.resourcepolicies.Get(ctx, key); err != nil {
+		if !gcperrors.IsNotFound(err) {
+			log.Error(err, "Error looking for resource policy", "name", spec.Name)
+			return err
+		}
+
+		log.V(2).Info("Creating a resource policy", "name", spec.Name)
+		if err := s.resourcepolicies.Insert(ctx, key, spec); err != nil {
+			log.Error(err, "Error creating a resource policy", "name", spec.Name)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete deletes the compact placement policy of the control plane machines.
+func (s *Service) Delete(ctx context.Context) error {
+	log := log.FromContext(ctx)
+	if s.scope.ControlPlanePlacementPolicy() == nil {
+		return nil
+	}
+
+	spec := s.scope.PlacementPolicySpec()
+	log.V(2).Info("Deleting a resource policy", "name", spec.Name)
+	if err := s.resourcepolicies.Delete(ctx, meta.RegionalKey(spec.Name, s.scope.Region())); err != nil && !gcperrors.IsNotFound(err) {
+		log.Error(err, "Error deleting resource policy", "name", spec.Name)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcepolicies
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/scope"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	_ = clusterv1.AddToScheme(scheme.Scheme)
+	_ = infrav1.AddToScheme(scheme.Scheme)
+}
+
+var fakeCluster = &clusterv1.Cluster{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "my-cluster",
+		Namespace: "default",
+	},
+	Spec: clusterv1.ClusterSpec{},
+}
+
+var fakeGCPCluster = &infrav1.GCPCluster{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "my-cluster",
+		Namespace: "default",
+	},
+	Spec: infrav1.GCPClusterSpec{
+		Project:        "my-proj",
+		Region:         "us-central1",
+		FailureDomains: []string{"us-central1-a"},
+	},
+}
+
+// fakeResourcePolicies keeps the resource policies in memory.
+type fakeResourcePolicies struct {
+	policies map[meta.Key]*compute.ResourcePolicy
+}
+
+func (f *fakeResourcePolicies) Get(_ context.Context, key *meta.Key) (*compute.ResourcePolicy, error) {
+	policy, ok := f.policies[*key]
+	if !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound}
+	}
+	return policy, nil
+}
+
+func (f *fakeResourcePolicies) Insert(_ context.Context, key *meta.Key, obj *compute.ResourcePolicy) error {
+	f.policies[*key] = obj
+	return nil
+}
+
+func (f *fakeResourcePolicies) Delete(_ context.Context, key *meta.Key) error {
+	if _, ok := f.policies[*key]; !ok {
+		return &googleapi.Error{Code: http.StatusNotFound}
+	}
+	delete(f.policies, *key)
+	return nil
+}
+
+func TestService_Reconcile(t *testing.T) {
+	tests := []struct {
+		name                 string
+		compactPlacement     *bool
+		wantResourcePolicies int
+	}{
+		{
+			name:                 "compact placement not enabled (should not create resource policy)",
+			wantResourcePolicies: 0,
+		},
+		{
+			name:                 "compact placement enabled (should create resource policy)",
+			compactPlacement:     ptr.To(true),
+			wantResourcePolicies: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gcpCluster := fakeGCPCluster.DeepCopy()
+			gcpCluster.Spec.ControlPlaneCompactPlacement = tt.compactPlacement
+			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+				Client:     fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+				Cluster:    fakeCluster,
+				GCPCluster: gcpCluster,
+				GCPServices: scope.GCPServices{
+					Compute: &compute.Service{},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resourcepolicies := &fakeResourcePolicies{policies: map[meta.Key]*compute.ResourcePolicy{}}
+			s := New(clusterScope)
+			s.resourcepolicies = resourcepolicies
+			if err := s.Reconcile(context.TODO()); err != nil {
+				t.Fatalf("Service.Reconcile() error = %v", err)
+			}
+			if len(resourcepolicies.policies) != tt.wantResourcePolicies {
+				t.Fatalf("Service.Reconcile() resource policies = %v, want %d", resourcepolicies.policies, tt.wantResourcePolicies)
+			}
+			if tt.wantResourcePolicies == 0 {
+				return
+			}
+
+			key := meta.RegionalKey("my-cluster-control-plane", "us-central1")
+			policy, ok := resourcepolicies.policies[*key]
+			if !ok || policy.GroupPlacementPolicy.Collocation != "COLLOCATED" {
+				t.Errorf("Service.Reconcile() resource policy %s = %+v", key.Name, policy)
+			}
+
+			// Reconciling again keeps the resource policy, deleting the cluster removes it.
+			if err := s.Reconcile(context.TODO()); err != nil {
+				t.Fatalf("Service.Reconcile() error = %v", err)
+			}
+			if err := s.Delete(context.TODO()); err != nil {
+				t.Fatalf("Service.Delete() error = %v", err)
+			}
+			if len(resourcepolicies.policies) != 0 {
+				t.Errorf("Service.Delete() resource policies = %v, want none", resourcepolicies.policies)
+			}
+			if err := s.Delete(context.TODO()); err != nil {
+				t.Errorf("Service.Delete() error = %v", err)
+			}
+		})
+	}
+}
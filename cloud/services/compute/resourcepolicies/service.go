@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcepolicies
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/compute/v1"
+
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/shared"
+)
+
+type resourcepoliciesInterface interface {
+	Get(ctx context.Context, key *meta.Key) (*compute.ResourcePolicy, error)
+	Insert(ctx context.Context, key *meta.Key, obj *compute.ResourcePolicy) error
+	Delete(ctx context.Context, key *meta.Key) error
+}
+
+// Scope is an interfaces that hold used methods.
+type Scope interface {
+	cloud.Cluster
+	PlacementPolicySpec() *compute.ResourcePolicy
+}
+
+// Service implements resource policies reconciler.
+type Service struct {
+	scope            Scope
+	resourcepolicies resourcepoliciesInterface
+}
+
+var _ cloud.Reconciler = &Service{}
+
+// New returns Service from given scope.
+func New(scope Scope) *Service {
+	return &Service{
+		scope:            scope,
+		resourcepolicies: &computeResourcePolicies{service: scope.ComputeService(), project: scope.Project()},
+	}
+}
+
+// computeResourcePolicies implements the resource policy operations through the compute service, as the cloud
+// doesn't support resource policies.
+type computeResourcePolicies struct {
+	service *compute.Service
+	project string
+}
+
+// Get returns the resource policy.
+func (c *computeResourcePolicies) Get(ctx context.Context, key *meta.Key) (*compute.ResourcePolicy, error) {
+	return c.service.ResourcePolicies.Get(c.project, key.Region, key.Name).Context(ctx).Do()
+}
+
+// Insert creates the resource policy and waits for the operation to complete.
+func (c *computeResourcePolicies) Insert(ctx context.Context, key *meta.Key, obj *compute.ResourcePolicy) error {
+	op, err := c.service.ResourcePolicies.Insert(c.project, key.Region, obj).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+// Delete deletes the resource policy and waits for the operation to complete.
+func (c *computeResourcePolicies) Delete(ctx context.Context, key *meta.Key) error {
+	op, err := c.service.ResourcePolicies.Delete(c.project, key.Region, key.Name).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, key, op)
+}
+
+func (c *computeResourcePolicies) wait(ctx context.Context, key *meta.Key, op *compute.Operation) error {
+	op, err := shared.WaitForOperation(ctx, c.service, c.project, op)
+	if err != nil {
+		return err
+	}
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		return fmt.Errorf("operation %s on resource policy %s failed: %s", op.OperationType, key.Name, op.Error.Errors[0].Message)
+	}
+
+	return nil
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package subnets
 
 import (
+	"cmp"
 	"context"
+	"slices"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 
@@ -35,8 +37,10 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	// reconcile subnets
 	if _, err := s.createOrGetSubnets(ctx); err != nil {
+		s.scope.MarkConditionFalse(infrav1.SubnetsReadyCondition, infrav1.SubnetsReconciliationFailedReason, err)
 		return err
 	}
+	s.scope.MarkConditionTrue(infrav1.SubnetsReadyCondition)
 
 	return nil
 }
@@ -110,6 +114,26 @@ func (s *Service) createOrGetSubnets(ctx context.Context) ([]*compute.Subnetwork
 				logger.Error(err, "Error getting existing subnet", "name", subnetSpec.Name)
 				return subnets, err
 			}
+		} else if !s.scope.IsSharedVpc() {
+			subnet, err = s.addSecondaryRanges(ctx, subnetKey, subnet, subnetSpec)
+			if err != nil {
+				return subnets, err
+			}
+
+			subnet, err = s.updateFlowLogs(ctx, subnetKey, subnet, subnetSpec)
+			if err != nil {
+				return subnets, err
+			}
+
+			subnet, err = s.updatePrivateGoogleAccess(ctx, subnetKey, subnet, subnetSpec)
+			if err != nil {
+				return subnets, err
+			}
+
+			subnet, err = s.updateStackType(ctx, subnetKey, subnet, subnetSpec)
+			if err != nil {
+				return subnets, err
+			}
 		}
 		subnets = append(subnets, subnet)
 	}
@@ -117,6 +141,133 @@ func (s *Service) createOrGetSubnets(ctx context.Context) ([]*compute.Subnetwork
 	return subnets, nil
 }
 
+// addSecondaryRanges adds the secondary ranges of the spec missing from an existing subnet created by CAPG.
+// Existing secondary ranges are left untouched as they may be in use.
+func (s *Service) addSecondaryRanges(ctx context.Context, subnetKey *meta.Key, subnet, subnetSpec *compute.Subnetwork) (*compute.Subnetwork, error) {
+	logger := log.FromContext(ctx)
+	if !s.isOwned(subnet, subnetSpec) {
+		return subnet, nil
+	}
+
+	existing := make(map[string]bool, len(subnet.SecondaryIpRanges))
+	for _, secondaryRange := range subnet.SecondaryIpRanges {
+		existing[secondaryRange.RangeName] = true
+	}
+	secondaryRanges := append([]*compute.SubnetworkSecondaryRange{}, subnet.SecondaryIpRanges...)
+	for _, secondaryRange := range subnetSpec.SecondaryIpRanges {
+		if !existing[secondaryRange.RangeName] {
+			secondaryRanges = append(secondaryRanges, secondaryRange)
+		}
+	}
+	if len(secondaryRanges) == len(subnet.SecondaryIpRanges) {
+		return subnet, nil
+	}
+
+	logger.V(2).Info("Adding secondary ranges to a subnet", "name", subnetSpec.Name)
+	patch := &compute.Subnetwork{
+		Fingerprint:       subnet.Fingerprint,
+		SecondaryIpRanges: secondaryRanges,
+	}
+	if err := s.subnets.Patch(ctx, subnetKey, patch); err != nil {
+		logger.Error(err, "Error adding secondary ranges to a subnet", "name", subnetSpec.Name)
+		return nil, err
+	}
+
+	return s.subnets.Get(ctx, subnetKey)
+}
+
+// updateFlowLogs updates the flow logs config of an existing subnet created by CAPG when it differs from the spec.
+func (s *Service) updateFlowLogs(ctx context.Context, subnetKey *meta.Key, subnet, subnetSpec *compute.Subnetwork) (*compute.Subnetwork, error) {
+	logger := log.FromContext(ctx)
+	if subnetSpec.LogConfig == nil || subnetLogConfigEqual(subnet.LogConfig, subnetSpec.LogConfig) {
+		return subnet, nil
+	}
+	if !s.isOwned(subnet, subnetSpec) {
+		return subnet, nil
+	}
+
+	logger.V(2).Info("Updating the flow logs of a subnet", "name", subnetSpec.Name)
+	patch := &compute.Subnetwork{
+		Fingerprint: subnet.Fingerprint,
+		LogConfig:   subnetSpec.LogConfig,
+	}
+	if err := s.subnets.Patch(ctx, subnetKey, patch); err != nil {
+		logger.Error(err, "Error updating the flow logs of a subnet", "name", subnetSpec.Name)
+		return nil, err
+	}
+
+	return s.subnets.Get(ctx, subnetKey)
+}
+
+// updatePrivateGoogleAccess updates the Private Google Access of an existing subnet created by CAPG when it differs
+// from the spec.
+func (s *Service) updatePrivateGoogleAccess(ctx context.Context, subnetKey *meta.Key, subnet, subnetSpec *compute.Subnetwork) (*compute.Subnetwork, error) {
+	logger := log.FromContext(ctx)
+	if subnet.PrivateIpGoogleAccess == subnetSpec.PrivateIpGoogleAccess || !s.isOwned(subnet, subnetSpec) {
+		return subnet, nil
+	}
+
+	logger.V(2).Info("Updating the Private Google Access of a subnet", "name", subnetSpec.Name, "enabled", subnetSpec.PrivateIpGoogleAccess)
+	if err := s.computeSubnetworks.SetPrivateIPGoogleAccess(ctx, subnetKey, subnetSpec.PrivateIpGoogleAccess); err != nil {
+		logger.Error(err, "Error updating the Private Google Access of a subnet", "name", subnetSpec.Name)
+		return nil, err
+	}
+
+	return s.subnets.Get(ctx, subnetKey)
+}
+
+// updateStackType updates the stack type and IPv6 access type of an existing subnet created by CAPG when they differ
+// from the spec, e.g. to turn an IPv4 only subnet into a dual-stack subnet.
+func (s *Service) updateStackType(ctx context.Context, subnetKey *meta.Key, subnet, subnetSpec *compute.Subnetwork) (*compute.Subnetwork, error) {
+	logger := log.FromContext(ctx)
+	if subnetSpec.StackType == "" || cmp.Or(subnet.StackType, "IPV4_ONLY") == subnetSpec.StackType {
+		return subnet, nil
+	}
+	if !s.isOwned(subnet, subnetSpec) {
+		return subnet, nil
+	}
+
+	logger.V(2).Info("Updating the stack type of a subnet", "name", subnetSpec.Name, "stackType", subnetSpec.StackType)
+	patch := &compute.Subnetwork{
+		Fingerprint:    subnet.Fingerprint,
+		StackType:      subnetSpec.StackType,
+		Ipv6AccessType: subnetSpec.Ipv6AccessType,
+	}
+	if err := s.subnets.Patch(ctx, subnetKey, patch); err != nil {
+		logger.Error(err, "Error updating the stack type of a subnet", "name", subnetSpec.Name)
+		return nil, err
+	}
+
+	return s.subnets.Get(ctx, subnetKey)
+}
+
+// isOwned reports whether the existing subnet was created by CAPG, either with the cluster tag or the description
+// of the spec as its description.
+func (s *Service) isOwned(subnet, subnetSpec *compute.Subnetwork) bool {
+	return subnet.Description == infrav1.ClusterTagKey(s.scope.Name()) || (subnetSpec.Description != "" && subnet.Description == subnetSpec.Description)
+}
+
+// subnetLogConfigEqual reports whether the current flow logs config matches the desired one, fields left unset in the
+// desired config are defaulted by GCP and ignored.
+func subnetLogConfigEqual(current, desired *compute.SubnetworkLogConfig) bool {
+	if current == nil {
+		return !desired.Enable
+	}
+	if current.Enable != desired.Enable {
+		return false
+	}
+	if desired.AggregationInterval != "" && current.AggregationInterval != desired.AggregationInterval {
+		return false
+	}
+	if desired.Metadata != "" && current.Metadata != desired.Metadata {
+		return false
+	}
+	if slices.Contains(desired.ForceSendFields, "FlowSampling") && current.FlowSampling != desired.FlowSampling {
+		return false
+	}
+	return true
+}
+
 // getSubnetRegion returns subnet region if user provided it, otherwise returns default scope region.
 func (s *Service) getSubnetRegion(subnetSpec *compute.Subnetwork) string {
 	if subnetSpec.Region != "" {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
@@ -131,6 +132,55 @@ func TestService_Reconcile(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	gcpClusterSecondaryRanges := fakeGCPCluster.DeepCopy()
+	gcpClusterSecondaryRanges.Spec.Network.Subnets[0].SecondaryCidrBlocks = map[string]string{
+		"pods":     "10.1.0.0/16",
+		"services": "10.2.0.0/20",
+	}
+	clusterScopeSecondaryRanges, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: gcpClusterSecondaryRanges,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gcpClusterFlowLogs := fakeGCPCluster.DeepCopy()
+	gcpClusterFlowLogs.Spec.Network.Subnets[0].FlowLogs = &infrav1.SubnetFlowLogs{
+		AggregationInterval: ptr.To("INTERVAL_1_MIN"),
+		FlowSampling:        ptr.To("0.25"),
+	}
+	clusterScopeFlowLogs, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: gcpClusterFlowLogs,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gcpClusterDualStack := fakeGCPCluster.DeepCopy()
+	gcpClusterDualStack.Spec.Network.Subnets[0].StackType = string(infrav1.StackTypeIPv4IPv6)
+	gcpClusterDualStack.Spec.Network.Subnets[0].Ipv6AccessType = ptr.To(infrav1.Ipv6AccessTypeExternal)
+	clusterScopeDualStack, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: gcpClusterDualStack,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	tests := []testCase{
 		{
 			name:  "subnet already exist (should return existing subnet)",
@@ -189,6 +239,141 @@ func TestService_Reconcile(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:  "subnet exists without some secondary ranges (should add the missing ones)",
+			scope: func() Scope { return clusterScopeSecondaryRanges },
+			mockSubnetworks: &cloud.MockSubnetworks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "my-proj"},
+				Objects: map[meta.Key]*cloud.MockSubnetworksObj{
+					*meta.RegionalKey(fakeGCPCluster.Spec.Network.Subnets[0].Name, fakeGCPCluster.Spec.Region): {Obj: &compute.Subnetwork{
+						Name:              fakeGCPCluster.Spec.Network.Subnets[0].Name,
+						Description:       infrav1.ClusterTagKey(fakeCluster.Name),
+						Fingerprint:       "fingerprint",
+						SecondaryIpRanges: []*compute.SubnetworkSecondaryRange{{RangeName: "pods", IpCidrRange: "10.1.0.0/16"}},
+					}},
+				},
+				PatchHook: func(_ context.Context, key *meta.Key, obj *compute.Subnetwork, m *cloud.MockSubnetworks, _ ...cloud.Option) error {
+					if obj.Fingerprint != "fingerprint" {
+						return errors.New("subnet was patched without its fingerprint")
+					}
+					subnet := m.Objects[*key].Obj.(*compute.Subnetwork)
+					subnet.SecondaryIpRanges = obj.SecondaryIpRanges
+					return nil
+				},
+			},
+			assert: func(ctx context.Context, t testCase) error {
+				key := meta.RegionalKey(fakeGCPCluster.Spec.Network.Subnets[0].Name, fakeGCPCluster.Spec.Region)
+				subnet, err := t.mockSubnetworks.Get(ctx, key)
+				if err != nil {
+					return err
+				}
+
+				ranges := map[string]string{}
+				for _, secondaryRange := range subnet.SecondaryIpRanges {
+					ranges[secondaryRange.RangeName] = secondaryRange.IpCidrRange
+				}
+				if len(ranges) != 2 || ranges["pods"] != "10.1.0.0/16" || ranges["services"] != "10.2.0.0/20" {
+					return errors.New("subnet secondary ranges were not added")
+				}
+
+				return nil
+			},
+		},
+		{
+			name:  "subnet does not exist with flow logs (should create subnet with the flow logs config)",
+			scope: func() Scope { return clusterScopeFlowLogs },
+			mockSubnetworks: &cloud.MockSubnetworks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "my-proj"},
+				Objects:       map[meta.Key]*cloud.MockSubnetworksObj{},
+			},
+			assert: func(ctx context.Context, t testCase) error {
+				key := meta.RegionalKey(fakeGCPCluster.Spec.Network.Subnets[0].Name, fakeGCPCluster.Spec.Region)
+				subnet, err := t.mockSubnetworks.Get(ctx, key)
+				if err != nil {
+					return err
+				}
+
+				if !subnet.EnableFlowLogs || subnet.LogConfig == nil || !subnet.LogConfig.Enable ||
+					subnet.LogConfig.AggregationInterval != "INTERVAL_1_MIN" || subnet.LogConfig.FlowSampling != 0.25 {
+					return errors.New("subnet was created without the flow logs config")
+				}
+
+				return nil
+			},
+		},
+		{
+			name:  "subnet exists with different flow logs (should update the flow logs config)",
+			scope: func() Scope { return clusterScopeFlowLogs },
+			mockSubnetworks: &cloud.MockSubnetworks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "my-proj"},
+				Objects: map[meta.Key]*cloud.MockSubnetworksObj{
+					*meta.RegionalKey(fakeGCPCluster.Spec.Network.Subnets[0].Name, fakeGCPCluster.Spec.Region): {Obj: &compute.Subnetwork{
+						Name:        fakeGCPCluster.Spec.Network.Subnets[0].Name,
+						Description: infrav1.ClusterTagKey(fakeCluster.Name),
+						Fingerprint: "fingerprint",
+						LogConfig:   &compute.SubnetworkLogConfig{Enable: true, AggregationInterval: "INTERVAL_5_SEC", FlowSampling: 0.5},
+					}},
+				},
+				PatchHook: func(_ context.Context, key *meta.Key, obj *compute.Subnetwork, m *cloud.MockSubnetworks, _ ...cloud.Option) error {
+					if obj.Fingerprint != "fingerprint" {
+						return errors.New("subnet was patched without its fingerprint")
+					}
+					subnet := m.Objects[*key].Obj.(*compute.Subnetwork)
+					subnet.LogConfig = obj.LogConfig
+					return nil
+				},
+			},
+			assert: func(ctx context.Context, t testCase) error {
+				key := meta.RegionalKey(fakeGCPCluster.Spec.Network.Subnets[0].Name, fakeGCPCluster.Spec.Region)
+				subnet, err := t.mockSubnetworks.Get(ctx, key)
+				if err != nil {
+					return err
+				}
+
+				if subnet.LogConfig.AggregationInterval != "INTERVAL_1_MIN" || subnet.LogConfig.FlowSampling != 0.25 {
+					return errors.New("subnet flow logs config was not updated")
+				}
+
+				return nil
+			},
+		},
+		{
+			name:  "subnet exists as IPv4 only (should update it to dual-stack)",
+			scope: func() Scope { return clusterScopeDualStack },
+			mockSubnetworks: &cloud.MockSubnetworks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "my-proj"},
+				Objects: map[meta.Key]*cloud.MockSubnetworksObj{
+					*meta.RegionalKey(fakeGCPCluster.Spec.Network.Subnets[0].Name, fakeGCPCluster.Spec.Region): {Obj: &compute.Subnetwork{
+						Name:        fakeGCPCluster.Spec.Network.Subnets[0].Name,
+						Description: infrav1.ClusterTagKey(fakeCluster.Name),
+						Fingerprint: "fingerprint",
+						StackType:   "IPV4_ONLY",
+					}},
+				},
+				PatchHook: func(_ context.Context, key *meta.Key, obj *compute.Subnetwork, m *cloud.MockSubnetworks, _ ...cloud.Option) error {
+					if obj.Fingerprint != "fingerprint" {
+						return errors.New("subnet was patched without its fingerprint")
+					}
+					subnet := m.Objects[*key].Obj.(*compute.Subnetwork)
+					subnet.StackType = obj.StackType
+					subnet.Ipv6AccessType = obj.Ipv6AccessType
+					return nil
+				},
+			},
+			assert: func(ctx context.Context, t testCase) error {
+				key := meta.RegionalKey(fakeGCPCluster.Spec.Network.Subnets[0].Name, fakeGCPCluster.Spec.Region)
+				subnet, err := t.mockSubnetworks.Get(ctx, key)
+				if err != nil {
+					return err
+				}
+
+				if subnet.StackType != "IPV4_IPV6" || subnet.Ipv6AccessType != "EXTERNAL" {
+					return errors.New("subnet stack type was not updated")
+				}
+
+				return nil
+			},
+		},
 		{
 			name:  "subnet list error find issue shared vpc",
 			scope: func() Scope { return clusterScopeSharedVpc },
@@ -315,3 +500,69 @@ func TestService_Delete(t *testing.T) {
 		})
 	}
 }
+
+type fakeComputeSubnetworks struct {
+	privateGoogleAccess *bool
+}
+
+func (f *fakeComputeSubnetworks) SetPrivateIPGoogleAccess(_ context.Context, _ *meta.Key, enabled bool) error {
+	f.privateGoogleAccess = ptr.To(enabled)
+	return nil
+}
+
+func TestService_updatePrivateGoogleAccess(t *testing.T) {
+	fakec := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		Build()
+
+	gcpCluster := fakeGCPCluster.DeepCopy()
+	gcpCluster.Spec.Network.Subnets[0].PrivateGoogleAccess = ptr.To(true)
+	clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+		Client:     fakec,
+		Cluster:    fakeCluster,
+		GCPCluster: gcpCluster,
+		GCPServices: scope.GCPServices{
+			Compute: &compute.Service{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		description string
+		want        *bool
+	}{
+		{
+			name:        "subnet created by CAPG (should enable Private Google Access)",
+			description: infrav1.ClusterTagKey(fakeCluster.Name),
+			want:        ptr.To(true),
+		},
+		{
+			name:        "subnet not created by CAPG (should be left untouched)",
+			description: "existing subnet",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subnetKey := meta.RegionalKey(gcpCluster.Spec.Network.Subnets[0].Name, gcpCluster.Spec.Region)
+			computeSubnetworks := &fakeComputeSubnetworks{}
+			s := New(clusterScope)
+			s.computeSubnetworks = computeSubnetworks
+			s.subnets = &cloud.MockSubnetworks{
+				ProjectRouter: &cloud.SingleProjectRouter{ID: "my-proj"},
+				Objects: map[meta.Key]*cloud.MockSubnetworksObj{
+					*subnetKey: {Obj: &compute.Subnetwork{Name: subnetKey.Name}},
+				},
+			}
+			subnet := &compute.Subnetwork{Name: subnetKey.Name, Description: tt.description}
+			if _, err := s.updatePrivateGoogleAccess(context.TODO(), subnetKey, subnet, clusterScope.SubnetSpecs()[0]); err != nil {
+				t.Fatalf("Service.updatePrivateGoogleAccess() error = %v", err)
+			}
+			if !reflect.DeepEqual(computeSubnetworks.privateGoogleAccess, tt.want) {
+				t.Errorf("Service.updatePrivateGoogleAccess() Private Google Access = %v, want %v", computeSubnetworks.privateGoogleAccess, tt.want)
+			}
+		})
+	}
+}
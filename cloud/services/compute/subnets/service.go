@@ -18,18 +18,25 @@ package subnets
 
 import (
 	"context"
+	"fmt"
 
 	k8scloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"google.golang.org/api/compute/v1"
 
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/shared"
 )
 
 type subnetsInterface interface {
 	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Subnetwork, error)
 	Insert(ctx context.Context, key *meta.Key, obj *compute.Subnetwork, options ...k8scloud.Option) error
 	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
+	Patch(ctx context.Context, key *meta.Key, obj *compute.Subnetwork, options ...k8scloud.Option) error
+}
+
+type computeSubnetworksInterface interface {
+	SetPrivateIPGoogleAccess(ctx context.Context, key *meta.Key, enabled bool) error
 }
 
 // Scope is an interfaces that hold used methods.
@@ -40,8 +47,9 @@ type Scope interface {
 
 // Service implements subnets reconciler.
 type Service struct {
-	scope   Scope
-	subnets subnetsInterface
+	scope              Scope
+	subnets            subnetsInterface
+	computeSubnetworks computeSubnetworksInterface
 }
 
 var _ cloud.Reconciler = &Service{}
@@ -54,7 +62,66 @@ func New(scope Scope) *Service {
 	}
 
 	return &Service{
-		scope:   scope,
-		subnets: cloudScope.Subnetworks(),
+		scope:              scope,
+		subnets:            &cachedSubnets{subnetsInterface: cloudScope.Subnetworks(), identity: scope.CredentialsIdentity(), project: scope.NetworkProject()},
+		computeSubnetworks: &computeSubnetworks{service: scope.ComputeService(), project: scope.NetworkProject()},
 	}
 }
+
+// cachedSubnets caches the subnetworks read, invalidating them when they are modified.
+type cachedSubnets struct {
+	subnetsInterface
+	identity string
+	project  string
+}
+
+// Get returns the subnetwork from the read cache, getting it when it isn't cached.
+func (c *cachedSubnets) Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Subnetwork, error) {
+	return shared.CachedGet(ctx, c.identity, c.project, "subnetworks", key, func(ctx context.Context) (*compute.Subnetwork, error) {
+		return c.subnetsInterface.Get(ctx, key, options...)
+	})
+}
+
+// Insert creates the subnetwork and invalidates its cached reads.
+func (c *cachedSubnets) Insert(ctx context.Context, key *meta.Key, obj *compute.Subnetwork, options ...k8scloud.Option) error {
+	defer shared.InvalidateCache(c.project, "subnetworks", key)
+	return c.subnetsInterface.Insert(ctx, key, obj, options...)
+}
+
+// Delete deletes the subnetwork and invalidates its cached reads.
+func (c *cachedSubnets) Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error {
+	defer shared.InvalidateCache(c.project, "subnetworks", key)
+	return c.subnetsInterface.Delete(ctx, key, options...)
+}
+
+// Patch patches the subnetwork and invalidates its cached reads.
+func (c *cachedSubnets) Patch(ctx context.Context, key *meta.Key, obj *compute.Subnetwork, options ...k8scloud.Option) error {
+	defer shared.InvalidateCache(c.project, "subnetworks", key)
+	return c.subnetsInterface.Patch(ctx, key, obj, options...)
+}
+
+// computeSubnetworks implements the subnetwork operations the cloud doesn't support through the compute service.
+type computeSubnetworks struct {
+	service *compute.Service
+	project string
+}
+
+// SetPrivateIPGoogleAccess sets the Private Google Access of the subnetwork and waits for the operation to complete.
+func (c *computeSubnetworks) SetPrivateIPGoogleAccess(ctx context.Context, key *meta.Key, enabled bool) error {
+	defer shared.InvalidateCache(c.project, "subnetworks", key)
+	req := &compute.SubnetworksSetPrivateIpGoogleAccessRequest{PrivateIpGoogleAccess: enabled, ForceSendFields: []string{"PrivateIpGoogleAccess"}}
+	op, err := c.service.Subnetworks.SetPrivateIpGoogleAccess(c.project, key.Region, key.Name, req).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	op, err = shared.WaitForOperation(ctx, c.service, c.project, op)
+	if err != nil {
+		return err
+	}
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		return fmt.Errorf("operation %s on subnetwork %s failed: %s", op.OperationType, key.Name, op.Error.Errors[0].Message)
+	}
+
+	return nil
+}
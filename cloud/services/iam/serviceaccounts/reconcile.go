@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccounts
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/iam/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/gcperrors"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Reconcile reconciles the service account of the worker machines and its role bindings on the project.
+func (s *Service) Reconcile(ctx context.Context) error {
+	log := log.FromContext(ctx)
+	spec := s.scope.NodeServiceAccountSpec()
+	if spec == nil {
+		return nil
+	}
+
+	log.Info("Reconciling service account resources")
+	name := s.serviceAccountName(spec)
+	log.V(2).Info("Looking for service account", "name", name)
+	serviceAccount, err := s.serviceaccounts.Get(ctx, name)
+	if err != nil {
+		if !gcperrors.IsNotFound(err) {
+			log.Error(err, "Error looking for service account", "name", name)
+			return err
+		}
+
+		log.V(2).Info("Creating a service account", "name", name)
+		serviceAccount, err = s.serviceaccounts.Create(ctx, s.scope.Project(), spec)
+		if err != nil {
+			log.Error(err, "Error creating a service account", "name", name)
+			return err
+		}
+	} else if !s.isOwned(serviceAccount) {
+		return fmt.Errorf("service account %s already exists and isn't owned by the cluster", name)
+	}
+
+	member := serviceAccountMember(serviceAccount.Email)
+	policy, err := s.projects.GetIamPolicy(ctx, s.scope.Project())
+	if err != nil {
+		log.Error(err, "Error getting the IAM policy of the project", "project", s.scope.Project())
+		return err
+	}
+	if addRoleBindings(policy, member, s.scope.NodeServiceAccountRoles()) {
+		log.V(2).Info("Granting roles to the service account", "name", name, "roles", s.scope.NodeServiceAccountRoles())
+		if err := s.projects.SetIamPolicy(ctx, s.scope.Project(), policy); err != nil {
+			log.Error(err, "Error granting roles to the service account", "name", name)
+			return err
+		}
+	}
+
+	s.scope.SetNodeServiceAccountEmail(serviceAccount.Email)
+	return nil
+}
+
+// Delete deletes the service account of the worker machines and its role bindings on the project.
+func (s *Service) Delete(ctx context.Context) error {
+	log := log.FromContext(ctx)
+	spec := s.scope.NodeServiceAccountSpec()
+	if spec == nil {
+		return nil
+	}
+
+	name := s.serviceAccountName(spec)
+	serviceAccount, err := s.serviceaccounts.Get(ctx, name)
+	switch {
+	case err != nil && !gcperrors.IsNotFound(err):
+		log.Error(err, "Error looking for service account", "name", name)
+		return err
+	case err == nil && !s.isOwned(serviceAccount):
+		log.Info("Skipping the deletion of a service account not owned by the cluster", "name", name)
+		s.scope.SetNodeServiceAccountEmail("")
+		return nil
+	}
+
+	// Deleting the service account doesn't remove its role bindings, which would count against the policy size limit.
+	policy, err := s.projects.GetIamPolicy(ctx, s.scope.Project())
+	if err != nil {
+		log.Error(err, "Error getting the IAM policy of the project", "project", s.scope.Project())
+		return err
+	}
+	if removeRoleBindings(policy, serviceAccountMember(s.serviceAccountEmail(spec))) {
+		log.V(2).Info("Revoking the roles of the service account", "name", name)
+		if err := s.projects.SetIamPolicy(ctx, s.scope.Project(), policy); err != nil {
+			log.Error(err, "Error revoking the roles of the service account", "name", name)
+			return err
+		}
+	}
+
+	log.V(2).Info("Deleting a service account", "name", name)
+	if err := s.serviceaccounts.Delete(ctx, name); err != nil && !gcperrors.IsNotFound(err) {
+		log.Error(err, "Error deleting service account", "name", name)
+		return err
+	}
+
+	s.scope.SetNodeServiceAccountEmail("")
+	return nil
+}
+
+func (s *Service) serviceAccountEmail(spec *iam.CreateServiceAccountRequest) string {
+	return fmt.Sprintf("%s@%s.iam.gserviceaccount.com", spec.AccountId, s.scope.Project())
+}
+
+func (s *Service) serviceAccountName(spec *iam.CreateServiceAccountRequest) string {
+	return fmt.Sprintf("projects/%s/serviceAccounts/%s", s.scope.Project(), s.serviceAccountEmail(spec))
+}
+
+// isOwned reports whether the service account was created for the cluster, which describes it with its tag key.
+func (s *Service) isOwned(serviceAccount *iam.ServiceAccount) bool {
+	return serviceAccount.Description == infrav1.ClusterTagKey(s.scope.Name())
+}
+
+func serviceAccountMember(email string) string {
+	return "serviceAccount:" + email
+}
+
+// addRoleBindings adds the member to the unconditional bindings of the roles in the policy, and reports whether the
+// policy changed.
+func addRoleBindings(policy *cloudresourcemanager.Policy, member string, roles []string) bool {
+	changed := false
+	for _, role := range roles {
+		index := slices.IndexFunc(policy.Bindings, func(binding *cloudresourcemanager.Binding) bool {
+			return binding.Role == role && binding.Condition == nil
+		})
+		if index < 0 {
+			policy.Bindings = append(policy.Bindings, &cloudresourcemanager.Binding{Role: role, Members: []string{member}})
+			changed = true
+			continue
+		}
+		if !slices.Contains(policy.Bindings[index].Members, member) {
+			policy.Bindings[index].Members = append(policy.Bindings[index].Members, member)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// removeRoleBindings removes the member from all the bindings of the policy, dropping the bindings left without
+// members, and reports whether the policy changed.
+func removeRoleBindings(policy *cloudresourcemanager.Policy, member string) bool {
+	changed := false
+	bindings := make([]*cloudresourcemanager.Binding, 0, len(policy.Bindings))
+	for _, binding := range policy.Bindings {
+		if members := slices.DeleteFunc(slices.Clone(binding.Members), func(m string) bool { return m == member }); len(members) != len(binding.Members) {
+			changed = true
+			if len(members) == 0 {
+				continue
+			}
+			binding.Members = members
+		}
+		bindings = append(bindings, binding)
+	}
+	policy.Bindings = bindings
+	return changed
+}
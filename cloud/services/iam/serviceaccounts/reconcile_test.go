@@ -0,0 +1,278 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccounts
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iam/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/scope"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	_ = clusterv1.AddToScheme(scheme.Scheme)
+	_ = infrav1.AddToScheme(scheme.Scheme)
+}
+
+var fakeCluster = &clusterv1.Cluster{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "my-cluster",
+		Namespace: "default",
+	},
+	Spec: clusterv1.ClusterSpec{},
+}
+
+var fakeGCPCluster = &infrav1.GCPCluster{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "my-cluster",
+		Namespace: "default",
+	},
+	Spec: infrav1.GCPClusterSpec{
+		Project: "my-proj",
+		Region:  "us-central1",
+	},
+}
+
+// fakeServiceAccounts keeps the service accounts in memory.
+type fakeServiceAccounts struct {
+	serviceAccounts map[string]*iam.ServiceAccount
+}
+
+func (f *fakeServiceAccounts) Get(_ context.Context, name string) (*iam.ServiceAccount, error) {
+	serviceAccount, ok := f.serviceAccounts[name]
+	if !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound}
+	}
+	return serviceAccount, nil
+}
+
+func (f *fakeServiceAccounts) Create(_ context.Context, project string, req *iam.CreateServiceAccountRequest) (*iam.ServiceAccount, error) {
+	serviceAccount := *req.ServiceAccount
+	serviceAccount.Email = req.AccountId + "@" + project + ".iam.gserviceaccount.com"
+	serviceAccount.Name = "projects/" + project + "/serviceAccounts/" + serviceAccount.Email
+	f.serviceAccounts[serviceAccount.Name] = &serviceAccount
+	return &serviceAccount, nil
+}
+
+func (f *fakeServiceAccounts) Delete(_ context.Context, name string) error {
+	if _, ok := f.serviceAccounts[name]; !ok {
+		return &googleapi.Error{Code: http.StatusNotFound}
+	}
+	delete(f.serviceAccounts, name)
+	return nil
+}
+
+// fakeProjects keeps the IAM policy of the project in memory and counts its updates.
+type fakeProjects struct {
+	policy  *cloudresourcemanager.Policy
+	updates int
+}
+
+func (f *fakeProjects) GetIamPolicy(_ context.Context, _ string) (*cloudresourcemanager.Policy, error) {
+	policy := &cloudresourcemanager.Policy{}
+	for _, binding := range f.policy.Bindings {
+		policy.Bindings = append(policy.Bindings, &cloudresourcemanager.Binding{Role: binding.Role, Members: slices.Clone(binding.Members)})
+	}
+	return policy, nil
+}
+
+func (f *fakeProjects) SetIamPolicy(_ context.Context, _ string, policy *cloudresourcemanager.Policy) error {
+	f.policy = policy
+	f.updates++
+	return nil
+}
+
+func (f *fakeProjects) members(role string) []string {
+	for _, binding := range f.policy.Bindings {
+		if binding.Role == role {
+			return binding.Members
+		}
+	}
+	return nil
+}
+
+func TestService_Reconcile(t *testing.T) {
+	tests := []struct {
+		name               string
+		clusterName        string
+		nodeServiceAccount *infrav1.NodeServiceAccountSpec
+		wantEmail          string
+	}{
+		{
+			name: "node service account not enabled (should not create service account)",
+		},
+		{
+			name:               "node service account enabled (should create service account)",
+			nodeServiceAccount: &infrav1.NodeServiceAccountSpec{AdditionalRoles: []string{"roles/storage.objectViewer"}},
+			wantEmail:          "capg-",
+		},
+		{
+			name:               "long cluster name (should create service account with a valid ID)",
+			clusterName:        "my-very-long-cluster-name-for-tests",
+			nodeServiceAccount: &infrav1.NodeServiceAccountSpec{},
+			wantEmail:          "capg-",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gcpCluster := fakeGCPCluster.DeepCopy()
+			gcpCluster.Spec.NodeServiceAccount = tt.nodeServiceAccount
+			cluster := fakeCluster.DeepCopy()
+			if tt.clusterName != "" {
+				cluster.Name = tt.clusterName
+			}
+			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+				Client:     fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+				Cluster:    cluster,
+				GCPCluster: gcpCluster,
+				GCPServices: scope.GCPServices{
+					Compute:         &compute.Service{},
+					IAM:             &iam.Service{},
+					ResourceManager: &cloudresourcemanager.Service{},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			serviceAccounts := &fakeServiceAccounts{serviceAccounts: map[string]*iam.ServiceAccount{}}
+			projects := &fakeProjects{policy: &cloudresourcemanager.Policy{Bindings: []*cloudresourcemanager.Binding{
+				{Role: "roles/logging.logWriter", Members: []string{"user:admin@example.com"}},
+			}}}
+			s := New(clusterScope)
+			s.serviceaccounts = serviceAccounts
+			s.projects = projects
+			if err := s.Reconcile(context.TODO()); err != nil {
+				t.Fatalf("Service.Reconcile() error = %v", err)
+			}
+			if tt.wantEmail == "" {
+				if len(serviceAccounts.serviceAccounts) != 0 || projects.updates != 0 {
+					t.Fatalf("Service.Reconcile() service accounts = %v, policy updates = %d, want none", serviceAccounts.serviceAccounts, projects.updates)
+				}
+				return
+			}
+
+			email := gcpCluster.Status.NodeServiceAccountEmail
+			if !strings.HasPrefix(email, tt.wantEmail) || len(strings.Split(email, "@")[0]) > 30 {
+				t.Fatalf("Service.Reconcile() NodeServiceAccountEmail = %q, want %s", email, tt.wantEmail)
+			}
+			member := "serviceAccount:" + email
+			for _, role := range clusterScope.NodeServiceAccountRoles() {
+				if !slices.Contains(projects.members(role), member) {
+					t.Errorf("Service.Reconcile() role %s members = %v, want %s", role, projects.members(role), member)
+				}
+			}
+			if !slices.Contains(projects.members("roles/logging.logWriter"), "user:admin@example.com") {
+				t.Errorf("Service.Reconcile() removed the existing role bindings")
+			}
+
+			// Reconciling again doesn't update the policy, deleting the cluster revokes the roles and removes the service account.
+			if err := s.Reconcile(context.TODO()); err != nil {
+				t.Fatalf("Service.Reconcile() error = %v", err)
+			}
+			if projects.updates != 1 {
+				t.Errorf("Service.Reconcile() policy updates = %d, want 1", projects.updates)
+			}
+			if err := s.Delete(context.TODO()); err != nil {
+				t.Fatalf("Service.Delete() error = %v", err)
+			}
+			if len(serviceAccounts.serviceAccounts) != 0 {
+				t.Errorf("Service.Delete() service accounts = %v, want none", serviceAccounts.serviceAccounts)
+			}
+			if len(projects.policy.Bindings) != 1 || !slices.Equal(projects.policy.Bindings[0].Members, []string{"user:admin@example.com"}) {
+				t.Errorf("Service.Delete() policy bindings = %+v, want only the existing one", projects.policy.Bindings)
+			}
+			if err := s.Delete(context.TODO()); err != nil {
+				t.Errorf("Service.Delete() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestService_NotOwned(t *testing.T) {
+	newService := func(namespace string, serviceAccounts *fakeServiceAccounts, projects *fakeProjects) (*Service, *infrav1.GCPCluster) {
+		gcpCluster := fakeGCPCluster.DeepCopy()
+		gcpCluster.Namespace = namespace
+		gcpCluster.Spec.NodeServiceAccount = &infrav1.NodeServiceAccountSpec{}
+		cluster := fakeCluster.DeepCopy()
+		cluster.Namespace = namespace
+		clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+			Client:     fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+			Cluster:    cluster,
+			GCPCluster: gcpCluster,
+			GCPServices: scope.GCPServices{
+				Compute:         &compute.Service{},
+				IAM:             &iam.Service{},
+				ResourceManager: &cloudresourcemanager.Service{},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := New(clusterScope)
+		s.serviceaccounts = serviceAccounts
+		s.projects = projects
+		return s, gcpCluster
+	}
+	serviceAccounts := &fakeServiceAccounts{serviceAccounts: map[string]*iam.ServiceAccount{}}
+	projects := &fakeProjects{policy: &cloudresourcemanager.Policy{}}
+
+	// The clusters of the same name in other namespaces have their own service account.
+	s, gcpCluster := newService("default", serviceAccounts, projects)
+	if err := s.Reconcile(context.TODO()); err != nil {
+		t.Fatalf("Service.Reconcile() error = %v", err)
+	}
+	other, otherGCPCluster := newService("other", serviceAccounts, projects)
+	if err := other.Reconcile(context.TODO()); err != nil {
+		t.Fatalf("Service.Reconcile() error = %v", err)
+	}
+	if gcpCluster.Status.NodeServiceAccountEmail == otherGCPCluster.Status.NodeServiceAccountEmail {
+		t.Fatalf("Service.Reconcile() NodeServiceAccountEmail = %q for both clusters", gcpCluster.Status.NodeServiceAccountEmail)
+	}
+
+	// A service account with the same ID but another description isn't adopted nor deleted.
+	for _, serviceAccount := range serviceAccounts.serviceAccounts {
+		serviceAccount.Description = "created by hand"
+	}
+	if err := s.Reconcile(context.TODO()); err == nil {
+		t.Errorf("Service.Reconcile() error = nil, want an error for the service account not owned by the cluster")
+	}
+	updates := projects.updates
+	if err := s.Delete(context.TODO()); err != nil {
+		t.Fatalf("Service.Delete() error = %v", err)
+	}
+	if len(serviceAccounts.serviceAccounts) != 2 || projects.updates != updates {
+		t.Errorf("Service.Delete() service accounts = %v, policy updates = %d, want them untouched", serviceAccounts.serviceAccounts, projects.updates-updates)
+	}
+	if gcpCluster.Status.NodeServiceAccountEmail != "" {
+		t.Errorf("Service.Delete() NodeServiceAccountEmail = %q, want none", gcpCluster.Status.NodeServiceAccountEmail)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccounts
+
+import (
+	"context"
+
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/iam/v1"
+
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud"
+)
+
+type serviceaccountsInterface interface {
+	Get(ctx context.Context, name string) (*iam.ServiceAccount, error)
+	Create(ctx context.Context, project string, req *iam.CreateServiceAccountRequest) (*iam.ServiceAccount, error)
+	Delete(ctx context.Context, name string) error
+}
+
+type projectsInterface interface {
+	GetIamPolicy(ctx context.Context, project string) (*cloudresourcemanager.Policy, error)
+	SetIamPolicy(ctx context.Context, project string, policy *cloudresourcemanager.Policy) error
+}
+
+// Scope is an interfaces that hold used methods.
+type Scope interface {
+	cloud.Cluster
+	IAMService() *iam.Service
+	ResourceManagerService() *cloudresourcemanager.Service
+	NodeServiceAccountSpec() *iam.CreateServiceAccountRequest
+	NodeServiceAccountRoles() []string
+	SetNodeServiceAccountEmail(email string)
+}
+
+// Service implements service accounts reconciler.
+type Service struct {
+	scope           Scope
+	serviceaccounts serviceaccountsInterface
+	projects        projectsInterface
+}
+
+var _ cloud.Reconciler = &Service{}
+
+// New returns Service from given scope.
+func New(scope Scope) *Service {
+	return &Service{
+		scope:           scope,
+		serviceaccounts: &iamServiceAccounts{service: scope.IAMService()},
+		projects:        &resourceManagerProjects{service: scope.ResourceManagerService()},
+	}
+}
+
+// iamServiceAccounts implements the service account operations through the IAM service.
+type iamServiceAccounts struct {
+	service *iam.Service
+}
+
+// Get returns the service account.
+func (c *iamServiceAccounts) Get(ctx context.Context, name string) (*iam.ServiceAccount, error) {
+	return c.service.Projects.ServiceAccounts.Get(name).Context(ctx).Do()
+}
+
+// Create creates the service account in the project.
+func (c *iamServiceAccounts) Create(ctx context.Context, project string, req *iam.CreateServiceAccountRequest) (*iam.ServiceAccount, error) {
+	return c.service.Projects.ServiceAccounts.Create("projects/"+project, req).Context(ctx).Do()
+}
+
+// Delete deletes the service account.
+func (c *iamServiceAccounts) Delete(ctx context.Context, name string) error {
+	_, err := c.service.Projects.ServiceAccounts.Delete(name).Context(ctx).Do()
+	return err
+}
+
+// resourceManagerProjects implements the project IAM policy operations through the resource manager service.
+type resourceManagerProjects struct {
+	service *cloudresourcemanager.Service
+}
+
+// GetIamPolicy returns the IAM policy of the project, including its conditional role bindings.
+func (c *resourceManagerProjects) GetIamPolicy(ctx context.Context, project string) (*cloudresourcemanager.Policy, error) {
+	req := &cloudresourcemanager.GetIamPolicyRequest{
+		Options: &cloudresourcemanager.GetPolicyOptions{RequestedPolicyVersion: 3},
+	}
+	return c.service.Projects.GetIamPolicy(project, req).Context(ctx).Do()
+}
+
+// SetIamPolicy sets the IAM policy of the project. The policy etag makes the update fail if the policy was changed
+// since it was read.
+func (c *resourceManagerProjects) SetIamPolicy(ctx context.Context, project string, policy *cloudresourcemanager.Policy) error {
+	_, err := c.service.Projects.SetIamPolicy(project, &cloudresourcemanager.SetIamPolicyRequest{Policy: policy}).Context(ctx).Do()
+	return err
+}
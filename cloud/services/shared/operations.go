@@ -0,0 +1,50 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"context"
+	"path"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// WaitForOperation waits for the zonal, regional or global compute operation to be done, and returns it so that the
+// caller checks its error. A wait call returns after about 2 minutes even when the operation is still running, the
+// operation is waited for again until it is done or the context is done.
+func WaitForOperation(ctx context.Context, service *compute.Service, project string, op *compute.Operation) (*compute.Operation, error) {
+	for op.Status != "DONE" {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var err error
+		switch {
+		case op.Zone != "":
+			op, err = service.ZoneOperations.Wait(project, path.Base(op.Zone), op.Name).Context(ctx).Do()
+		case op.Region != "":
+			op, err = service.RegionOperations.Wait(project, path.Base(op.Region), op.Name).Context(ctx).Do()
+		default:
+			op, err = service.GlobalOperations.Wait(project, op.Name).Context(ctx).Do()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return op, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+func TestWaitForOperation(t *testing.T) {
+	tests := []struct {
+		name     string
+		op       *compute.Operation
+		wantPath string
+	}{
+		{
+			name:     "zonal operation",
+			op:       &compute.Operation{Name: "op-1", Zone: "https://www.googleapis.com/compute/v1/projects/my-proj/zones/us-central1-a"},
+			wantPath: "/projects/my-proj/zones/us-central1-a/operations/op-1/wait",
+		},
+		{
+			name:     "regional operation",
+			op:       &compute.Operation{Name: "op-1", Region: "https://www.googleapis.com/compute/v1/projects/my-proj/regions/us-central1"},
+			wantPath: "/projects/my-proj/regions/us-central1/operations/op-1/wait",
+		},
+		{
+			name:     "global operation",
+			op:       &compute.Operation{Name: "op-1"},
+			wantPath: "/projects/my-proj/global/operations/op-1/wait",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var paths []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path)
+				// The wait call returns the running operation the first time, as when it gives up after 2 minutes.
+				op := *tt.op
+				op.Status = "RUNNING"
+				if len(paths) > 1 {
+					op.Status = "DONE"
+				}
+				_ = json.NewEncoder(w).Encode(&op)
+			}))
+			defer server.Close()
+			service, err := compute.NewService(context.TODO(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+			g.Expect(err).NotTo(HaveOccurred())
+
+			op, err := WaitForOperation(context.TODO(), service, "my-proj", tt.op)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(op.Status).To(Equal("DONE"))
+			g.Expect(paths).To(Equal([]string{tt.wantPath, tt.wantPath}))
+		})
+	}
+}
+
+func TestWaitForOperation_canceled(t *testing.T) {
+	g := NewWithT(t)
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	_, err := WaitForOperation(ctx, &compute.Service{}, "my-proj", &compute.Operation{Name: "op-1", Status: "RUNNING"})
+	g.Expect(err).To(MatchError(context.Canceled))
+
+	// The operations already done aren't waited for.
+	op, err := WaitForOperation(ctx, &compute.Service{}, "my-proj", &compute.Operation{Name: "op-1", Status: "DONE"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(op.Status).To(Equal("DONE"))
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+// readCacheSize is the max number of resources in the read cache, a cluster reading a handful of networks, subnets
+// and firewall rules.
+const readCacheSize = 8192
+
+var (
+	// readCache caches the GCP resources read by the services, by credentials, project, resource type and key, as they
+	// are read on every reconcile of every cluster while they rarely change.
+	readCache = cache.NewLRUExpireCache(readCacheSize)
+	// readCacheTTL is how long the resources are cached, the cache is disabled when it isn't positive.
+	readCacheTTL atomic.Int64
+	// readCacheGeneration is incremented on every invalidation, so that a resource read before it isn't cached.
+	readCacheGeneration atomic.Uint64
+)
+
+// SetReadCacheTTL sets how long the GCP resources read by the services are cached, 0 disabling the cache.
+func SetReadCacheTTL(ttl time.Duration) {
+	readCacheTTL.Store(int64(ttl))
+}
+
+// CachedGet returns a copy of the resource from the read cache, getting and caching it when it isn't cached. The
+// resources are cached per credentials identity, so that a cluster never reads a resource its credentials can't read.
+func CachedGet[T any](ctx context.Context, identity, project, resource string, key *meta.Key, get func(context.Context) (*T, error)) (*T, error) {
+	ttl := time.Duration(readCacheTTL.Load())
+	if ttl <= 0 {
+		return get(ctx)
+	}
+
+	cacheKey := readCacheKey{identity: identity, resource: resourcePath(project, resource, key)}
+	if cached, ok := readCache.Get(cacheKey); ok {
+		if obj, err := clone(cached.(*T)); err == nil {
+			return obj, nil
+		}
+	}
+
+	generation := readCacheGeneration.Load()
+	obj, err := get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cached, err := clone(obj)
+	if err != nil || readCacheGeneration.Load() != generation {
+		// The resource may have been modified while it was read.
+		return obj, nil //nolint:nilerr // The resource just isn't cached.
+	}
+	readCache.Add(cacheKey, cached, ttl)
+	return obj, nil
+}
+
+// InvalidateCache removes the resource from the read cache for all the credentials, it must be called whenever the
+// resource is modified.
+func InvalidateCache(project, resource string, key *meta.Key) {
+	readCacheGeneration.Add(1)
+	invalidated := resourcePath(project, resource, key)
+	readCache.RemoveAll(func(cacheKey any) bool {
+		return cacheKey.(readCacheKey).resource == invalidated
+	})
+}
+
+// readCacheKey is the key of a resource in the read cache.
+type readCacheKey struct {
+	// identity identifies the credentials the resource was read with.
+	identity string
+	// resource is the path of the resource.
+	resource string
+}
+
+func resourcePath(project, resource string, key *meta.Key) string {
+	return path.Join(project, resource, key.String())
+}
+
+// clone deep copies the resource, the callers being free to modify the resources they get.
+func clone[T any](obj *T) (*T, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	out := new(T)
+	if err := json.Unmarshal(data, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
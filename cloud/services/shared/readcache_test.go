@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestCachedGet(t *testing.T) {
+	g := NewWithT(t)
+	key := meta.GlobalKey("my-network")
+	reads := 0
+	get := func(context.Context) (*compute.Network, error) {
+		reads++
+		return &compute.Network{Name: "my-network", Mtu: 1460}, nil
+	}
+
+	// The cache is disabled by default.
+	_, err := CachedGet(context.TODO(), "default", "my-proj", "networks", key, get)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = CachedGet(context.TODO(), "default", "my-proj", "networks", key, get)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reads).To(Equal(2))
+
+	SetReadCacheTTL(time.Minute)
+	t.Cleanup(func() { SetReadCacheTTL(0) })
+
+	network, err := CachedGet(context.TODO(), "default", "my-proj", "networks", key, get)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reads).To(Equal(3))
+	// The callers modifying the resource don't modify the cached one.
+	network.Mtu = 8896
+
+	network, err = CachedGet(context.TODO(), "default", "my-proj", "networks", key, get)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reads).To(Equal(3))
+	g.Expect(network.Mtu).To(Equal(int64(1460)))
+
+	// The resources are cached per project.
+	_, err = CachedGet(context.TODO(), "default", "other-proj", "networks", key, get)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reads).To(Equal(4))
+
+	InvalidateCache("my-proj", "networks", key)
+	_, err = CachedGet(context.TODO(), "default", "my-proj", "networks", key, get)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reads).To(Equal(5))
+}
+
+func TestCachedGet_credentials(t *testing.T) {
+	g := NewWithT(t)
+	SetReadCacheTTL(time.Minute)
+	t.Cleanup(func() { SetReadCacheTTL(0) })
+	key := meta.GlobalKey("my-firewall")
+	reads := map[string]int{}
+	getWith := func(identity string) func(context.Context) (*compute.Firewall, error) {
+		return func(context.Context) (*compute.Firewall, error) {
+			reads[identity]++
+			return &compute.Firewall{Name: "my-firewall"}, nil
+		}
+	}
+
+	// The clusters of two tenants, or impersonating another service account, don't share the resources read with
+	// their credentials.
+	for _, identity := range []string{"tenant-a/credentials", "tenant-b/credentials", "tenant-b/credentials/admin@tenant-b.iam.gserviceaccount.com"} {
+		for range 2 {
+			_, err := CachedGet(context.TODO(), identity, "my-proj", "firewalls", key, getWith(identity))
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		g.Expect(reads[identity]).To(Equal(1))
+	}
+
+	// A modified resource is invalidated for all the credentials.
+	InvalidateCache("my-proj", "firewalls", key)
+	for _, identity := range []string{"tenant-a/credentials", "tenant-b/credentials"} {
+		_, err := CachedGet(context.TODO(), identity, "my-proj", "firewalls", key, getWith(identity))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(reads[identity]).To(Equal(2))
+	}
+}
+
+func TestCachedGet_errorsAndConcurrentInvalidation(t *testing.T) {
+	g := NewWithT(t)
+	SetReadCacheTTL(time.Minute)
+	t.Cleanup(func() { SetReadCacheTTL(0) })
+	key := meta.RegionalKey("my-subnet", "us-central1")
+	reads := 0
+
+	// The errors aren't cached.
+	failingGet := func(context.Context) (*compute.Subnetwork, error) {
+		reads++
+		return nil, errors.New("not found")
+	}
+	_, err := CachedGet(context.TODO(), "default", "my-proj", "subnetworks", key, failingGet)
+	g.Expect(err).To(HaveOccurred())
+	_, err = CachedGet(context.TODO(), "default", "my-proj", "subnetworks", key, failingGet)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(reads).To(Equal(2))
+
+	// The resource modified while it was read isn't cached.
+	invalidatingGet := func(context.Context) (*compute.Subnetwork, error) {
+		reads++
+		InvalidateCache("my-proj", "subnetworks", key)
+		return &compute.Subnetwork{Name: "my-subnet"}, nil
+	}
+	_, err = CachedGet(context.TODO(), "default", "my-proj", "subnetworks", key, invalidatingGet)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = CachedGet(context.TODO(), "default", "my-proj", "subnetworks", key, invalidatingGet)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reads).To(Equal(4))
+}
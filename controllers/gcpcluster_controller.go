@@ -18,24 +18,29 @@ package controllers
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/compute/firewalls"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/compute/loadbalancers"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/compute/networks"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/compute/resourcepolicies"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/compute/subnets"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/iam/serviceaccounts"
 	"sigs.k8s.io/cluster-api-provider-gcp/util/reconciler"
 	clusterv1beta1 "sigs.k8s.io/cluster-api/api/core/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	v1beta1conditions "sigs.k8s.io/cluster-api/util/deprecated/v1beta1/conditions"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	"sigs.k8s.io/cluster-api/util/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -180,30 +185,41 @@ func (r *GCPClusterReconciler) reconcile(ctx context.Context, clusterScope *scop
 	}
 
 	failureDomains := make(clusterv1beta1.FailureDomains, len(zones))
+	configured := sets.New(clusterScope.GCPCluster.Spec.FailureDomains...)
+	unknown := configured.Clone()
 	for _, zone := range zones {
-		if len(clusterScope.GCPCluster.Spec.FailureDomains) > 0 {
-			for _, fd := range clusterScope.GCPCluster.Spec.FailureDomains {
-				if fd == zone.Name {
-					failureDomains[zone.Name] = clusterv1beta1.FailureDomainSpec{
-						ControlPlane: true,
-					}
-				}
-			}
-		} else {
-			failureDomains[zone.Name] = clusterv1beta1.FailureDomainSpec{
-				ControlPlane: true,
-			}
+		unknown.Delete(zone.Name)
+		if zone.Status == "DOWN" {
+			// Machines can't be created in a zone that is down, don't offer it as a failure domain.
+			log.Info("Skipping zone that is down", "zone", zone.Name)
+			continue
+		}
+		if configured.Len() > 0 && !configured.Has(zone.Name) {
+			continue
+		}
+		failureDomains[zone.Name] = clusterv1beta1.FailureDomainSpec{
+			ControlPlane: true,
 		}
 	}
+	if unknown.Len() > 0 {
+		// Report the configured zones that don't exist in the region rather than silently dropping them.
+		record.Warnf(clusterScope.GCPCluster, "UnknownFailureDomains", "Failure domains %s are not zones of region %s",
+			strings.Join(sets.List(unknown), ", "), clusterScope.Region())
+	}
 
 	clusterScope.SetFailureDomains(failureDomains)
 
+	// The services report the state of their resources in their own conditions, summarize them in Ready.
+	defer setReadyCondition(clusterScope.GCPCluster)
+
 	reconcilers := []cloud.Reconciler{
 		networks.New(clusterScope),
 		firewalls.New(clusterScope),
 		// Reconcile subnets before loadbalancers since subnet is needed for internal LB
 		subnets.New(clusterScope),
 		loadbalancers.New(clusterScope),
+		resourcepolicies.New(clusterScope),
+		serviceaccounts.New(clusterScope),
 	}
 
 	for _, r := range reconcilers {
@@ -214,6 +230,21 @@ func (r *GCPClusterReconciler) reconcile(ctx context.Context, clusterScope *scop
 		}
 	}
 
+	if peerings := clusterScope.GCPCluster.Spec.Network.NetworkPeerings; len(peerings) > 0 && !clusterScope.IsSharedVpc() {
+		inactive := []string{}
+		for _, peering := range peerings {
+			if clusterScope.Network().Peerings[peering.Name] != "ACTIVE" {
+				inactive = append(inactive, peering.Name)
+			}
+		}
+		if len(inactive) == 0 {
+			v1beta1conditions.MarkTrue(clusterScope.GCPCluster, infrav1.NetworkPeeringsReadyCondition)
+		} else {
+			v1beta1conditions.MarkFalse(clusterScope.GCPCluster, infrav1.NetworkPeeringsReadyCondition, infrav1.NetworkPeeringInactiveReason, clusterv1beta1.ConditionSeverityWarning,
+				"Network peerings %s are inactive, make sure the peer networks have the reciprocal peerings", strings.Join(inactive, ", "))
+		}
+	}
+
 	controlPlaneEndpoint := clusterScope.ControlPlaneEndpoint()
 	if controlPlaneEndpoint.Host == "" {
 		log.Info("GCPCluster does not have control-plane endpoint yet. Reconciling")
@@ -227,11 +258,26 @@ func (r *GCPClusterReconciler) reconcile(ctx context.Context, clusterScope *scop
 	return ctrl.Result{}, nil
 }
 
+// setReadyCondition aggregates the conditions reported by the cluster services into the Ready condition.
+func setReadyCondition(gcpCluster *infrav1.GCPCluster) {
+	v1beta1conditions.SetSummary(gcpCluster,
+		v1beta1conditions.WithConditions(
+			infrav1.NetworkReadyCondition,
+			infrav1.RouterReadyCondition,
+			infrav1.SubnetsReadyCondition,
+			infrav1.FirewallsReadyCondition,
+			infrav1.LoadBalancerReadyCondition,
+		),
+	)
+}
+
 func (r *GCPClusterReconciler) reconcileDelete(ctx context.Context, clusterScope *scope.ClusterScope) error {
 	log := log.FromContext(ctx)
 	log.Info("Reconciling Delete GCPCluster")
 
 	reconcilers := []cloud.Reconciler{
+		serviceaccounts.New(clusterScope),
+		resourcepolicies.New(clusterScope),
 		loadbalancers.New(clusterScope),
 		subnets.New(clusterScope),
 		firewalls.New(clusterScope),
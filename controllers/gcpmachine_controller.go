@@ -19,17 +19,23 @@ package controllers
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/providerid"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/compute/instances"
 	"sigs.k8s.io/cluster-api-provider-gcp/util/reconciler"
+	clusterv1beta1 "sigs.k8s.io/cluster-api/api/core/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	v1beta1conditions "sigs.k8s.io/cluster-api/util/deprecated/v1beta1/conditions"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	"sigs.k8s.io/cluster-api/util/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -44,8 +50,9 @@ import (
 // GCPMachineReconciler reconciles a GCPMachine object.
 type GCPMachineReconciler struct {
 	client.Client
-	ReconcileTimeout time.Duration
-	WatchFilterValue string
+	ReconcileTimeout        time.Duration
+	WatchFilterValue        string
+	DisableDriftRemediation bool
 }
 
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
@@ -190,10 +197,11 @@ func (r *GCPMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Create the machine scope
 	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
-		Client:        r.Client,
-		Machine:       machine,
-		GCPMachine:    gcpMachine,
-		ClusterGetter: clusterScope,
+		Client:                  r.Client,
+		Machine:                 machine,
+		GCPMachine:              gcpMachine,
+		ClusterGetter:           clusterScope,
+		DisableDriftRemediation: r.DisableDriftRemediation,
 	})
 	if err != nil {
 		return ctrl.Result{}, errors.Errorf("failed to create scope: %+v", err)
@@ -208,7 +216,7 @@ func (r *GCPMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Handle deleted machines
 	if !gcpMachine.DeletionTimestamp.IsZero() {
-		return ctrl.Result{}, r.reconcileDelete(ctx, machineScope)
+		return r.reconcileDelete(ctx, machineScope)
 	}
 
 	// Handle non-deleted machines
@@ -224,41 +232,249 @@ func (r *GCPMachineReconciler) reconcile(ctx context.Context, machineScope *scop
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileZone(ctx, machineScope); err != nil {
+		log.Error(err, "Error selecting the zone of the machine")
+		return ctrl.Result{}, err
+	}
+
 	if err := instances.New(machineScope).Reconcile(ctx); err != nil {
 		log.Error(err, "Error reconciling instance resources")
 		record.Warnf(machineScope.GCPMachine, "GCPMachineReconcile", "Reconcile error - %v", err)
+		if ptr.Deref(machineScope.GCPMachine.Status.FailureReason, "") == "InvalidConfiguration" {
+			// Retrying won't help as the GCPMachine spec is immutable.
+			v1beta1conditions.MarkFalse(machineScope.GCPMachine, infrav1.InstanceReadyCondition, infrav1.InvalidConfigurationReason, clusterv1beta1.ConditionSeverityError,
+				"%s", ptr.Deref(machineScope.GCPMachine.Status.FailureMessage, ""))
+			return ctrl.Result{}, nil
+		}
+		if ptr.Deref(machineScope.GCPMachine.Status.FailureReason, "") == "InsufficientResources" {
+			// Retrying won't help until the quota is raised, let the Machine be remediated.
+			v1beta1conditions.MarkFalse(machineScope.GCPMachine, infrav1.InstanceReadyCondition, infrav1.InsufficientResourcesReason, clusterv1beta1.ConditionSeverityError,
+				"%s", ptr.Deref(machineScope.GCPMachine.Status.FailureMessage, ""))
+			return ctrl.Result{}, nil
+		}
+		var exhausted *instances.ZoneResourcePoolExhaustedError
+		if errors.As(err, &exhausted) {
+			// Report the lack of capacity so that it can be acted upon, e.g. by a MachineHealthCheck remediating
+			// the Machine, retrying in case resources become available.
+			v1beta1conditions.MarkFalse(machineScope.GCPMachine, infrav1.InstanceReadyCondition, infrav1.ZoneResourcePoolExhaustedReason, clusterv1beta1.ConditionSeverityWarning,
+				"Zone %s doesn't have enough resources to create the instance", exhausted.Zone)
+			// Stockouts usually last a while, don't hammer the API with the default backoff.
+			return ctrl.Result{RequeueAfter: zoneResourcePoolExhaustedRetryInterval}, nil
+		}
+		var tagsErr *instances.ResourceManagerTagsError
+		if errors.As(err, &tagsErr) {
+			v1beta1conditions.MarkFalse(machineScope.GCPMachine, infrav1.InstanceReadyCondition, infrav1.ResourceManagerTagsBindingFailedReason, clusterv1beta1.ConditionSeverityWarning,
+				"%s", tagsErr.Error())
+		}
 		return ctrl.Result{}, err
 	}
 
+	if ptr.Deref(machineScope.PublicIP(), false) || machineScope.ClusterGetter.Network().Router != nil {
+		v1beta1conditions.MarkTrue(machineScope.GCPMachine, infrav1.EgressReadyCondition)
+	} else {
+		v1beta1conditions.MarkFalse(machineScope.GCPMachine, infrav1.EgressReadyCondition, infrav1.NoCloudNATReason, clusterv1beta1.ConditionSeverityWarning,
+			"Instance has no public IP and the cluster doesn't manage a Cloud NAT, make sure the network provides egress")
+	}
+
+	if !ptr.Deref(machineScope.PublicIP(), false) && !ptr.Deref(machineScope.PrivateGoogleAccess(), true) {
+		v1beta1conditions.MarkFalse(machineScope.GCPMachine, infrav1.PrivateGoogleAccessReadyCondition, infrav1.PrivateGoogleAccessDisabledReason, clusterv1beta1.ConditionSeverityWarning,
+			"Instance has no public IP and Private Google Access is disabled on its subnet, Google APIs such as Artifact Registry may be unreachable")
+	} else {
+		v1beta1conditions.MarkTrue(machineScope.GCPMachine, infrav1.PrivateGoogleAccessReadyCondition)
+	}
+
+	return r.reconcileInstanceState(ctx, machineScope), nil
+}
+
+// reconcileZone selects the zone of a GCPMachine whose Machine has no failure domain, spreading the machines with the
+// same owner, i.e. MachineSet or control plane, across the failure domains of the GCPCluster. The zone is recorded in
+// the GCPMachine spec so that it doesn't change afterwards.
+func (r *GCPMachineReconciler) reconcileZone(ctx context.Context, machineScope *scope.MachineScope) error {
+	log := log.FromContext(ctx)
+	if machineScope.Machine.Spec.FailureDomain != "" || machineScope.GCPMachine.Spec.Zone != nil {
+		return nil
+	}
+
+	if machineScope.GCPMachine.Spec.ProviderID != nil {
+		// The instance was created before its zone was recorded, or is being adopted, in the zone of its provider ID.
+		providerID, err := providerid.Parse(*machineScope.GCPMachine.Spec.ProviderID)
+		if err != nil {
+			return err
+		}
+		machineScope.SetSelectedZone(providerID.Location())
+		return nil
+	}
+
+	zones := machineScope.ClusterGetter.FailureDomains()
+	if len(zones) == 0 {
+		return nil
+	}
+	sort.Strings(zones)
+
+	machinesPerZone := make(map[string]int, len(zones))
+	if owner := metav1.GetControllerOf(machineScope.Machine); owner != nil {
+		machineList := &clusterv1.MachineList{}
+		labels := map[string]string{clusterv1.ClusterNameLabel: machineScope.Machine.Spec.ClusterName}
+		if err := r.List(ctx, machineList, client.InNamespace(machineScope.Namespace()), client.MatchingLabels(labels)); err != nil {
+			return errors.Wrap(err, "failed to list Machines")
+		}
+
+		for i := range machineList.Items {
+			m := &machineList.Items[i]
+			if m.Name == machineScope.Machine.Name {
+				continue
+			}
+			if mOwner := metav1.GetControllerOf(m); mOwner == nil || mOwner.UID != owner.UID {
+				continue
+			}
+
+			zone := m.Spec.FailureDomain
+			if zone == "" && m.Spec.InfrastructureRef.Name != "" {
+				gcpMachine := &infrav1.GCPMachine{}
+				if err := r.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: m.Spec.InfrastructureRef.Name}, gcpMachine); err != nil {
+					if apierrors.IsNotFound(err) {
+						continue
+					}
+					return errors.Wrapf(err, "failed to get GCPMachine %s", m.Spec.InfrastructureRef.Name)
+				}
+				zone = ptr.Deref(gcpMachine.Spec.Zone, "")
+			}
+			machinesPerZone[zone]++
+		}
+	}
+
+	selected := zones[0]
+	for _, zone := range zones[1:] {
+		if machinesPerZone[zone] < machinesPerZone[selected] {
+			selected = zone
+		}
+	}
+	log.Info("Selected the zone of the machine", "zone", selected)
+	machineScope.SetSelectedZone(selected)
+	return nil
+}
+
+// preemptionCheckInterval is how often the running Spot and preemptible instances are checked for preemption.
+const preemptionCheckInterval = time.Minute
+
+// zoneResourcePoolExhaustedRetryInterval is how often the creation of an instance is retried when its zone is out of
+// resources.
+const zoneResourcePoolExhaustedRetryInterval = 5 * time.Minute
+
+// instanceStateReasons maps the states of a GCE instance to the reason of the InstanceReady condition.
+var instanceStateReasons = map[infrav1.InstanceStatus]string{
+	infrav1.InstanceStatusProvisioning: infrav1.InstanceProvisioningReason,
+	infrav1.InstanceStatusStaging:      infrav1.InstanceStagingReason,
+	infrav1.InstanceStatusRepairing:    infrav1.InstanceRepairingReason,
+	infrav1.InstanceStatusStopping:     infrav1.InstanceStoppingReason,
+	infrav1.InstanceStatusStopped:      infrav1.InstanceStoppedReason,
+	infrav1.InstanceStatusSuspending:   infrav1.InstanceSuspendingReason,
+	infrav1.InstanceStatusSuspended:    infrav1.InstanceSuspendedReason,
+	infrav1.InstanceStatusTerminated:   infrav1.InstanceTerminatedReason,
+}
+
+// reconcileInstanceState reports the state of the GCE instance in the GCPMachine Ready status and InstanceReady
+// condition. An instance that stopped outside of a delete is reported as a failure so that it can be remediated.
+func (r *GCPMachineReconciler) reconcileInstanceState(ctx context.Context, machineScope *scope.MachineScope) ctrl.Result {
+	log := log.FromContext(ctx)
 	instanceState := *machineScope.GetInstanceStatus()
 	switch instanceState {
 	case infrav1.InstanceStatusProvisioning, infrav1.InstanceStatusStaging:
 		log.Info("GCPMachine instance is pending", "instance-id", *machineScope.GetInstanceID())
 		record.Eventf(machineScope.GCPMachine, "GCPMachineReconcile", "GCPMachine instance is pending - instance-id: %s", *machineScope.GetInstanceID())
-		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		machineScope.SetNotReady()
+		v1beta1conditions.MarkFalse(machineScope.GCPMachine, infrav1.InstanceReadyCondition, instanceStateReasons[instanceState], clusterv1beta1.ConditionSeverityInfo,
+			"Instance %s is %s", *machineScope.GetInstanceID(), instanceState)
+		return ctrl.Result{RequeueAfter: 5 * time.Second}
 	case infrav1.InstanceStatusRunning:
 		log.Info("GCPMachine instance is running", "instance-id", *machineScope.GetInstanceID())
 		record.Eventf(machineScope.GCPMachine, "GCPMachineReconcile", "GCPMachine instance is running - instance-id: %s", *machineScope.GetInstanceID())
 		record.Event(machineScope.GCPMachine, "GCPMachineReconcile", "Reconciled")
 		machineScope.SetReady()
-		return ctrl.Result{}, nil
+		v1beta1conditions.MarkTrue(machineScope.GCPMachine, infrav1.InstanceReadyCondition)
+		if machineScope.IsPreemptible() {
+			// GCE doesn't notify about preemptions, poll the instance to replace it quickly once preempted.
+			return ctrl.Result{RequeueAfter: preemptionCheckInterval}
+		}
+		return ctrl.Result{}
+	case infrav1.InstanceStatusRepairing, infrav1.InstanceStatusStopping, infrav1.InstanceStatusSuspending:
+		// The instance may come back to running, e.g. after GCE repaired it, or end up stopped.
+		log.Info("GCPMachine instance is not running", "instance-id", *machineScope.GetInstanceID(), "state", instanceState)
+		record.Warnf(machineScope.GCPMachine, "GCPMachineReconcile", "GCPMachine instance is %s - instance-id: %s", instanceState, *machineScope.GetInstanceID())
+		machineScope.SetNotReady()
+		v1beta1conditions.MarkFalse(machineScope.GCPMachine, infrav1.InstanceReadyCondition, instanceStateReasons[instanceState], clusterv1beta1.ConditionSeverityWarning,
+			"Instance %s is %s", *machineScope.GetInstanceID(), instanceState)
+		return ctrl.Result{RequeueAfter: reconciler.DefaultRetryTime}
 	default:
+		machineScope.SetNotReady()
+		// The instance won't be restarted, fail the machine so that it can be remediated.
 		machineScope.SetFailureReason("UpdateError")
-		machineScope.SetFailureMessage(errors.Errorf("GCPMachine instance state %s is unexpected", instanceState))
-		return ctrl.Result{RequeueAfter: reconciler.DefaultRetryTime}, nil
+		if machineScope.IsPreemptible() && (instanceState == infrav1.InstanceStatusTerminated || instanceState == infrav1.InstanceStatusStopped) {
+			if v1beta1conditions.GetReason(machineScope.GCPMachine, infrav1.InstanceReadyCondition) != infrav1.InstancePreemptedReason {
+				log.Info("GCPMachine instance has been preempted", "instance-id", *machineScope.GetInstanceID())
+				record.Warnf(machineScope.GCPMachine, "GCPMachineReconcile", "GCPMachine instance has been preempted - instance-id: %s", *machineScope.GetInstanceID())
+				preemptionsTotal.WithLabelValues(machineScope.Namespace(), machineScope.Machine.Spec.ClusterName).Inc()
+			}
+			v1beta1conditions.MarkFalse(machineScope.GCPMachine, infrav1.InstanceReadyCondition, infrav1.InstancePreemptedReason, clusterv1beta1.ConditionSeverityWarning, "Instance %s has been preempted", *machineScope.GetInstanceID())
+			machineScope.SetFailureMessage(errors.Errorf("GCPMachine instance %s has been preempted", *machineScope.GetInstanceID()))
+		} else {
+			log.Info("GCPMachine instance is stopped", "instance-id", *machineScope.GetInstanceID(), "state", instanceState)
+			record.Warnf(machineScope.GCPMachine, "GCPMachineReconcile", "GCPMachine instance is %s - instance-id: %s", instanceState, *machineScope.GetInstanceID())
+			reason, ok := instanceStateReasons[instanceState]
+			if !ok {
+				reason = infrav1.InstanceStateUnknownReason
+			}
+			v1beta1conditions.MarkFalse(machineScope.GCPMachine, infrav1.InstanceReadyCondition, reason, clusterv1beta1.ConditionSeverityError,
+				"Instance %s is %s", *machineScope.GetInstanceID(), instanceState)
+			machineScope.SetFailureMessage(errors.Errorf("GCPMachine instance state %s is unexpected", instanceState))
+		}
+		return ctrl.Result{RequeueAfter: reconciler.DefaultRetryTime}
 	}
 }
 
-func (r *GCPMachineReconciler) reconcileDelete(ctx context.Context, machineScope *scope.MachineScope) error {
+func (r *GCPMachineReconciler) reconcileDelete(ctx context.Context, machineScope *scope.MachineScope) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 	log.Info("Reconciling Delete GCPMachine")
 
+	if remaining, err := r.reconcileDrain(ctx, machineScope); err != nil || remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, err
+	}
+
 	if err := instances.New(machineScope).Delete(ctx); err != nil {
 		log.Error(err, "Error deleting instance resources")
-		return err
+		return ctrl.Result{}, err
 	}
 
 	controllerutil.RemoveFinalizer(machineScope.GCPMachine, infrav1.MachineFinalizer)
 	record.Event(machineScope.GCPMachine, "GCPMachineReconcile", "Reconciled")
-	return nil
+	return ctrl.Result{}, nil
+}
+
+// reconcileDrain removes the instance of a deleted GCPMachine from the instance groups managed by the provider, then
+// waits for the drain timeout of the GCPMachine so that the load balancers drain the connections to the instance
+// before it is deleted. It returns how long is left to wait.
+func (r *GCPMachineReconciler) reconcileDrain(ctx context.Context, machineScope *scope.MachineScope) (time.Duration, error) {
+	log := log.FromContext(ctx)
+	timeout := machineScope.GCPMachine.Spec.DrainTimeout
+	if timeout == nil || timeout.Duration <= 0 || v1beta1conditions.IsTrue(machineScope.GCPMachine, infrav1.InstanceDrainedCondition) {
+		return 0, nil
+	}
+
+	if !v1beta1conditions.Has(machineScope.GCPMachine, infrav1.InstanceDrainedCondition) {
+		if err := instances.New(machineScope).Deregister(ctx); err != nil {
+			log.Error(err, "Error removing the instance from its instance groups")
+			return 0, err
+		}
+		log.Info("Draining GCPMachine instance", "timeout", timeout.Duration)
+		v1beta1conditions.MarkFalse(machineScope.GCPMachine, infrav1.InstanceDrainedCondition, infrav1.InstanceDrainingReason, clusterv1beta1.ConditionSeverityInfo,
+			"Waiting %s for the connections to the instance to drain", timeout.Duration)
+	}
+
+	started := v1beta1conditions.GetLastTransitionTime(machineScope.GCPMachine, infrav1.InstanceDrainedCondition)
+	if remaining := timeout.Duration - time.Since(started.Time); remaining > 0 {
+		return remaining, nil
+	}
+
+	v1beta1conditions.MarkTrue(machineScope.GCPMachine, infrav1.InstanceDrainedCondition)
+	return 0, nil
 }
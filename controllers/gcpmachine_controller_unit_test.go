@@ -19,12 +19,18 @@ package controllers
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/scope"
+	clusterv1beta1 "sigs.k8s.io/cluster-api/api/core/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	v1beta1conditions "sigs.k8s.io/cluster-api/util/deprecated/v1beta1/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -101,3 +107,279 @@ func TestGCPMachineReconciler_GCPClusterToGCPMachines(t *testing.T) {
 	})
 	g.Expect(rr).To(HaveLen(2))
 }
+
+func TestGCPMachineReconciler_reconcileInstanceState(t *testing.T) {
+	tests := []struct {
+		name              string
+		preemptible       bool
+		state             infrav1.InstanceStatus
+		wantReady         bool
+		wantReason        string
+		wantFailure       bool
+		wantRequeue       bool
+		wantInstanceReady bool
+	}{
+		{
+			name:        "provisioning instance is pending",
+			state:       infrav1.InstanceStatusProvisioning,
+			wantReason:  infrav1.InstanceProvisioningReason,
+			wantRequeue: true,
+		},
+		{
+			name:        "staging instance is pending",
+			state:       infrav1.InstanceStatusStaging,
+			wantReason:  infrav1.InstanceStagingReason,
+			wantRequeue: true,
+		},
+		{
+			name:              "running instance is ready",
+			state:             infrav1.InstanceStatusRunning,
+			wantReady:         true,
+			wantInstanceReady: true,
+		},
+		{
+			name:        "repairing instance is not ready",
+			state:       infrav1.InstanceStatusRepairing,
+			wantReason:  infrav1.InstanceRepairingReason,
+			wantRequeue: true,
+		},
+		{
+			name:        "stopping instance is not ready",
+			state:       infrav1.InstanceStatusStopping,
+			wantReason:  infrav1.InstanceStoppingReason,
+			wantRequeue: true,
+		},
+		{
+			name:        "terminated instance is a failure",
+			state:       infrav1.InstanceStatusTerminated,
+			wantReason:  infrav1.InstanceTerminatedReason,
+			wantFailure: true,
+			wantRequeue: true,
+		},
+		{
+			name:        "terminated preemptible instance has been preempted",
+			preemptible: true,
+			state:       infrav1.InstanceStatusTerminated,
+			wantReason:  infrav1.InstancePreemptedReason,
+			wantFailure: true,
+			wantRequeue: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+
+			gcpMachine := &infrav1.GCPMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-machine", Namespace: "default"},
+				Spec: infrav1.GCPMachineSpec{
+					ProviderID:  ptr.To("gce://my-proj/us-central1-c/my-machine"),
+					Preemptible: tt.preemptible,
+				},
+				// The instance was running before.
+				Status: infrav1.GCPMachineStatus{Ready: true, InstanceStatus: &tt.state},
+			}
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:     fake.NewClientBuilder().WithScheme(scheme).Build(),
+				Machine:    newMachine("my-cluster", "my-machine"),
+				GCPMachine: gcpMachine,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			reconciler := &GCPMachineReconciler{}
+			result := reconciler.reconcileInstanceState(context.TODO(), machineScope)
+			g.Expect(result.RequeueAfter > 0).To(Equal(tt.wantRequeue))
+			g.Expect(gcpMachine.Status.Ready).To(Equal(tt.wantReady))
+			g.Expect(gcpMachine.Status.FailureReason != nil).To(Equal(tt.wantFailure))
+			g.Expect(v1beta1conditions.IsTrue(gcpMachine, infrav1.InstanceReadyCondition)).To(Equal(tt.wantInstanceReady))
+			if tt.wantReason != "" {
+				g.Expect(v1beta1conditions.GetReason(gcpMachine, infrav1.InstanceReadyCondition)).To(Equal(tt.wantReason))
+			}
+		})
+	}
+}
+
+func TestGCPMachineReconciler_reconcileInstanceState_preemption(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+
+	running := infrav1.InstanceStatusRunning
+	gcpMachine := &infrav1.GCPMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-spot-machine", Namespace: "default"},
+		Spec: infrav1.GCPMachineSpec{
+			ProviderID:        ptr.To("gce://my-proj/us-central1-c/my-spot-machine"),
+			ProvisioningModel: ptr.To(infrav1.ProvisioningModelSpot),
+		},
+		Status: infrav1.GCPMachineStatus{InstanceStatus: &running},
+	}
+	machine := newMachine("my-spot-cluster", "my-spot-machine")
+	machine.Spec.ClusterName = "my-spot-cluster"
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:     fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Machine:    machine,
+		GCPMachine: gcpMachine,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	preemptions := preemptionsTotal.WithLabelValues("default", "my-spot-cluster")
+
+	// A running Spot instance is polled to detect its preemption.
+	reconciler := &GCPMachineReconciler{}
+	result := reconciler.reconcileInstanceState(context.TODO(), machineScope)
+	g.Expect(result.RequeueAfter).To(Equal(preemptionCheckInterval))
+	g.Expect(gcpMachine.Status.Ready).To(BeTrue())
+	g.Expect(testutil.ToFloat64(preemptions)).To(BeZero())
+
+	// GCE preempts the instance.
+	terminated := infrav1.InstanceStatusTerminated
+	gcpMachine.Status.InstanceStatus = &terminated
+	for range 2 {
+		reconciler.reconcileInstanceState(context.TODO(), machineScope)
+		g.Expect(gcpMachine.Status.Ready).To(BeFalse())
+		g.Expect(ptr.Deref(gcpMachine.Status.FailureMessage, "")).To(ContainSubstring("has been preempted"))
+		g.Expect(v1beta1conditions.GetReason(gcpMachine, infrav1.InstanceReadyCondition)).To(Equal(infrav1.InstancePreemptedReason))
+		// The preemption is counted once.
+		g.Expect(testutil.ToFloat64(preemptions)).To(Equal(float64(1)))
+	}
+}
+
+func TestGCPMachineReconciler_reconcileDrain(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+
+	gcpMachine := &infrav1.GCPMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-machine", Namespace: "default"},
+	}
+	// Worker machines are not members of the control plane instance groups.
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Machine:       newMachine("my-cluster", "my-machine"),
+		GCPMachine:    gcpMachine,
+		ClusterGetter: &scope.ClusterScope{GCPCluster: &infrav1.GCPCluster{}},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	reconciler := &GCPMachineReconciler{}
+
+	// Without a drain timeout the instance is deleted right away.
+	requeueAfter, err := reconciler.reconcileDrain(context.TODO(), machineScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(requeueAfter).To(BeZero())
+	g.Expect(v1beta1conditions.Has(gcpMachine, infrav1.InstanceDrainedCondition)).To(BeFalse())
+
+	// The first reconcile starts draining and waits for the timeout.
+	gcpMachine.Spec.DrainTimeout = &metav1.Duration{Duration: time.Minute}
+	requeueAfter, err = reconciler.reconcileDrain(context.TODO(), machineScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(requeueAfter).To(BeNumerically(">", 0))
+	g.Expect(requeueAfter).To(BeNumerically("<=", time.Minute))
+	g.Expect(v1beta1conditions.GetReason(gcpMachine, infrav1.InstanceDrainedCondition)).To(Equal(infrav1.InstanceDrainingReason))
+
+	// Once the timeout elapsed the instance is drained.
+	for i := range gcpMachine.Status.Conditions {
+		gcpMachine.Status.Conditions[i].LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	}
+	requeueAfter, err = reconciler.reconcileDrain(context.TODO(), machineScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(requeueAfter).To(BeZero())
+	g.Expect(v1beta1conditions.IsTrue(gcpMachine, infrav1.InstanceDrainedCondition)).To(BeTrue())
+}
+
+func TestGCPMachineReconciler_reconcileZone(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+	clusterName := "my-cluster"
+	owner := metav1.OwnerReference{APIVersion: clusterv1.GroupVersion.String(), Kind: "MachineSet", Name: "my-machineset", UID: "my-machineset-uid", Controller: ptr.To(true)}
+	otherOwner := metav1.OwnerReference{APIVersion: clusterv1.GroupVersion.String(), Kind: "MachineSet", Name: "other-machineset", UID: "other-machineset-uid", Controller: ptr.To(true)}
+	newOwnedMachine := func(name string, owner metav1.OwnerReference, failureDomain string) *clusterv1.Machine {
+		m := newMachineWithInfrastructureRef(clusterName, name)
+		m.OwnerReferences = []metav1.OwnerReference{owner}
+		m.Spec.ClusterName = clusterName
+		m.Spec.FailureDomain = failureDomain
+		return m
+	}
+	newGCPMachine := func(name string, zone *string) *infrav1.GCPMachine {
+		return &infrav1.GCPMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "gcp" + name, Namespace: "default"},
+			Spec:       infrav1.GCPMachineSpec{Zone: zone},
+		}
+	}
+	clusterScope := &scope.ClusterScope{GCPCluster: &infrav1.GCPCluster{
+		Status: infrav1.GCPClusterStatus{FailureDomains: clusterv1beta1.FailureDomains{
+			"us-central1-a": clusterv1beta1.FailureDomainSpec{ControlPlane: true},
+			"us-central1-b": clusterv1beta1.FailureDomainSpec{ControlPlane: true},
+			"us-central1-c": clusterv1beta1.FailureDomainSpec{ControlPlane: true},
+		}},
+	}}
+
+	tests := []struct {
+		name          string
+		failureDomain string
+		zone          *string
+		providerID    *string
+		want          *string
+	}{
+		{
+			name: "zone with the fewest machines of the same owner is selected",
+			want: ptr.To("us-central1-c"),
+		},
+		{
+			name:          "machine with a failure domain is left as is",
+			failureDomain: "us-central1-a",
+		},
+		{
+			name: "selected zone is sticky",
+			zone: ptr.To("us-central1-b"),
+			want: ptr.To("us-central1-b"),
+		},
+		{
+			name:       "zone of an existing instance is recorded",
+			providerID: ptr.To("gce://my-proj/us-central1-a/my-machine"),
+			want:       ptr.To("us-central1-a"),
+		},
+		{
+			name:       "zone of an instance to adopt is recorded",
+			providerID: ptr.To("gce://my-proj/us-central1-b/my-machine"),
+			want:       ptr.To("us-central1-b"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := newOwnedMachine("my-machine", owner, tt.failureDomain)
+			gcpMachine := newGCPMachine("my-machine", tt.zone)
+			gcpMachine.Spec.ProviderID = tt.providerID
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				machine,
+				gcpMachine,
+				// Siblings in us-central1-a and us-central1-b, through a failure domain or a selected zone.
+				newOwnedMachine("sibling-0", owner, "us-central1-a"),
+				newOwnedMachine("sibling-1", owner, ""),
+				newGCPMachine("sibling-1", ptr.To("us-central1-b")),
+				// Machines of another owner don't count.
+				newOwnedMachine("other-0", otherOwner, "us-central1-c"),
+				newOwnedMachine("other-1", otherOwner, "us-central1-c"),
+			).Build()
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:        c,
+				Machine:       machine,
+				GCPMachine:    gcpMachine,
+				ClusterGetter: clusterScope,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			reconciler := &GCPMachineReconciler{Client: c}
+			g.Expect(reconciler.reconcileZone(context.TODO(), machineScope)).To(Succeed())
+			g.Expect(gcpMachine.Spec.Zone).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/compute/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/gcperrors"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-gcp/util/reconciler"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// machineTypeCacheTTL is how long the machine types are cached, they practically never change.
+	machineTypeCacheTTL = time.Hour
+	// gpuResourceName is the resource name of the NVIDIA GPUs advertised by the NVIDIA device plugin.
+	gpuResourceName corev1.ResourceName = "nvidia.com/gpu"
+	// templateCapacityRetryInterval is how long to wait for the failure domains of the GCPCluster, needed to look
+	// up the machine type, before retrying.
+	templateCapacityRetryInterval = 30 * time.Second
+)
+
+// machineTypeCache caches the machine types per project and zone, all the templates of a cluster usually sharing a
+// handful of machine types.
+var machineTypeCache = cache.NewLRUExpireCache(256)
+
+// customMachineTypeRegexp matches the custom machine types, e.g. custom-4-16384 or n2-custom-8-32768-ext, capturing
+// their number of vCPUs and memory in MiB.
+var customMachineTypeRegexp = regexp.MustCompile(`^(?:[a-z0-9]+-)?custom-(\d+)-(\d+)(?:-ext)?$`)
+
+// machineTypeGetter gets a machine type from the machineTypes API.
+type machineTypeGetter func(ctx context.Context, project, zone, name string) (*compute.MachineType, error)
+
+// GCPMachineTemplateReconciler reconciles a GCPMachineTemplate object.
+type GCPMachineTemplateReconciler struct {
+	client.Client
+	ReconcileTimeout time.Duration
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=gcpmachinetemplates,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=gcpmachinetemplates/status,verbs=get;update;patch
+
+func (r *GCPMachineTemplateReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	log := log.FromContext(ctx).WithValues("controller", "GCPMachineTemplate")
+
+	_, err := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.GCPMachineTemplate{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), log, r.WatchFilterValue)).
+		Build(r)
+	if err != nil {
+		return errors.Wrap(err, "error creating controller")
+	}
+
+	return nil
+}
+
+// Reconcile populates the capacity of the GCPMachineTemplate status from its machine type and accelerators, so that
+// the cluster-autoscaler can scale node groups using the template from zero.
+func (r *GCPMachineTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultedLoopTimeout(r.ReconcileTimeout))
+	defer cancel()
+
+	log := log.FromContext(ctx)
+	gcpMachineTemplate := &infrav1.GCPMachineTemplate{}
+	if err := r.Get(ctx, req.NamespacedName, gcpMachineTemplate); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !gcpMachineTemplate.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// The templates of a ClusterClass are owned by the Cluster, the others are expected to have the cluster name label.
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, gcpMachineTemplate.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if cluster == nil {
+		if _, ok := gcpMachineTemplate.Labels[clusterv1.ClusterNameLabel]; !ok {
+			log.V(4).Info("GCPMachineTemplate has no owner Cluster nor cluster name label, not populating its capacity")
+			return ctrl.Result{}, nil
+		}
+		cluster, err = util.GetClusterFromMetadata(ctx, r.Client, gcpMachineTemplate.ObjectMeta)
+		if err != nil {
+			log.Info("GCPMachineTemplate cluster does not exist yet")
+			return ctrl.Result{}, nil
+		}
+	}
+
+	if annotations.IsPaused(cluster, gcpMachineTemplate) {
+		log.Info("GCPMachineTemplate or linked Cluster is marked as paused. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	if !cluster.Spec.InfrastructureRef.IsDefined() || cluster.Spec.InfrastructureRef.Kind != "GCPCluster" {
+		// The templates of GKE clusters don't use the GCPCluster credentials and zones.
+		return ctrl.Result{}, nil
+	}
+	gcpCluster := &infrav1.GCPCluster{}
+	gcpClusterKey := client.ObjectKey{Namespace: gcpMachineTemplate.Namespace, Name: cluster.Spec.InfrastructureRef.Name}
+	if err := r.Get(ctx, gcpClusterKey, gcpCluster); err != nil {
+		log.Info("GCPCluster is not available yet")
+		return ctrl.Result{}, nil
+	}
+
+	zones := templateZones(gcpMachineTemplate, gcpCluster)
+	if len(zones) == 0 {
+		log.V(4).Info("GCPCluster has no failure domains yet, retrying")
+		return ctrl.Result{RequeueAfter: templateCapacityRetryInterval}, nil
+	}
+
+	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+		Client:     r.Client,
+		Cluster:    cluster,
+		GCPCluster: gcpCluster,
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	get := func(ctx context.Context, project, zone, name string) (*compute.MachineType, error) {
+		return clusterScope.ComputeService().MachineTypes.Get(project, zone, name).Context(ctx).Do()
+	}
+	spec := gcpMachineTemplate.Spec.Template.Spec
+	machineType, err := lookupMachineType(ctx, get, clusterScope.Project(), zones, spec.InstanceType)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to get machine type %s", spec.InstanceType)
+	}
+
+	helper, err := patch.NewHelper(gcpMachineTemplate, r.Client)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to init patch helper")
+	}
+	gcpMachineTemplate.Status.Capacity = machineTypeCapacity(machineType, spec.GuestAccelerators)
+
+	return ctrl.Result{}, helper.Patch(ctx, gcpMachineTemplate)
+}
+
+// templateZones returns the zones to look up the machine type of the template in: its zone when set, otherwise the
+// failure domains of the GCPCluster, as the machine type may not be available in all of them.
+func templateZones(gcpMachineTemplate *infrav1.GCPMachineTemplate, gcpCluster *infrav1.GCPCluster) []string {
+	if zone := ptr.Deref(gcpMachineTemplate.Spec.Template.Spec.Zone, ""); zone != "" {
+		return []string{zone}
+	}
+
+	zones := make([]string, 0, len(gcpCluster.Status.FailureDomains))
+	for zone := range gcpCluster.Status.FailureDomains {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+// lookupMachineType returns the machine type from the first zone it is available in. The custom machine types are
+// parsed from their name rather than looked up.
+func lookupMachineType(ctx context.Context, get machineTypeGetter, project string, zones []string, name string) (*compute.MachineType, error) {
+	if machineType, ok := parseCustomMachineType(name); ok {
+		return machineType, nil
+	}
+
+	var err error
+	for _, zone := range zones {
+		key := path.Join(project, zone, name)
+		if machineType, ok := machineTypeCache.Get(key); ok {
+			return machineType.(*compute.MachineType), nil
+		}
+
+		var machineType *compute.MachineType
+		machineType, err = get(ctx, project, zone, name)
+		if err != nil {
+			if gcperrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		machineTypeCache.Add(key, machineType, machineTypeCacheTTL)
+		return machineType, nil
+	}
+	return nil, err
+}
+
+// parseCustomMachineType returns the vCPUs and memory of a custom machine type from its name.
+func parseCustomMachineType(name string) (*compute.MachineType, bool) {
+	matches := customMachineTypeRegexp.FindStringSubmatch(name)
+	if matches == nil {
+		return nil, false
+	}
+
+	cpus, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	memory, err := strconv.ParseInt(matches[2], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	return &compute.MachineType{Name: name, GuestCpus: cpus, MemoryMb: memory}, true
+}
+
+// machineTypeCapacity returns the capacity of the machines with the machine type and the accelerators, counting both
+// the GPUs bundled with accelerator-optimized machine types and the ones attached to the machine.
+func machineTypeCapacity(machineType *compute.MachineType, accelerators []infrav1.Accelerator) corev1.ResourceList {
+	capacity := corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewQuantity(machineType.GuestCpus, resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(machineType.MemoryMb*1024*1024, resource.BinarySI),
+	}
+
+	var gpus int64
+	for _, accelerator := range machineType.Accelerators {
+		if isNvidiaGPU(accelerator.GuestAcceleratorType) {
+			gpus += accelerator.GuestAcceleratorCount
+		}
+	}
+	for _, accelerator := range accelerators {
+		if isNvidiaGPU(accelerator.Type) {
+			gpus += accelerator.Count
+		}
+	}
+	if gpus > 0 {
+		capacity[gpuResourceName] = *resource.NewQuantity(gpus, resource.DecimalSI)
+	}
+	return capacity
+}
+
+// isNvidiaGPU reports whether the accelerator type, either a name or a URL, is an NVIDIA GPU.
+func isNvidiaGPU(acceleratorType string) bool {
+	return strings.HasPrefix(path.Base(acceleratorType), "nvidia-")
+}
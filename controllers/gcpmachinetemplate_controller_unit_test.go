@@ -0,0 +1,142 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+)
+
+func TestParseCustomMachineType(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantCPUs   int64
+		wantMemory int64
+		wantOK     bool
+	}{
+		{name: "custom-4-16384", wantCPUs: 4, wantMemory: 16384, wantOK: true},
+		{name: "n2-custom-8-32768", wantCPUs: 8, wantMemory: 32768, wantOK: true},
+		{name: "n2d-custom-2-65536-ext", wantCPUs: 2, wantMemory: 65536, wantOK: true},
+		{name: "e2-custom-medium-4096", wantOK: false},
+		{name: "n2-standard-4", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineType, ok := parseCustomMachineType(tt.name)
+			g.Expect(ok).To(Equal(tt.wantOK))
+			if tt.wantOK {
+				g.Expect(machineType.GuestCpus).To(Equal(tt.wantCPUs))
+				g.Expect(machineType.MemoryMb).To(Equal(tt.wantMemory))
+			}
+		})
+	}
+}
+
+func TestMachineTypeCapacity(t *testing.T) {
+	tests := []struct {
+		name         string
+		machineType  *compute.MachineType
+		accelerators []infrav1.Accelerator
+		want         corev1.ResourceList
+	}{
+		{
+			name:        "general purpose machine type",
+			machineType: &compute.MachineType{GuestCpus: 4, MemoryMb: 16384},
+			want: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+		},
+		{
+			name:        "machine type with attached GPUs",
+			machineType: &compute.MachineType{GuestCpus: 8, MemoryMb: 30720},
+			accelerators: []infrav1.Accelerator{
+				{Type: "projects/my-proj/zones/us-central1-c/acceleratorTypes/nvidia-tesla-t4", Count: 2},
+			},
+			want: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("30Gi"),
+				gpuResourceName:       resource.MustParse("2"),
+			},
+		},
+		{
+			name: "accelerator-optimized machine type",
+			machineType: &compute.MachineType{GuestCpus: 12, MemoryMb: 87040, Accelerators: []*compute.MachineTypeAccelerators{
+				{GuestAcceleratorType: "nvidia-tesla-a100", GuestAcceleratorCount: 1},
+			}},
+			want: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("12"),
+				corev1.ResourceMemory: resource.MustParse("85Gi"),
+				gpuResourceName:       resource.MustParse("1"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got := machineTypeCapacity(tt.machineType, tt.accelerators)
+			g.Expect(got).To(HaveLen(len(tt.want)))
+			for name, quantity := range tt.want {
+				gotQuantity := got[name]
+				g.Expect(gotQuantity.Cmp(quantity)).To(BeZero(), "resource %s = %s, want %s", name, gotQuantity.String(), quantity.String())
+			}
+		})
+	}
+}
+
+func TestLookupMachineType(t *testing.T) {
+	g := NewWithT(t)
+	calls := map[string]int{}
+	get := func(_ context.Context, _, zone, name string) (*compute.MachineType, error) {
+		calls[zone]++
+		if zone == "us-central1-a" {
+			return nil, &googleapi.Error{Code: http.StatusNotFound}
+		}
+		return &compute.MachineType{Name: name, GuestCpus: 4, MemoryMb: 16384}, nil
+	}
+	zones := []string{"us-central1-a", "us-central1-b"}
+
+	// The machine type isn't available in the first zone, it is looked up in the next one.
+	machineType, err := lookupMachineType(context.TODO(), get, "lookup-proj", zones, "n2-standard-4")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(machineType.GuestCpus).To(Equal(int64(4)))
+	g.Expect(calls).To(Equal(map[string]int{"us-central1-a": 1, "us-central1-b": 1}))
+
+	// The machine type is cached.
+	_, err = lookupMachineType(context.TODO(), get, "lookup-proj", []string{"us-central1-b"}, "n2-standard-4")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(calls["us-central1-b"]).To(Equal(1))
+
+	// The custom machine types aren't looked up.
+	machineType, err = lookupMachineType(context.TODO(), get, "lookup-proj", zones, "n2-custom-2-8192")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(machineType.MemoryMb).To(Equal(int64(8192)))
+	g.Expect(calls["us-central1-b"]).To(Equal(1))
+
+	// The machine type isn't available in any zone.
+	_, err = lookupMachineType(context.TODO(), get, "lookup-proj", []string{"us-central1-a"}, "n2-standard-8")
+	g.Expect(err).To(HaveOccurred())
+}
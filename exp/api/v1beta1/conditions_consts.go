@@ -83,9 +83,18 @@ const (
 	MIGProvisionFailedReason = "ManagedInstanceGroupProvisionFailed"
 	// MIGDeletionInProgress MIG is in a deletion in progress state.
 	MIGDeletionInProgress = "ManagedInstanceGroupDeletionInProgress"
+	// MIGNotStableReason used when the managed instance group is creating, replacing or deleting instances, e.g.
+	// while rolling out an instance template change.
+	MIGNotStableReason = "ManagedInstanceGroupNotStable"
 
 	// InstanceTemplateReadyCondition represents the status of an AWSMachinePool's associated Launch Template.
 	InstanceTemplateReadyCondition clusterv1.ConditionType = "InstanceTemplateReady"
 	// InstanceTemplateReconcileFailedReason used for failures during Launch Template reconciliation.
 	InstanceTemplateReconcileFailedReason = "InstanceTemplateReconcileFailed"
+
+	// AutoscalerReadyCondition reports on the status of the GCE autoscaler of the managed instance group, when the
+	// GCPMachinePool enables autoscaling.
+	AutoscalerReadyCondition clusterv1.ConditionType = "AutoscalerReady"
+	// AutoscalerReconcileFailedReason used for failures during the autoscaler reconciliation.
+	AutoscalerReconcileFailedReason = "AutoscalerReconcileFailed"
 )
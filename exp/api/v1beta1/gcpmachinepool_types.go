@@ -19,6 +19,7 @@ package v1beta1
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	capg "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -156,6 +157,121 @@ type GCPMachinePoolSpec struct {
 	// attached to the instance.
 	// +optional
 	GuestAccelerators []capg.Accelerator `json:"guestAccelerators,omitempty"`
+
+	// RollingUpdate configures how the managed instance group replaces its instances when the spec changes, i.e. when
+	// a new instance template is created. Defaults to creating one instance per zone above the target size before
+	// deleting the old ones, and no unavailable instance.
+	// +optional
+	RollingUpdate *MachinePoolRollingUpdate `json:"rollingUpdate,omitempty"`
+
+	// Autoscaling configures a GCE autoscaler sizing the managed instance group from the CPU utilization of its
+	// instances. When set, the replicas of the MachinePool reflect the size of the group instead of setting it.
+	// +optional
+	Autoscaling *MachinePoolAutoscaling `json:"autoscaling,omitempty"`
+
+	// DistributionPolicy sets the zones of a regional managed instance group, and how its instances are distributed
+	// across them. When set, the managed instance group is regional, even with a single zone. Its zones can't be
+	// changed once set, as GCE can't change the zones of an existing regional managed instance group. Defaults to the
+	// failure domains of the MachinePool, evenly distributed.
+	// +optional
+	DistributionPolicy *MachinePoolDistributionPolicy `json:"distributionPolicy,omitempty"`
+
+	// StatefulDisks are the additional disks, by device name, that the managed instance group preserves when it
+	// recreates their instance, e.g. to roll out a spec change. The instances are then recreated in place rather than
+	// replaced by new ones, and the managed instance group can't be autoscaled.
+	// +listType=map
+	// +listMapKey=deviceName
+	// +optional
+	StatefulDisks []MachinePoolStatefulDisk `json:"statefulDisks,omitempty"`
+}
+
+// StatefulDiskDeletionPolicy is what happens to a stateful disk when its instance is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain
+type StatefulDiskDeletionPolicy string
+
+const (
+	// StatefulDiskDeletionPolicyDelete deletes the disk along with its instance.
+	StatefulDiskDeletionPolicyDelete StatefulDiskDeletionPolicy = "Delete"
+	// StatefulDiskDeletionPolicyRetain keeps the disk once its instance is deleted.
+	StatefulDiskDeletionPolicyRetain StatefulDiskDeletionPolicy = "Retain"
+)
+
+// MachinePoolStatefulDisk configures an additional disk preserved by the managed instance group.
+type MachinePoolStatefulDisk struct {
+	// DeviceName is the device name of the disk, which must be the deviceName of one of the additionalDisks.
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=32
+	DeviceName string `json:"deviceName"`
+
+	// DeletionPolicy is whether the disk is deleted or retained when its instance is permanently deleted, i.e. when
+	// the pool is scaled down or deleted. Defaults to Delete.
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy StatefulDiskDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// DistributionTargetShape is how a regional managed instance group distributes its instances across its zones.
+// +kubebuilder:validation:Enum=EVEN;BALANCED;ANY_SINGLE_ZONE
+type DistributionTargetShape string
+
+const (
+	// DistributionTargetShapeEven distributes the instances evenly across the zones.
+	DistributionTargetShapeEven DistributionTargetShape = "EVEN"
+	// DistributionTargetShapeBalanced prefers the zones with available resources, while keeping the instances as
+	// evenly distributed as possible.
+	DistributionTargetShapeBalanced DistributionTargetShape = "BALANCED"
+	// DistributionTargetShapeAnySingleZone creates all the instances in the single zone with the most available
+	// resources.
+	DistributionTargetShapeAnySingleZone DistributionTargetShape = "ANY_SINGLE_ZONE"
+)
+
+// MachinePoolDistributionPolicy configures the zones of a regional managed instance group.
+type MachinePoolDistributionPolicy struct {
+	// Zones are the zones of the managed instance group, in the region of the cluster, e.g. us-central1-a.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Zones []string `json:"zones"`
+
+	// TargetShape is how the instances are distributed across the zones. Proactive instance redistribution is
+	// disabled for the BALANCED and ANY_SINGLE_ZONE shapes, as GCE requires. Defaults to EVEN.
+	// +kubebuilder:default=EVEN
+	// +optional
+	TargetShape DistributionTargetShape `json:"targetShape,omitempty"`
+}
+
+// MachinePoolAutoscaling configures the GCE autoscaler of a managed instance group.
+type MachinePoolAutoscaling struct {
+	// MinReplicas is the minimum number of instances of the managed instance group.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the maximum number of instances of the managed instance group, it must not be less than
+	// MinReplicas.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// CPUTarget is the average CPU utilization of the instances, in percent, that the autoscaler maintains.
+	// Defaults to 60.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	CPUTarget *int32 `json:"cpuTarget,omitempty"`
+}
+
+// MachinePoolRollingUpdate configures the rolling update of the instances of a managed instance group.
+type MachinePoolRollingUpdate struct {
+	// MaxSurge is the maximum number of instances, or percentage of the target size, that can be created above the
+	// target size during the update. For a managed instance group spanning several zones, a fixed value must be 0 or
+	// at least the number of zones.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// MaxUnavailable is the maximum number of instances, or percentage of the target size, that can be unavailable
+	// during the update. For a managed instance group spanning several zones, a fixed value must be 0 or at least the
+	// number of zones.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
 // GCPMachinePoolStatus defines the observed state of GCPMachinePool.
@@ -168,6 +284,11 @@ type GCPMachinePoolStatus struct {
 	// +optional
 	Replicas int32 `json:"replicas"`
 
+	// InfrastructureMachineKind is the kind of the infrastructure machines representing the instances of the
+	// managed instance group, so that the MachinePool controller creates a Machine for each of them.
+	// +optional
+	InfrastructureMachineKind string `json:"infrastructureMachineKind,omitempty"`
+
 	// Conditions defines current service state of the GCPMachinePool.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
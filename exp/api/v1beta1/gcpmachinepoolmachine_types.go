@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capg "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+)
+
+const (
+	// MachinePoolMachineFinalizer allows the GCPMachinePool controller to delete the instance of a
+	// GCPMachinePoolMachine from the managed instance group before removing it from the API server.
+	MachinePoolMachineFinalizer = "gcpmachinepoolmachine.infrastructure.cluster.x-k8s.io"
+
+	// GCPMachinePoolMachineKind is the kind of the infrastructure machines of the GCPMachinePools.
+	GCPMachinePoolMachineKind = "GCPMachinePoolMachine"
+)
+
+// GCPMachinePoolMachineSpec defines the desired state of GCPMachinePoolMachine.
+type GCPMachinePoolMachineSpec struct {
+	// ProviderID is the unique identifier of the instance of the managed instance group, as provided by the
+	// cloud provider.
+	// +optional
+	ProviderID string `json:"providerID,omitempty"`
+}
+
+// GCPMachinePoolMachineStatus defines the observed state of GCPMachinePoolMachine.
+type GCPMachinePoolMachineStatus struct {
+	// Ready is true when the instance is running.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// InstanceStatus is the status of the GCP instance for this machine.
+	// +optional
+	InstanceStatus *capg.InstanceStatus `json:"instanceState,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=gcpmachinepoolmachines,scope=Namespaced,categories=cluster-api,shortName=gcpmpm
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this GCPMachinePoolMachine belongs"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.instanceState",description="GCE instance state"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Machine ready status"
+// +kubebuilder:printcolumn:name="InstanceID",type="string",JSONPath=".spec.providerID",description="GCE instance ID"
+// +kubebuilder:printcolumn:name="Machine",type="string",JSONPath=".metadata.ownerReferences[?(@.kind==\"Machine\")].name",description="Machine object which owns with this GCPMachinePoolMachine"
+
+// GCPMachinePoolMachine is the Schema for the gcpmachinepoolmachines API, representing an instance of the managed
+// instance group of a GCPMachinePool.
+type GCPMachinePoolMachine struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GCPMachinePoolMachineSpec   `json:"spec,omitempty"`
+	Status GCPMachinePoolMachineStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+
+// GCPMachinePoolMachineList contains a list of GCPMachinePoolMachine.
+type GCPMachinePoolMachineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GCPMachinePoolMachine `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GCPMachinePoolMachine{}, &GCPMachinePoolMachineList{})
+}
@@ -23,6 +23,7 @@ package v1beta1
 import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	apiv1beta1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 	corev1beta1 "sigs.k8s.io/cluster-api/api/core/v1beta1"
 )
@@ -186,6 +187,100 @@ func (in *GCPMachinePoolList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPMachinePoolMachine) DeepCopyInto(out *GCPMachinePoolMachine) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPMachinePoolMachine.
+func (in *GCPMachinePoolMachine) DeepCopy() *GCPMachinePoolMachine {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPMachinePoolMachine)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GCPMachinePoolMachine) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPMachinePoolMachineList) DeepCopyInto(out *GCPMachinePoolMachineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GCPMachinePoolMachine, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPMachinePoolMachineList.
+func (in *GCPMachinePoolMachineList) DeepCopy() *GCPMachinePoolMachineList {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPMachinePoolMachineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GCPMachinePoolMachineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPMachinePoolMachineSpec) DeepCopyInto(out *GCPMachinePoolMachineSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPMachinePoolMachineSpec.
+func (in *GCPMachinePoolMachineSpec) DeepCopy() *GCPMachinePoolMachineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPMachinePoolMachineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPMachinePoolMachineStatus) DeepCopyInto(out *GCPMachinePoolMachineStatus) {
+	*out = *in
+	if in.InstanceStatus != nil {
+		in, out := &in.InstanceStatus, &out.InstanceStatus
+		*out = new(apiv1beta1.InstanceStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPMachinePoolMachineStatus.
+func (in *GCPMachinePoolMachineStatus) DeepCopy() *GCPMachinePoolMachineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPMachinePoolMachineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GCPMachinePoolSpec) DeepCopyInto(out *GCPMachinePoolSpec) {
 	*out = *in
@@ -290,6 +385,26 @@ func (in *GCPMachinePoolSpec) DeepCopyInto(out *GCPMachinePoolSpec) {
 		*out = make([]apiv1beta1.Accelerator, len(*in))
 		copy(*out, *in)
 	}
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(MachinePoolRollingUpdate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(MachinePoolAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DistributionPolicy != nil {
+		in, out := &in.DistributionPolicy, &out.DistributionPolicy
+		*out = new(MachinePoolDistributionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatefulDisks != nil {
+		in, out := &in.StatefulDisks, &out.StatefulDisks
+		*out = make([]MachinePoolStatefulDisk, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPMachinePoolSpec.
@@ -1201,6 +1316,86 @@ func (in *LinuxNodeConfig) DeepCopy() *LinuxNodeConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolAutoscaling) DeepCopyInto(out *MachinePoolAutoscaling) {
+	*out = *in
+	if in.CPUTarget != nil {
+		in, out := &in.CPUTarget, &out.CPUTarget
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolAutoscaling.
+func (in *MachinePoolAutoscaling) DeepCopy() *MachinePoolAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolDistributionPolicy) DeepCopyInto(out *MachinePoolDistributionPolicy) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolDistributionPolicy.
+func (in *MachinePoolDistributionPolicy) DeepCopy() *MachinePoolDistributionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolDistributionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolRollingUpdate) DeepCopyInto(out *MachinePoolRollingUpdate) {
+	*out = *in
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolRollingUpdate.
+func (in *MachinePoolRollingUpdate) DeepCopy() *MachinePoolRollingUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolRollingUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolStatefulDisk) DeepCopyInto(out *MachinePoolStatefulDisk) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolStatefulDisk.
+func (in *MachinePoolStatefulDisk) DeepCopy() *MachinePoolStatefulDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolStatefulDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MasterAuthorizedNetworksConfig) DeepCopyInto(out *MasterAuthorizedNetworksConfig) {
 	*out = *in
@@ -20,15 +20,18 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -42,10 +45,12 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/labels/format"
 	"sigs.k8s.io/cluster-api/util/predicates"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/compute/autoscalers"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/compute/instancegroupmanagers"
 	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/compute/instancetemplates"
 	expinfrav1 "sigs.k8s.io/cluster-api-provider-gcp/exp/api/v1beta1"
@@ -63,6 +68,9 @@ type GCPMachinePoolReconciler struct {
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=gcpmachinepools,verbs=get;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=gcpmachinepools/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=gcpmachinepoolmachines,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=gcpmachinepoolmachines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;watch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
 
@@ -130,7 +138,7 @@ func (r *GCPMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		// Compute the Ready condition from the other conditions
 		if err := conditions.SetSummaryCondition(machinePoolScope.GCPMachinePool, machinePoolScope.GCPMachinePool,
 			expinfrav1.ReadyCondition,
-			conditions.ForConditionTypes([]string{string(expinfrav1.MIGReadyCondition), string(expinfrav1.InstanceTemplateReadyCondition)}),
+			conditions.ForConditionTypes(machinePoolReadyConditions(machinePoolScope)),
 		); err != nil && reterr == nil {
 			reterr = err
 		}
@@ -155,6 +163,10 @@ func (r *GCPMachinePoolReconciler) SetupWithManager(ctx context.Context, mgr ctr
 			&clusterv1.MachinePool{},
 			handler.EnqueueRequestsFromMapFunc(machinePoolToInfrastructureMapFunc(expinfrav1.GroupVersion.WithKind("GCPMachinePool"))),
 		).
+		Watches(
+			&expinfrav1.GCPMachinePoolMachine{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &expinfrav1.GCPMachinePool{}),
+		).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(mgr.GetScheme(), log.FromContext(ctx), r.WatchFilterValue)).
 		WithEventFilter(
 			predicate.Funcs{
@@ -239,37 +251,56 @@ func (r *GCPMachinePoolReconciler) reconcile(ctx context.Context, machinePoolSco
 	}
 
 	// set the MIGReadyCondition condition
-	conditions.Set(machinePoolScope.GCPMachinePool, metav1.Condition{
-		Type:   string(expinfrav1.MIGReadyCondition),
-		Status: metav1.ConditionTrue,
-	})
+	if igm.Status != nil && !igm.Status.IsStable {
+		conditions.Set(machinePoolScope.GCPMachinePool, metav1.Condition{
+			Type:    string(expinfrav1.MIGReadyCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  expinfrav1.MIGNotStableReason,
+			Message: migCurrentActionsMessage(igm.CurrentActions),
+		})
+	} else {
+		conditions.Set(machinePoolScope.GCPMachinePool, metav1.Condition{
+			Type:   string(expinfrav1.MIGReadyCondition),
+			Status: metav1.ConditionTrue,
+		})
+	}
 
-	igmInstances, err := instancegroupmanagers.New(machinePoolScope).ListInstances(ctx, igm)
+	if err := r.reconcileAutoscaler(ctx, machinePoolScope, igm); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	igmInstances, err := instancegroupmanagers.New(machinePoolScope).ListManagedInstances(ctx)
 	if err != nil {
 		log.Error(err, "Error listing instances in instanceGroupManager")
 		return ctrl.Result{}, err
 	}
 
-	providerIDList := make([]string, len(igmInstances))
-
-	for i, instance := range igmInstances {
-		var providerID string
+	providerIDList := make([]string, 0, len(igmInstances))
+	instancesByProviderID := make(map[string]*compute.ManagedInstance, len(igmInstances))
 
-		// Convert instance URL to providerID format
-		u := instance.Instance
-		u = strings.TrimPrefix(u, "https://www.googleapis.com/compute/v1/")
-		tokens := strings.Split(u, "/")
-		if len(tokens) == 6 && tokens[0] == "projects" && tokens[2] == "zones" && tokens[4] == "instances" {
-			providerID = fmt.Sprintf("gce://%s/%s/%s", tokens[1], tokens[3], tokens[5])
-		} else {
-			return ctrl.Result{}, fmt.Errorf("unexpected instance URL format: %s", instance.Instance)
+	for _, instance := range igmInstances {
+		if instance.InstanceStatus == "" {
+			// The instanceGroupManager hasn't created the instance yet.
+			continue
+		}
+		providerID, err := instanceProviderID(instance.Instance)
+		if err != nil {
+			return ctrl.Result{}, err
 		}
 
-		providerIDList[i] = providerID
+		providerIDList = append(providerIDList, providerID)
+		instancesByProviderID[providerID] = instance
 	}
 
 	machinePoolScope.GCPMachinePool.Spec.ProviderIDList = providerIDList
 	machinePoolScope.GCPMachinePool.Status.Replicas = int32(len(providerIDList))
+
+	if err := r.reconcileMachines(ctx, machinePoolScope.MachinePool, machinePoolScope.GCPMachinePool, instancesByProviderID, instancegroupmanagers.New(machinePoolScope)); err != nil {
+		log.Error(err, "Error reconciling GCPMachinePoolMachines")
+		return ctrl.Result{}, err
+	}
+
+	machinePoolScope.GCPMachinePool.Status.InfrastructureMachineKind = expinfrav1.GCPMachinePoolMachineKind
 	machinePoolScope.GCPMachinePool.Status.Ready = true
 
 	// Requeue so that we can keep the spec.providerIDList and status in sync with the MIG.
@@ -278,11 +309,288 @@ func (r *GCPMachinePoolReconciler) reconcile(ctx context.Context, machinePoolSco
 	return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
 }
 
+// instanceProviderID converts the URL of an instance of the managed instance group to its providerID.
+func instanceProviderID(instanceURL string) (string, error) {
+	u := strings.TrimPrefix(instanceURL, "https://www.googleapis.com/compute/v1/")
+	tokens := strings.Split(u, "/")
+	if len(tokens) == 6 && tokens[0] == "projects" && tokens[2] == "zones" && tokens[4] == "instances" {
+		return fmt.Sprintf("gce://%s/%s/%s", tokens[1], tokens[3], tokens[5]), nil
+	}
+	return "", fmt.Errorf("unexpected instance URL format: %s", instanceURL)
+}
+
+// instanceDeleter deletes instances from the managed instance group.
+type instanceDeleter interface {
+	DeleteInstances(ctx context.Context, instances []string) error
+}
+
+// reconcileMachines keeps a GCPMachinePoolMachine for every instance of the managed instance group, for which the
+// MachinePool controller creates a Machine.
+//
+// The instances are only deleted from the managed instance group when their Machine is deleted, e.g. when remediated
+// by a MachineHealthCheck. The group then replaces them, as it is resized back to the replicas of the MachinePool.
+// The instances the group recreates keep their name, and so their GCPMachinePoolMachine, while the ones it deletes
+// or substitutes by itself, e.g. when scaling in or rolling out a new instance template, have their Machine deleted
+// without deleting anything from the group.
+func (r *GCPMachinePoolReconciler) reconcileMachines(ctx context.Context, machinePool *clusterv1.MachinePool, gcpMachinePool *expinfrav1.GCPMachinePool,
+	instances map[string]*compute.ManagedInstance, deleter instanceDeleter,
+) error {
+	log := log.FromContext(ctx)
+
+	machines, err := r.listMachinePoolMachines(ctx, machinePool)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool, len(machines))
+	var deletedInstances []string
+	for i := range machines {
+		machine := &machines[i]
+		existing[machine.Spec.ProviderID] = true
+		instance, inGroup := instances[machine.Spec.ProviderID]
+
+		switch {
+		case !machine.DeletionTimestamp.IsZero() && inGroup:
+			// The finalizer is kept until the instance leaves the group, so that no GCPMachinePoolMachine is created
+			// for it while it is being deleted.
+			if !isLeavingGroup(instance) {
+				deletedInstances = append(deletedInstances, instance.Instance)
+			}
+		case !machine.DeletionTimestamp.IsZero():
+			if err := r.removeMachineFinalizer(ctx, machine); err != nil {
+				return err
+			}
+		case !inGroup:
+			if err := r.deleteOrphanedMachine(ctx, machine); err != nil {
+				return err
+			}
+		default:
+			if err := r.updateMachineStatus(ctx, machine, instance); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(deletedInstances) > 0 {
+		// The instances which left the group since they were listed are skipped.
+		if err := deleter.DeleteInstances(ctx, deletedInstances); err != nil {
+			r.Recorder.Eventf(gcpMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to delete instances: %v", err)
+			return err
+		}
+	}
+
+	for providerID, instance := range instances {
+		// The instances the group deletes by itself, e.g. when scaling in, don't need a GCPMachinePoolMachine.
+		if existing[providerID] || isLeavingGroup(instance) {
+			continue
+		}
+
+		machine := &expinfrav1.GCPMachinePoolMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      path.Base(instance.Instance),
+				Namespace: gcpMachinePool.Namespace,
+				Labels: map[string]string{
+					clusterv1.MachinePoolNameLabel: format.MustFormatValue(machinePool.Name),
+					clusterv1.ClusterNameLabel:     machinePool.Spec.ClusterName,
+				},
+				// The Machine controller sets itself as the controller of the GCPMachinePoolMachine.
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: expinfrav1.GroupVersion.String(),
+					Kind:       "GCPMachinePool",
+					Name:       gcpMachinePool.Name,
+					UID:        gcpMachinePool.UID,
+				}},
+				Finalizers: []string{expinfrav1.MachinePoolMachineFinalizer},
+			},
+			Spec: expinfrav1.GCPMachinePoolMachineSpec{
+				ProviderID: providerID,
+			},
+		}
+		if watchFilter, ok := gcpMachinePool.Labels[clusterv1.WatchLabel]; ok {
+			machine.Labels[clusterv1.WatchLabel] = watchFilter
+		}
+
+		log.V(2).Info("Creating GCPMachinePoolMachine for instance", "instance", instance.Instance)
+		if err := r.Client.Create(ctx, machine); err != nil {
+			return fmt.Errorf("creating GCPMachinePoolMachine for instance %s: %w", instance.Instance, err)
+		}
+		if err := r.updateMachineStatus(ctx, machine, instance); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isLeavingGroup reports whether the managed instance group is already deleting or abandoning the instance.
+func isLeavingGroup(instance *compute.ManagedInstance) bool {
+	return instance.CurrentAction == "DELETING" || instance.CurrentAction == "ABANDONING"
+}
+
+// listMachinePoolMachines lists the GCPMachinePoolMachines of the MachinePool.
+func (r *GCPMachinePoolReconciler) listMachinePoolMachines(ctx context.Context, machinePool *clusterv1.MachinePool) ([]expinfrav1.GCPMachinePoolMachine, error) {
+	machines := &expinfrav1.GCPMachinePoolMachineList{}
+	if err := r.Client.List(ctx, machines, client.InNamespace(machinePool.Namespace), client.MatchingLabels{
+		clusterv1.MachinePoolNameLabel: format.MustFormatValue(machinePool.Name),
+		clusterv1.ClusterNameLabel:     machinePool.Spec.ClusterName,
+	}); err != nil {
+		return nil, fmt.Errorf("listing GCPMachinePoolMachines: %w", err)
+	}
+	return machines.Items, nil
+}
+
+// deleteOrphanedMachine deletes the Machine of a GCPMachinePoolMachine whose instance isn't in the managed instance
+// group anymore, or the GCPMachinePoolMachine itself when the MachinePool controller hasn't created its Machine yet.
+func (r *GCPMachinePoolReconciler) deleteOrphanedMachine(ctx context.Context, machine *expinfrav1.GCPMachinePoolMachine) error {
+	log := log.FromContext(ctx).WithValues("gcpMachinePoolMachine", klog.KObj(machine))
+
+	owner, err := util.GetOwnerMachine(ctx, r.Client, machine.ObjectMeta)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("getting Machine of GCPMachinePoolMachine %s: %w", machine.Name, err)
+	}
+	if owner != nil {
+		if !owner.DeletionTimestamp.IsZero() {
+			return nil
+		}
+		log.Info("Deleting Machine of an instance which is no longer in the instanceGroupManager", "machine", klog.KObj(owner))
+		if err := r.Client.Delete(ctx, owner); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("deleting Machine %s: %w", owner.Name, err)
+		}
+		return nil
+	}
+
+	log.Info("Deleting GCPMachinePoolMachine of an instance which is no longer in the instanceGroupManager")
+	if err := r.removeMachineFinalizer(ctx, machine); err != nil {
+		return err
+	}
+	if err := r.Client.Delete(ctx, machine); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("deleting GCPMachinePoolMachine %s: %w", machine.Name, err)
+	}
+	return nil
+}
+
+// removeMachineFinalizer removes the finalizer of the GCPMachinePoolMachine, once its instance was deleted.
+func (r *GCPMachinePoolReconciler) removeMachineFinalizer(ctx context.Context, machine *expinfrav1.GCPMachinePoolMachine) error {
+	original := machine.DeepCopy()
+	if !controllerutil.RemoveFinalizer(machine, expinfrav1.MachinePoolMachineFinalizer) {
+		return nil
+	}
+	if err := r.Client.Patch(ctx, machine, client.MergeFrom(original)); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("removing finalizer of GCPMachinePoolMachine %s: %w", machine.Name, err)
+	}
+	return nil
+}
+
+// updateMachineStatus reflects the status of the instance in the GCPMachinePoolMachine.
+func (r *GCPMachinePoolReconciler) updateMachineStatus(ctx context.Context, machine *expinfrav1.GCPMachinePoolMachine, instance *compute.ManagedInstance) error {
+	original := machine.DeepCopy()
+	instanceStatus := infrav1.InstanceStatus(instance.InstanceStatus)
+	machine.Status.InstanceStatus = &instanceStatus
+	machine.Status.Ready = instanceStatus == infrav1.InstanceStatusRunning
+	if cmp.Equal(original.Status, machine.Status) {
+		return nil
+	}
+	if err := r.Client.Status().Patch(ctx, machine, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("updating status of GCPMachinePoolMachine %s: %w", machine.Name, err)
+	}
+	return nil
+}
+
+// deleteMachinePoolMachines deletes the GCPMachinePoolMachines of the MachinePool, once its managed instance group
+// was deleted along with all their instances.
+func (r *GCPMachinePoolReconciler) deleteMachinePoolMachines(ctx context.Context, machinePool *clusterv1.MachinePool) error {
+	machines, err := r.listMachinePoolMachines(ctx, machinePool)
+	if err != nil {
+		return err
+	}
+	for i := range machines {
+		machine := &machines[i]
+		if err := r.removeMachineFinalizer(ctx, machine); err != nil {
+			return err
+		}
+		if machine.DeletionTimestamp.IsZero() {
+			if err := r.Client.Delete(ctx, machine); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("deleting GCPMachinePoolMachine %s: %w", machine.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// machinePoolReadyConditions returns the conditions summarized in the Ready condition of the GCPMachinePool.
+func machinePoolReadyConditions(machinePoolScope *scope.MachinePoolScope) []string {
+	readyConditions := []string{string(expinfrav1.MIGReadyCondition), string(expinfrav1.InstanceTemplateReadyCondition)}
+	if machinePoolScope.ReplicasManagedByAutoscaler() {
+		readyConditions = append(readyConditions, string(expinfrav1.AutoscalerReadyCondition))
+	}
+	return readyConditions
+}
+
+// reconcileAutoscaler reconciles the autoscaler of the managed instance group, and reflects the size it sets in the
+// replicas of the MachinePool.
+func (r *GCPMachinePoolReconciler) reconcileAutoscaler(ctx context.Context, machinePoolScope *scope.MachinePoolScope, igm *compute.InstanceGroupManager) error {
+	log := log.FromContext(ctx)
+
+	if err := autoscalers.New(machinePoolScope).Reconcile(ctx, igm); err != nil {
+		log.Error(err, "Error reconciling autoscaler")
+		conditions.Set(machinePoolScope.GCPMachinePool, metav1.Condition{
+			Type:    string(expinfrav1.AutoscalerReadyCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  expinfrav1.AutoscalerReconcileFailedReason,
+			Message: fmt.Sprintf("Error reconciling autoscaler: %v", err),
+		})
+		return err
+	}
+
+	if !machinePoolScope.ReplicasManagedByAutoscaler() {
+		conditions.Delete(machinePoolScope.GCPMachinePool, string(expinfrav1.AutoscalerReadyCondition))
+		return nil
+	}
+	conditions.Set(machinePoolScope.GCPMachinePool, metav1.Condition{
+		Type:   string(expinfrav1.AutoscalerReadyCondition),
+		Status: metav1.ConditionTrue,
+	})
+
+	replicas := int32(igm.TargetSize) //nolint:gosec // The size of a managed instance group fits in an int32.
+	if ptr.Deref(machinePoolScope.MachinePool.Spec.Replicas, 0) != replicas {
+		log.V(2).Info("Reflecting the size of the autoscaled instanceGroupManager in the MachinePool replicas", "replicas", replicas)
+		machinePoolScope.MachinePool.Spec.Replicas = &replicas
+		if err := machinePoolScope.PatchCAPIMachinePoolObject(ctx); err != nil {
+			return fmt.Errorf("updating MachinePool replicas: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// migCurrentActionsMessage describes the actions the managed instance group is performing on its instances.
+func migCurrentActionsMessage(actions *compute.InstanceGroupManagerActionsSummary) string {
+	if actions == nil {
+		return "Managed instance group is not stable"
+	}
+	return fmt.Sprintf("Managed instance group is not stable: creating %d, recreating %d, deleting %d, verifying %d instances",
+		actions.Creating+actions.CreatingWithoutRetries, actions.Recreating, actions.Deleting, actions.Verifying)
+}
+
 func (r *GCPMachinePoolReconciler) reconcileDelete(ctx context.Context, machinePoolScope *scope.MachinePoolScope) error {
 	log := log.FromContext(ctx)
 
 	log.Info("Handling deleted GCPMachinePool")
 
+	// The autoscaler must be deleted before the instanceGroupManager it scales.
+	if err := autoscalers.New(machinePoolScope).Delete(ctx); err != nil {
+		log.Error(err, "Error deleting autoscaler")
+		r.Recorder.Eventf(machinePoolScope.GCPMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to delete autoscaler: %v", err)
+
+		conditions.Set(machinePoolScope.GCPMachinePool, metav1.Condition{
+			Type:    string(expinfrav1.AutoscalerReadyCondition),
+			Status:  metav1.ConditionUnknown,
+			Reason:  expinfrav1.AutoscalerReconcileFailedReason,
+			Message: fmt.Sprintf("Error deleting autoscaler: %v", err),
+		})
+		return err
+	}
+
 	if err := instancegroupmanagers.New(machinePoolScope).Delete(ctx); err != nil {
 		log.Error(err, "Error deleting instanceGroupManager")
 		r.Recorder.Eventf(machinePoolScope.GCPMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to delete instancegroupmanager: %v", err)
@@ -296,6 +604,11 @@ func (r *GCPMachinePoolReconciler) reconcileDelete(ctx context.Context, machineP
 		return err
 	}
 
+	if err := r.deleteMachinePoolMachines(ctx, machinePoolScope.MachinePool); err != nil {
+		log.Error(err, "Error deleting GCPMachinePoolMachines")
+		return err
+	}
+
 	if err := instancetemplates.New(machinePoolScope).Delete(ctx); err != nil {
 		log.Error(err, "Error deleting instanceTemplates")
 		r.Recorder.Eventf(machinePoolScope.GCPMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to delete instance template: %v", err)
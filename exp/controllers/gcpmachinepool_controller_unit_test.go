@@ -0,0 +1,161 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/compute/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-gcp/exp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeInstanceDeleter records the instances deleted from the managed instance group.
+type fakeInstanceDeleter struct {
+	deleted []string
+}
+
+func (f *fakeInstanceDeleter) DeleteInstances(_ context.Context, instances []string) error {
+	f.deleted = append(f.deleted, instances...)
+	return nil
+}
+
+func newMachinePoolMachine(name string, owners ...metav1.OwnerReference) *expinfrav1.GCPMachinePoolMachine {
+	return &expinfrav1.GCPMachinePoolMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				clusterv1.MachinePoolNameLabel: "my-pool",
+				clusterv1.ClusterNameLabel:     "my-cluster",
+			},
+			OwnerReferences: owners,
+			Finalizers:      []string{expinfrav1.MachinePoolMachineFinalizer},
+		},
+		Spec: expinfrav1.GCPMachinePoolMachineSpec{
+			ProviderID: "gce://my-proj/us-central1-a/" + name,
+		},
+	}
+}
+
+func newInstance(name, status string) *compute.ManagedInstance {
+	return &compute.ManagedInstance{
+		Instance:       "https://www.googleapis.com/compute/v1/projects/my-proj/zones/us-central1-a/instances/" + name,
+		InstanceStatus: status,
+		CurrentAction:  "NONE",
+	}
+}
+
+func TestInstanceProviderID(t *testing.T) {
+	g := NewWithT(t)
+
+	providerID, err := instanceProviderID("https://www.googleapis.com/compute/v1/projects/my-proj/zones/us-central1-a/instances/my-pool-abcd")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(providerID).To(Equal("gce://my-proj/us-central1-a/my-pool-abcd"))
+
+	_, err = instanceProviderID("projects/my-proj/zones/us-central1-a/instanceGroups/my-pool")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestGCPMachinePoolReconciler_reconcileMachines(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+
+	scheme := runtime.NewScheme()
+	g.Expect(expinfrav1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+	machinePool := &clusterv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-pool", Namespace: "default"},
+		Spec:       clusterv1.MachinePoolSpec{ClusterName: "my-cluster"},
+	}
+	gcpMachinePool := &expinfrav1.GCPMachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-pool", Namespace: "default", UID: "pool-uid"},
+	}
+	// The instance substituted by the managed instance group has a Machine.
+	substituted := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "my-pool-subs", Namespace: "default", UID: "machine-uid"}}
+	// The instance removed by the managed instance group before the MachinePool controller created its Machine.
+	removed := newMachinePoolMachine("my-pool-gone")
+	// The Machine of the instance is being deleted, e.g. remediated by a MachineHealthCheck.
+	remediated := newMachinePoolMachine("my-pool-rmdt")
+	remediated.DeletionTimestamp = ptr.To(metav1.Now())
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(substituted, removed, remediated,
+			newMachinePoolMachine("my-pool-subs", metav1.OwnerReference{
+				APIVersion: clusterv1.GroupVersion.String(), Kind: "Machine", Name: substituted.Name, UID: substituted.UID,
+			})).
+		WithStatusSubresource(&expinfrav1.GCPMachinePoolMachine{}).
+		Build()
+	r := &GCPMachinePoolReconciler{Client: c, Recorder: record.NewFakeRecorder(10)}
+	deleter := &fakeInstanceDeleter{}
+
+	// The instance the managed instance group deletes by itself when scaling in.
+	scaledIn := newInstance("my-pool-scin", "STOPPING")
+	scaledIn.CurrentAction = "DELETING"
+
+	instances := map[string]*compute.ManagedInstance{
+		"gce://my-proj/us-central1-a/my-pool-abcd": newInstance("my-pool-abcd", "RUNNING"),
+		"gce://my-proj/us-central1-a/my-pool-efgh": newInstance("my-pool-efgh", "STAGING"),
+		"gce://my-proj/us-central1-a/my-pool-rmdt": newInstance("my-pool-rmdt", "RUNNING"),
+		"gce://my-proj/us-central1-a/my-pool-scin": scaledIn,
+	}
+	g.Expect(r.reconcileMachines(ctx, machinePool, gcpMachinePool, instances, deleter)).To(Succeed())
+
+	// A GCPMachinePoolMachine is created for every new instance.
+	machine := &expinfrav1.GCPMachinePoolMachine{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "my-pool-abcd"}, machine)).To(Succeed())
+	g.Expect(machine.Spec.ProviderID).To(Equal("gce://my-proj/us-central1-a/my-pool-abcd"))
+	g.Expect(machine.Labels).To(HaveKeyWithValue(clusterv1.MachinePoolNameLabel, "my-pool"))
+	g.Expect(machine.OwnerReferences).To(ConsistOf(HaveField("UID", gcpMachinePool.UID)))
+	g.Expect(machine.Finalizers).To(ConsistOf(expinfrav1.MachinePoolMachineFinalizer))
+	g.Expect(machine.Status.Ready).To(BeTrue())
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "my-pool-efgh"}, machine)).To(Succeed())
+	g.Expect(machine.Status.Ready).To(BeFalse())
+	g.Expect(*machine.Status.InstanceStatus).To(Equal(infrav1.InstanceStatusStaging))
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "my-pool-scin"}, machine))).To(BeTrue())
+
+	// The machines of the instances which left the group are deleted, without deleting anything from the group.
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(substituted), &clusterv1.Machine{}))).To(BeTrue())
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(removed), machine))).To(BeTrue())
+
+	// The instance of the deleted machine is deleted from the group, its machine being kept until it left the group.
+	g.Expect(deleter.deleted).To(ConsistOf(instances["gce://my-proj/us-central1-a/my-pool-rmdt"].Instance))
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(remediated), machine)).To(Succeed())
+
+	// The instance isn't deleted again while the group is deleting it.
+	instances["gce://my-proj/us-central1-a/my-pool-rmdt"].CurrentAction = "DELETING"
+	g.Expect(r.reconcileMachines(ctx, machinePool, gcpMachinePool, instances, deleter)).To(Succeed())
+	g.Expect(deleter.deleted).To(HaveLen(1))
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(remediated), machine)).To(Succeed())
+
+	delete(instances, "gce://my-proj/us-central1-a/my-pool-rmdt")
+	g.Expect(r.reconcileMachines(ctx, machinePool, gcpMachinePool, instances, deleter)).To(Succeed())
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(remediated), machine))).To(BeTrue())
+	g.Expect(deleter.deleted).To(HaveLen(1))
+}
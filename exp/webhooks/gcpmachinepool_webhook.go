@@ -19,9 +19,16 @@ package webhooks
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 	expinfrav1 "sigs.k8s.io/cluster-api-provider-gcp/exp/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -43,7 +50,7 @@ func (r *GCPMachinePool) SetupWebhookWithManager(mgr ctrl.Manager) error {
 // GCPMachinePool implements a validating webhook for GCPMachinePool.
 type GCPMachinePool struct{}
 
-//+kubebuilder:webhook:verbs=update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-gcpmachinepool,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=gcpmachinepools,versions=v1beta1,name=validation.gcpmachinepool.infrastructure.cluster.x-k8s.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-gcpmachinepool,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=gcpmachinepools,versions=v1beta1,name=validation.gcpmachinepool.infrastructure.cluster.x-k8s.io,admissionReviewVersions=v1
 
 var _ webhook.CustomValidator = &GCPMachinePool{}
 
@@ -56,23 +63,32 @@ func (*GCPMachinePool) ValidateCreate(_ context.Context, obj runtime.Object) (ad
 
 	gcpMachinePoolLog.Info("Validating GCPMachinePool create", "name", r.Name)
 
-	// Add custom validation logic upon creation if needed.
-
-	return nil, nil
+	return nil, validateGCPMachinePool(r)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (*GCPMachinePool) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+func (*GCPMachinePool) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	r, ok := newObj.(*expinfrav1.GCPMachinePool)
 	if !ok {
 		return nil, fmt.Errorf("expected a GCPMachinePool object but got %T", r)
 	}
+	old, ok := oldObj.(*expinfrav1.GCPMachinePool)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a GCPMachinePool but got a %T", oldObj))
+	}
 
 	gcpMachinePoolLog.Info("Validating GCPMachinePool update", "name", r.Name)
 
-	// Add custom validation logic upon update if needed.
+	if err := validateGCPMachinePool(r); err != nil {
+		return nil, err
+	}
 
-	return nil, nil
+	allErrs := validateDistributionPolicyUpdate(old.Spec.DistributionPolicy, r.Spec.DistributionPolicy, field.NewPath("spec", "distributionPolicy"))
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+
+	return nil, apierrors.NewInvalid(expinfrav1.GroupVersion.WithKind("GCPMachinePool").GroupKind(), r.Name, allErrs)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -88,3 +104,163 @@ func (*GCPMachinePool) ValidateDelete(_ context.Context, obj runtime.Object) (ad
 
 	return nil, nil
 }
+
+// validateGCPMachinePool validates the fields of the GCPMachinePool the CRD schema can't.
+func validateGCPMachinePool(r *expinfrav1.GCPMachinePool) error {
+	allErrs := validateRollingUpdate(r.Spec.RollingUpdate, len(r.Spec.StatefulDisks) > 0, field.NewPath("spec", "rollingUpdate"))
+	allErrs = append(allErrs, validateAutoscaling(r.Spec.Autoscaling, field.NewPath("spec", "autoscaling"))...)
+	allErrs = append(allErrs, validateDistributionPolicy(r.Spec.DistributionPolicy, field.NewPath("spec", "distributionPolicy"))...)
+	allErrs = append(allErrs, validateStatefulDisks(r.Spec, field.NewPath("spec", "statefulDisks"))...)
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(expinfrav1.GroupVersion.WithKind("GCPMachinePool").GroupKind(), r.Name, allErrs)
+}
+
+// validateAutoscaling validates that the autoscaler can scale the instances between minReplicas and maxReplicas.
+func validateAutoscaling(autoscaling *expinfrav1.MachinePoolAutoscaling, fldPath *field.Path) field.ErrorList {
+	if autoscaling == nil || autoscaling.MaxReplicas >= autoscaling.MinReplicas {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(fldPath.Child("maxReplicas"), autoscaling.MaxReplicas, "must be greater than or equal to minReplicas"),
+	}
+}
+
+// validateDistributionPolicy validates that the zones are distinct zones of a single region. The controller checks that
+// it is the region of the cluster.
+func validateDistributionPolicy(distributionPolicy *expinfrav1.MachinePoolDistributionPolicy, fldPath *field.Path) field.ErrorList {
+	if distributionPolicy == nil {
+		return nil
+	}
+
+	zonesPath := fldPath.Child("zones")
+	if len(distributionPolicy.Zones) == 0 {
+		return field.ErrorList{field.Required(zonesPath, "at least one zone is required")}
+	}
+
+	var allErrs field.ErrorList
+	seen := map[string]bool{}
+	region := zoneRegion(distributionPolicy.Zones[0])
+	for i, zone := range distributionPolicy.Zones {
+		switch {
+		case strings.Contains(zone, "/"):
+			allErrs = append(allErrs, field.Invalid(zonesPath.Index(i), zone, "must be a zone name, e.g. us-central1-a"))
+		case seen[zone]:
+			allErrs = append(allErrs, field.Duplicate(zonesPath.Index(i), zone))
+		case zoneRegion(zone) != region:
+			allErrs = append(allErrs, field.Invalid(zonesPath.Index(i), zone, fmt.Sprintf("must be in the region %s of the other zones", region)))
+		}
+		seen[zone] = true
+	}
+	return allErrs
+}
+
+// validateDistributionPolicyUpdate rejects the changes of the zones of the managed instance group, GCE not supporting
+// them. Adding or removing the distribution policy is rejected too, as it may change the zones, or whether the managed
+// instance group is zonal or regional.
+func validateDistributionPolicyUpdate(oldPolicy, newPolicy *expinfrav1.MachinePoolDistributionPolicy, fldPath *field.Path) field.ErrorList {
+	switch {
+	case oldPolicy == nil && newPolicy == nil:
+		return nil
+	case oldPolicy == nil || newPolicy == nil:
+		return field.ErrorList{field.Forbidden(fldPath, "can't be added or removed, as GCE can't change the zones of an existing managed instance group")}
+	case !sets.New(oldPolicy.Zones...).Equal(sets.New(newPolicy.Zones...)):
+		return field.ErrorList{field.Forbidden(fldPath.Child("zones"), "can't be changed, as GCE can't change the zones of an existing managed instance group")}
+	default:
+		return nil
+	}
+}
+
+// zoneRegion returns the region of a zone, e.g. us-central1 for us-central1-a.
+func zoneRegion(zone string) string {
+	if idx := strings.LastIndex(zone, "-"); idx > 0 {
+		return zone[:idx]
+	}
+	return zone
+}
+
+// validateStatefulDisks validates that the stateful disks are persistent additional disks, and that the managed
+// instance group isn't autoscaled, as GCE doesn't support autoscaling stateful managed instance groups.
+func validateStatefulDisks(spec expinfrav1.GCPMachinePoolSpec, fldPath *field.Path) field.ErrorList {
+	if len(spec.StatefulDisks) == 0 {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if spec.Autoscaling != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "can't be set along with autoscaling"))
+	}
+
+	additionalDisks := map[string]infrav1.AttachedDiskSpec{}
+	for _, disk := range spec.AdditionalDisks {
+		if disk.DeviceName != nil {
+			additionalDisks[*disk.DeviceName] = disk
+		}
+	}
+	for i, statefulDisk := range spec.StatefulDisks {
+		disk, ok := additionalDisks[statefulDisk.DeviceName]
+		switch {
+		case !ok:
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("deviceName"), statefulDisk.DeviceName, "must be the deviceName of one of the additionalDisks"))
+		case ptr.Deref(disk.DeviceType, "") == infrav1.LocalSsdDiskType:
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("deviceName"), statefulDisk.DeviceName, "local SSDs can't be stateful"))
+		}
+	}
+	return allErrs
+}
+
+// validateRollingUpdate validates that maxSurge and maxUnavailable are numbers or percentages, and that they don't
+// both prevent replacing the instances.
+func validateRollingUpdate(rollingUpdate *expinfrav1.MachinePoolRollingUpdate, stateful bool, fldPath *field.Path) field.ErrorList {
+	if rollingUpdate == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	maxSurge, surgeErr := validateFixedOrPercent(rollingUpdate.MaxSurge, fldPath.Child("maxSurge"))
+	if surgeErr != nil {
+		allErrs = append(allErrs, surgeErr)
+	}
+	maxUnavailable, unavailableErr := validateFixedOrPercent(rollingUpdate.MaxUnavailable, fldPath.Child("maxUnavailable"))
+	if unavailableErr != nil {
+		allErrs = append(allErrs, unavailableErr)
+	}
+	// maxSurge defaults to a non-zero value, and maxUnavailable to zero, the other way around with stateful disks.
+	surgeZero, unavailableZero := stateful, !stateful
+	if rollingUpdate.MaxSurge != nil {
+		surgeZero = surgeErr == nil && maxSurge == 0
+	}
+	if rollingUpdate.MaxUnavailable != nil {
+		unavailableZero = unavailableErr == nil && maxUnavailable == 0
+	}
+	if surgeZero && unavailableZero {
+		allErrs = append(allErrs, field.Invalid(fldPath, rollingUpdate, "maxSurge and maxUnavailable cannot both be 0"))
+	}
+	if stateful && rollingUpdate.MaxSurge != nil && surgeErr == nil && maxSurge != 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxSurge"), rollingUpdate.MaxSurge.String(), "must be 0 with statefulDisks, as the instances are recreated"))
+	}
+	return allErrs
+}
+
+// validateFixedOrPercent validates that the value is a non-negative number or a percentage, e.g. "20%", and returns
+// it.
+func validateFixedOrPercent(value *intstr.IntOrString, fldPath *field.Path) (int64, *field.Error) {
+	if value == nil {
+		return 0, nil
+	}
+	if value.Type == intstr.Int {
+		if value.IntVal < 0 {
+			return 0, field.Invalid(fldPath, value.IntVal, "must be greater or equal zero")
+		}
+		return int64(value.IntVal), nil
+	}
+
+	percent, err := strconv.ParseInt(strings.TrimSuffix(value.StrVal, "%"), 10, 64)
+	if err != nil || !strings.HasSuffix(value.StrVal, "%") || percent < 0 || percent > 100 {
+		return 0, field.Invalid(fldPath, value.StrVal, "must be a number or a percentage between 0% and 100%")
+	}
+	return percent, nil
+}
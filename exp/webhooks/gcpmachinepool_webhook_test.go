@@ -0,0 +1,260 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-gcp/exp/api/v1beta1"
+)
+
+func TestGCPMachinePoolValidatingWebhookCreate(t *testing.T) {
+	tests := []struct {
+		name          string
+		rollingUpdate *expinfrav1.MachinePoolRollingUpdate
+		autoscaling   *expinfrav1.MachinePoolAutoscaling
+		distribution  *expinfrav1.MachinePoolDistributionPolicy
+		statefulDisks []expinfrav1.MachinePoolStatefulDisk
+		expectError   bool
+	}{
+		{
+			name:        "default rolling update",
+			expectError: false,
+		},
+		{
+			name: "fixed and percent values",
+			rollingUpdate: &expinfrav1.MachinePoolRollingUpdate{
+				MaxSurge:       ptr.To(intstr.FromString("25%")),
+				MaxUnavailable: ptr.To(intstr.FromInt32(1)),
+			},
+			expectError: false,
+		},
+		{
+			name: "only maxUnavailable",
+			rollingUpdate: &expinfrav1.MachinePoolRollingUpdate{
+				MaxUnavailable: ptr.To(intstr.FromInt32(0)),
+			},
+			expectError: false,
+		},
+		{
+			name: "negative maxSurge",
+			rollingUpdate: &expinfrav1.MachinePoolRollingUpdate{
+				MaxSurge: ptr.To(intstr.FromInt32(-1)),
+			},
+			expectError: true,
+		},
+		{
+			name: "maxUnavailable is not a percentage",
+			rollingUpdate: &expinfrav1.MachinePoolRollingUpdate{
+				MaxUnavailable: ptr.To(intstr.FromString("25")),
+			},
+			expectError: true,
+		},
+		{
+			name: "maxUnavailable above 100%",
+			rollingUpdate: &expinfrav1.MachinePoolRollingUpdate{
+				MaxUnavailable: ptr.To(intstr.FromString("150%")),
+			},
+			expectError: true,
+		},
+		{
+			name: "maxSurge and maxUnavailable both 0",
+			rollingUpdate: &expinfrav1.MachinePoolRollingUpdate{
+				MaxSurge:       ptr.To(intstr.FromString("0%")),
+				MaxUnavailable: ptr.To(intstr.FromInt32(0)),
+			},
+			expectError: true,
+		},
+		{
+			name: "maxSurge 0 and maxUnavailable defaulted",
+			rollingUpdate: &expinfrav1.MachinePoolRollingUpdate{
+				MaxSurge: ptr.To(intstr.FromInt32(0)),
+			},
+			expectError: true,
+		},
+		{
+			name:        "autoscaling",
+			autoscaling: &expinfrav1.MachinePoolAutoscaling{MinReplicas: 1, MaxReplicas: 5, CPUTarget: ptr.To[int32](80)},
+			expectError: false,
+		},
+		{
+			name:        "autoscaling to a fixed number of replicas",
+			autoscaling: &expinfrav1.MachinePoolAutoscaling{MinReplicas: 3, MaxReplicas: 3},
+			expectError: false,
+		},
+		{
+			name:        "autoscaling maxReplicas below minReplicas",
+			autoscaling: &expinfrav1.MachinePoolAutoscaling{MinReplicas: 5, MaxReplicas: 2},
+			expectError: true,
+		},
+		{
+			name: "distribution policy",
+			distribution: &expinfrav1.MachinePoolDistributionPolicy{
+				Zones:       []string{"us-central1-a", "us-central1-b"},
+				TargetShape: expinfrav1.DistributionTargetShapeBalanced,
+			},
+			expectError: false,
+		},
+		{
+			name:         "distribution policy without zones",
+			distribution: &expinfrav1.MachinePoolDistributionPolicy{},
+			expectError:  true,
+		},
+		{
+			name:         "distribution policy with duplicate zones",
+			distribution: &expinfrav1.MachinePoolDistributionPolicy{Zones: []string{"us-central1-a", "us-central1-a"}},
+			expectError:  true,
+		},
+		{
+			name:         "distribution policy with zones in several regions",
+			distribution: &expinfrav1.MachinePoolDistributionPolicy{Zones: []string{"us-central1-a", "us-east1-b"}},
+			expectError:  true,
+		},
+		{
+			name:         "distribution policy with a zone link",
+			distribution: &expinfrav1.MachinePoolDistributionPolicy{Zones: []string{"zones/us-central1-a"}},
+			expectError:  true,
+		},
+		{
+			name:          "stateful disks",
+			statefulDisks: []expinfrav1.MachinePoolStatefulDisk{{DeviceName: "data", DeletionPolicy: expinfrav1.StatefulDiskDeletionPolicyRetain}},
+			expectError:   false,
+		},
+		{
+			name:          "stateful disks recreated one at a time",
+			rollingUpdate: &expinfrav1.MachinePoolRollingUpdate{MaxSurge: ptr.To(intstr.FromInt32(0)), MaxUnavailable: ptr.To(intstr.FromInt32(1))},
+			statefulDisks: []expinfrav1.MachinePoolStatefulDisk{{DeviceName: "data"}},
+			expectError:   false,
+		},
+		{
+			name:          "stateful disks with maxSurge",
+			rollingUpdate: &expinfrav1.MachinePoolRollingUpdate{MaxSurge: ptr.To(intstr.FromInt32(1))},
+			statefulDisks: []expinfrav1.MachinePoolStatefulDisk{{DeviceName: "data"}},
+			expectError:   true,
+		},
+		{
+			name:          "stateful disks with maxUnavailable 0",
+			rollingUpdate: &expinfrav1.MachinePoolRollingUpdate{MaxUnavailable: ptr.To(intstr.FromInt32(0))},
+			statefulDisks: []expinfrav1.MachinePoolStatefulDisk{{DeviceName: "data"}},
+			expectError:   true,
+		},
+		{
+			name:          "stateful disk not in the additional disks",
+			statefulDisks: []expinfrav1.MachinePoolStatefulDisk{{DeviceName: "logs"}},
+			expectError:   true,
+		},
+		{
+			name:          "stateful local SSD",
+			statefulDisks: []expinfrav1.MachinePoolStatefulDisk{{DeviceName: "scratch"}},
+			expectError:   true,
+		},
+		{
+			name:          "autoscaled stateful disks",
+			autoscaling:   &expinfrav1.MachinePoolAutoscaling{MinReplicas: 1, MaxReplicas: 5},
+			statefulDisks: []expinfrav1.MachinePoolStatefulDisk{{DeviceName: "data"}},
+			expectError:   true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mp := &expinfrav1.GCPMachinePool{
+				Spec: expinfrav1.GCPMachinePoolSpec{
+					InstanceType:       "n2-standard-4",
+					RollingUpdate:      tc.rollingUpdate,
+					Autoscaling:        tc.autoscaling,
+					DistributionPolicy: tc.distribution,
+					StatefulDisks:      tc.statefulDisks,
+					AdditionalDisks: []infrav1.AttachedDiskSpec{
+						{DeviceName: ptr.To("data")},
+						{DeviceName: ptr.To("scratch"), DeviceType: ptr.To(infrav1.LocalSsdDiskType)},
+					},
+				},
+			}
+			warn, err := (&GCPMachinePool{}).ValidateCreate(context.Background(), mp)
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			// Nothing emits warnings yet
+			g.Expect(warn).To(BeEmpty())
+		})
+	}
+}
+
+func TestGCPMachinePoolValidatingWebhookUpdate(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldPolicy   *expinfrav1.MachinePoolDistributionPolicy
+		newPolicy   *expinfrav1.MachinePoolDistributionPolicy
+		expectError bool
+	}{
+		{
+			name:        "no distribution policy",
+			expectError: false,
+		},
+		{
+			name:        "reordered zones and changed target shape",
+			oldPolicy:   &expinfrav1.MachinePoolDistributionPolicy{Zones: []string{"us-central1-a", "us-central1-b"}},
+			newPolicy:   &expinfrav1.MachinePoolDistributionPolicy{Zones: []string{"us-central1-b", "us-central1-a"}, TargetShape: expinfrav1.DistributionTargetShapeAnySingleZone},
+			expectError: false,
+		},
+		{
+			name:        "changed zones",
+			oldPolicy:   &expinfrav1.MachinePoolDistributionPolicy{Zones: []string{"us-central1-a", "us-central1-b"}},
+			newPolicy:   &expinfrav1.MachinePoolDistributionPolicy{Zones: []string{"us-central1-a", "us-central1-c"}},
+			expectError: true,
+		},
+		{
+			name:        "added distribution policy",
+			newPolicy:   &expinfrav1.MachinePoolDistributionPolicy{Zones: []string{"us-central1-a"}},
+			expectError: true,
+		},
+		{
+			name:        "removed distribution policy",
+			oldPolicy:   &expinfrav1.MachinePoolDistributionPolicy{Zones: []string{"us-central1-a"}},
+			expectError: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			oldMP := &expinfrav1.GCPMachinePool{
+				Spec: expinfrav1.GCPMachinePoolSpec{InstanceType: "n2-standard-4", DistributionPolicy: tc.oldPolicy},
+			}
+			newMP := &expinfrav1.GCPMachinePool{
+				Spec: expinfrav1.GCPMachinePoolSpec{InstanceType: "n2-standard-4", DistributionPolicy: tc.newPolicy},
+			}
+			warn, err := (&GCPMachinePool{}).ValidateUpdate(context.Background(), oldMP, newMP)
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			// Nothing emits warnings yet
+			g.Expect(warn).To(BeEmpty())
+		})
+	}
+}
@@ -35,6 +35,8 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	infrav1beta1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-gcp/cloud/services/shared"
 	"sigs.k8s.io/cluster-api-provider-gcp/controllers"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-gcp/exp/api/v1beta1"
 	gkebootstrapv1exp "sigs.k8s.io/cluster-api-provider-gcp/exp/bootstrap/gke/api/v1beta1"
@@ -90,6 +92,11 @@ var (
 	leaderElectionLeaseDuration time.Duration
 	leaderElectionRenewDeadline time.Duration
 	leaderElectionRetryPeriod   time.Duration
+	disableDriftRemediation     bool
+	operationPollInterval       time.Duration
+	operationPollMaxInterval    time.Duration
+	readCacheTTL                time.Duration
+	serviceEndpoints            infrav1beta1.ServiceEndpoints
 )
 
 // Add RBAC for the authorized diagnostics endpoint.
@@ -131,6 +138,16 @@ func main() {
 
 	ctrl.SetLogger(klog.Background())
 
+	if err := scope.SetOperationPollBackoff(operationPollInterval, operationPollMaxInterval); err != nil {
+		setupLog.Error(err, "Invalid operation poll flags")
+		os.Exit(1)
+	}
+	shared.SetReadCacheTTL(readCacheTTL)
+	if err := scope.SetDefaultServiceEndpoints(serviceEndpoints); err != nil {
+		setupLog.Error(err, "Invalid GCP service endpoint flags")
+		os.Exit(1)
+	}
+
 	setupLog.Info(fmt.Sprintf("feature gates: %+v\n", feature.Gates))
 
 	// Machine and cluster operations can create enough events to trigger the event recorder spam filter
@@ -196,9 +213,10 @@ func main() {
 
 func setupReconcilers(ctx context.Context, mgr ctrl.Manager) error {
 	if err := (&controllers.GCPMachineReconciler{
-		Client:           mgr.GetClient(),
-		ReconcileTimeout: reconcileTimeout,
-		WatchFilterValue: watchFilterValue,
+		Client:                  mgr.GetClient(),
+		ReconcileTimeout:        reconcileTimeout,
+		WatchFilterValue:        watchFilterValue,
+		DisableDriftRemediation: disableDriftRemediation,
 	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: gcpMachineConcurrency}); err != nil {
 		return fmt.Errorf("setting up GCPMachine controller: %w", err)
 	}
@@ -209,6 +227,13 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) error {
 	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: gcpClusterConcurrency}); err != nil {
 		return fmt.Errorf("setting up GCPCluster controller: %w", err)
 	}
+	if err := (&controllers.GCPMachineTemplateReconciler{
+		Client:           mgr.GetClient(),
+		ReconcileTimeout: reconcileTimeout,
+		WatchFilterValue: watchFilterValue,
+	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: gcpMachineConcurrency}); err != nil {
+		return fmt.Errorf("setting up GCPMachineTemplate controller: %w", err)
+	}
 
 	if feature.Gates.Enabled(capifeature.MachinePool) {
 		setupLog.Info("Enabling MachinePool reconcilers")
@@ -429,6 +454,60 @@ func initFlags(fs *pflag.FlagSet) {
 		"The maximum duration a reconcile loop can run (e.g. 90m)",
 	)
 
+	fs.BoolVar(&disableDriftRemediation,
+		"disable-drift-remediation",
+		false,
+		"Only report the drift of the GCE instance labels, metadata, network tags and deletion protection from the GCPMachine spec instead of converging them",
+	)
+
+	fs.DurationVar(&operationPollInterval,
+		"gcp-operation-poll-interval",
+		reconciler.DefaultOperationPollInterval,
+		"The interval before the first poll of a GCP operation, doubling with each poll (e.g. 1s)",
+	)
+
+	fs.DurationVar(&operationPollMaxInterval,
+		"gcp-operation-poll-max-interval",
+		reconciler.DefaultOperationPollMaxInterval,
+		"The maximum interval between the polls of a GCP operation (e.g. 30s)",
+	)
+
+	fs.DurationVar(&readCacheTTL,
+		"gcp-read-cache-ttl",
+		0,
+		"How long the networks, subnetworks and firewall rules read from the GCP API are cached, invalidated when the controller modifies them. 0 disables the cache (e.g. 30s)",
+	)
+
+	fs.StringVar(&serviceEndpoints.ComputeServiceEndpoint,
+		"gcp-compute-endpoint",
+		"",
+		"The base URL of the GCP compute API of the clusters not setting spec.serviceEndpoints.compute, e.g. a private endpoint or an API emulator (e.g. https://compute-example.p.googleapis.com/compute/v1/)",
+	)
+
+	fs.StringVar(&serviceEndpoints.ContainerServiceEndpoint,
+		"gcp-container-endpoint",
+		"",
+		"The host:port gRPC endpoint of the GCP container API of the clusters not setting spec.serviceEndpoints.container (e.g. container.example.com:443)",
+	)
+
+	fs.StringVar(&serviceEndpoints.IAMServiceEndpoint,
+		"gcp-iam-endpoint",
+		"",
+		"The base URL of the GCP IAM API of the clusters not setting spec.serviceEndpoints.iam",
+	)
+
+	fs.StringVar(&serviceEndpoints.ResourceManagerServiceEndpoint,
+		"gcp-resource-manager-endpoint",
+		"",
+		"The base URL of the GCP resource manager API of the clusters not setting spec.serviceEndpoints.resourceManager",
+	)
+
+	fs.StringVar(&serviceEndpoints.StorageServiceEndpoint,
+		"gcp-storage-endpoint",
+		"",
+		"The base URL of the GCP storage API of the clusters not setting spec.serviceEndpoints.storage",
+	)
+
 	flags.AddManagerOptions(fs, &managerOptions)
 
 	feature.MutableGates.AddFlag(fs)
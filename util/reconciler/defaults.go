@@ -28,6 +28,10 @@ const (
 	DefaultMappingTimeout = 60 * time.Second
 	// DefaultRetryTime is the default time to retry when certain conditions are not met.
 	DefaultRetryTime = 1 * time.Minute
+	// DefaultOperationPollInterval is the default interval before the first poll of a GCP operation.
+	DefaultOperationPollInterval = 1 * time.Second
+	// DefaultOperationPollMaxInterval is the default max interval between the polls of a GCP operation.
+	DefaultOperationPollMaxInterval = 30 * time.Second
 )
 
 // DefaultedLoopTimeout will default the timeout if it is zero valued.
@@ -17,13 +17,21 @@ limitations under the License.
 package webhooks
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"maps"
+	"net/netip"
 	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -34,6 +42,10 @@ import (
 // clusterlog is for logging in this package.
 var clusterlog = logf.Log.WithName("gcpcluster-resource")
 
+// GCE resource names, such as address or resource policy names, must start with a lowercase letter, end with a
+// lowercase letter or a number and can only contain lowercase letters, numbers and dashes, up to 63 characters.
+var resourceNameRegexp = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
 // SetupWebhookWithManager sets up and registers the webhook with the manager.
 func (c *GCPCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
@@ -60,8 +72,66 @@ func (*GCPCluster) Default(_ context.Context, _ runtime.Object) error {
 }
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
-func (*GCPCluster) ValidateCreate(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
-	return nil, nil
+func (*GCPCluster) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	c, ok := obj.(*infrav1.GCPCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected an GCPCluster object but got %T", c)
+	}
+
+	clusterlog.Info("validate create", "name", c.Name)
+
+	if err := validateLabels(c.Spec.AdditionalLabels); err != nil {
+		return nil, err
+	}
+	if err := validateNetworkTags(c.Spec.AdditionalNetworkTags); err != nil {
+		return nil, err
+	}
+	if err := validateNetworkMtu(c.Spec.Network); err != nil {
+		return nil, err
+	}
+	if err := validateInternalLoadBalancer(c.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateAPIServerAddress(c.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateFailureDomains(c.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateControlPlaneCompactPlacement(c.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateHealthCheck(c.Spec.LoadBalancer.HealthCheck); err != nil {
+		return nil, err
+	}
+	if err := validateFirewallRules(c.Spec.Network.Firewall.FirewallRules); err != nil {
+		return nil, err
+	}
+	if err := validateCloudNAT(c.Spec.Network); err != nil {
+		return nil, err
+	}
+	if err := validateNetworkPeerings(c.Spec.Network); err != nil {
+		return nil, err
+	}
+	if err := validateSubnetRanges(c.Spec.Network); err != nil {
+		return nil, err
+	}
+	if err := validateSubnetFlowLogs(c.Spec.Network); err != nil {
+		return nil, err
+	}
+	if err := validateSubnetStackType(c.Spec.Network); err != nil {
+		return nil, err
+	}
+	if err := validateImageLookupFormat(c.Spec.ImageLookupFormat); err != nil {
+		return nil, err
+	}
+	if err := validateNodeServiceAccount(c.Spec.NodeServiceAccount); err != nil {
+		return nil, err
+	}
+	if err := validateBootstrapDataStorage(c.Spec.BootstrapDataStorage); err != nil {
+		return nil, err
+	}
+	return nil, validateClusterDiskEncryptionKey(c.Spec)
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
@@ -96,13 +166,44 @@ func (*GCPCluster) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Obje
 		)
 	}
 
-	if !reflect.DeepEqual(c.Spec.LoadBalancer, old.Spec.LoadBalancer) {
+	if !reflect.DeepEqual(c.Spec.ControlPlaneCompactPlacement, old.Spec.ControlPlaneCompactPlacement) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "ControlPlaneCompactPlacement"),
+				c.Spec.ControlPlaneCompactPlacement, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(c.Spec.NodeServiceAccount, old.Spec.NodeServiceAccount) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "NodeServiceAccount"),
+				c.Spec.NodeServiceAccount, "field is immutable"),
+		)
+	}
+
+	// The health check parameters are reconciled, the rest of the load balancer is immutable.
+	newLoadBalancer, oldLoadBalancer := c.Spec.LoadBalancer, old.Spec.LoadBalancer
+	newLoadBalancer.HealthCheck, oldLoadBalancer.HealthCheck = nil, nil
+	if !reflect.DeepEqual(newLoadBalancer, oldLoadBalancer) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "LoadBalancer"),
 				c.Spec.LoadBalancer, "field is immutable"),
 		)
 	}
 
+	if err := validateFailureDomains(c.Spec); err != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "FailureDomains"),
+				c.Spec.FailureDomains, err.Error()),
+		)
+	}
+
+	if err := validateHealthCheck(c.Spec.LoadBalancer.HealthCheck); err != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "LoadBalancer", "HealthCheck"),
+				c.Spec.LoadBalancer.HealthCheck, err.Error()),
+		)
+	}
+
 	if c.Spec.Network.Mtu < int64(1300) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "Network", "Mtu"),
@@ -117,33 +218,87 @@ func (*GCPCluster) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Obje
 		)
 	}
 
-	for i, firewallRule := range c.Spec.Network.Firewall.FirewallRules {
-		for j, allowRule := range firewallRule.Allowed {
-			if allowRule.IPProtocol != infrav1.FirewallProtocolTCP && allowRule.IPProtocol != infrav1.FirewallProtocolUDP &&
-				len(allowRule.Ports) > 0 {
-				allErrs = append(allErrs,
-					field.Invalid(field.NewPath("spec", "Network", "Firewall",
-						fmt.Sprintf("FirewallRules[%d]", i), fmt.Sprintf("Allowed[%d]", j),
-					),
-						allowRule.Ports,
-						"field should not exist unless IPProtocol is TCP or UDP"),
-				)
-			}
-		}
-		for j, denyRule := range firewallRule.Denied {
-			if denyRule.IPProtocol != infrav1.FirewallProtocolTCP && denyRule.IPProtocol != infrav1.FirewallProtocolUDP &&
-				len(denyRule.Ports) > 0 {
-				allErrs = append(allErrs,
-					field.Invalid(field.NewPath("spec", "Network", "Firewall",
-						fmt.Sprintf("FirewallRules[%d]", i), fmt.Sprintf("Denied[%d]", j),
-					),
-						denyRule.Ports,
-						"field should not exist unless IPProtocol is TCP or UDP"),
-				)
-			}
+	// The labels accepted before they were validated are kept as is, otherwise the cluster couldn't be updated, nor
+	// deleted, anymore.
+	if !reflect.DeepEqual(c.Spec.AdditionalLabels, old.Spec.AdditionalLabels) {
+		if err := validateLabels(c.Spec.AdditionalLabels); err != nil {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "AdditionalLabels"),
+					c.Spec.AdditionalLabels, err.Error()),
+			)
 		}
 	}
 
+	if err := validateNetworkTags(c.Spec.AdditionalNetworkTags); err != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "AdditionalNetworkTags"),
+				c.Spec.AdditionalNetworkTags, err.Error()),
+		)
+	}
+
+	if err := validateCloudNAT(c.Spec.Network); err != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "Network", "CloudNAT"),
+				c.Spec.Network.CloudNAT, err.Error()),
+		)
+	}
+
+	if err := validateNetworkPeerings(c.Spec.Network); err != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "Network", "NetworkPeerings"),
+				c.Spec.Network.NetworkPeerings, err.Error()),
+		)
+	}
+
+	if err := validateSubnetRanges(c.Spec.Network); err != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "Network", "Subnets"),
+				c.Spec.Network.Subnets, err.Error()),
+		)
+	}
+
+	if err := validateSubnetFlowLogs(c.Spec.Network); err != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "Network", "Subnets"),
+				c.Spec.Network.Subnets, err.Error()),
+		)
+	}
+
+	if err := validateSubnetStackType(c.Spec.Network); err != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "Network", "Subnets"),
+				c.Spec.Network.Subnets, err.Error()),
+		)
+	}
+
+	if err := validateBootstrapDataStorage(c.Spec.BootstrapDataStorage); err != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "BootstrapDataStorage"),
+				c.Spec.BootstrapDataStorage, err.Error()),
+		)
+	}
+
+	if err := validateImageLookupFormat(c.Spec.ImageLookupFormat); err != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "ImageLookupFormat"),
+				c.Spec.ImageLookupFormat, err.Error()),
+		)
+	}
+
+	if err := validateClusterDiskEncryptionKey(c.Spec); err != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "DiskEncryptionKey"),
+				c.Spec.DiskEncryptionKey, err.Error()),
+		)
+	}
+
+	if err := validateFirewallRules(c.Spec.Network.Firewall.FirewallRules); err != nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "Network", "Firewall", "FirewallRules"),
+				c.Spec.Network.Firewall.FirewallRules, err.Error()),
+		)
+	}
+
 	if len(allErrs) == 0 {
 		return nil, nil
 	}
@@ -155,3 +310,295 @@ func (*GCPCluster) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Obje
 func (*GCPCluster) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
 	return nil, nil
 }
+
+func validateClusterDiskEncryptionKey(spec infrav1.GCPClusterSpec) error {
+	if spec.DiskEncryptionKey == nil {
+		return nil
+	}
+	if spec.DiskEncryptionKey.KeyType != infrav1.CustomerManagedKey {
+		return fmt.Errorf("DiskEncryptionKey KeyType must be %s", infrav1.CustomerManagedKey)
+	}
+	return checkKeyType(spec.DiskEncryptionKey)
+}
+
+// validateInternalLoadBalancer makes sure a subnet of the cluster region is available for the address of the
+// Internal Load Balancer, as Internal Load Balancers are regional.
+func validateInternalLoadBalancer(spec infrav1.GCPClusterSpec) error {
+	lbType := ptr.Deref(spec.LoadBalancer.LoadBalancerType, infrav1.External)
+	if lbType != infrav1.Internal && lbType != infrav1.InternalExternal {
+		return nil
+	}
+	subnet := ""
+	if spec.LoadBalancer.InternalLoadBalancer != nil {
+		subnet = ptr.Deref(spec.LoadBalancer.InternalLoadBalancer.Subnet, "")
+	}
+	for _, s := range spec.Network.Subnets {
+		if s.Region != "" && s.Region != spec.Region {
+			continue
+		}
+		if subnet == "" || strings.HasSuffix(s.Name, subnet) {
+			return nil
+		}
+	}
+	if subnet != "" {
+		return fmt.Errorf("InternalLoadBalancer subnet %s must be one of the network subnets in region %s", subnet, spec.Region)
+	}
+	return fmt.Errorf("LoadBalancerType %s requires a network subnet in region %s", lbType, spec.Region)
+}
+
+// validateAPIServerAddress makes sure the provided address is an address name or an IP address, and that an
+// External Load Balancer uses it.
+func validateAPIServerAddress(spec infrav1.GCPClusterSpec) error {
+	address := spec.LoadBalancer.APIServerAddress
+	if address == nil {
+		return nil
+	}
+	if lbType := ptr.Deref(spec.LoadBalancer.LoadBalancerType, infrav1.External); lbType == infrav1.Internal {
+		return fmt.Errorf("APIServerAddress can't be set with LoadBalancerType %s, use the InternalLoadBalancer IPAddress instead", lbType)
+	}
+	if _, err := netip.ParseAddr(*address); err != nil && !resourceNameRegexp.MatchString(*address) {
+		return fmt.Errorf("APIServerAddress %q must be an address name or an IP address", *address)
+	}
+	return nil
+}
+
+// validateHealthCheck makes sure probes time out before the next one is sent, as GCE requires.
+func validateHealthCheck(hc *infrav1.HealthCheck) error {
+	if hc == nil {
+		return nil
+	}
+	interval, timeout := ptr.Deref(hc.CheckIntervalSec, 10), ptr.Deref(hc.TimeoutSec, 5)
+	if timeout > interval {
+		return fmt.Errorf("HealthCheck TimeoutSec %d can't be greater than CheckIntervalSec %d", timeout, interval)
+	}
+	return nil
+}
+
+// validateFirewallRules makes sure the rules have unique names, ports are only set for protocols that support them
+// and are valid port numbers or ranges, and source tags are only used by ingress rules.
+func validateFirewallRules(rules []infrav1.FirewallRule) error {
+	names := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		// Rules without a name are named after their direction.
+		name := rule.Name
+		if name == "" {
+			name = strings.ToLower(string(cmp.Or(rule.Direction, infrav1.FirewallRuleDirectionIngress)))
+		}
+		if names[name] {
+			return fmt.Errorf("FirewallRules[%d] name %s is already used, rules without a name are named after their direction", i, name)
+		}
+		names[name] = true
+		if rule.Direction == infrav1.FirewallRuleDirectionEgress && len(rule.SourceTags) > 0 {
+			return fmt.Errorf("FirewallRules[%d] SourceTags can't be set for Egress rules", i)
+		}
+		if err := validateFirewallDescriptors(rule.Allowed); err != nil {
+			return fmt.Errorf("FirewallRules[%d] Allowed: %w", i, err)
+		}
+		if err := validateFirewallDescriptors(rule.Denied); err != nil {
+			return fmt.Errorf("FirewallRules[%d] Denied: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateFirewallDescriptors(descriptors []infrav1.FirewallDescriptor) error {
+	for _, descriptor := range descriptors {
+		if len(descriptor.Ports) == 0 {
+			continue
+		}
+		if descriptor.IPProtocol != infrav1.FirewallProtocolTCP && descriptor.IPProtocol != infrav1.FirewallProtocolUDP &&
+			descriptor.IPProtocol != infrav1.FirewallProtocolSCTP {
+			return fmt.Errorf("ports should not exist unless IPProtocol is TCP, UDP or SCTP, got %s", descriptor.IPProtocol)
+		}
+		for _, ports := range descriptor.Ports {
+			if err := validateFirewallPorts(ports); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateFirewallPorts makes sure ports is a port number or a range of port numbers, e.g. 80 or 30000-32767.
+func validateFirewallPorts(ports string) error {
+	first, last, isRange := strings.Cut(ports, "-")
+	start, err := strconv.Atoi(first)
+	if err != nil || start < 0 || start > 65535 {
+		return fmt.Errorf("port %q is not a valid port number or range", ports)
+	}
+	if !isRange {
+		return nil
+	}
+	end, err := strconv.Atoi(last)
+	if err != nil || end < start || end > 65535 {
+		return fmt.Errorf("port %q is not a valid port number or range", ports)
+	}
+	return nil
+}
+
+// validateFailureDomains makes sure the failure domains are distinct zones of the cluster region.
+func validateFailureDomains(spec infrav1.GCPClusterSpec) error {
+	for i, zone := range spec.FailureDomains {
+		if zoneRegion(zone) != spec.Region {
+			return fmt.Errorf("FailureDomains zone %s must be in region %s", zone, spec.Region)
+		}
+		if slices.Contains(spec.FailureDomains[:i], zone) {
+			return fmt.Errorf("FailureDomains zone %s is listed more than once", zone)
+		}
+	}
+	return nil
+}
+
+// validateControlPlaneCompactPlacement makes sure the control plane machines run in a single zone, as compact
+// placement policies can't place instances of different zones.
+func validateControlPlaneCompactPlacement(spec infrav1.GCPClusterSpec) error {
+	if !ptr.Deref(spec.ControlPlaneCompactPlacement, false) {
+		return nil
+	}
+	if len(spec.FailureDomains) != 1 {
+		return fmt.Errorf("ControlPlaneCompactPlacement requires FailureDomains to list exactly one zone, got %d", len(spec.FailureDomains))
+	}
+	return nil
+}
+
+// iamRoleRegexp matches the names of predefined and custom IAM roles.
+var iamRoleRegexp = regexp.MustCompile(`^(roles|(projects|organizations)/[^/]+/roles)/[a-zA-Z0-9_.]+$`)
+
+func validateNodeServiceAccount(nodeServiceAccount *infrav1.NodeServiceAccountSpec) error {
+	if nodeServiceAccount == nil {
+		return nil
+	}
+	for _, role := range nodeServiceAccount.AdditionalRoles {
+		if !iamRoleRegexp.MatchString(role) {
+			return fmt.Errorf("NodeServiceAccount role %s must be of the form roles/<role>, projects/<project>/roles/<role> or organizations/<organization>/roles/<role>", role)
+		}
+	}
+	return nil
+}
+
+// bucketNameRegexp matches the names of GCS buckets, dotted names can be up to 222 characters.
+// reference: https://cloud.google.com/storage/docs/buckets#naming
+var bucketNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{1,220}[a-z0-9]$`)
+
+func validateBootstrapDataStorage(storage *infrav1.BootstrapDataStorageSpec) error {
+	if storage == nil {
+		return nil
+	}
+	if storage.TTL != nil && storage.TTL.Duration <= 0 {
+		return fmt.Errorf("BootstrapDataStorage TTL must be positive, got %s", storage.TTL.Duration)
+	}
+	if storage.Type != infrav1.BootstrapDataStorageGCS {
+		if storage.Bucket != "" {
+			return fmt.Errorf("BootstrapDataStorage Bucket can only be set with the %s type", infrav1.BootstrapDataStorageGCS)
+		}
+		return nil
+	}
+	if !bucketNameRegexp.MatchString(storage.Bucket) {
+		return fmt.Errorf("BootstrapDataStorage Bucket %q must be a valid GCS bucket name with the %s type", storage.Bucket, infrav1.BootstrapDataStorageGCS)
+	}
+	return nil
+}
+
+func validateNetworkMtu(network infrav1.NetworkSpec) error {
+	// An unset MTU is defaulted to 1460.
+	if network.Mtu != 0 && (network.Mtu < 1300 || network.Mtu > 8896) {
+		return fmt.Errorf("network Mtu (%d) must be between 1300 and 8896", network.Mtu)
+	}
+	return nil
+}
+
+func validateCloudNAT(network infrav1.NetworkSpec) error {
+	if network.CloudNAT == nil || network.CloudNAT.MaxPortsPerVM == nil {
+		return nil
+	}
+	// Dynamic port allocation requires the port counts to be powers of 2.
+	minPorts, maxPorts := network.MinPortsPerVM, *network.CloudNAT.MaxPortsPerVM
+	if minPorts <= 0 || minPorts&(minPorts-1) != 0 || maxPorts&(maxPorts-1) != 0 {
+		return fmt.Errorf("MinPortsPerVM (%d) and CloudNAT MaxPortsPerVM (%d) must be powers of 2", minPorts, maxPorts)
+	}
+	if maxPorts <= minPorts {
+		return fmt.Errorf("CloudNAT MaxPortsPerVM (%d) must be greater than MinPortsPerVM (%d)", maxPorts, minPorts)
+	}
+	return nil
+}
+
+func validateNetworkPeerings(network infrav1.NetworkSpec) error {
+	names := make(map[string]bool, len(network.NetworkPeerings))
+	for _, peering := range network.NetworkPeerings {
+		if names[peering.Name] {
+			return fmt.Errorf("NetworkPeerings name %s is used by more than one peering", peering.Name)
+		}
+		names[peering.Name] = true
+	}
+	return nil
+}
+
+func validateSubnetFlowLogs(network infrav1.NetworkSpec) error {
+	for _, subnet := range network.Subnets {
+		if subnet.FlowLogs == nil || subnet.FlowLogs.FlowSampling == nil {
+			continue
+		}
+		sampling, err := strconv.ParseFloat(*subnet.FlowLogs.FlowSampling, 64)
+		if err != nil || sampling < 0 || sampling > 1 {
+			return fmt.Errorf("subnet %s FlowLogs FlowSampling %s must be a number between 0 and 1", subnet.Name, *subnet.FlowLogs.FlowSampling)
+		}
+	}
+	return nil
+}
+
+// validateSubnetStackType makes sure IPv6 subnets have an IPv6 access type and a purpose supporting IPv6.
+func validateSubnetStackType(network infrav1.NetworkSpec) error {
+	for _, subnet := range network.Subnets {
+		ipv6 := subnet.StackType == string(infrav1.StackTypeIPv4IPv6) || subnet.StackType == string(infrav1.StackTypeIPv6Only)
+		if !ipv6 {
+			if subnet.Ipv6AccessType != nil {
+				return fmt.Errorf("subnet %s Ipv6AccessType requires the IPV4_IPV6 or IPV6_ONLY StackType", subnet.Name)
+			}
+			continue
+		}
+		if subnet.Ipv6AccessType == nil {
+			return fmt.Errorf("subnet %s Ipv6AccessType is required with the %s StackType", subnet.Name, subnet.StackType)
+		}
+		if purpose := ptr.Deref(subnet.Purpose, "PRIVATE_RFC_1918"); purpose != "PRIVATE_RFC_1918" && purpose != "PRIVATE" {
+			return fmt.Errorf("subnet %s with the %s Purpose does not support the %s StackType", subnet.Name, purpose, subnet.StackType)
+		}
+	}
+	return nil
+}
+
+// validateSubnetRanges makes sure the primary and secondary ranges of the subnets are valid IPv4 CIDR ranges
+// which don't overlap, as the ranges of all the subnets of a network must be unique.
+func validateSubnetRanges(network infrav1.NetworkSpec) error {
+	type subnetRange struct {
+		name   string
+		prefix netip.Prefix
+	}
+	ranges := []subnetRange{}
+	add := func(name, cidr string) error {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil || !prefix.Addr().Is4() {
+			return fmt.Errorf("%s range %s is not a valid IPv4 CIDR range", name, cidr)
+		}
+		for _, r := range ranges {
+			if r.prefix.Overlaps(prefix) {
+				return fmt.Errorf("%s range %s overlaps with %s range %s", name, cidr, r.name, r.prefix)
+			}
+		}
+		ranges = append(ranges, subnetRange{name: name, prefix: prefix.Masked()})
+		return nil
+	}
+
+	for _, subnet := range network.Subnets {
+		if subnet.CidrBlock != "" {
+			if err := add(fmt.Sprintf("Subnets %s primary", subnet.Name), subnet.CidrBlock); err != nil {
+				return err
+			}
+		}
+		for _, rangeName := range slices.Sorted(maps.Keys(subnet.SecondaryCidrBlocks)) {
+			if err := add(fmt.Sprintf("Subnets %s secondary %s", subnet.Name, rangeName), subnet.SecondaryCidrBlocks[rangeName]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -17,9 +17,13 @@ limitations under the License.
 package webhooks
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 )
 
@@ -50,6 +54,48 @@ func TestGCPCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "GCPCluster with unchanged uppercase AdditionalLabels - valid",
+			newCluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network:          infrav1.NetworkSpec{Mtu: int64(1500)},
+					AdditionalLabels: infrav1.Labels{"Team": "Platform"},
+				},
+			},
+			oldCluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network:          infrav1.NetworkSpec{Mtu: int64(1400)},
+					AdditionalLabels: infrav1.Labels{"Team": "Platform"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with changed uppercase AdditionalLabels - invalid",
+			newCluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network:          infrav1.NetworkSpec{Mtu: int64(1500)},
+					AdditionalLabels: infrav1.Labels{"Team": "Payments"},
+				},
+			},
+			oldCluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network:          infrav1.NetworkSpec{Mtu: int64(1500)},
+					AdditionalLabels: infrav1.Labels{"Team": "Platform"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with changed NodeServiceAccount - invalid",
+			newCluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					NodeServiceAccount: &infrav1.NodeServiceAccountSpec{},
+				},
+			},
+			oldCluster: &infrav1.GCPCluster{},
+			wantErr:    true,
+		},
 		{
 			name: "GCPCluster with MTU field more than 8896",
 			newCluster: &infrav1.GCPCluster{
@@ -212,6 +258,55 @@ func TestGCPCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "GCPCluster with updated LoadBalancer HealthCheck - valid",
+			newCluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{Mtu: int64(1500)},
+					LoadBalancer: infrav1.LoadBalancerSpec{
+						HealthCheck: &infrav1.HealthCheck{CheckIntervalSec: ptr.To[int64](30)},
+					},
+				},
+			},
+			oldCluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{Mtu: int64(1500)},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with LoadBalancer HealthCheck TimeoutSec greater than CheckIntervalSec - invalid",
+			newCluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{Mtu: int64(1500)},
+					LoadBalancer: infrav1.LoadBalancerSpec{
+						HealthCheck: &infrav1.HealthCheck{TimeoutSec: ptr.To[int64](20)},
+					},
+				},
+			},
+			oldCluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{Mtu: int64(1500)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with updated LoadBalancerType - invalid",
+			newCluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network:      infrav1.NetworkSpec{Mtu: int64(1500)},
+					LoadBalancer: infrav1.LoadBalancerSpec{LoadBalancerType: ptr.To(infrav1.InternalExternal)},
+				},
+			},
+			oldCluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{Mtu: int64(1500)},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -226,3 +321,645 @@ func TestGCPCluster_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestGCPCluster_ValidateCreate(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		cluster *infrav1.GCPCluster
+		wantErr bool
+	}{
+		{
+			name: "GCPCluster with DiskEncryptionKey KeyType Managed - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					DiskEncryptionKey: &infrav1.CustomerEncryptionKey{
+						KeyType: infrav1.CustomerManagedKey,
+						ManagedKey: &infrav1.ManagedKey{
+							KMSKeyName: "projects/my-project/locations/us-central1/keyRings/us-central1/cryptoKeys/some-key",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with DiskEncryptionKey KeyType Supplied - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					DiskEncryptionKey: &infrav1.CustomerEncryptionKey{
+						KeyType: infrav1.CustomerSuppliedKey,
+						SuppliedKey: &infrav1.SuppliedKey{
+							RawKey: []byte("SGVsbG8gZnJvbSBHb29nbGUgQ2xvdWQgUGxhdGZvcm0="),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with DiskEncryptionKey and malformed KMSKeyName - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					DiskEncryptionKey: &infrav1.CustomerEncryptionKey{
+						KeyType: infrav1.CustomerManagedKey,
+						ManagedKey: &infrav1.ManagedKey{
+							KMSKeyName: "some-key",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with invalid AdditionalLabels key - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					AdditionalLabels: infrav1.Labels{"1team": "payments"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with AdditionalNetworkTags - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					AdditionalNetworkTags: []string{"allow-ssh"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with AdditionalNetworkTags too long - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					AdditionalNetworkTags: []string{strings.Repeat("a", 64)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with Internal LoadBalancer and a subnet in the cluster region - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Region:       "us-central1",
+					LoadBalancer: infrav1.LoadBalancerSpec{LoadBalancerType: ptr.To(infrav1.Internal)},
+					Network: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{{Name: "control-plane", CidrBlock: "10.0.0.0/24"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with Internal LoadBalancer and no subnet in the cluster region - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Region:       "us-central1",
+					LoadBalancer: infrav1.LoadBalancerSpec{LoadBalancerType: ptr.To(infrav1.Internal)},
+					Network: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{{Name: "control-plane", CidrBlock: "10.0.0.0/24", Region: "europe-west1"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with Internal LoadBalancer on an unknown subnet - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Region: "us-central1",
+					LoadBalancer: infrav1.LoadBalancerSpec{
+						LoadBalancerType:     ptr.To(infrav1.InternalExternal),
+						InternalLoadBalancer: &infrav1.LoadBalancer{Subnet: ptr.To("workers")},
+					},
+					Network: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{{Name: "control-plane", CidrBlock: "10.0.0.0/24"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with an APIServerAddress name - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					LoadBalancer: infrav1.LoadBalancerSpec{APIServerAddress: ptr.To("my-apiserver-ip")},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with an APIServerAddress IP address - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					LoadBalancer: infrav1.LoadBalancerSpec{APIServerAddress: ptr.To("34.120.10.10")},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with a malformed APIServerAddress - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					LoadBalancer: infrav1.LoadBalancerSpec{APIServerAddress: ptr.To("My_Address")},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with an APIServerAddress and an Internal LoadBalancer - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Region: "us-central1",
+					LoadBalancer: infrav1.LoadBalancerSpec{
+						LoadBalancerType: ptr.To(infrav1.Internal),
+						APIServerAddress: ptr.To("my-apiserver-ip"),
+					},
+					Network: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{{Name: "control-plane", CidrBlock: "10.0.0.0/24"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with FailureDomains in the cluster region - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Region:         "us-central1",
+					FailureDomains: []string{"us-central1-a", "us-central1-b"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with FailureDomains outside the cluster region - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Region:         "us-central1",
+					FailureDomains: []string{"us-central1-a", "us-east1-b"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with duplicate FailureDomains - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Region:         "us-central1",
+					FailureDomains: []string{"us-central1-a", "us-central1-a"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with ControlPlaneCompactPlacement in a single zone - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Region:                       "us-central1",
+					FailureDomains:               []string{"us-central1-a"},
+					ControlPlaneCompactPlacement: ptr.To(true),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with ControlPlaneCompactPlacement across zones - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Region:                       "us-central1",
+					FailureDomains:               []string{"us-central1-a", "us-central1-b"},
+					ControlPlaneCompactPlacement: ptr.To(true),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with NodeServiceAccount additional roles - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					NodeServiceAccount: &infrav1.NodeServiceAccountSpec{
+						AdditionalRoles: []string{"roles/storage.objectViewer", "projects/my-project/roles/myCustomRole"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with a malformed NodeServiceAccount role - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					NodeServiceAccount: &infrav1.NodeServiceAccountSpec{
+						AdditionalRoles: []string{"storage.objectViewer"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with GCS BootstrapDataStorage - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					BootstrapDataStorage: &infrav1.BootstrapDataStorageSpec{
+						Type:   infrav1.BootstrapDataStorageGCS,
+						Bucket: "my-bootstrap-data",
+						TTL:    &metav1.Duration{Duration: 30 * time.Minute},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with GCS BootstrapDataStorage without Bucket - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					BootstrapDataStorage: &infrav1.BootstrapDataStorageSpec{
+						Type: infrav1.BootstrapDataStorageGCS,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with Metadata BootstrapDataStorage and Bucket - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					BootstrapDataStorage: &infrav1.BootstrapDataStorageSpec{
+						Type:   infrav1.BootstrapDataStorageMetadata,
+						Bucket: "my-bootstrap-data",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with negative BootstrapDataStorage TTL - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					BootstrapDataStorage: &infrav1.BootstrapDataStorageSpec{
+						Type:   infrav1.BootstrapDataStorageGCS,
+						Bucket: "my-bootstrap-data",
+						TTL:    &metav1.Duration{Duration: -time.Minute},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with FirewallRules opening the NodePort range - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Firewall: infrav1.FirewallSpec{
+							FirewallRules: []infrav1.FirewallRule{
+								{
+									Name:         "nodeports",
+									Allowed:      []infrav1.FirewallDescriptor{{IPProtocol: infrav1.FirewallProtocolTCP, Ports: []string{"30000-32767"}}},
+									SourceRanges: []string{"10.0.0.0/8"},
+								},
+								{
+									Name:       "monitoring",
+									Allowed:    []infrav1.FirewallDescriptor{{IPProtocol: infrav1.FirewallProtocolTCP, Ports: []string{"9100"}}},
+									SourceTags: []string{"monitoring"},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with FirewallRules with an inverted port range - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Firewall: infrav1.FirewallSpec{
+							FirewallRules: []infrav1.FirewallRule{
+								{Allowed: []infrav1.FirewallDescriptor{{IPProtocol: infrav1.FirewallProtocolTCP, Ports: []string{"32767-30000"}}}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with FirewallRules with a port out of range - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Firewall: infrav1.FirewallSpec{
+							FirewallRules: []infrav1.FirewallRule{
+								{Allowed: []infrav1.FirewallDescriptor{{IPProtocol: infrav1.FirewallProtocolUDP, Ports: []string{"70000"}}}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with FirewallRules with ports for ICMP - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Firewall: infrav1.FirewallSpec{
+							FirewallRules: []infrav1.FirewallRule{
+								{Denied: []infrav1.FirewallDescriptor{{IPProtocol: infrav1.FirewallProtocolICMP, Ports: []string{"8"}}}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with an Egress FirewallRule with SourceTags - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Firewall: infrav1.FirewallSpec{
+							FirewallRules: []infrav1.FirewallRule{
+								{
+									Direction:  infrav1.FirewallRuleDirectionEgress,
+									Allowed:    []infrav1.FirewallDescriptor{{IPProtocol: infrav1.FirewallProtocolTCP}},
+									SourceTags: []string{"monitoring"},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with two unnamed Ingress FirewallRules - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Firewall: infrav1.FirewallSpec{
+							FirewallRules: []infrav1.FirewallRule{
+								{Allowed: []infrav1.FirewallDescriptor{{IPProtocol: infrav1.FirewallProtocolTCP, Ports: []string{"80"}}}},
+								{
+									Direction: infrav1.FirewallRuleDirectionIngress,
+									Allowed:   []infrav1.FirewallDescriptor{{IPProtocol: infrav1.FirewallProtocolTCP, Ports: []string{"443"}}},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with MTU 8896 - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{Mtu: 8896},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with MTU lower than 1300 - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{Mtu: 1200},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with CloudNAT dynamic port allocation - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						MinPortsPerVM: 64,
+						CloudNAT: &infrav1.CloudNATSpec{
+							MaxPortsPerVM: ptr.To[int64](1024),
+							LogFilter:     ptr.To(infrav1.CloudNATLogFilterErrorsOnly),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with CloudNAT MaxPortsPerVM lower than MinPortsPerVM - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						MinPortsPerVM: 128,
+						CloudNAT: &infrav1.CloudNATSpec{
+							MaxPortsPerVM: ptr.To[int64](64),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with CloudNAT MaxPortsPerVM not a power of 2 - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						MinPortsPerVM: 64,
+						CloudNAT: &infrav1.CloudNATSpec{
+							MaxPortsPerVM: ptr.To[int64](1000),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with NetworkPeerings - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						NetworkPeerings: []infrav1.NetworkPeering{
+							{Name: "transit", PeerNetwork: "projects/my-transit-project/global/networks/transit", ImportCustomRoutes: ptr.To(true)},
+							{Name: "shared-services", PeerNetwork: "projects/my-services-project/global/networks/services"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with duplicate NetworkPeerings names - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						NetworkPeerings: []infrav1.NetworkPeering{
+							{Name: "transit", PeerNetwork: "projects/my-transit-project/global/networks/transit"},
+							{Name: "transit", PeerNetwork: "projects/my-services-project/global/networks/services"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with subnet secondary ranges - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							{
+								Name:                "workers",
+								CidrBlock:           "10.0.0.0/20",
+								SecondaryCidrBlocks: map[string]string{"pods": "10.4.0.0/14", "services": "10.8.0.0/20"},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with subnet FlowLogs sampling between 0 and 1 - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							{Name: "control-plane", CidrBlock: "10.0.0.0/24", FlowLogs: &infrav1.SubnetFlowLogs{FlowSampling: ptr.To("0.5")}},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with subnet FlowLogs sampling greater than 1 - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							{Name: "control-plane", CidrBlock: "10.0.0.0/24", FlowLogs: &infrav1.SubnetFlowLogs{FlowSampling: ptr.To("1.5")}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with a dual-stack subnet - valid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							{Name: "control-plane", CidrBlock: "10.0.0.0/24", StackType: "IPV4_IPV6", Ipv6AccessType: ptr.To(infrav1.Ipv6AccessTypeExternal)},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPCluster with a dual-stack subnet without Ipv6AccessType - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							{Name: "control-plane", CidrBlock: "10.0.0.0/24", StackType: "IPV4_IPV6"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with an IPv4 only subnet with an Ipv6AccessType - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							{Name: "control-plane", CidrBlock: "10.0.0.0/24", StackType: "IPV4_ONLY", Ipv6AccessType: ptr.To(infrav1.Ipv6AccessTypeInternal)},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with a dual-stack proxy-only subnet - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							{Name: "proxy", CidrBlock: "10.0.0.0/24", Purpose: ptr.To("REGIONAL_MANAGED_PROXY"), StackType: "IPV4_IPV6", Ipv6AccessType: ptr.To(infrav1.Ipv6AccessTypeInternal)},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with a subnet secondary range overlapping the primary range - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							{
+								Name:                "workers",
+								CidrBlock:           "10.0.0.0/16",
+								SecondaryCidrBlocks: map[string]string{"pods": "10.0.128.0/17"},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with overlapping subnet secondary ranges - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							{
+								Name:                "workers",
+								CidrBlock:           "10.0.0.0/20",
+								SecondaryCidrBlocks: map[string]string{"pods": "10.4.0.0/14", "services": "10.5.0.0/20"},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPCluster with an invalid subnet secondary range - invalid",
+			cluster: &infrav1.GCPCluster{
+				Spec: infrav1.GCPClusterSpec{
+					Network: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							{
+								Name:                "workers",
+								CidrBlock:           "10.0.0.0/20",
+								SecondaryCidrBlocks: map[string]string{"pods": "10.4.0.0"},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			warn, err := (&GCPCluster{}).ValidateCreate(t.Context(), test.cluster)
+			if test.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(warn).To(BeNil())
+		})
+	}
+}
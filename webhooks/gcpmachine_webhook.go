@@ -19,8 +19,13 @@ package webhooks
 import (
 	"context"
 	"fmt"
+	"net"
+	"path"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"text/template"
 
 	"k8s.io/utils/strings/slices"
 
@@ -28,6 +33,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -42,6 +48,156 @@ var (
 	confidentialMachineSeriesSupportingTdx    = []string{"c3"}
 )
 
+// Guest accelerators can't be attached to shared-core machine types.
+// reference: https://cloud.google.com/compute/docs/general-purpose-machines#sharedcore
+var sharedCoreMachineTypes = []string{"e2-micro", "e2-small", "e2-medium", "f1-micro", "g1-small"}
+
+// Machine series that GPUs can be attached to, keyed by accelerator type.
+// reference: https://cloud.google.com/compute/docs/gpus
+var acceleratorMachineSeries = map[string][]string{
+	"nvidia-tesla-t4":       {"n1"},
+	"nvidia-tesla-p4":       {"n1"},
+	"nvidia-tesla-p100":     {"n1"},
+	"nvidia-tesla-v100":     {"n1"},
+	"nvidia-tesla-k80":      {"n1"},
+	"nvidia-tesla-a100":     {"a2"},
+	"nvidia-a100-80gb":      {"a2"},
+	"nvidia-l4":             {"g2"},
+	"nvidia-h100-80gb":      {"a3"},
+	"nvidia-h100-mega-80gb": {"a3"},
+}
+
+// Cloud KMS key resource name, e.g. projects/my-project/locations/us-central1/keyRings/my-key-ring/cryptoKeys/my-key,
+// optionally pinned to a key version, e.g. .../cryptoKeys/my-key/cryptoKeyVersions/1.
+// reference: https://cloud.google.com/kms/docs/resource-hierarchy#retrieve_resource_id
+var kmsKeyNameRegexp = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+(/cryptoKeyVersions/[^/]+)?$`)
+
+// CPU platforms available per machine series, series that don't support a minimum CPU platform are set to nil.
+// reference: https://cloud.google.com/compute/docs/instances/specify-min-cpu-platform#availablezones
+var minCPUPlatformsPerMachineSeries = map[string][]string{
+	"n1":  {"Intel Sandy Bridge", "Intel Ivy Bridge", "Intel Haswell", "Intel Broadwell", "Intel Skylake"},
+	"n2":  {"Intel Cascade Lake", "Intel Ice Lake"},
+	"n2d": {"AMD Rome", "AMD Milan"},
+	"c2":  {"Intel Cascade Lake"},
+	"c2d": {"AMD Milan"},
+	"c3":  {"Intel Sapphire Rapids"},
+	"t2d": {"AMD Milan"},
+	"t2a": {"Ampere Altra"},
+	"e2":  nil,
+	"c4a": nil,
+}
+
+// Nested virtualization is only supported on Intel processors, the following machine series don't support it.
+// reference: https://cloud.google.com/compute/docs/instances/nested-virtualization/overview#restrictions
+var nestedVirtualizationUnsupportedMachineSeries = []string{"e2", "n2d", "c2d", "c3d", "t2d", "t2a"}
+
+// Machine series supporting the TIER_1 egress bandwidth tier, which requires at least 30 vCPUs.
+// reference: https://cloud.google.com/compute/docs/networking/configure-vm-with-high-bandwidth-configuration
+var (
+	tier1MachineSeries = []string{"n2", "n2d", "c2", "c2d", "c3", "c3d", "c4", "m3", "z3", "a2", "a3", "g2", "h3"}
+	tier1MinVCPUs      = 30
+)
+
+// Minimum boot disk size in GB of public images, Windows Server images require larger boot disks.
+// reference: https://cloud.google.com/compute/docs/images/os-details
+const (
+	minRootDeviceSize        = 10
+	minWindowsRootDeviceSize = 50
+)
+
+// Limits of the instance metadata values and of the whole instance metadata in bytes, bootstrap data exceeding the
+// value limit can be stored in GCS through the GCPCluster BootstrapDataStorage.
+// reference: https://cloud.google.com/compute/docs/metadata/setting-custom-metadata#limitations
+const (
+	metadataValueMaxSize = 256 * 1024
+	metadataMaxSize      = 512 * 1024
+)
+
+// Supported boot disk types with their size limits in GB.
+// reference: https://cloud.google.com/compute/docs/disks#disk-types
+var rootDeviceSizeLimits = map[infrav1.DiskType]struct{ min, max int64 }{
+	infrav1.PdStandardDiskType:        {min: 10, max: 65536},
+	infrav1.PdSsdDiskType:             {min: 10, max: 65536},
+	infrav1.PdBalancedDiskType:        {min: 10, max: 65536},
+	infrav1.PdExtremeDiskType:         {min: 500, max: 65536},
+	infrav1.HyperdiskBalancedDiskType: {min: 4, max: 65536},
+}
+
+// Local SSDs have a fixed size of 375GB.
+const localSSDSizeGb = 375
+
+// Minimum size of the regional persistent disks per device type, device types that don't support regional disks
+// are omitted.
+// reference: https://cloud.google.com/compute/docs/disks/regional-persistent-disk#limitations
+var regionalDiskMinSizeGb = map[infrav1.DiskType]int64{
+	infrav1.PdStandardDiskType: 200,
+	infrav1.PdBalancedDiskType: 10,
+	infrav1.PdSsdDiskType:      10,
+}
+
+// Maximum number of local SSDs per machine series, series that don't support local SSDs are set to 0.
+// reference: https://cloud.google.com/compute/docs/disks/local-ssd#lssd_disk_options
+var localSSDMaxCountPerMachineSeries = map[string]int64{
+	"n1":  24,
+	"n2":  24,
+	"n2d": 24,
+	"c2":  8,
+	"c2d": 8,
+	"a2":  8,
+	"g2":  8,
+	"e2":  0,
+	"t2d": 0,
+	"t2a": 0,
+	"n4":  0,
+}
+
+// GCE label keys must start with a lowercase letter and label keys and values can only contain lowercase letters,
+// numbers, underscores and dashes, up to 63 characters. A resource can have up to 64 labels.
+// reference: https://cloud.google.com/compute/docs/labeling-resources#requirements
+var (
+	labelKeyRegexp   = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,62}$`)
+	labelValueRegexp = regexp.MustCompile(`^[a-z0-9_-]{0,63}$`)
+)
+
+const maxLabels = 64
+
+// Network tags must start with a lowercase letter, end with a lowercase letter or a number and can only contain
+// lowercase letters, numbers and dashes, up to 63 characters. An instance can have up to 64 network tags, two of
+// which are set by the provider for the cluster firewall rules.
+// reference: https://cloud.google.com/vpc/docs/add-remove-network-tags#restrictions
+var networkTagRegexp = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+const maxAdditionalNetworkTags = 62
+
+// Instance names must start with a lowercase letter and can only contain lowercase letters, numbers and dashes, the
+// prefix of instance names is limited to leave room for the GCPMachine name.
+// reference: https://cloud.google.com/compute/docs/naming-resources#resource-name-format
+var instanceNamePrefixRegexp = regexp.MustCompile(`^[a-z][-a-z0-9]{0,31}$`)
+
+// Service account emails, or "default" for the Compute Engine default service account.
+var serviceAccountEmailRegexp = regexp.MustCompile(`^(default|[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,})$`)
+
+const serviceAccountScopeURLPrefix = "https://www.googleapis.com/auth/"
+
+// Instances can have up to 8 network interfaces, instances with 2 vCPUs or less can have up to 2.
+// Instances with more vCPUs can have up to 1 network interface per vCPU.
+// reference: https://cloud.google.com/vpc/docs/create-use-multiple-interfaces#max-interfaces
+const (
+	maxNetworkInterfaces       = 8
+	minMaxNetworkInterfaces    = 2
+	sharedCoreMachineTypeVCPUs = 2
+)
+
+// Markers of the CPU architecture in the names of public images and image families, e.g. ubuntu-2204-lts-arm64.
+var imageArchitectureMarkers = map[infrav1.Architecture][]string{
+	infrav1.ArchitectureARM64: {"arm64", "aarch64"},
+	infrav1.ArchitectureAMD64: {"amd64", "x86-64"},
+}
+
+// Secure Boot requires a UEFI-compatible image. Public image families older than the following do not support UEFI.
+// reference: https://cloud.google.com/compute/shielded-vm/docs/images
+var nonUEFIImageFamilyPrefixes = []string{"centos-6", "rhel-6", "debian-8", "ubuntu-1404", "windows-2008"}
+
 func (m *GCPMachine) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&infrav1.GCPMachine{}).
@@ -70,10 +226,85 @@ func (*GCPMachine) ValidateCreate(_ context.Context, obj runtime.Object) (admiss
 
 	clusterlog.Info("validate create", "name", m.Name)
 
+	if err := validateInstanceNamePrefix(m.Spec); err != nil {
+		return nil, err
+	}
 	if err := validateConfidentialCompute(m.Spec); err != nil {
 		return nil, err
 	}
-	return nil, validateCustomerEncryptionKey(m.Spec)
+	if err := validateShieldedInstanceConfig(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateScheduling(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateGuestAccelerators(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateLocalSSDs(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateRootDevice(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateMinCPUPlatform(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateArchitecture(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateDrainTimeout(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateAdvancedMachineFeatures(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateNetworkPerformanceConfig(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateAdditionalMetadata(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateOSLogin(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateOSFamily(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateLabels(m.Spec.AdditionalLabels); err != nil {
+		return nil, err
+	}
+	if err := validateNetworkTags(m.Spec.AdditionalNetworkTags); err != nil {
+		return nil, err
+	}
+	if err := validateNetworkInterfaces(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateInternalAddress(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateImageLookupFormat(m.Spec.ImageLookupFormat); err != nil {
+		return nil, err
+	}
+	if err := validateServiceAccount(m.Spec.ServiceAccount); err != nil {
+		return nil, err
+	}
+	if err := validateResourcePolicies(m.Spec.ResourcePolicies); err != nil {
+		return nil, err
+	}
+	if err := validateAttachedDisks(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateStackType(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateImageFamily(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateReplicaZones(m.Spec); err != nil {
+		return nil, err
+	}
+	return append(osLoginWarnings(m.Spec), serviceAccountWarnings(m.Spec.ServiceAccount)...), validateCustomerEncryptionKey(m.Spec)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -111,13 +342,59 @@ func (*GCPMachine) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Obje
 	delete(oldGCPMachineSpec, "additionalNetworkTags")
 	delete(newGCPMachineSpec, "additionalNetworkTags")
 
+	// allow changes to additionalMetadata
+	delete(oldGCPMachineSpec, "additionalMetadata")
+	delete(newGCPMachineSpec, "additionalMetadata")
+
+	// allow changes to enableOSLogin and enableOSLogin2FA, applied through the instance metadata
+	delete(oldGCPMachineSpec, "enableOSLogin")
+	delete(newGCPMachineSpec, "enableOSLogin")
+	delete(oldGCPMachineSpec, "enableOSLogin2FA")
+	delete(newGCPMachineSpec, "enableOSLogin2FA")
+
+	// allow changes to deletionProtection
+	delete(oldGCPMachineSpec, "deletionProtection")
+	delete(newGCPMachineSpec, "deletionProtection")
+
+	// allow changes to drainTimeout, only used when the machine is deleted
+	delete(oldGCPMachineSpec, "drainTimeout")
+	delete(newGCPMachineSpec, "drainTimeout")
+
+	// allow changes to rebootstrapPolicy, only used until the machine joins the cluster
+	delete(oldGCPMachineSpec, "rebootstrapPolicy")
+	delete(newGCPMachineSpec, "rebootstrapPolicy")
+
+	// allow setting zone, the controller records the zone it selected for a machine without failure domain
+	if _, ok := oldGCPMachineSpec["zone"]; !ok {
+		delete(newGCPMachineSpec, "zone")
+	}
+
 	if !reflect.DeepEqual(oldGCPMachineSpec, newGCPMachineSpec) {
 		return nil, apierrors.NewInvalid(infrav1.GroupVersion.WithKind("GCPMachine").GroupKind(), m.Name, field.ErrorList{
 			field.Forbidden(field.NewPath("spec"), "cannot be modified"),
 		})
 	}
 
-	return nil, nil
+	// The labels accepted before they were validated are kept as is, otherwise the machine couldn't be updated, nor
+	// deleted, anymore.
+	if old, ok := oldObj.(*infrav1.GCPMachine); !ok || !reflect.DeepEqual(m.Spec.AdditionalLabels, old.Spec.AdditionalLabels) {
+		if err := validateLabels(m.Spec.AdditionalLabels); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateAdditionalMetadata(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateOSLogin(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateOSFamily(m.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateDrainTimeout(m.Spec); err != nil {
+		return nil, err
+	}
+	return osLoginWarnings(m.Spec), validateNetworkTags(m.Spec.AdditionalNetworkTags)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -157,12 +434,580 @@ func validateConfidentialCompute(spec infrav1.GCPMachineSpec) error {
 	return nil
 }
 
+func validateScheduling(spec infrav1.GCPMachineSpec) error {
+	if spec.Preemptible && spec.OnHostMaintenance != nil && *spec.OnHostMaintenance == infrav1.HostMaintenancePolicyMigrate {
+		return fmt.Errorf("Preemptible instances require OnHostMaintenance to be set to %s, the current value is: %s", infrav1.HostMaintenancePolicyTerminate, infrav1.HostMaintenancePolicyMigrate)
+	}
+
+	spot := spec.ProvisioningModel != nil && *spec.ProvisioningModel == infrav1.ProvisioningModelSpot
+	if spot && spec.Preemptible {
+		return fmt.Errorf("Preemptible cannot be set together with ProvisioningModel %s", infrav1.ProvisioningModelSpot)
+	}
+	if spot && spec.OnHostMaintenance != nil && *spec.OnHostMaintenance == infrav1.HostMaintenancePolicyMigrate {
+		return fmt.Errorf("ProvisioningModel %s requires OnHostMaintenance to be set to %s, the current value is: %s", infrav1.ProvisioningModelSpot, infrav1.HostMaintenancePolicyTerminate, infrav1.HostMaintenancePolicyMigrate)
+	}
+	if spec.TerminationAction != nil && !spot {
+		return fmt.Errorf("TerminationAction requires ProvisioningModel to be set to %s", infrav1.ProvisioningModelSpot)
+	}
+	if (spec.Preemptible || spot) && ptr.Deref(spec.AutomaticRestart, false) {
+		return errors.New("Preemptible and Spot instances cannot be automatically restarted, AutomaticRestart must not be set to true")
+	}
+	if len(spec.GuestAccelerators) > 0 && spec.OnHostMaintenance != nil && *spec.OnHostMaintenance == infrav1.HostMaintenancePolicyMigrate {
+		return fmt.Errorf("instances with GuestAccelerators require OnHostMaintenance to be set to %s, the current value is: %s", infrav1.HostMaintenancePolicyTerminate, infrav1.HostMaintenancePolicyMigrate)
+	}
+	if len(spec.NodeAffinities) > 0 && (spec.Preemptible || spot) {
+		return errors.New("NodeAffinities cannot be set for Preemptible or Spot instances as they can't run on sole-tenant nodes")
+	}
+	return nil
+}
+
+func validateGuestAccelerators(spec infrav1.GCPMachineSpec) error {
+	if len(spec.GuestAccelerators) == 0 {
+		return nil
+	}
+
+	if slices.Contains(sharedCoreMachineTypes, spec.InstanceType) {
+		return fmt.Errorf("GuestAccelerators cannot be attached to shared-core machine type %s", spec.InstanceType)
+	}
+	for _, accelerator := range spec.GuestAccelerators {
+		if accelerator.Count <= 0 {
+			return fmt.Errorf("GuestAccelerators count must be greater than 0, %d was found for %s", accelerator.Count, accelerator.Type)
+		}
+		machineSeries := strings.Split(spec.InstanceType, "-")[0]
+		acceleratorType := path.Base(accelerator.Type)
+		if series, ok := acceleratorMachineSeries[acceleratorType]; ok && !slices.Contains(series, machineSeries) {
+			return fmt.Errorf("GuestAccelerators type %s is not supported on machine type %s, supported machine series are %v", acceleratorType, spec.InstanceType, series)
+		}
+	}
+	return nil
+}
+
+func validateLocalSSDs(spec infrav1.GCPMachineSpec) error {
+	var count int64
+	for _, ssd := range spec.LocalSSDs {
+		count += ssd.Count
+	}
+	for _, disk := range spec.AdditionalDisks {
+		if disk.DeviceType == nil || *disk.DeviceType != infrav1.LocalSsdDiskType {
+			continue
+		}
+		if disk.Size != nil && *disk.Size != localSSDSizeGb {
+			return fmt.Errorf("local SSD disks have a fixed size of %dGB, got %dGB", localSSDSizeGb, *disk.Size)
+		}
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+
+	machineSeries := strings.Split(spec.InstanceType, "-")[0]
+	if maxCount, ok := localSSDMaxCountPerMachineSeries[machineSeries]; ok && count > maxCount {
+		return fmt.Errorf("local SSD count of %d exceeds the maximum of %d for machine series %s", count, maxCount, machineSeries)
+	}
+	return nil
+}
+
+func validateAdditionalMetadata(spec infrav1.GCPMachineSpec) error {
+	bootstrapDataKey := infrav1.BootstrapDataMetadataKey
+	if ptr.Deref(spec.OSFamily, infrav1.OSFamilyLinux) == infrav1.OSFamilyWindows {
+		bootstrapDataKey = infrav1.WindowsBootstrapDataMetadataKey
+	}
+	if key := ptr.Deref(spec.BootstrapDataMetadataKey, ""); key != "" {
+		bootstrapDataKey = key
+	}
+	size := 0
+	for _, item := range spec.AdditionalMetadata {
+		if item.Key == bootstrapDataKey {
+			return fmt.Errorf("AdditionalMetadata key %s is reserved for the bootstrap data", bootstrapDataKey)
+		}
+		value := ptr.Deref(item.Value, "")
+		if len(value) > metadataValueMaxSize {
+			return fmt.Errorf("AdditionalMetadata value of key %s is %d bytes, it must not exceed %d bytes", item.Key, len(value), metadataValueMaxSize)
+		}
+		size += len(item.Key) + len(value)
+	}
+	if size > metadataMaxSize {
+		return fmt.Errorf("AdditionalMetadata is %d bytes, it must not exceed %d bytes", size, metadataMaxSize)
+	}
+	return nil
+}
+
+// hasWindowsImage reports whether the image of the instance is a public Windows Server image, named windows-*.
+func hasWindowsImage(spec infrav1.GCPMachineSpec) bool {
+	for _, image := range []*string{spec.Image, spec.ImageFamily} {
+		if image != nil && strings.HasPrefix(path.Base(*image), "windows-") {
+			return true
+		}
+	}
+	return false
+}
+
+func validateOSFamily(spec infrav1.GCPMachineSpec) error {
+	if ptr.Deref(spec.OSFamily, infrav1.OSFamilyLinux) != infrav1.OSFamilyWindows {
+		if hasWindowsImage(spec) {
+			return fmt.Errorf("OSFamily must be %s to bootstrap a Windows Server image", infrav1.OSFamilyWindows)
+		}
+		return nil
+	}
+
+	if ptr.Deref(spec.EnableOSLogin, false) || ptr.Deref(spec.EnableOSLogin2FA, false) {
+		return errors.New("EnableOSLogin and EnableOSLogin2FA are not supported by Windows Server images")
+	}
+	if spec.ConfidentialCompute != nil {
+		switch *spec.ConfidentialCompute {
+		case infrav1.ConfidentialComputePolicySEVSNP, infrav1.ConfidentialComputePolicyTDX:
+			return fmt.Errorf("ConfidentialCompute %s is not supported by Windows Server images", *spec.ConfidentialCompute)
+		}
+	}
+	return nil
+}
+
+func validateLabels(labels infrav1.Labels) error {
+	if len(labels) > maxLabels {
+		return fmt.Errorf("AdditionalLabels can't have more than %d labels", maxLabels)
+	}
+	for key, value := range labels {
+		if !labelKeyRegexp.MatchString(key) {
+			return fmt.Errorf("AdditionalLabels key %q must start with a lowercase letter and only contain lowercase letters, numbers, underscores and dashes, up to 63 characters", key)
+		}
+		if !labelValueRegexp.MatchString(value) {
+			return fmt.Errorf("AdditionalLabels value %q of key %q must only contain lowercase letters, numbers, underscores and dashes, up to 63 characters", value, key)
+		}
+	}
+	return nil
+}
+
+func validateNetworkTags(tags []string) error {
+	if len(tags) > maxAdditionalNetworkTags {
+		return fmt.Errorf("AdditionalNetworkTags can't have more than %d tags", maxAdditionalNetworkTags)
+	}
+	for _, tag := range tags {
+		if !networkTagRegexp.MatchString(tag) {
+			return fmt.Errorf("AdditionalNetworkTags tag %q must start with a lowercase letter, end with a lowercase letter or a number and only contain lowercase letters, numbers and dashes, up to 63 characters", tag)
+		}
+	}
+	return nil
+}
+
+func validateNetworkInterfaces(spec infrav1.GCPMachineSpec) error {
+	if len(spec.AdditionalNetworkInterfaces) == 0 {
+		return nil
+	}
+
+	maxCount := maxNetworkInterfaces
+	if vCPUs, ok := machineTypeVCPUs(spec.InstanceType); ok {
+		maxCount = min(max(vCPUs, minMaxNetworkInterfaces), maxNetworkInterfaces)
+	}
+	if count := len(spec.AdditionalNetworkInterfaces) + 1; count > maxCount {
+		return fmt.Errorf("network interface count of %d exceeds the maximum of %d for machine type %s", count, maxCount, spec.InstanceType)
+	}
+
+	networks := make(map[string]bool, len(spec.AdditionalNetworkInterfaces))
+	for _, nic := range spec.AdditionalNetworkInterfaces {
+		network := path.Base(nic.Network)
+		if networks[network] {
+			return fmt.Errorf("AdditionalNetworkInterfaces network %s is used by more than one network interface", network)
+		}
+		networks[network] = true
+
+		if nic.InternalIP != nil {
+			if ip := net.ParseIP(*nic.InternalIP); ip == nil || ip.To4() == nil {
+				return fmt.Errorf("AdditionalNetworkInterfaces internal IP %s is not a valid IPv4 address", *nic.InternalIP)
+			}
+		}
+
+		for _, aliasIPRange := range nic.AliasIPRanges {
+			if aliasIPRange.SubnetworkRangeName != "" && nic.Subnetwork == nil {
+				return fmt.Errorf("AdditionalNetworkInterfaces network %s alias IP range references secondary range %s but has no subnetwork", network, aliasIPRange.SubnetworkRangeName)
+			}
+		}
+	}
+	return nil
+}
+
+func validateInternalAddress(spec infrav1.GCPMachineSpec) error {
+	if spec.InternalAddress == nil || spec.InternalAddress.Address == nil {
+		return nil
+	}
+	if ip := net.ParseIP(*spec.InternalAddress.Address); ip == nil || ip.To4() == nil {
+		return fmt.Errorf("InternalAddress address %s is not a valid IPv4 address", *spec.InternalAddress.Address)
+	}
+	return nil
+}
+
+func validateImageLookupFormat(format *string) error {
+	if format == nil {
+		return nil
+	}
+	if _, err := template.New("imageLookupFormat").Parse(*format); err != nil {
+		return fmt.Errorf("ImageLookupFormat %q is not a valid template: %w", *format, err)
+	}
+	return nil
+}
+
+// machineTypeVCPUs returns the number of vCPUs of predefined and custom machine types, e.g. n2-standard-4 or
+// n2-custom-4-8192. It returns false if the number of vCPUs can't be inferred from the machine type.
+func machineTypeVCPUs(instanceType string) (int, bool) {
+	if slices.Contains(sharedCoreMachineTypes, instanceType) {
+		return sharedCoreMachineTypeVCPUs, true
+	}
+	parts := strings.Split(instanceType, "-")
+	if len(parts) < 3 {
+		return 0, false
+	}
+	vCPUs, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return 0, false
+	}
+	return vCPUs, true
+}
+
+func validateOSLogin(spec infrav1.GCPMachineSpec) error {
+	if ptr.Deref(spec.EnableOSLogin2FA, false) && spec.EnableOSLogin != nil && !*spec.EnableOSLogin {
+		return errors.New("EnableOSLogin2FA requires EnableOSLogin")
+	}
+	return nil
+}
+
+// osLoginWarnings warns about ssh-keys metadata set along with OS Login, as GCE ignores it when OS Login is enabled.
+func osLoginWarnings(spec infrav1.GCPMachineSpec) admission.Warnings {
+	if !ptr.Deref(spec.EnableOSLogin, false) {
+		return nil
+	}
+	for _, item := range spec.AdditionalMetadata {
+		if item.Key == "ssh-keys" {
+			return admission.Warnings{"AdditionalMetadata ssh-keys is ignored by GCE as OS Login is enabled"}
+		}
+	}
+	return nil
+}
+
+func validateServiceAccount(serviceAccount *infrav1.ServiceAccount) error {
+	if serviceAccount == nil {
+		return nil
+	}
+	if !serviceAccountEmailRegexp.MatchString(serviceAccount.Email) {
+		return fmt.Errorf("ServiceAccount email %q must be a service account email or \"default\"", serviceAccount.Email)
+	}
+	for _, scope := range serviceAccount.Scopes {
+		if _, ok := infrav1.ServiceAccountScopeAliases[scope]; !ok && !strings.HasPrefix(scope, serviceAccountScopeURLPrefix) {
+			return fmt.Errorf("ServiceAccount scope %s must be a full URL starting with %s or a known alias", scope, serviceAccountScopeURLPrefix)
+		}
+	}
+	return nil
+}
+
+// serviceAccountWarnings warns about narrow scopes set along with the cloud-platform scope, as the latter already
+// grants access to all the Google Cloud APIs, leaving the IAM roles of the service account as the only restriction.
+func serviceAccountWarnings(serviceAccount *infrav1.ServiceAccount) admission.Warnings {
+	if serviceAccount == nil || len(serviceAccount.Scopes) < 2 {
+		return nil
+	}
+	cloudPlatform := infrav1.ServiceAccountScopeAliases["cloud-platform"]
+	for _, scope := range serviceAccount.Scopes {
+		if scope == "cloud-platform" || scope == cloudPlatform {
+			return admission.Warnings{fmt.Sprintf("ServiceAccount scope %s grants access to all Google Cloud APIs, "+
+				"the other scopes have no effect, restrict the access with the IAM roles of %s instead", cloudPlatform, serviceAccount.Email)}
+		}
+	}
+	return nil
+}
+
+func validateResourcePolicies(policies []string) error {
+	for _, policy := range policies {
+		if strings.Contains(policy, "/") {
+			if !strings.Contains(policy, "/resourcePolicies/") {
+				return fmt.Errorf("ResourcePolicies %s must be a resource policy name or URL", policy)
+			}
+			continue
+		}
+		if !resourceNameRegexp.MatchString(policy) {
+			return fmt.Errorf("ResourcePolicies %q must be a resource policy name or URL", policy)
+		}
+	}
+	return nil
+}
+
+func validateAttachedDisks(spec infrav1.GCPMachineSpec) error {
+	disks := make(map[string]bool, len(spec.AttachedDisks))
+	deviceNames := make(map[string]bool, len(spec.AdditionalDisks)+len(spec.AttachedDisks))
+	for _, disk := range spec.AdditionalDisks {
+		if disk.DeviceName != nil {
+			deviceNames[*disk.DeviceName] = true
+		}
+	}
+
+	for _, disk := range spec.AttachedDisks {
+		if strings.Contains(disk.Name, "/") {
+			if !strings.Contains(disk.Name, "/zones/") || !strings.Contains(disk.Name, "/disks/") {
+				return fmt.Errorf("AttachedDisks name %s must be a disk name or URL", disk.Name)
+			}
+		} else if !resourceNameRegexp.MatchString(disk.Name) {
+			return fmt.Errorf("AttachedDisks name %q must be a disk name or URL", disk.Name)
+		}
+		if disks[disk.Name] {
+			return fmt.Errorf("AttachedDisks disk %s is attached more than once", disk.Name)
+		}
+		disks[disk.Name] = true
+
+		if disk.DeviceName == nil {
+			continue
+		}
+		if deviceNames[*disk.DeviceName] {
+			return fmt.Errorf("AttachedDisks device name %s is already used by another disk", *disk.DeviceName)
+		}
+		deviceNames[*disk.DeviceName] = true
+	}
+	return nil
+}
+
+func validateStackType(spec infrav1.GCPMachineSpec) error {
+	if spec.Ipv6AccessType != nil && ptr.Deref(spec.StackType, infrav1.StackTypeIPv4Only) != infrav1.StackTypeIPv4IPv6 {
+		return errors.New("Ipv6AccessType requires the IPV4_IPV6 StackType")
+	}
+	return nil
+}
+
+func validateImageFamily(spec infrav1.GCPMachineSpec) error {
+	if spec.ImageFamily == nil {
+		if spec.ImageFamilyProject != nil {
+			return errors.New("ImageFamilyProject requires ImageFamily")
+		}
+		return nil
+	}
+
+	family := *spec.ImageFamily
+	if strings.Contains(family, "/") {
+		if !strings.Contains(family, "/global/images/family/") {
+			return fmt.Errorf("ImageFamily %s must be an image family name or URL", family)
+		}
+		if spec.ImageFamilyProject != nil {
+			return fmt.Errorf("ImageFamilyProject can't be set when ImageFamily %s is an image family URL", family)
+		}
+		return nil
+	}
+	if !resourceNameRegexp.MatchString(family) {
+		return fmt.Errorf("ImageFamily %q must be an image family name or URL", family)
+	}
+	return nil
+}
+
+func validateReplicaZones(spec infrav1.GCPMachineSpec) error {
+	if len(spec.RootDeviceReplicaZones) > 0 {
+		diskType := ptr.Deref(spec.RootDeviceType, infrav1.PdStandardDiskType)
+		minSize, ok := regionalDiskMinSizeGb[diskType]
+		if !ok {
+			return fmt.Errorf("RootDeviceReplicaZones are not supported by the %s root device type", diskType)
+		}
+		size := spec.RootDeviceSize
+		if size == 0 {
+			size = 30
+		}
+		if size < minSize {
+			return fmt.Errorf("RootDeviceSize of a regional %s root device must be at least %dGB, got %dGB", diskType, minSize, size)
+		}
+		if len(spec.RootDeviceReplicaZones) != 2 || spec.RootDeviceReplicaZones[0] == spec.RootDeviceReplicaZones[1] {
+			return fmt.Errorf("RootDeviceReplicaZones %s must be two distinct zones", strings.Join(spec.RootDeviceReplicaZones, ", "))
+		}
+		if zoneRegion(spec.RootDeviceReplicaZones[0]) != zoneRegion(spec.RootDeviceReplicaZones[1]) {
+			return fmt.Errorf("RootDeviceReplicaZones %s must be in the same region", strings.Join(spec.RootDeviceReplicaZones, ", "))
+		}
+	}
+
+	for _, disk := range spec.AdditionalDisks {
+		if len(disk.ReplicaZones) == 0 {
+			continue
+		}
+
+		diskType := ptr.Deref(disk.DeviceType, infrav1.PdStandardDiskType)
+		minSize, ok := regionalDiskMinSizeGb[diskType]
+		if !ok {
+			return fmt.Errorf("AdditionalDisks ReplicaZones are not supported by the %s device type", diskType)
+		}
+		if size := ptr.Deref(disk.Size, 30); size < minSize {
+			return fmt.Errorf("AdditionalDisks regional %s disks must be at least %dGB, got %dGB", diskType, minSize, size)
+		}
+		if len(disk.ReplicaZones) != 2 || disk.ReplicaZones[0] == disk.ReplicaZones[1] {
+			return fmt.Errorf("AdditionalDisks ReplicaZones %s must be two distinct zones", strings.Join(disk.ReplicaZones, ", "))
+		}
+		if zoneRegion(disk.ReplicaZones[0]) != zoneRegion(disk.ReplicaZones[1]) {
+			return fmt.Errorf("AdditionalDisks ReplicaZones %s must be in the same region", strings.Join(disk.ReplicaZones, ", "))
+		}
+	}
+	return nil
+}
+
+// zoneRegion returns the region of a zone, e.g. us-central1 for us-central1-a.
+func zoneRegion(zone string) string {
+	if idx := strings.LastIndex(zone, "-"); idx > 0 {
+		return zone[:idx]
+	}
+	return zone
+}
+
+func validateInstanceNamePrefix(spec infrav1.GCPMachineSpec) error {
+	if spec.InstanceNamePrefix != nil && !instanceNamePrefixRegexp.MatchString(*spec.InstanceNamePrefix) {
+		return fmt.Errorf("InstanceNamePrefix %q must start with a lowercase letter and only contain lowercase letters, numbers and dashes, up to 32 characters", *spec.InstanceNamePrefix)
+	}
+	return nil
+}
+
+func validateDrainTimeout(spec infrav1.GCPMachineSpec) error {
+	if spec.DrainTimeout != nil && spec.DrainTimeout.Duration < 0 {
+		return fmt.Errorf("DrainTimeout %s must not be negative", spec.DrainTimeout.Duration)
+	}
+	return nil
+}
+
+func validateMinCPUPlatform(spec infrav1.GCPMachineSpec) error {
+	if spec.MinCPUPlatform == nil || *spec.MinCPUPlatform == "Automatic" {
+		return nil
+	}
+
+	platform := *spec.MinCPUPlatform
+	if !strings.HasPrefix(platform, "Intel ") && !strings.HasPrefix(platform, "AMD ") && !strings.HasPrefix(platform, "Ampere ") {
+		return fmt.Errorf("MinCPUPlatform %q is not a valid CPU platform, e.g. \"Intel Ice Lake\"", platform)
+	}
+
+	machineSeries := strings.Split(spec.InstanceType, "-")[0]
+	platforms, ok := minCPUPlatformsPerMachineSeries[machineSeries]
+	if !ok {
+		return nil
+	}
+	if len(platforms) == 0 {
+		return fmt.Errorf("MinCPUPlatform is not supported for machine series %s", machineSeries)
+	}
+	if !slices.Contains(platforms, platform) {
+		return fmt.Errorf("MinCPUPlatform %s is not available for machine series %s, supported CPU platforms are %v", platform, machineSeries, platforms)
+	}
+	return nil
+}
+
+// validateArchitecture makes sure the image of the machine, when its name tells its architecture, and the features
+// it enables are available on the architecture of the machine type. The architecture of the image itself is checked
+// when the instance is created.
+func validateArchitecture(spec infrav1.GCPMachineSpec) error {
+	arch := infrav1.MachineTypeArchitecture(spec.InstanceType)
+	image := ptr.Deref(spec.Image, ptr.Deref(spec.ImageFamily, ""))
+	imageName := path.Base(image)
+	for imageArch, markers := range imageArchitectureMarkers {
+		if imageArch == arch {
+			continue
+		}
+		for _, marker := range markers {
+			if strings.Contains(imageName, marker) {
+				return fmt.Errorf("image %s is built for the %s architecture, machine type %s requires %s images", image, imageArch, spec.InstanceType, arch)
+			}
+		}
+	}
+
+	if arch != infrav1.ArchitectureARM64 {
+		return nil
+	}
+	if ptr.Deref(spec.OSFamily, infrav1.OSFamilyLinux) == infrav1.OSFamilyWindows {
+		return fmt.Errorf("OSFamily Windows is not supported on the %s machine type %s", arch, spec.InstanceType)
+	}
+	if config := spec.ShieldedInstanceConfig; config != nil && config.IntegrityMonitoring == infrav1.IntegrityMonitoringPolicyEnabled {
+		return fmt.Errorf("ShieldedInstanceConfig IntegrityMonitoring is not supported on the %s machine type %s", arch, spec.InstanceType)
+	}
+	return nil
+}
+
+func validateAdvancedMachineFeatures(spec infrav1.GCPMachineSpec) error {
+	features := spec.AdvancedMachineFeatures
+	if features == nil {
+		return nil
+	}
+
+	if ptr.Deref(features.EnableNestedVirtualization, false) {
+		machineSeries := strings.Split(spec.InstanceType, "-")[0]
+		if slices.Contains(nestedVirtualizationUnsupportedMachineSeries, machineSeries) {
+			return fmt.Errorf("AdvancedMachineFeatures EnableNestedVirtualization is not supported for machine series %s", machineSeries)
+		}
+	}
+	if features.ThreadsPerCore != nil && *features.ThreadsPerCore != 1 && *features.ThreadsPerCore != 2 {
+		return fmt.Errorf("AdvancedMachineFeatures ThreadsPerCore must be 1 or 2, got %d", *features.ThreadsPerCore)
+	}
+	if features.VisibleCoreCount != nil && *features.VisibleCoreCount < 1 {
+		return fmt.Errorf("AdvancedMachineFeatures VisibleCoreCount must be at least 1, got %d", *features.VisibleCoreCount)
+	}
+	return nil
+}
+
+func validateNetworkPerformanceConfig(spec infrav1.GCPMachineSpec) error {
+	if spec.NetworkPerformanceConfig == nil || ptr.Deref(spec.NetworkPerformanceConfig.TotalEgressBandwidthTier, infrav1.EgressBandwidthTierDefault) != infrav1.EgressBandwidthTier1 {
+		return nil
+	}
+
+	if ptr.Deref(spec.NicType, "") != infrav1.NicTypeGVNIC {
+		return errors.New("NetworkPerformanceConfig TotalEgressBandwidthTier TIER_1 requires the GVNIC NicType")
+	}
+	machineSeries := strings.Split(spec.InstanceType, "-")[0]
+	if !slices.Contains(tier1MachineSeries, machineSeries) {
+		return fmt.Errorf("NetworkPerformanceConfig TotalEgressBandwidthTier TIER_1 is not supported for machine series %s", machineSeries)
+	}
+	if vCPUs, ok := machineTypeVCPUs(spec.InstanceType); ok && vCPUs < tier1MinVCPUs {
+		return fmt.Errorf("NetworkPerformanceConfig TotalEgressBandwidthTier TIER_1 requires at least %d vCPUs, InstanceType %s has %d", tier1MinVCPUs, spec.InstanceType, vCPUs)
+	}
+	return nil
+}
+
+func validateRootDevice(spec infrav1.GCPMachineSpec) error {
+	diskType := ptr.Deref(spec.RootDeviceType, infrav1.PdStandardDiskType)
+	limits, ok := rootDeviceSizeLimits[diskType]
+	if !ok {
+		return fmt.Errorf("RootDeviceType %s is not supported for boot disks", diskType)
+	}
+	if spec.RootDeviceProvisionedIops != nil && diskType != infrav1.PdExtremeDiskType && diskType != infrav1.HyperdiskBalancedDiskType {
+		return fmt.Errorf("RootDeviceProvisionedIops is not supported by RootDeviceType %s", diskType)
+	}
+	if spec.RootDeviceProvisionedThroughput != nil && diskType != infrav1.HyperdiskBalancedDiskType {
+		return fmt.Errorf("RootDeviceProvisionedThroughput is not supported by RootDeviceType %s", diskType)
+	}
+	if spec.RootDeviceSize == 0 {
+		if limits.min > minRootDeviceSize {
+			return fmt.Errorf("RootDeviceSize must be set to at least %dGB for RootDeviceType %s", limits.min, diskType)
+		}
+		return nil
+	}
+	if spec.RootDeviceSize < limits.min || spec.RootDeviceSize > limits.max {
+		return fmt.Errorf("RootDeviceSize of %dGB must be between %dGB and %dGB for RootDeviceType %s", spec.RootDeviceSize, limits.min, limits.max, diskType)
+	}
+
+	minSize := int64(minRootDeviceSize)
+	if ptr.Deref(spec.OSFamily, infrav1.OSFamilyLinux) == infrav1.OSFamilyWindows || hasWindowsImage(spec) {
+		minSize = minWindowsRootDeviceSize
+	}
+	if spec.RootDeviceSize < minSize {
+		return fmt.Errorf("RootDeviceSize of %dGB is smaller than the minimum of %dGB required by the image", spec.RootDeviceSize, minSize)
+	}
+	return nil
+}
+
+func validateShieldedInstanceConfig(spec infrav1.GCPMachineSpec) error {
+	if spec.ShieldedInstanceConfig == nil || spec.ShieldedInstanceConfig.SecureBoot != infrav1.SecureBootPolicyEnabled {
+		return nil
+	}
+
+	image := ptr.Deref(spec.Image, ptr.Deref(spec.ImageFamily, ""))
+	imageName := path.Base(image)
+	for _, prefix := range nonUEFIImageFamilyPrefixes {
+		if strings.HasPrefix(imageName, prefix) {
+			return fmt.Errorf("ShieldedInstanceConfig SecureBoot requires a UEFI-compatible image, %s does not support UEFI", image)
+		}
+	}
+	return nil
+}
+
 func checkKeyType(key *infrav1.CustomerEncryptionKey) error {
 	switch key.KeyType {
 	case infrav1.CustomerManagedKey:
 		if key.ManagedKey == nil || key.SuppliedKey != nil {
 			return errors.New("CustomerEncryptionKey KeyType of Managed requires only ManagedKey to be set")
 		}
+		if !kmsKeyNameRegexp.MatchString(key.ManagedKey.KMSKeyName) {
+			return fmt.Errorf("CustomerEncryptionKey ManagedKey KMSKeyName %s must be of the form projects/<project>/locations/<location>/keyRings/<key-ring>/cryptoKeys/<key>[/cryptoKeyVersions/<version>]", key.ManagedKey.KMSKeyName)
+		}
 	case infrav1.CustomerSuppliedKey:
 		if key.SuppliedKey == nil || key.ManagedKey != nil {
 			return errors.New("CustomerEncryptionKey KeyType of Supplied requires only SuppliedKey to be set")
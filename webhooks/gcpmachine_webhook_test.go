@@ -17,9 +17,13 @@ limitations under the License.
 package webhooks
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 )
 
@@ -32,6 +36,8 @@ func TestGCPMachine_ValidateCreate(t *testing.T) {
 	confidentialComputeFooBar := infrav1.ConfidentialComputePolicy("foobar")
 	onHostMaintenanceTerminate := infrav1.HostMaintenancePolicyTerminate
 	onHostMaintenanceMigrate := infrav1.HostMaintenancePolicyMigrate
+	provisioningModelSpot := infrav1.ProvisioningModelSpot
+	terminationActionDelete := infrav1.InstanceTerminationActionDelete
 	tests := []struct {
 		name string
 		*infrav1.GCPMachine
@@ -269,6 +275,1323 @@ func TestGCPMachine_ValidateCreate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "GCPMachine with SecureBoot enabled and UEFI-compatible image - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					Image: ptr.To[string]("projects/ubuntu-os-cloud/global/images/ubuntu-2204-jammy-v20240126"),
+					ShieldedInstanceConfig: &infrav1.GCPShieldedInstanceConfig{
+						SecureBoot: infrav1.SecureBootPolicyEnabled,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with SecureBoot enabled and legacy image family - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					ImageFamily: ptr.To[string]("projects/centos-cloud/global/images/family/centos-6"),
+					ShieldedInstanceConfig: &infrav1.GCPShieldedInstanceConfig{
+						SecureBoot: infrav1.SecureBootPolicyEnabled,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with SecureBoot disabled and legacy image family - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					ImageFamily: ptr.To[string]("projects/centos-cloud/global/images/family/centos-6"),
+					ShieldedInstanceConfig: &infrav1.GCPShieldedInstanceConfig{
+						SecureBoot: infrav1.SecureBootPolicyDisabled,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with Preemptible and OnHostMaintenance set to Terminate - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					Preemptible:       true,
+					OnHostMaintenance: &onHostMaintenanceTerminate,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with Preemptible and OnHostMaintenance set to Migrate - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					Preemptible:       true,
+					OnHostMaintenance: &onHostMaintenanceMigrate,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with ProvisioningModel Spot and TerminationAction Delete - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					ProvisioningModel: &provisioningModelSpot,
+					TerminationAction: &terminationActionDelete,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with ProvisioningModel Spot and Preemptible - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					Preemptible:       true,
+					ProvisioningModel: &provisioningModelSpot,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with TerminationAction and without ProvisioningModel Spot - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					TerminationAction: &terminationActionDelete,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with GuestAccelerators - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n1-standard-4",
+					GuestAccelerators: []infrav1.Accelerator{
+						{Type: "nvidia-tesla-t4", Count: 1},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with GuestAccelerators count of 0 - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n1-standard-4",
+					GuestAccelerators: []infrav1.Accelerator{
+						{Type: "nvidia-tesla-t4", Count: 0},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with GuestAccelerators on shared-core machine type - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "e2-medium",
+					GuestAccelerators: []infrav1.Accelerator{
+						{Type: "nvidia-tesla-t4", Count: 1},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with LocalSSDs within the machine series maximum - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-8",
+					LocalSSDs: []infrav1.LocalSSDSpec{
+						{Count: 2},
+						{Count: 2, Interface: ptr.To(infrav1.LocalSSDInterfaceSCSI)},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with LocalSSDs exceeding the machine series maximum - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "c2-standard-8",
+					LocalSSDs: []infrav1.LocalSSDSpec{
+						{Count: 16},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with LocalSSDs on a machine series without local SSD support - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "e2-standard-4",
+					LocalSSDs: []infrav1.LocalSSDSpec{
+						{Count: 1},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with local-ssd AdditionalDisk of 375GB - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-8",
+					AdditionalDisks: []infrav1.AttachedDiskSpec{
+						{DeviceType: ptr.To(infrav1.LocalSsdDiskType), Size: ptr.To[int64](375)},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with local-ssd AdditionalDisk not of 375GB - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-8",
+					AdditionalDisks: []infrav1.AttachedDiskSpec{
+						{DeviceType: ptr.To(infrav1.LocalSsdDiskType), Size: ptr.To[int64](100)},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with LocalSSDs and local-ssd AdditionalDisks exceeding the machine series maximum - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "c2-standard-8",
+					LocalSSDs: []infrav1.LocalSSDSpec{
+						{Count: 8},
+					},
+					AdditionalDisks: []infrav1.AttachedDiskSpec{
+						{DeviceType: ptr.To(infrav1.LocalSsdDiskType)},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with GuestAccelerators on a compatible machine type - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n1-standard-4",
+					GuestAccelerators: []infrav1.Accelerator{
+						{Type: "projects/my-project/zones/us-central1-c/acceleratorTypes/nvidia-tesla-t4", Count: 1},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with GuestAccelerators on an incompatible machine type - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					GuestAccelerators: []infrav1.Accelerator{
+						{Type: "nvidia-tesla-t4", Count: 1},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with RootDiskEncryptionKey KeyType Managed and malformed KMSKeyName - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					RootDiskEncryptionKey: &infrav1.CustomerEncryptionKey{
+						KeyType: infrav1.CustomerManagedKey,
+						ManagedKey: &infrav1.ManagedKey{
+							KMSKeyName: "projects/my-project/keyRings/us-central1/cryptoKeys/some-key",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with RootDiskEncryptionKey KeyType Managed and KMSKeyName of a key version - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					RootDiskEncryptionKey: &infrav1.CustomerEncryptionKey{
+						KeyType: infrav1.CustomerManagedKey,
+						ManagedKey: &infrav1.ManagedKey{
+							KMSKeyName: "projects/my-project/locations/us-central1/keyRings/us-central1/cryptoKeys/some-key/cryptoKeyVersions/1",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with RootDiskEncryptionKey KeyType Managed and KMSKeyName with an empty key version - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					RootDiskEncryptionKey: &infrav1.CustomerEncryptionKey{
+						KeyType: infrav1.CustomerManagedKey,
+						ManagedKey: &infrav1.ManagedKey{
+							KMSKeyName: "projects/my-project/locations/us-central1/keyRings/us-central1/cryptoKeys/some-key/cryptoKeyVersions/",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with AdditionalDisk Encryption KeyType Managed and valid KMSKeyName - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalDisks: []infrav1.AttachedDiskSpec{
+						{
+							EncryptionKey: &infrav1.CustomerEncryptionKey{
+								KeyType: infrav1.CustomerManagedKey,
+								ManagedKey: &infrav1.ManagedKey{
+									KMSKeyName: "projects/my-project/locations/us-central1/keyRings/us-central1/cryptoKeys/data-key",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with RootDeviceSize above the image minimum - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					RootDeviceSize: 30,
+					RootDeviceType: ptr.To(infrav1.DiskType("pd-balanced")),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with RootDeviceSize below the image minimum - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					RootDeviceSize: 5,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with RootDeviceSize below the Windows image minimum - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					OSFamily:       ptr.To(infrav1.OSFamilyWindows),
+					ImageFamily:    ptr.To[string]("projects/windows-cloud/global/images/family/windows-2022"),
+					RootDeviceSize: 30,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with a Windows image - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					OSFamily:       ptr.To(infrav1.OSFamilyWindows),
+					ImageFamily:    ptr.To[string]("projects/windows-cloud/global/images/family/windows-2022"),
+					RootDeviceSize: 50,
+					AdditionalMetadata: []infrav1.MetadataItem{
+						{Key: "user-data", Value: ptr.To("foo")},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with a Windows image without the Windows OSFamily - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					ImageFamily:    ptr.To[string]("projects/windows-cloud/global/images/family/windows-2022"),
+					RootDeviceSize: 50,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with the Windows bootstrap data metadata key - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					OSFamily: ptr.To(infrav1.OSFamilyWindows),
+					AdditionalMetadata: []infrav1.MetadataItem{
+						{Key: "windows-startup-script-ps1", Value: ptr.To("foo")},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with OS Login on Windows - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					OSFamily:      ptr.To(infrav1.OSFamilyWindows),
+					EnableOSLogin: ptr.To(true),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with SEV-SNP ConfidentialCompute on Windows - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:        "n2d-standard-4",
+					OSFamily:            ptr.To(infrav1.OSFamilyWindows),
+					ConfidentialCompute: ptr.To(infrav1.ConfidentialComputePolicySEVSNP),
+					OnHostMaintenance:   ptr.To(infrav1.HostMaintenancePolicyTerminate),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with local-ssd RootDeviceType - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					RootDeviceType: ptr.To(infrav1.LocalSsdDiskType),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with pd-extreme RootDeviceType and provisioned IOPS - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					RootDeviceSize:            500,
+					RootDeviceType:            ptr.To(infrav1.PdExtremeDiskType),
+					RootDeviceProvisionedIops: ptr.To[int64](20000),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with pd-extreme RootDeviceType below the minimum size - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					RootDeviceSize: 100,
+					RootDeviceType: ptr.To(infrav1.PdExtremeDiskType),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with pd-extreme RootDeviceType and the default size - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					RootDeviceType: ptr.To(infrav1.PdExtremeDiskType),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with hyperdisk-balanced RootDeviceType and provisioned throughput - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					RootDeviceSize:                  50,
+					RootDeviceType:                  ptr.To(infrav1.HyperdiskBalancedDiskType),
+					RootDeviceProvisionedIops:       ptr.To[int64](3000),
+					RootDeviceProvisionedThroughput: ptr.To[int64](140),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with pd-balanced RootDeviceType and provisioned IOPS - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					RootDeviceSize:            50,
+					RootDeviceType:            ptr.To(infrav1.PdBalancedDiskType),
+					RootDeviceProvisionedIops: ptr.To[int64](3000),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with pd-extreme RootDeviceType and provisioned throughput - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					RootDeviceSize:                  500,
+					RootDeviceType:                  ptr.To(infrav1.PdExtremeDiskType),
+					RootDeviceProvisionedThroughput: ptr.To[int64](140),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with unsupported RootDeviceType - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					RootDeviceType: ptr.To(infrav1.DiskType("pd-fast")),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with NodeAffinities - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					NodeAffinities: []infrav1.NodeAffinity{
+						{Key: "compute.googleapis.com/node-group-name", Operator: infrav1.NodeAffinityOperatorIn, Values: []string{"my-node-group"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with NodeAffinities and Spot ProvisioningModel - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					ProvisioningModel: &provisioningModelSpot,
+					NodeAffinities: []infrav1.NodeAffinity{
+						{Key: "compute.googleapis.com/node-group-name", Operator: infrav1.NodeAffinityOperatorIn, Values: []string{"my-node-group"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with MinCPUPlatform available for the machine series - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:   "n2-standard-4",
+					MinCPUPlatform: ptr.To[string]("Intel Ice Lake"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with MinCPUPlatform not available for the machine series - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:   "n2d-standard-4",
+					MinCPUPlatform: ptr.To[string]("Intel Ice Lake"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with Ampere Altra MinCPUPlatform on a T2A machine type - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:   "t2a-standard-4",
+					MinCPUPlatform: ptr.To[string]("Ampere Altra"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with Intel MinCPUPlatform on a T2A machine type - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:   "t2a-standard-4",
+					MinCPUPlatform: ptr.To[string]("Intel Ice Lake"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with arm64 image family on a T2A machine type - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "t2a-standard-4",
+					ImageFamily:  ptr.To[string]("projects/ubuntu-os-cloud/global/images/family/ubuntu-2204-lts-arm64"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with arm64 image family on an x86-64 machine type - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					ImageFamily:  ptr.To[string]("projects/ubuntu-os-cloud/global/images/family/ubuntu-2204-lts-arm64"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with amd64 image on a T2A machine type - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "t2a-standard-4",
+					Image:        ptr.To[string]("projects/ubuntu-os-cloud/global/images/ubuntu-2204-jammy-amd64-v20240614"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with Windows on a T2A machine type - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "t2a-standard-4",
+					OSFamily:     ptr.To(infrav1.OSFamilyWindows),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with integrity monitoring on a T2A machine type - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "t2a-standard-4",
+					ShieldedInstanceConfig: &infrav1.GCPShieldedInstanceConfig{
+						IntegrityMonitoring: infrav1.IntegrityMonitoringPolicyEnabled,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with nested virtualization on an Intel machine series - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					AdvancedMachineFeatures: &infrav1.AdvancedMachineFeatures{
+						EnableNestedVirtualization: ptr.To(true),
+						ThreadsPerCore:             ptr.To[int64](2),
+						VisibleCoreCount:           ptr.To[int64](2),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with nested virtualization on an E2 machine series - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "e2-standard-4",
+					AdvancedMachineFeatures: &infrav1.AdvancedMachineFeatures{
+						EnableNestedVirtualization: ptr.To(true),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with ThreadsPerCore other than 1 or 2 - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					AdvancedMachineFeatures: &infrav1.AdvancedMachineFeatures{
+						ThreadsPerCore: ptr.To[int64](4),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with malformed MinCPUPlatform - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:   "n2-standard-4",
+					MinCPUPlatform: ptr.To[string]("icelake"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with InstanceNamePrefix - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:       "n2-standard-4",
+					InstanceNamePrefix: ptr.To("prod-"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with InstanceNamePrefix starting with a number - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:       "n2-standard-4",
+					InstanceNamePrefix: ptr.To("1-prod-"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with InstanceNamePrefix with uppercase letters - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:       "n2-standard-4",
+					InstanceNamePrefix: ptr.To("Prod-"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with InstanceNamePrefix of 32 characters - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:       "n2-standard-4",
+					InstanceNamePrefix: ptr.To(strings.Repeat("a", 32)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with InstanceNamePrefix of 33 characters - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:       "n2-standard-4",
+					InstanceNamePrefix: ptr.To(strings.Repeat("a", 33)),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with DrainTimeout - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					DrainTimeout: &metav1.Duration{Duration: 30 * time.Second},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with negative DrainTimeout - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					DrainTimeout: &metav1.Duration{Duration: -time.Second},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with MinCPUPlatform on a machine series without CPU platform selection - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:   "e2-standard-4",
+					MinCPUPlatform: ptr.To[string]("Intel Skylake"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with AutomaticRestart disabled and OnHostMaintenance set to Terminate - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					OnHostMaintenance: &onHostMaintenanceTerminate,
+					AutomaticRestart:  ptr.To(false),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with Spot ProvisioningModel and AutomaticRestart enabled - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					ProvisioningModel: &provisioningModelSpot,
+					AutomaticRestart:  ptr.To(true),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with GuestAccelerators and OnHostMaintenance set to Migrate - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:      "n1-standard-4",
+					OnHostMaintenance: &onHostMaintenanceMigrate,
+					GuestAccelerators: []infrav1.Accelerator{
+						{Type: "nvidia-tesla-t4", Count: 1},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with AdditionalMetadata - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalMetadata: []infrav1.MetadataItem{
+						{Key: "enable-oslogin", Value: ptr.To[string]("TRUE")},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with AdditionalMetadata overriding the bootstrap data - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalMetadata: []infrav1.MetadataItem{
+						{Key: "user-data", Value: ptr.To[string]("#cloud-config")},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with AdditionalMetadata overriding the BootstrapDataMetadataKey - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					BootstrapDataMetadataKey: ptr.To("ignition-config"),
+					AdditionalMetadata: []infrav1.MetadataItem{
+						{Key: "ignition-config", Value: ptr.To[string]("{}")},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with AdditionalMetadata setting user-data with another BootstrapDataMetadataKey - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					BootstrapDataMetadataKey: ptr.To("ignition-config"),
+					AdditionalMetadata: []infrav1.MetadataItem{
+						{Key: "user-data", Value: ptr.To[string]("#cloud-config")},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with an AdditionalMetadata value exceeding the metadata value limit - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalMetadata: []infrav1.MetadataItem{
+						{Key: "startup-script", Value: ptr.To(strings.Repeat("#", 256*1024+1))},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with AdditionalMetadata exceeding the metadata limit - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalMetadata: []infrav1.MetadataItem{
+						{Key: "startup-script", Value: ptr.To(strings.Repeat("#", 256*1024))},
+						{Key: "shutdown-script", Value: ptr.To(strings.Repeat("#", 256*1024))},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with EnableOSLogin and EnableOSLogin2FA - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					EnableOSLogin:    ptr.To(true),
+					EnableOSLogin2FA: ptr.To(true),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with EnableOSLogin2FA and EnableOSLogin disabled - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					EnableOSLogin:    ptr.To(false),
+					EnableOSLogin2FA: ptr.To(true),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with valid AdditionalLabels - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalLabels: infrav1.Labels{"team": "payments", "cost-center": ""},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with uppercase AdditionalLabels key - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalLabels: infrav1.Labels{"Team": "payments"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with AdditionalLabels value too long - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalLabels: infrav1.Labels{"team": strings.Repeat("a", 64)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with valid AdditionalNetworkTags - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalNetworkTags: []string{"allow-ssh", "web1"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with AdditionalNetworkTags ending with a dash - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalNetworkTags: []string{"allow-"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with uppercase AdditionalNetworkTags - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalNetworkTags: []string{"Allow-SSH"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with AdditionalNetworkInterfaces within the machine type limit - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					AdditionalNetworkInterfaces: []infrav1.NetworkInterfaceSpec{
+						{Network: "mgmt", Subnetwork: ptr.To("mgmt-subnet")},
+						{Network: "projects/appliances/global/networks/appliance", PublicIP: ptr.To(true)},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with AdditionalNetworkInterfaces exceeding the machine type limit - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "e2-medium",
+					AdditionalNetworkInterfaces: []infrav1.NetworkInterfaceSpec{
+						{Network: "mgmt"},
+						{Network: "appliance"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with AdditionalNetworkInterfaces attached to the same network - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-8",
+					AdditionalNetworkInterfaces: []infrav1.NetworkInterfaceSpec{
+						{Network: "mgmt"},
+						{Network: "projects/my-project/global/networks/mgmt"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with AdditionalNetworkInterfaces and a static internal IP - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					AdditionalNetworkInterfaces: []infrav1.NetworkInterfaceSpec{
+						{Network: "appliance", Subnetwork: ptr.To("appliance-subnet"), InternalIP: ptr.To("10.10.0.5")},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with AdditionalNetworkInterfaces and an invalid internal IP - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					AdditionalNetworkInterfaces: []infrav1.NetworkInterfaceSpec{
+						{Network: "appliance", InternalIP: ptr.To("10.10.0.500")},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with AdditionalNetworkInterfaces alias IP ranges - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					AdditionalNetworkInterfaces: []infrav1.NetworkInterfaceSpec{
+						{
+							Network:       "appliance",
+							Subnetwork:    ptr.To("appliance-subnet"),
+							AliasIPRanges: []infrav1.AliasIPRange{{IPCidrRange: "/24", SubnetworkRangeName: "pods"}},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with AdditionalNetworkInterfaces alias IP range secondary range but no subnetwork - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					AdditionalNetworkInterfaces: []infrav1.NetworkInterfaceSpec{
+						{
+							Network:       "appliance",
+							AliasIPRanges: []infrav1.AliasIPRange{{IPCidrRange: "/24", SubnetworkRangeName: "pods"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with an InternalAddress and a static address - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:    "n2-standard-4",
+					InternalAddress: &infrav1.InternalAddressSpec{Name: "my-address", Address: ptr.To("10.0.0.10")},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with an InternalAddress and an IPv6 address - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:    "n2-standard-4",
+					InternalAddress: &infrav1.InternalAddressSpec{Name: "my-address", Address: ptr.To("fd20::10")},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with an ImageLookupFormat - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:       "n2-standard-4",
+					ImageLookupProject: ptr.To("my-images"),
+					ImageLookupFormat:  ptr.To("capi-{{.BaseOS}}-{{.K8sVersion}}"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with an invalid ImageLookupFormat template - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:       "n2-standard-4",
+					ImageLookupProject: ptr.To("my-images"),
+					ImageLookupFormat:  ptr.To("capi-{{.BaseOS"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with a service account and scope aliases - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					ServiceAccount: &infrav1.ServiceAccount{
+						Email:  "workers@my-project.iam.gserviceaccount.com",
+						Scopes: []string{"logging-write", "https://www.googleapis.com/auth/devstorage.read_only"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with an invalid service account email - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:   "n2-standard-4",
+					ServiceAccount: &infrav1.ServiceAccount{Email: "workers"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with an unknown service account scope - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					ServiceAccount: &infrav1.ServiceAccount{
+						Email:  "default",
+						Scopes: []string{"storage"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with gVNIC and TIER_1 networking - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-32",
+					NicType:      ptr.To(infrav1.NicTypeGVNIC),
+					NetworkPerformanceConfig: &infrav1.NetworkPerformanceConfig{
+						TotalEgressBandwidthTier: ptr.To(infrav1.EgressBandwidthTier1),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with TIER_1 networking without gVNIC - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-32",
+					NetworkPerformanceConfig: &infrav1.NetworkPerformanceConfig{
+						TotalEgressBandwidthTier: ptr.To(infrav1.EgressBandwidthTier1),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with TIER_1 networking on an unsupported machine series - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "e2-standard-32",
+					NicType:      ptr.To(infrav1.NicTypeGVNIC),
+					NetworkPerformanceConfig: &infrav1.NetworkPerformanceConfig{
+						TotalEgressBandwidthTier: ptr.To(infrav1.EgressBandwidthTier1),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with TIER_1 networking on a small machine type - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-8",
+					NicType:      ptr.To(infrav1.NicTypeGVNIC),
+					NetworkPerformanceConfig: &infrav1.NetworkPerformanceConfig{
+						TotalEgressBandwidthTier: ptr.To(infrav1.EgressBandwidthTier1),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with resource policy names and URLs - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					ResourcePolicies: []string{
+						"my-placement",
+						"projects/my-project/regions/us-central1/resourcePolicies/my-schedule",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with a malformed resource policy - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:     "n2-standard-4",
+					ResourcePolicies: []string{"projects/my-project/regions/us-central1/subnetworks/my-subnet"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with attached disks - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					AttachedDisks: []infrav1.ExistingDiskSpec{
+						{Name: "my-disk", DeviceName: ptr.To("data")},
+						{Name: "projects/my-project/zones/us-central1-a/disks/my-shared-disk", Mode: ptr.To(infrav1.DiskModeReadOnly)},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with a dual-stack interface and external IPv6 - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:   "n2-standard-4",
+					StackType:      ptr.To(infrav1.StackTypeIPv4IPv6),
+					Ipv6AccessType: ptr.To(infrav1.Ipv6AccessTypeExternal),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with an Ipv6AccessType without the dual-stack StackType - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:   "n2-standard-4",
+					Ipv6AccessType: ptr.To(infrav1.Ipv6AccessTypeInternal),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with an image family name and project - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:       "n2-standard-4",
+					ImageFamily:        ptr.To("ubuntu-2204-lts"),
+					ImageFamilyProject: ptr.To("ubuntu-os-cloud"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with an image family URL and project - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:       "n2-standard-4",
+					ImageFamily:        ptr.To("projects/my-images/global/images/family/capi-ubuntu-2204"),
+					ImageFamilyProject: ptr.To("ubuntu-os-cloud"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with an image URL as image family - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					ImageFamily:  ptr.To("projects/my-images/global/images/capi-ubuntu-2204-v1"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with a regional additional disk - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					AdditionalDisks: []infrav1.AttachedDiskSpec{
+						{DeviceType: ptr.To(infrav1.PdBalancedDiskType), ReplicaZones: []string{"us-central1-a", "us-central1-b"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with a regional additional disk across regions - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					AdditionalDisks: []infrav1.AttachedDiskSpec{
+						{DeviceType: ptr.To(infrav1.PdBalancedDiskType), ReplicaZones: []string{"us-central1-a", "us-east1-b"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with a regional additional disk replicated in the same zone - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					AdditionalDisks: []infrav1.AttachedDiskSpec{
+						{DeviceType: ptr.To(infrav1.PdSsdDiskType), ReplicaZones: []string{"us-central1-a", "us-central1-a"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with a small regional pd-standard additional disk - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					AdditionalDisks: []infrav1.AttachedDiskSpec{
+						{ReplicaZones: []string{"us-central1-a", "us-central1-b"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with a regional pd-extreme additional disk - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+					AdditionalDisks: []infrav1.AttachedDiskSpec{
+						{DeviceType: ptr.To(infrav1.PdExtremeDiskType), Size: ptr.To[int64](500), ReplicaZones: []string{"us-central1-a", "us-central1-b"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with a regional root device - valid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:           "n2-standard-4",
+					RootDeviceType:         ptr.To(infrav1.PdBalancedDiskType),
+					RootDeviceReplicaZones: []string{"us-central1-a", "us-central1-b"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with a regional pd-standard root device smaller than 200GB - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:           "n2-standard-4",
+					RootDeviceReplicaZones: []string{"us-central1-a", "us-central1-b"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with root device replica zones in different regions - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:           "n2-standard-4",
+					RootDeviceType:         ptr.To(infrav1.PdSsdDiskType),
+					RootDeviceReplicaZones: []string{"us-central1-a", "us-east1-b"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with a regional hyperdisk-balanced root device - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:           "n4-standard-4",
+					RootDeviceType:         ptr.To(infrav1.HyperdiskBalancedDiskType),
+					RootDeviceSize:         100,
+					RootDeviceReplicaZones: []string{"us-central1-a", "us-central1-b"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with a malformed attached disk - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:  "n2-standard-4",
+					AttachedDisks: []infrav1.ExistingDiskSpec{{Name: "projects/my-project/global/images/my-image"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with a disk attached twice - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:  "n2-standard-4",
+					AttachedDisks: []infrav1.ExistingDiskSpec{{Name: "my-disk"}, {Name: "my-disk", Mode: ptr.To(infrav1.DiskModeReadOnly)}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with an attached disk device name used by an additional disk - invalid",
+			GCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType:    "n2-standard-4",
+					AdditionalDisks: []infrav1.AttachedDiskSpec{{DeviceName: ptr.To("data")}},
+					AttachedDisks:   []infrav1.ExistingDiskSpec{{Name: "my-disk", DeviceName: ptr.To("data")}},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -283,3 +1606,227 @@ func TestGCPMachine_ValidateCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestGCPMachine_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+	tests := []struct {
+		name          string
+		oldGCPMachine *infrav1.GCPMachine
+		newGCPMachine *infrav1.GCPMachine
+		wantErr       bool
+	}{
+		{
+			name: "GCPMachine with changed AdditionalLabels - valid",
+			oldGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					MinCPUPlatform: ptr.To[string]("Intel Ice Lake"),
+				},
+			},
+			newGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					MinCPUPlatform:   ptr.To[string]("Intel Ice Lake"),
+					AdditionalLabels: infrav1.Labels{"foo": "bar"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with unchanged uppercase AdditionalLabels - valid",
+			oldGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalLabels: infrav1.Labels{"Team": "Platform"},
+				},
+			},
+			newGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalLabels:   infrav1.Labels{"Team": "Platform"},
+					DeletionProtection: ptr.To(false),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with changed uppercase AdditionalLabels - invalid",
+			oldGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalLabels: infrav1.Labels{"Team": "Platform"},
+				},
+			},
+			newGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalLabels: infrav1.Labels{"Team": "Payments"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with changed AdditionalMetadata - valid",
+			oldGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalMetadata: []infrav1.MetadataItem{{Key: "enable-oslogin", Value: ptr.To("TRUE")}},
+				},
+			},
+			newGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalMetadata: []infrav1.MetadataItem{{Key: "block-project-ssh-keys", Value: ptr.To("TRUE")}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:          "GCPMachine with changed DeletionProtection - valid",
+			oldGCPMachine: &infrav1.GCPMachine{},
+			newGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					DeletionProtection: ptr.To(true),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:          "GCPMachine with changed InstanceNamePrefix - invalid",
+			oldGCPMachine: &infrav1.GCPMachine{},
+			newGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceNamePrefix: ptr.To("prod-"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:          "GCPMachine with changed DrainTimeout - valid",
+			oldGCPMachine: &infrav1.GCPMachine{},
+			newGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					DrainTimeout: &metav1.Duration{Duration: time.Minute},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:          "GCPMachine with changed RebootstrapPolicy - valid",
+			oldGCPMachine: &infrav1.GCPMachine{},
+			newGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					RebootstrapPolicy: ptr.To(infrav1.RebootstrapPolicyReset),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:          "GCPMachine with user-data AdditionalMetadata on update - invalid",
+			oldGCPMachine: &infrav1.GCPMachine{},
+			newGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					AdditionalMetadata: []infrav1.MetadataItem{{Key: "user-data", Value: ptr.To("foo")}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:          "GCPMachine with changed EnableOSLogin - valid",
+			oldGCPMachine: &infrav1.GCPMachine{},
+			newGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					EnableOSLogin: ptr.To(true),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:          "GCPMachine with Zone set - valid",
+			oldGCPMachine: &infrav1.GCPMachine{},
+			newGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					Zone: ptr.To("us-central1-a"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachine with changed Zone - invalid",
+			oldGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					Zone: ptr.To("us-central1-a"),
+				},
+			},
+			newGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					Zone: ptr.To("us-central1-b"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachine with changed MinCPUPlatform - invalid",
+			oldGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					MinCPUPlatform: ptr.To[string]("Intel Cascade Lake"),
+				},
+			},
+			newGCPMachine: &infrav1.GCPMachine{
+				Spec: infrav1.GCPMachineSpec{
+					MinCPUPlatform: ptr.To[string]("Intel Ice Lake"),
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			warn, err := (&GCPMachine{}).ValidateUpdate(t.Context(), test.oldGCPMachine, test.newGCPMachine)
+			if test.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(warn).To(BeNil())
+		})
+	}
+}
+
+func TestGCPMachine_ValidateCreate_osLoginWarning(t *testing.T) {
+	g := NewWithT(t)
+	machine := &infrav1.GCPMachine{
+		Spec: infrav1.GCPMachineSpec{
+			InstanceType:       "n2d-standard-4",
+			EnableOSLogin:      ptr.To(true),
+			AdditionalMetadata: []infrav1.MetadataItem{{Key: "ssh-keys", Value: ptr.To("user:ssh-ed25519 AAAA")}},
+		},
+	}
+	warn, err := (&GCPMachine{}).ValidateCreate(t.Context(), machine)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warn).To(HaveLen(1))
+
+	machine.Spec.EnableOSLogin = ptr.To(false)
+	warn, err = (&GCPMachine{}).ValidateCreate(t.Context(), machine)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warn).To(BeNil())
+}
+
+func TestGCPMachine_ValidateCreate_serviceAccountWarning(t *testing.T) {
+	g := NewWithT(t)
+	machine := &infrav1.GCPMachine{
+		Spec: infrav1.GCPMachineSpec{
+			InstanceType: "n2d-standard-4",
+			ServiceAccount: &infrav1.ServiceAccount{
+				Email:  "worker@my-project.iam.gserviceaccount.com",
+				Scopes: []string{"cloud-platform", "logging-write"},
+			},
+		},
+	}
+	warn, err := (&GCPMachine{}).ValidateCreate(t.Context(), machine)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warn).To(HaveLen(1))
+
+	machine.Spec.ServiceAccount.Scopes = []string{"https://www.googleapis.com/auth/cloud-platform", "monitoring-write"}
+	warn, err = (&GCPMachine{}).ValidateCreate(t.Context(), machine)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warn).To(HaveLen(1))
+
+	machine.Spec.ServiceAccount.Scopes = []string{"logging-write", "monitoring-write"}
+	warn, err = (&GCPMachine{}).ValidateCreate(t.Context(), machine)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warn).To(BeEmpty())
+}
@@ -23,9 +23,13 @@ import (
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/topology"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -59,16 +63,120 @@ func (*GCPMachineTemplate) ValidateCreate(_ context.Context, obj runtime.Object)
 
 	clusterlog.Info("validate create", "name", r.Name)
 
-	return nil, validateConfidentialCompute(r.Spec.Template.Spec)
+	if err := validateInstanceNamePrefix(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateConfidentialCompute(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateShieldedInstanceConfig(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateScheduling(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateGuestAccelerators(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateLocalSSDs(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateRootDevice(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateMinCPUPlatform(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateArchitecture(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateDrainTimeout(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateAdvancedMachineFeatures(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateNetworkPerformanceConfig(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateAdditionalMetadata(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateOSLogin(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateOSFamily(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateLabels(r.Spec.Template.Spec.AdditionalLabels); err != nil {
+		return nil, err
+	}
+	if err := validateNetworkTags(r.Spec.Template.Spec.AdditionalNetworkTags); err != nil {
+		return nil, err
+	}
+	if err := validateNetworkInterfaces(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateImageLookupFormat(r.Spec.Template.Spec.ImageLookupFormat); err != nil {
+		return nil, err
+	}
+	if err := validateServiceAccount(r.Spec.Template.Spec.ServiceAccount); err != nil {
+		return nil, err
+	}
+	if err := validateResourcePolicies(r.Spec.Template.Spec.ResourcePolicies); err != nil {
+		return nil, err
+	}
+	if err := validateAttachedDisks(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateStackType(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateImageFamily(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := validateReplicaZones(r.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	for _, disk := range r.Spec.Template.Spec.AttachedDisks {
+		if ptr.Deref(disk.Mode, infrav1.DiskModeReadWrite) == infrav1.DiskModeReadWrite {
+			return nil, fmt.Errorf("AttachedDisks disk %s must be attached in ReadOnly mode on a GCPMachineTemplate as a disk can only be attached to a single machine in ReadWrite mode", disk.Name)
+		}
+	}
+	if r.Spec.Template.Spec.InternalAddress != nil {
+		return nil, errors.New("InternalAddress can't be set on a GCPMachineTemplate as the address can only be used by a single machine")
+	}
+	return append(osLoginWarnings(r.Spec.Template.Spec), serviceAccountWarnings(r.Spec.Template.Spec.ServiceAccount)...), nil
+}
+
+// gcpMachineTemplateMutableFields lists the fields of a GCPMachineTemplate spec that can be changed in place.
+// Everything else is immutable per the Cluster API contract, a new template has to be created instead.
+var gcpMachineTemplateMutableFields = [][]string{
+	// labels and annotations propagated to the GCPMachines created from the template
+	{"spec", "template", "metadata"},
+	{"spec", "template", "spec", "providerID"},
+	{"spec", "template", "spec", "additionalLabels"},
+	{"spec", "template", "spec", "additionalNetworkTags"},
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (*GCPMachineTemplate) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+func (*GCPMachineTemplate) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	r, ok := newObj.(*infrav1.GCPMachineTemplate)
 	if !ok {
 		return nil, fmt.Errorf("expected an GCPMachineTemplate object but got %T", r)
 	}
 
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an admission.Request inside context: %v", err))
+	}
+
+	// The topology controller dry-runs updates of the templates of a ClusterClass to detect changes, it then
+	// rotates the template instead of updating it. Let these requests through so topology reconciliation works.
+	if topology.IsDryRunRequest(req, r) {
+		return nil, nil
+	}
+
 	newGCPMachineTemplate, err := runtime.DefaultUnstructuredConverter.ToUnstructured(r)
 	if err != nil {
 		return nil, apierrors.NewInvalid(infrav1.GroupVersion.WithKind("GCPMachineTemplate").GroupKind(), r.Name, field.ErrorList{
@@ -82,25 +190,28 @@ func (*GCPMachineTemplate) ValidateUpdate(_ context.Context, oldObj, newObj runt
 		})
 	}
 
-	newGCPMachineTemplateSpec := newGCPMachineTemplate["spec"].(map[string]interface{})
-	oldGCPMachineTemplateSpec := oldGCPMachineTemplate["spec"].(map[string]interface{})
-
-	// allow changes to providerID
-	delete(oldGCPMachineTemplateSpec, "providerID")
-	delete(newGCPMachineTemplateSpec, "providerID")
-
-	// allow changes to additionalLabels
-	delete(oldGCPMachineTemplateSpec, "additionalLabels")
-	delete(newGCPMachineTemplateSpec, "additionalLabels")
+	for _, path := range gcpMachineTemplateMutableFields {
+		unstructured.RemoveNestedField(oldGCPMachineTemplate, path...)
+		unstructured.RemoveNestedField(newGCPMachineTemplate, path...)
+	}
 
-	// allow changes to additionalNetworkTags
-	delete(oldGCPMachineTemplateSpec, "additionalNetworkTags")
-	delete(newGCPMachineTemplateSpec, "additionalNetworkTags")
+	oldGCPMachineSpec, _, _ := unstructured.NestedMap(oldGCPMachineTemplate, "spec", "template", "spec")
+	newGCPMachineSpec, _, _ := unstructured.NestedMap(newGCPMachineTemplate, "spec", "template", "spec")
 
-	if !reflect.DeepEqual(oldGCPMachineTemplateSpec, newGCPMachineTemplateSpec) {
-		return nil, apierrors.NewInvalid(infrav1.GroupVersion.WithKind("GCPMachineTemplate").GroupKind(), r.Name, field.ErrorList{
-			field.Forbidden(field.NewPath("spec"), "cannot be modified"),
-		})
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec", "template", "spec")
+	for _, name := range sets.List(sets.KeySet(oldGCPMachineSpec).Union(sets.KeySet(newGCPMachineSpec))) {
+		if !reflect.DeepEqual(oldGCPMachineSpec[name], newGCPMachineSpec[name]) {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child(name),
+				"GCPMachineTemplate spec is immutable, create a new GCPMachineTemplate and update the references to it instead"))
+		}
+	}
+	if len(allErrs) == 0 && !reflect.DeepEqual(oldGCPMachineTemplate["spec"], newGCPMachineTemplate["spec"]) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"),
+			"GCPMachineTemplate spec is immutable, create a new GCPMachineTemplate and update the references to it instead"))
+	}
+	if len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(infrav1.GroupVersion.WithKind("GCPMachineTemplate").GroupKind(), r.Name, allErrs)
 	}
 
 	return nil, nil
@@ -18,9 +18,14 @@ package webhooks
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 func TestGCPMachineTemplate_ValidateCreate(t *testing.T) {
@@ -30,7 +35,9 @@ func TestGCPMachineTemplate_ValidateCreate(t *testing.T) {
 	confidentialComputeSEVSNP := infrav1.ConfidentialComputePolicySEVSNP
 	confidentialComputeTDX := infrav1.ConfidentialComputePolicyTDX
 	onHostMaintenanceTerminate := infrav1.HostMaintenancePolicyTerminate
+	invalidInstanceNamePrefix := "prod_"
 	onHostMaintenanceMigrate := infrav1.HostMaintenancePolicyMigrate
+	diskModeReadOnly := infrav1.DiskModeReadOnly
 	tests := []struct {
 		name     string
 		template *infrav1.GCPMachineTemplate
@@ -50,6 +57,20 @@ func TestGCPMachineTemplate_ValidateCreate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "GCPMachineTemplate with invalid InstanceNamePrefix - invalid",
+			template: &infrav1.GCPMachineTemplate{
+				Spec: infrav1.GCPMachineTemplateSpec{
+					Template: infrav1.GCPMachineTemplateResource{
+						Spec: infrav1.GCPMachineSpec{
+							InstanceType:       "n2-standard-4",
+							InstanceNamePrefix: &invalidInstanceNamePrefix,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "GCPMachineTemplate with ConfidentialCompute enabled and OnHostMaintenance set to Terminate - valid",
 			template: &infrav1.GCPMachineTemplate{
@@ -229,6 +250,91 @@ func TestGCPMachineTemplate_ValidateCreate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "GCPMachineTemplate with Preemptible and OnHostMaintenance set to Migrate - invalid",
+			template: &infrav1.GCPMachineTemplate{
+				Spec: infrav1.GCPMachineTemplateSpec{
+					Template: infrav1.GCPMachineTemplateResource{
+						Spec: infrav1.GCPMachineSpec{
+							InstanceType:      "n2d-standard-4",
+							Preemptible:       true,
+							OnHostMaintenance: &onHostMaintenanceMigrate,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachineTemplate with an InternalAddress - invalid",
+			template: &infrav1.GCPMachineTemplate{
+				Spec: infrav1.GCPMachineTemplateSpec{
+					Template: infrav1.GCPMachineTemplateResource{
+						Spec: infrav1.GCPMachineSpec{
+							InstanceType:    "n2d-standard-4",
+							InternalAddress: &infrav1.InternalAddressSpec{Name: "my-address"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachineTemplate with a read-only attached disk - valid",
+			template: &infrav1.GCPMachineTemplate{
+				Spec: infrav1.GCPMachineTemplateSpec{
+					Template: infrav1.GCPMachineTemplateResource{
+						Spec: infrav1.GCPMachineSpec{
+							InstanceType:  "n2d-standard-4",
+							AttachedDisks: []infrav1.ExistingDiskSpec{{Name: "my-disk", Mode: &diskModeReadOnly}},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachineTemplate with a read-write attached disk - invalid",
+			template: &infrav1.GCPMachineTemplate{
+				Spec: infrav1.GCPMachineTemplateSpec{
+					Template: infrav1.GCPMachineTemplateResource{
+						Spec: infrav1.GCPMachineSpec{
+							InstanceType:  "n2d-standard-4",
+							AttachedDisks: []infrav1.ExistingDiskSpec{{Name: "my-disk"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachineTemplate with DrainTimeout - valid",
+			template: &infrav1.GCPMachineTemplate{
+				Spec: infrav1.GCPMachineTemplateSpec{
+					Template: infrav1.GCPMachineTemplateResource{
+						Spec: infrav1.GCPMachineSpec{
+							InstanceType: "n2d-standard-4",
+							DrainTimeout: &metav1.Duration{Duration: 30 * time.Second},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachineTemplate with negative DrainTimeout - invalid",
+			template: &infrav1.GCPMachineTemplate{
+				Spec: infrav1.GCPMachineTemplateSpec{
+					Template: infrav1.GCPMachineTemplateResource{
+						Spec: infrav1.GCPMachineSpec{
+							InstanceType: "n2d-standard-4",
+							DrainTimeout: &metav1.Duration{Duration: -time.Second},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -243,3 +349,98 @@ func TestGCPMachineTemplate_ValidateCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestGCPMachineTemplate_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+	oldTemplate := &infrav1.GCPMachineTemplate{
+		Spec: infrav1.GCPMachineTemplateSpec{
+			Template: infrav1.GCPMachineTemplateResource{
+				Spec: infrav1.GCPMachineSpec{
+					InstanceType: "n2-standard-4",
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name        string
+		newTemplate func(*infrav1.GCPMachineTemplate)
+		dryRun      bool
+		wantErr     bool
+	}{
+		{
+			name:        "GCPMachineTemplate unchanged - valid",
+			newTemplate: func(*infrav1.GCPMachineTemplate) {},
+			wantErr:     false,
+		},
+		{
+			name: "GCPMachineTemplate with changed labels and annotations - valid",
+			newTemplate: func(template *infrav1.GCPMachineTemplate) {
+				template.Labels = map[string]string{"foo": "bar"}
+				template.Annotations = map[string]string{"foo": "bar"}
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachineTemplate with changed template metadata - valid",
+			newTemplate: func(template *infrav1.GCPMachineTemplate) {
+				template.Spec.Template.ObjectMeta.Labels = map[string]string{"foo": "bar"}
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachineTemplate with changed AdditionalLabels - valid",
+			newTemplate: func(template *infrav1.GCPMachineTemplate) {
+				template.Spec.Template.Spec.AdditionalLabels = infrav1.Labels{"foo": "bar"}
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachineTemplate with changed AdditionalNetworkTags - valid",
+			newTemplate: func(template *infrav1.GCPMachineTemplate) {
+				template.Spec.Template.Spec.AdditionalNetworkTags = []string{"foo"}
+			},
+			wantErr: false,
+		},
+		{
+			name: "GCPMachineTemplate with changed InstanceType - invalid",
+			newTemplate: func(template *infrav1.GCPMachineTemplate) {
+				template.Spec.Template.Spec.InstanceType = "n2-standard-8"
+			},
+			wantErr: true,
+		},
+		{
+			name: "GCPMachineTemplate with changed InstanceType in a dry-run without the topology annotation - invalid",
+			newTemplate: func(template *infrav1.GCPMachineTemplate) {
+				template.Spec.Template.Spec.InstanceType = "n2-standard-8"
+			},
+			dryRun:  true,
+			wantErr: true,
+		},
+		{
+			name: "GCPMachineTemplate with changed InstanceType in a topology dry-run - valid",
+			newTemplate: func(template *infrav1.GCPMachineTemplate) {
+				template.Annotations = map[string]string{clusterv1.TopologyDryRunAnnotation: ""}
+				template.Spec.Template.Spec.InstanceType = "n2-standard-8"
+			},
+			dryRun:  true,
+			wantErr: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			newTemplate := oldTemplate.DeepCopy()
+			test.newTemplate(newTemplate)
+			ctx := admission.NewContextWithRequest(t.Context(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{DryRun: &test.dryRun},
+			})
+			warn, err := (&GCPMachineTemplate{}).ValidateUpdate(ctx, oldTemplate, newTemplate)
+			if test.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(warn).To(BeNil())
+		})
+	}
+}